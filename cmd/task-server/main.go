@@ -5,21 +5,108 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/go-kratos/kratos/v2/config"
 	"github.com/go-kratos/kratos/v2/config/file"
 	"github.com/go-kratos/kratos/v2/log"
+	"qantas.com/task/internal/biz"
 	conf "qantas.com/task/internal/conf"
+	"qantas.com/task/internal/server"
 )
 
 var (
 	flagconf string
 )
 
+// defaultGracePeriod is used when server.grace_period is not configured.
+const defaultGracePeriod = 10 * time.Second
+
 func init() {
 	flag.StringVar(&flagconf, "conf", "../../configs/dev_config.yaml", "config path, eg: -conf config.yaml")
 }
 
+// app runs the HTTP and gRPC servers side by side, along with the scheduler
+// sending the reminders of the tasks and creating the occurrences of the series.
+type app struct {
+	http      *server.HTTPServer
+	grpc      *server.GRPCServer
+	scheduler *biz.Scheduler
+}
+
+func newApp(hs *server.HTTPServer, gs *server.GRPCServer, sc *biz.Scheduler) *app {
+	return &app{http: hs, grpc: gs, scheduler: sc}
+}
+
+func (a *app) servers() []server.IServer {
+	return []server.IServer{a.grpc, a.http, a.scheduler}
+}
+
+// Run serves every listener and returns the first error any of them fails with.
+func (a *app) Run() error {
+	servers := a.servers()
+	errs := make(chan error, len(servers))
+	for _, s := range servers {
+		go func(s server.IServer) { errs <- s.Run() }(s)
+	}
+
+	for range servers {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops every server, waiting for their in-flight requests until ctx is done.
+func (a *app) Stop(ctx context.Context) error {
+	servers := a.servers()
+	errs := make(chan error, len(servers))
+	for _, s := range servers {
+		go func(s server.IServer) { errs <- s.Stop(ctx) }(s)
+	}
+
+	var err error
+	for range servers {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// serve runs the app until it fails or the process is asked to terminate, then
+// stops it within the grace period.
+func serve(a *app, grace time.Duration, logger *log.Helper) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() { errs <- a.Run() }()
+
+	var err error
+	select {
+	case err = <-errs:
+		logger.Errorf("server failed: %v", err)
+	case <-ctx.Done():
+		logger.Infof("shutting down, waiting up to %s for in-flight requests", grace)
+	}
+	// A second signal kills the process at once
+	stop()
+
+	shutdown, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	if e := a.Stop(shutdown); e != nil {
+		logger.Errorf("failed to stop gracefully: %v", e)
+		if err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
 func main() {
 
 	flag.Parse()
@@ -42,16 +129,22 @@ func main() {
 		log.Fatal(err)
 	}
 
-	logger := log.With(log.NewStdLogger(os.Stdout))
+	logger := log.With(log.NewStdLogger(os.Stdout), "request_id", server.RequestID())
 
-	app, cleanup, err := wireApp(bc.Server, bc.Data, logger, context.Background())
+	app, cleanup, err := wireApp(bc.Server, bc.Data, logger)
 	if err != nil {
 		panic(err)
 	}
 
-	defer cleanup()
+	grace := bc.Server.GetGracePeriod().AsDuration()
+	if grace <= 0 {
+		grace = defaultGracePeriod
+	}
+	err = serve(app, grace, log.NewHelper(logger))
 
-	if err := app.Run(); err != nil {
-		panic(err)
+	// Flush the store only once no request can change it any more
+	cleanup()
+	if err != nil {
+		os.Exit(1)
 	}
 }
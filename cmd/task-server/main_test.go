@@ -1,10 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -13,6 +20,13 @@ import (
 	"github.com/go-kratos/kratos/v2/config/file"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"qantas.com/task/internal/biz"
 	conf "qantas.com/task/internal/conf"
 	"qantas.com/task/internal/encoder"
@@ -27,8 +41,19 @@ type _HTTPSuccess_Task struct {
 }
 
 type _HTTPSuccess_Tasks struct {
+	Code       int            `json:"code,omitempty"`
+	Data       []model.T_Task `json:"data,omitempty"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+type _HTTPSuccess_SearchResults struct {
+	Code int                    `json:"code,omitempty"`
+	Data []model.T_SearchResult `json:"data,omitempty"`
+}
+
+type _HTTPSuccess_Series struct {
 	Code int            `json:"code,omitempty"`
-	Data []model.T_Task `json:"data,omitempty"`
+	Data model.T_Series `json:"data,omitempty"`
 }
 
 // type _HTTPSuccess struct {
@@ -50,23 +75,44 @@ func (s *IntegrationTestSuite) SetupSuite() {
 	if err := c.Scan(&bc); err != nil {
 		s.T().Fatalf("failed to scan config file to conf.Bootstrap. Error: %s", err.Error())
 	}
+
+	// Keep the durable store away from the developer's own log
+	if bc.Data.GetWal().GetPath() != "" {
+		bc.Data.Wal.Path = filepath.Join(s.T().TempDir(), "tasks.wal")
+	}
+	if bc.Data.GetAttachments().GetPath() != "" {
+		bc.Data.Attachments.Path = filepath.Join(s.T().TempDir(), "attachments")
+		s.attachments = bc.Data.Attachments.Path
+	}
 	logger := log.With(log.NewStdLogger(os.Stdout))
 
 	s.context = context.Background()
-	app, cleanup, err := wireApp(bc.Server, bc.Data, logger, s.context)
+	app, cleanup, err := wireApp(bc.Server, bc.Data, logger)
 	if err != nil {
 		s.T().Fatalf("failed to wire app. Error: %s", err.Error())
 	}
 
-	defer cleanup()
+	s.cleanup = cleanup
+	s.app = app
 
-	httpServer := app.(*server.HTTPServer)
+	httpServer := app.http
 	s.testServer = httptest.NewServer(httpServer.GetRouter())
 	httpHandler := httpServer.GetHttpHandler()
 	taskHttpHandler := httpHandler.(*server.TasksHTTPHandler)
 	taskService := taskHttpHandler.GetTaskService()
 	s.uc = taskService.GetTaskUsecase()
 
+	// Serve gRPC over an in-memory listener as well
+	s.grpcListener = bufconn.Listen(1 << 20)
+	go app.grpc.Serve(s.grpcListener)
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(
+		func(ctx context.Context, _ string) (net.Conn, error) { return s.grpcListener.DialContext(ctx) }))
+	if err != nil {
+		s.T().Fatalf("failed to dial grpc server. Error: %s", err.Error())
+	}
+	s.grpcConn = conn
+	s.grpcClient = model.NewTaskServiceClient(conn)
+
 	go func() {
 		if err := app.Run(); err != nil {
 			log.Fatalf("failed to run app. Error: %s", err.Error())
@@ -78,11 +124,25 @@ func (s *IntegrationTestSuite) TearDownTest() {
 	s.uc.ClearTasks(s.context)
 }
 
+func (s *IntegrationTestSuite) TearDownSuite() {
+	s.testServer.Close()
+	s.grpcConn.Close()
+	s.Require().Nil(s.app.Stop(s.context))
+	s.cleanup()
+}
+
 type IntegrationTestSuite struct {
 	suite.Suite
-	context    context.Context
-	testServer *httptest.Server
-	uc         *biz.TaskUsecase
+	context      context.Context
+	testServer   *httptest.Server
+	grpcListener *bufconn.Listener
+	grpcConn     *grpc.ClientConn
+	grpcClient   model.TaskServiceClient
+	uc           *biz.TaskUsecase
+	app          *app
+	cleanup      func()
+	// attachments is the directory of the attachment store.
+	attachments string
 }
 
 func TestSuite(t *testing.T) {
@@ -116,6 +176,23 @@ func (s *IntegrationTestSuite) Test_CreateTask_Success() {
 	s.Require().Equal("content1", rt.Data.Content)
 }
 
+func (s *IntegrationTestSuite) Test_CreateTask_MalformedBody() {
+	// A malformed body is rejected instead of creating an empty task "POST", "/task"
+	res, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(`{"name":`))
+	s.Require().Equal(http.StatusBadRequest, res.StatusCode)
+
+	actual := encoder.HTTPError{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &actual))
+	s.Require().Equal(400, actual.Code)
+	s.Require().Contains(actual.Errors, model.ErrorReason_BAD_REQUEST.String())
+
+	res, resp = utils.TestRequest(s.T(), s.testServer, "GET", "/tasks", nil)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	rt := _HTTPSuccess_Tasks{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &rt))
+	s.Require().Equal(0, len(rt.Data))
+}
+
 func (s *IntegrationTestSuite) Test_GetTask_Success() {
 	// Create a task "POST", "/task"
 	t := model.Task{Name: "user1", Content: "content2"}
@@ -195,6 +272,45 @@ func (s *IntegrationTestSuite) Test_UpdateTask_Success() {
 	s.Require().Nil(rt.Data.DeletedAt)
 }
 
+func (s *IntegrationTestSuite) Test_UpdateTask_IfMatch() {
+	// Create a task
+	taskJson, err := json.Marshal(model.Task{Name: "user1", Content: "content1"})
+	s.Require().Nil(err)
+	_, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(string(taskJson)))
+	ct := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+	s.Require().Equal(200, ct.Code)
+
+	// Read its ETag "GET", "/task/{id}"
+	path := fmt.Sprintf("/task/%d", ct.Data.TaskID)
+	res, _ := utils.TestRequest(s.T(), s.testServer, "GET", path, nil)
+	tag := res.Header.Get("ETag")
+	s.Require().Equal(`"1"`, tag)
+
+	// The first client to write with the ETag wins
+	taskJson, err = json.Marshal(model.Task{TaskID: ct.Data.TaskID, Name: "user2", Content: "content2"})
+	s.Require().Nil(err)
+	res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "PUT", "/task", http.Header{"If-Match": {tag}}, strings.NewReader(string(taskJson)))
+	rt := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &rt))
+	s.Require().Equal(200, rt.Code)
+	s.Require().Equal(uint64(2), rt.Data.Version)
+	s.Require().Equal(`"2"`, res.Header.Get("ETag"))
+
+	// The second one gets a precondition failure instead of clobbering the first
+	taskJson, err = json.Marshal(model.Task{TaskID: ct.Data.TaskID, Name: "user3", Content: "content3"})
+	s.Require().Nil(err)
+	_, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "PUT", "/task", http.Header{"If-Match": {tag}}, strings.NewReader(string(taskJson)))
+	actualError := encoder.HTTPError{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &actualError))
+	s.Require().Equal(*encoder.FromError(model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH))), actualError)
+
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", path, nil)
+	gt := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &gt))
+	s.Require().Equal("user2", gt.Data.Name)
+}
+
 func (s *IntegrationTestSuite) Test_UpdateTask_TaskNotFound() {
 	// Update a task "PUT", "/task"
 	t := model.Task{TaskID: 1, Name: "user2", Content: "content2"}
@@ -310,12 +426,749 @@ func (s *IntegrationTestSuite) Test_ListTask_Success() {
 
 	// Verify the output
 	s.Require().Equal(200, rts.Code)
+	s.Require().Equal(rt1.Data, rts.Data[0])
+	s.Require().Equal(rt2.Data, rts.Data[1])
+}
+
+func (s *IntegrationTestSuite) Test_ListTask_Paging() {
+	// Create three tasks "POST", "/task"
+	for _, name := range []string{"deploy b", "review", "deploy a"} {
+		taskJson, err := json.Marshal(model.Task{Name: name})
+		s.Require().Nil(err)
+		utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(string(taskJson)))
+	}
+
+	// First page "GET", "/tasks?namePrefix=deploy&sortBy=name&pageSize=1"
+	_, resp := utils.TestRequest(s.T(), s.testServer, "GET", "/tasks?namePrefix=deploy&sortBy=name&pageSize=1", nil)
+	rts := _HTTPSuccess_Tasks{}
+	err := json.Unmarshal([]byte(resp), &rts)
+	s.Require().Nil(err)
 
-	if rts.Data[0].TaskID == rt1.Data.TaskID {
-		s.Require().Equal(rt1.Data, rts.Data[0])
-		s.Require().Equal(rt2.Data, rts.Data[1])
-	} else {
-		s.Require().Equal(rt1.Data, rts.Data[1])
-		s.Require().Equal(rt2.Data, rts.Data[0])
+	s.Require().Equal(1, len(rts.Data))
+	s.Require().Equal("deploy a", rts.Data[0].Name)
+	s.Require().NotEmpty(rts.NextCursor)
+
+	// Second and last page
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", "/tasks?namePrefix=deploy&sortBy=name&pageSize=1&cursor="+rts.NextCursor, nil)
+	rts = _HTTPSuccess_Tasks{}
+	err = json.Unmarshal([]byte(resp), &rts)
+	s.Require().Nil(err)
+
+	s.Require().Equal(1, len(rts.Data))
+	s.Require().Equal("deploy b", rts.Data[0].Name)
+	s.Require().Empty(rts.NextCursor)
+}
+
+func (s *IntegrationTestSuite) Test_Trash_RestoreAndPurge() {
+	for _, name := range []string{"user1", "user2"} {
+		taskJson, err := json.Marshal(model.Task{Name: name, Content: "content"})
+		s.Require().Nil(err)
+		_, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(string(taskJson)))
+		ct := _HTTPSuccess_Task{}
+		s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+		s.Require().Equal(200, ct.Code)
 	}
+	utils.TestRequest(s.T(), s.testServer, "DELETE", "/task/1", nil)
+	utils.TestRequest(s.T(), s.testServer, "DELETE", "/task/2", nil)
+
+	// Both deleted tasks are in the trash "GET", "/tasks/trash"
+	_, resp := utils.TestRequest(s.T(), s.testServer, "GET", "/tasks/trash", nil)
+	trash := _HTTPSuccess_Tasks{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &trash))
+	s.Require().Equal(2, len(trash.Data))
+	s.Require().NotNil(trash.Data[0].DeletedAt)
+
+	// Restore the first one "POST", "/task/1/restore"
+	_, resp = utils.TestRequest(s.T(), s.testServer, "POST", "/task/1/restore", nil)
+	rt := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &rt))
+	s.Require().Equal(200, rt.Code)
+	s.Require().Nil(rt.Data.DeletedAt)
+
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", "/task/1", nil)
+	gt := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &gt))
+	s.Require().Equal(200, gt.Code)
+
+	// A live task can be neither restored nor purged
+	_, resp = utils.TestRequest(s.T(), s.testServer, "DELETE", "/task/1/purge", nil)
+	actualError := encoder.HTTPError{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &actualError))
+	s.Require().Equal(*encoder.FromError(model.ErrorTaskNotDeleted(string(encoder.TASK_NOT_DELETED))), actualError)
+
+	// Purge the second one "DELETE", "/task/2/purge"
+	_, resp = utils.TestRequest(s.T(), s.testServer, "DELETE", "/task/2/purge", nil)
+	actual := encoder.HTTPSuccess{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &actual))
+	s.Require().Equal(*encoder.FromResponse(nil), actual)
+
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", "/tasks/trash", nil)
+	trash = _HTTPSuccess_Tasks{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &trash))
+	s.Require().Equal(0, len(trash.Data))
+}
+
+func (s *IntegrationTestSuite) Test_History_Revert() {
+	// Create a task and update it
+	taskJson, err := json.Marshal(model.Task{Name: "user1", Content: "content1"})
+	s.Require().Nil(err)
+	_, resp := utils.TestRequestWithHeader(s.T(), s.testServer, "POST", "/task", http.Header{"X-Actor": {"alice"}}, strings.NewReader(string(taskJson)))
+	ct := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+	s.Require().Equal(200, ct.Code)
+
+	taskJson, err = json.Marshal(model.Task{TaskID: ct.Data.TaskID, Name: "user1", Content: "content2"})
+	s.Require().Nil(err)
+	_, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "PUT", "/task", http.Header{"X-Actor": {"bob"}}, strings.NewReader(string(taskJson)))
+	ut := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &ut))
+	s.Require().Equal(200, ut.Code)
+
+	// Both changes are in the history "GET", "/task/{id}/history"
+	path := fmt.Sprintf("/task/%d/history", ct.Data.TaskID)
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", path, nil)
+	history := struct {
+		Code int                `json:"code"`
+		Data []model.T_Revision `json:"data"`
+	}{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &history))
+	s.Require().Equal(200, history.Code)
+	s.Require().Equal(2, len(history.Data))
+	s.Require().Equal("alice", history.Data[0].Actor)
+	s.Require().Equal("bob", history.Data[1].Actor)
+	s.Require().Equal([]model.T_Change{{Field: "content", From: "content1", To: "content2"}}, history.Data[1].Changes)
+
+	// Undo the update "POST", "/task/{id}/history/1/revert"
+	_, resp = utils.TestRequest(s.T(), s.testServer, "POST", path+"/1/revert", nil)
+	rt := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &rt))
+	s.Require().Equal(200, rt.Code)
+	s.Require().Equal("content1", rt.Data.Content)
+	s.Require().Equal(uint64(3), rt.Data.Version)
+
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", path+"/3", nil)
+	revision := struct {
+		Code int              `json:"code"`
+		Data model.T_Revision `json:"data"`
+	}{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &revision))
+	s.Require().Equal(model.RevisionReverted, revision.Data.Action)
+	s.Require().Equal(uint64(1), revision.Data.RevertedTo)
+}
+
+func (s *IntegrationTestSuite) Test_GRPC_SharesTasksWithHTTP() {
+	ctx := metadata.AppendToOutgoingContext(s.context, "x-actor", "alice")
+
+	// Create a task over gRPC
+	created, err := s.grpcClient.CreateTask(ctx, &model.CreateTaskRequest{Name: "user1", Content: "content1"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(1), created.Version)
+
+	// It can be read over HTTP "GET", "/task/{id}"
+	_, resp := utils.TestRequest(s.T(), s.testServer, "GET", fmt.Sprintf("/task/%d", created.TaskId), nil)
+	rt := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &rt))
+	s.Require().Equal(200, rt.Code)
+	s.Require().Equal("user1", rt.Data.Name)
+
+	// Update it conditionally, a stale version is rejected
+	updated, err := s.grpcClient.UpdateTask(ctx, &model.UpdateTaskRequest{TaskId: created.TaskId, Name: "user1", Content: "content2", Version: 1})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(2), updated.Version)
+	s.Require().NotNil(updated.UpdatedAt)
+
+	_, err = s.grpcClient.UpdateTask(ctx, &model.UpdateTaskRequest{TaskId: created.TaskId, Name: "user1", Content: "content3", Version: 1})
+	s.Require().Equal(codes.FailedPrecondition, status.Code(err))
+
+	history, err := s.grpcClient.GetTaskHistory(s.context, &model.GetTaskHistoryRequest{TaskId: created.TaskId})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(history.Revisions))
+	s.Require().Equal("alice", history.Revisions[1].Actor)
+	s.Require().Equal("content", history.Revisions[1].Changes[0].Field)
+	s.Require().Equal("content2", history.Revisions[1].Changes[0].To.GetStringValue())
+
+	// Delete it and it is gone from both transports
+	_, err = s.grpcClient.DeleteTask(s.context, &model.DeleteTaskRequest{TaskId: created.TaskId})
+	s.Require().Nil(err)
+
+	_, err = s.grpcClient.GetTask(s.context, &model.GetTaskRequest{TaskId: created.TaskId})
+	s.Require().Equal(codes.NotFound, status.Code(err))
+	s.Require().True(model.IsTaskNotFound(err))
+
+	trash, err := s.grpcClient.ListDeletedTasks(s.context, &model.ListTasksRequest{})
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(trash.Tasks))
+	s.Require().NotNil(trash.Tasks[0].DeletedAt)
+}
+
+func (s *IntegrationTestSuite) Test_Transition_Workflow() {
+	taskJson, err := json.Marshal(model.Task{Name: "user1", Content: "content1"})
+	s.Require().Nil(err)
+	_, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(string(taskJson)))
+	ct := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+	s.Require().Equal(model.StatusTodo, ct.Data.Status)
+
+	// Work through the task "POST", "/task/{id}/transition"
+	path := fmt.Sprintf("/task/%d/transition", ct.Data.TaskID)
+	for _, status := range []model.TaskStatus{model.StatusInProgress, model.StatusBlocked, model.StatusInProgress, model.StatusDone} {
+		res, resp := utils.TestRequest(s.T(), s.testServer, "POST", path, strings.NewReader(fmt.Sprintf(`{"status":%q}`, status)))
+		s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	}
+
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", fmt.Sprintf("/task/%d", ct.Data.TaskID), nil)
+	gt := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &gt))
+	s.Require().Equal(model.StatusDone, gt.Data.Status)
+	s.Require().Equal(uint64(5), gt.Data.Version)
+
+	// Cycle time runs from when work started to when it was done
+	entered := gt.Data.StatusEnteredAt
+	s.Require().False(entered[model.StatusDone].Before(entered[model.StatusInProgress]))
+	s.Require().False(entered[model.StatusInProgress].Before(entered[model.StatusBlocked]))
+
+	// A finished task cannot be cancelled
+	res, resp := utils.TestRequest(s.T(), s.testServer, "POST", path, strings.NewReader(`{"status":"cancelled"}`))
+	s.Require().Equal(http.StatusConflict, res.StatusCode)
+	actual := encoder.HTTPError{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &actual))
+	s.Require().Contains(actual.Errors, model.ErrorReason_INVALID_TRANSITION.String())
+
+	// The status cannot be changed by an update either
+	res, _ = utils.TestRequest(s.T(), s.testServer, "PUT", "/task",
+		strings.NewReader(fmt.Sprintf(`{"taskID":%d,"name":"user1","status":"todo"}`, ct.Data.TaskID)))
+	s.Require().Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func (s *IntegrationTestSuite) Test_Subtasks() {
+	parent, err := s.grpcClient.CreateTask(s.context, &model.CreateTaskRequest{Name: "release"})
+	s.Require().Nil(err)
+
+	// Subtasks "POST", "/task" with a parentID
+	var ids []uint64
+	for _, name := range []string{"build", "publish"} {
+		_, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/task",
+			strings.NewReader(fmt.Sprintf(`{"name":%q,"parentID":%d}`, name, parent.TaskId)))
+		ct := _HTTPSuccess_Task{}
+		s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+		s.Require().Equal(parent.TaskId, ct.Data.ParentID)
+		ids = append(ids, ct.Data.TaskID)
+	}
+	_, err = s.grpcClient.CreateTask(s.context, &model.CreateTaskRequest{Name: "sign", ParentId: ids[1]})
+	s.Require().Nil(err)
+
+	// A task cannot be moved below its own subtask
+	res, resp := utils.TestRequest(s.T(), s.testServer, "PUT", "/task",
+		strings.NewReader(fmt.Sprintf(`{"taskID":%d,"name":"release","parentID":%d}`, parent.TaskId, ids[1])))
+	s.Require().Equal(http.StatusBadRequest, res.StatusCode)
+	actual := encoder.HTTPError{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &actual))
+	s.Require().Contains(actual.Errors, model.ErrorReason_TASK_INVALID_PARENT.String())
+
+	tree, err := s.grpcClient.GetTaskTree(s.context, &model.GetTaskTreeRequest{TaskId: parent.TaskId})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(tree.Children))
+	s.Require().Equal("sign", tree.Children[1].Children[0].Task.Name)
+
+	// A task with subtasks is only deleted along with them
+	res, _ = utils.TestRequest(s.T(), s.testServer, "DELETE", fmt.Sprintf("/task/%d", parent.TaskId), nil)
+	s.Require().Equal(http.StatusConflict, res.StatusCode)
+
+	res, resp = utils.TestRequest(s.T(), s.testServer, "DELETE", fmt.Sprintf("/task/%d?cascade=true", parent.TaskId), nil)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	s.Require().Equal("{\"code\":200,\"data\":{\"deleted\":4}}\n", resp)
+
+	trash, err := s.grpcClient.ListDeletedTasks(s.context, &model.ListTasksRequest{})
+	s.Require().Nil(err)
+	s.Require().Equal(4, len(trash.Tasks))
+}
+
+func (s *IntegrationTestSuite) Test_Dependencies() {
+	var ids []uint64
+	for _, name := range []string{"build", "test", "deploy"} {
+		created, err := s.grpcClient.CreateTask(s.context, &model.CreateTaskRequest{Name: name})
+		s.Require().Nil(err)
+		ids = append(ids, created.TaskId)
+	}
+	build, test, deploy := ids[0], ids[1], ids[2]
+
+	// deploy waits on test, which waits on build "PUT", "/task/{id}/dependencies/{dep}"
+	res, _ := utils.TestRequest(s.T(), s.testServer, "PUT", fmt.Sprintf("/task/%d/dependencies/%d", deploy, test), nil)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	_, err := s.grpcClient.AddTaskDependency(s.context, &model.TaskDependencyRequest{TaskId: test, DependsOn: build})
+	s.Require().Nil(err)
+
+	res, _ = utils.TestRequest(s.T(), s.testServer, "PUT", fmt.Sprintf("/task/%d/dependencies/%d", build, deploy), nil)
+	s.Require().Equal(http.StatusConflict, res.StatusCode)
+
+	graph, err := s.grpcClient.GetTaskGraph(s.context, &emptypb.Empty{})
+	s.Require().Nil(err)
+	s.Require().Equal([]uint64{build, test, deploy}, []uint64{graph.Tasks[0].TaskId, graph.Tasks[1].TaskId, graph.Tasks[2].TaskId})
+	s.Require().Equal([]uint64{test}, graph.Tasks[2].DependsOn)
+
+	// Only build can start
+	_, resp := utils.TestRequest(s.T(), s.testServer, "GET", "/tasks/ready", nil)
+	s.Require().Contains(resp, fmt.Sprintf(`"taskID":%d`, build))
+	s.Require().NotContains(resp, fmt.Sprintf(`"taskID":%d`, test))
+
+	res, resp = utils.TestRequest(s.T(), s.testServer, "POST", fmt.Sprintf("/task/%d/transition", test), strings.NewReader(`{"status":"in_progress"}`))
+	s.Require().Equal(http.StatusConflict, res.StatusCode)
+	actual := encoder.HTTPError{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &actual))
+	s.Require().Contains(actual.Errors, model.ErrorReason_TASK_BLOCKED.String())
+
+	for _, status := range []string{"in_progress", "done"} {
+		_, err := s.grpcClient.TransitionTask(s.context, &model.TransitionTaskRequest{TaskId: build, Status: status})
+		s.Require().Nil(err)
+	}
+
+	ready, err := s.grpcClient.ListReadyTasks(s.context, &model.ListTasksRequest{})
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(ready.Tasks))
+	s.Require().Equal(test, ready.Tasks[0].TaskId)
+
+	_, err = s.grpcClient.TransitionTask(s.context, &model.TransitionTaskRequest{TaskId: test, Status: "in_progress"})
+	s.Require().Nil(err)
+}
+
+func (s *IntegrationTestSuite) Test_Tags() {
+	for _, body := range []string{`{"name":"crash","tags":["Bug","urgent"]}`, `{"name":"typo","tags":["bug"]}`, `{"name":"login","tags":["feature"]}`} {
+		res, _ := utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(body))
+		s.Require().Equal(http.StatusOK, res.StatusCode)
+	}
+
+	// Tagged tasks "GET", "/tasks?tag=x&tag=y"
+	_, resp := utils.TestRequest(s.T(), s.testServer, "GET", "/tasks?tag=bug&tag=urgent", nil)
+	lt := _HTTPSuccess_Tasks{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &lt))
+	s.Require().Equal(1, len(lt.Data))
+	s.Require().Equal("crash", lt.Data[0].Name)
+	s.Require().Equal([]string{"bug", "urgent"}, lt.Data[0].Tags)
+
+	tagged, err := s.grpcClient.ListTasks(s.context, &model.ListTasksRequest{Tags: []string{"urgent", "feature"}, TagMatch: "any"})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(tagged.Tasks))
+
+	renamed, err := s.grpcClient.RenameTag(s.context, &model.RenameTagRequest{From: "urgent", To: "bug"})
+	s.Require().Nil(err)
+	s.Require().Equal(int32(1), renamed.Renamed)
+
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", "/tags", nil)
+	s.Require().Equal("{\"code\":200,\"data\":[{\"tag\":\"bug\",\"count\":2},{\"tag\":\"feature\",\"count\":1}]}\n", resp)
+}
+
+func (s *IntegrationTestSuite) Test_SearchTasks() {
+	for _, body := range []string{
+		`{"name":"Fix login","content":"The login page fails after a password reset."}`,
+		`{"name":"Docs","content":"Describe the login flow."}`,
+		`{"name":"Cleanup","content":"Remove the old reset code."}`,
+	} {
+		res, _ := utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(body))
+		s.Require().Equal(http.StatusOK, res.StatusCode)
+	}
+
+	// Search "GET", "/tasks/search?q=x"
+	res, resp := utils.TestRequest(s.T(), s.testServer, "GET", "/tasks/search?q=login+-docs", nil)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	sr := _HTTPSuccess_SearchResults{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &sr))
+	s.Require().Equal(1, len(sr.Data))
+	s.Require().Equal("Fix login", sr.Data[0].Task.Name)
+	s.Require().Equal("The **login** page fails after a password reset.", sr.Data[0].Snippet)
+
+	// The index follows updates
+	res, _ = utils.TestRequest(s.T(), s.testServer, "PUT", "/task", strings.NewReader(`{"taskID":2,"name":"Docs","content":"Describe the reset flow."}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+
+	found, err := s.grpcClient.SearchTasks(s.context, &model.SearchTasksRequest{Q: `"reset flow" OR rese*`, Limit: 2})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(found.Results))
+	s.Require().Equal(uint64(2), found.Results[0].Task.TaskId)
+
+	_, err = s.grpcClient.SearchTasks(s.context, &model.SearchTasksRequest{Q: "(login"})
+	s.Require().Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *IntegrationTestSuite) Test_ListTask_Filter() {
+	for _, body := range []string{`{"name":"deploy api","tags":["ops"]}`, `{"name":"deploy web","tags":["web"]}`, `{"name":"review","tags":["ops"]}`} {
+		res, _ := utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(body))
+		s.Require().Equal(http.StatusOK, res.StatusCode)
+	}
+	_, err := s.grpcClient.TransitionTask(s.context, &model.TransitionTaskRequest{TaskId: 1, Status: string(model.StatusInProgress)})
+	s.Require().Nil(err)
+
+	// Filtered tasks "GET", "/tasks?filter=x"
+	_, resp := utils.TestRequest(s.T(), s.testServer, "GET", "/tasks?filter="+url.QueryEscape(`name ~ "deploy*" AND NOT status = in_progress`), nil)
+	lt := _HTTPSuccess_Tasks{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &lt))
+	s.Require().Equal(1, len(lt.Data))
+	s.Require().Equal("deploy web", lt.Data[0].Name)
+
+	filtered, err := s.grpcClient.ListTasks(s.context, &model.ListTasksRequest{Filter: `tag = ops OR taskID = 2`, Descending: true})
+	s.Require().Nil(err)
+	s.Require().Equal(3, len(filtered.Tasks))
+	s.Require().Equal(uint64(3), filtered.Tasks[0].TaskId)
+
+	_, err = s.grpcClient.ListTasks(s.context, &model.ListTasksRequest{Filter: `tag = `})
+	s.Require().Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *IntegrationTestSuite) Test_DueDatesAndReminders() {
+	now := time.Now().UTC()
+	for _, task := range []model.Task{
+		{Name: "overdue", DueAt: timePtr(now.Add(-time.Hour))},
+		{Name: "due soon", DueAt: timePtr(now.Add(time.Hour)), RemindAt: timePtr(now.Add(-time.Minute))},
+		{Name: "due later", DueAt: timePtr(now.Add(48 * time.Hour))},
+	} {
+		body, _ := json.Marshal(task)
+		res, _ := utils.TestRequest(s.T(), s.testServer, "POST", "/task", bytes.NewReader(body))
+		s.Require().Equal(http.StatusOK, res.StatusCode)
+	}
+
+	// Overdue tasks "GET", "/tasks/overdue"
+	_, resp := utils.TestRequest(s.T(), s.testServer, "GET", "/tasks/overdue", nil)
+	lt := _HTTPSuccess_Tasks{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &lt))
+	s.Require().Equal(1, len(lt.Data))
+	s.Require().Equal("overdue", lt.Data[0].Name)
+
+	// Tasks due soon "GET", "/tasks/due?within=x"
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", "/tasks/due?within=72h", nil)
+	lt = _HTTPSuccess_Tasks{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &lt))
+	s.Require().Equal(2, len(lt.Data))
+	s.Require().Equal("due soon", lt.Data[0].Name)
+
+	due, err := s.grpcClient.ListDueTasks(s.context, &model.ListDueTasksRequest{Within: durationpb.New(2 * time.Hour)})
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(due.Tasks))
+	s.Require().Equal(uint64(2), due.Tasks[0].TaskId)
+
+	// The scheduler running alongside the servers sends the reminder which is due
+	s.Require().Eventually(func() bool {
+		task, err := s.grpcClient.GetTask(s.context, &model.GetTaskRequest{TaskId: 2})
+		return err == nil && task.RemindedAt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (s *IntegrationTestSuite) Test_RecurringTasks() {
+	// The first occurrence of a daily series started three days ago is overdue
+	start := time.Now().UTC().Truncate(time.Second).Add(-72 * time.Hour)
+	body, _ := json.Marshal(model.Series{Rule: "FREQ=DAILY", Start: &start, Name: "water the plants", Tags: []string{"home"}})
+	res, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/series", bytes.NewReader(body))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	cs := _HTTPSuccess_Series{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &cs))
+	s.Require().Equal(uint64(1), cs.Data.CurrentTaskID)
+	s.Require().True(cs.Data.CurrentAt.Equal(start))
+
+	// Its period has elapsed, so the scheduler creates the next occurrence, skipping the missed days
+	gs := _HTTPSuccess_Series{}
+	s.Require().Eventually(func() bool {
+		_, resp := utils.TestRequest(s.T(), s.testServer, "GET", "/series/1", nil)
+		gs = _HTTPSuccess_Series{}
+		return json.Unmarshal([]byte(resp), &gs) == nil && gs.Data.Occurrences == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	s.Require().True(gs.Data.CurrentAt.After(time.Now()))
+	s.Require().True(gs.Data.CurrentAt.Before(time.Now().Add(24 * time.Hour)))
+
+	// Finishing the current occurrence creates the next one right away
+	path := fmt.Sprintf("/task/%d/transition", gs.Data.CurrentTaskID)
+	for _, status := range []model.TaskStatus{model.StatusInProgress, model.StatusDone} {
+		res, resp := utils.TestRequest(s.T(), s.testServer, "POST", path, strings.NewReader(fmt.Sprintf(`{"status":%q}`, status)))
+		s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	}
+	series, err := s.grpcClient.GetSeries(s.context, &model.GetSeriesRequest{SeriesId: 1})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(3), series.Occurrences)
+	s.Require().True(series.CurrentAt.AsTime().Equal(gs.Data.CurrentAt.AddDate(0, 0, 1)))
+
+	// Editing the series renames the occurrence still open
+	body, _ = json.Marshal(model.Series{SeriesID: 1, Rule: "FREQ=DAILY", Name: "water the garden", Tags: []string{"home"}})
+	res, resp = utils.TestRequest(s.T(), s.testServer, "PUT", "/series", bytes.NewReader(body))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", "/tasks?filter="+url.QueryEscape("seriesID = 1 AND name ~ \"*garden\""), nil)
+	lt := _HTTPSuccess_Tasks{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &lt))
+	s.Require().Equal(2, len(lt.Data))
+	s.Require().Equal(uint64(1), lt.Data[0].TaskID)
+	s.Require().Equal(series.CurrentTaskId, lt.Data[1].TaskID)
+
+	// A stopped series creates no more occurrences
+	stopped, err := s.grpcClient.StopSeries(s.context, &model.GetSeriesRequest{SeriesId: 1})
+	s.Require().Nil(err)
+	s.Require().NotNil(stopped.StoppedAt)
+	res, _ = utils.TestRequest(s.T(), s.testServer, "POST", fmt.Sprintf("/task/%d/transition", series.CurrentTaskId), strings.NewReader(`{"status":"cancelled"}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	all, err := s.grpcClient.ListSeries(s.context, &emptypb.Empty{})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(3), all.Series[0].Occurrences)
+}
+
+func (s *IntegrationTestSuite) Test_AssigneesAndOwners() {
+	as := func(user string) http.Header { return http.Header{"X-Actor": {user}} }
+
+	res, resp := utils.TestRequestWithHeader(s.T(), s.testServer, "POST", "/task", as("alice"), strings.NewReader(`{"name":"deploy"}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	ct := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+	s.Require().Equal("alice", ct.Data.CreatedBy)
+	s.Require().Equal("alice", ct.Data.Owner)
+	path := fmt.Sprintf("/task/%d", ct.Data.TaskID)
+
+	// Nobody but the owner moves the task until it is assigned
+	res, _ = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", path+"/transition", as("bob"), strings.NewReader(`{"status":"in_progress"}`))
+	s.Require().Equal(http.StatusForbidden, res.StatusCode)
+	res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", path+"/assign", as("alice"),
+		strings.NewReader(`{"owner":"alice","assignees":["bob"]}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", path+"/transition", as("bob"), strings.NewReader(`{"status":"in_progress"}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+
+	// The assignee hands the task over through gRPC
+	ctx := metadata.AppendToOutgoingContext(s.context, "x-actor", "bob")
+	task, err := s.grpcClient.ReassignTask(ctx, &model.ReassignTaskRequest{TaskId: ct.Data.TaskID, Owner: "alice", Assignees: []string{"carol"}})
+	s.Require().Nil(err)
+	s.Require().Equal([]string{"carol"}, task.Assignees)
+	_, err = s.grpcClient.TransitionTask(ctx, &model.TransitionTaskRequest{TaskId: ct.Data.TaskID, Status: string(model.StatusDone)})
+	s.Require().True(model.IsTaskForbidden(err))
+
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", "/users/carol/tasks", nil)
+	lt := _HTTPSuccess_Tasks{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &lt))
+	s.Require().Equal(1, len(lt.Data))
+	reply, err := s.grpcClient.ListUserTasks(s.context, &model.ListUserTasksRequest{User: "bob"})
+	s.Require().Nil(err)
+	s.Require().Equal(0, len(reply.Tasks))
+
+	// Both reassignments are in the history, with who made them
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", path+"/history", nil)
+	history := struct {
+		Data []model.T_Revision `json:"data"`
+	}{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &history))
+	s.Require().Equal(4, len(history.Data))
+	s.Require().Equal(model.RevisionReassigned, history.Data[3].Action)
+	s.Require().Equal("bob", history.Data[3].Actor)
+}
+
+func (s *IntegrationTestSuite) Test_Tenants() {
+	res, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/tenants", strings.NewReader(`{"tenantID":"Acme","maxTasks":2}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	s.Require().Contains(resp, `"tenantID":"acme","maxTasks":2`)
+	defer s.grpcClient.DeleteTenant(s.context, &model.DeleteTenantRequest{TenantId: "acme"})
+	res, _ = utils.TestRequest(s.T(), s.testServer, "POST", "/tenants", strings.NewReader(`{"tenantID":"acme"}`))
+	s.Require().Equal(http.StatusConflict, res.StatusCode)
+
+	// Each tenant numbers its tasks from 1 and sees only its own
+	acme := http.Header{"X-Tenant": {"acme"}}
+	res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", "/task", acme, strings.NewReader(`{"name":"acme task"}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	ct := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+	s.Require().Equal(uint64(1), ct.Data.TaskID)
+	res, _ = utils.TestRequest(s.T(), s.testServer, "GET", "/task/1", nil)
+	s.Require().Equal(http.StatusNotFound, res.StatusCode)
+
+	ctx := metadata.AppendToOutgoingContext(s.context, "x-tenant", "acme")
+	_, err := s.grpcClient.CreateTask(ctx, &model.CreateTaskRequest{Name: "second"})
+	s.Require().Nil(err)
+	reply, err := s.grpcClient.ListTasks(ctx, &model.ListTasksRequest{})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(reply.Tasks))
+
+	// The quota of the tenant is reached
+	_, err = s.grpcClient.CreateTask(ctx, &model.CreateTaskRequest{Name: "third"})
+	s.Require().True(model.IsTenantQuotaExceeded(err))
+	res, _ = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", "/task", acme, strings.NewReader(`{"name":"third"}`))
+	s.Require().Equal(http.StatusConflict, res.StatusCode)
+
+	tenants, err := s.grpcClient.ListTenants(s.context, &emptypb.Empty{})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(tenants.Tenants))
+	s.Require().Equal("acme", tenants.Tenants[0].TenantId)
+	s.Require().Equal(int64(2), tenants.Tenants[0].Usage.Tasks)
+
+	// A deleted tenant is gone with its tasks
+	res, _ = utils.TestRequest(s.T(), s.testServer, "DELETE", "/tenants/acme", nil)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	res, _ = utils.TestRequestWithHeader(s.T(), s.testServer, "GET", "/tasks", acme, nil)
+	s.Require().Equal(http.StatusNotFound, res.StatusCode)
+	res, _ = utils.TestRequest(s.T(), s.testServer, "DELETE", "/tenants/default", nil)
+	s.Require().Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func (s *IntegrationTestSuite) Test_ProjectsAndBoards() {
+	res, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/projects", strings.NewReader(`{"projectID":"Apollo","columns":["todo","doing","done"]}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	s.Require().Equal(`"1"`, res.Header.Get("ETag"))
+	res, _ = utils.TestRequest(s.T(), s.testServer, "POST", "/projects", strings.NewReader(`{"projectID":"apollo","columns":["todo"]}`))
+	s.Require().Equal(http.StatusConflict, res.StatusCode)
+
+	var ids []uint64
+	for _, name := range []string{"design", "build", "test"} {
+		res, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(`{"name":"`+name+`"}`))
+		s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+		ct := _HTTPSuccess_Task{}
+		s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+		ids = append(ids, ct.Data.TaskID)
+
+		res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", fmt.Sprintf("/task/%d/move", ct.Data.TaskID),
+			http.Header{"If-Match": {`"1"`}}, strings.NewReader(`{"projectID":"apollo"}`))
+		s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+		s.Require().Equal(`"2"`, res.Header.Get("ETag"))
+	}
+
+	// The last task goes on top, and the first one to another column through gRPC
+	res, resp = utils.TestRequest(s.T(), s.testServer, "POST", fmt.Sprintf("/task/%d/move", ids[2]),
+		strings.NewReader(fmt.Sprintf(`{"projectID":"apollo","before":%d}`, ids[0])))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	task, err := s.grpcClient.MoveTask(s.context, &model.MoveTaskRequest{TaskId: ids[0], ProjectId: "apollo", Column: "done"})
+	s.Require().Nil(err)
+	s.Require().Equal("done", task.Column)
+	res, _ = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", fmt.Sprintf("/task/%d/move", ids[0]),
+		http.Header{"If-Match": {`"2"`}}, strings.NewReader(`{"projectID":"apollo"}`))
+	s.Require().Equal(http.StatusPreconditionFailed, res.StatusCode)
+
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", "/projects/apollo/board", nil)
+	board := struct {
+		Data model.T_Board `json:"data"`
+	}{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &board))
+	s.Require().Equal(3, len(board.Data.Columns))
+	s.Require().Equal(2, len(board.Data.Columns[0].Tasks))
+	s.Require().Equal("test", board.Data.Columns[0].Tasks[0].Name)
+	s.Require().Equal("build", board.Data.Columns[0].Tasks[1].Name)
+	s.Require().Equal(0, len(board.Data.Columns[1].Tasks))
+	s.Require().Equal("design", board.Data.Columns[2].Tasks[0].Name)
+
+	reply, err := s.grpcClient.GetBoard(s.context, &model.GetProjectRequest{ProjectId: "apollo"})
+	s.Require().Nil(err)
+	s.Require().Equal("done", reply.Columns[2].Name)
+	s.Require().Equal(ids[0], reply.Columns[2].Tasks[0].TaskId)
+
+	// A project is only deleted once its board is empty
+	res, _ = utils.TestRequest(s.T(), s.testServer, "DELETE", "/projects/apollo", nil)
+	s.Require().Equal(http.StatusConflict, res.StatusCode)
+	for _, id := range ids {
+		_, err := s.grpcClient.MoveTask(s.context, &model.MoveTaskRequest{TaskId: id})
+		s.Require().Nil(err)
+	}
+	_, err = s.grpcClient.DeleteProject(s.context, &model.GetProjectRequest{ProjectId: "apollo"})
+	s.Require().Nil(err)
+	res, _ = utils.TestRequest(s.T(), s.testServer, "GET", "/projects/apollo", nil)
+	s.Require().Equal(http.StatusNotFound, res.StatusCode)
+}
+
+func (s *IntegrationTestSuite) Test_CommentsAndActivity() {
+	as := func(user string) http.Header { return http.Header{"X-Actor": {user}} }
+
+	res, resp := utils.TestRequestWithHeader(s.T(), s.testServer, "POST", "/task", as("alice"), strings.NewReader(`{"name":"release"}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	ct := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+	path := fmt.Sprintf("/task/%d", ct.Data.TaskID)
+
+	res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", path+"/comments", as("bob"),
+		strings.NewReader(`{"body":"@alice is this ready? cc @carol."}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	comment := struct {
+		Data model.T_Comment `json:"data"`
+	}{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &comment))
+	s.Require().Equal([]string{"alice", "carol"}, comment.Data.Mentions)
+	s.Require().Equal("bob", comment.Data.Author)
+	commentPath := fmt.Sprintf("%s/comments/%d", path, comment.Data.CommentID)
+
+	// Only bob edits his comment, here through gRPC
+	res, _ = utils.TestRequestWithHeader(s.T(), s.testServer, "DELETE", commentPath, as("alice"), nil)
+	s.Require().Equal(http.StatusForbidden, res.StatusCode)
+	ctx := metadata.AppendToOutgoingContext(s.context, "x-actor", "bob")
+	record, err := s.grpcClient.UpdateComment(ctx, &model.CommentRequest{TaskId: ct.Data.TaskID, CommentId: comment.Data.CommentID,
+		Body: "@alice is this ready?", Version: 1})
+	s.Require().Nil(err)
+	s.Require().Equal([]string{"alice"}, record.Mentions)
+	s.Require().Equal(uint64(2), record.Version)
+
+	res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", path+"/transition", as("alice"), strings.NewReader(`{"status":"in_progress"}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", path+"/comments", as("alice"), strings.NewReader(`{"body":"shipped"}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+
+	// The feed interleaves the revisions of the task with the comments on it
+	_, resp = utils.TestRequest(s.T(), s.testServer, "GET", path+"/activity", nil)
+	feed := struct {
+		Data []model.T_Activity `json:"data"`
+	}{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &feed))
+	s.Require().Equal(4, len(feed.Data))
+	s.Require().Equal(model.RevisionCreated, feed.Data[0].Revision.Action)
+	s.Require().Equal("@alice is this ready?", feed.Data[1].Comment.Body)
+	s.Require().Equal(model.RevisionTransitioned, feed.Data[2].Revision.Action)
+	s.Require().Equal("shipped", feed.Data[3].Comment.Body)
+
+	activity, err := s.grpcClient.ListActivity(s.context, &model.ListCommentsRequest{TaskId: ct.Data.TaskID})
+	s.Require().Nil(err)
+	s.Require().Equal(4, len(activity.Activity))
+	s.Require().Equal("comment", activity.Activity[3].Kind)
+	s.Require().Equal("alice", activity.Activity[3].Actor)
+
+	_, err = s.grpcClient.DeleteComment(ctx, &model.DeleteCommentRequest{TaskId: ct.Data.TaskID, CommentId: comment.Data.CommentID})
+	s.Require().Nil(err)
+	comments, err := s.grpcClient.ListComments(s.context, &model.ListCommentsRequest{TaskId: ct.Data.TaskID})
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(comments.Comments))
+}
+
+func (s *IntegrationTestSuite) Test_Attachments() {
+	res, resp := utils.TestRequest(s.T(), s.testServer, "POST", "/task", strings.NewReader(`{"name":"release"}`))
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	ct := _HTTPSuccess_Task{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &ct))
+	path := fmt.Sprintf("/task/%d", ct.Data.TaskID)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "logo.png")
+	s.Require().Nil(err)
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 100)...)
+	_, err = part.Write(png)
+	s.Require().Nil(err)
+	s.Require().Nil(form.Close())
+
+	res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "POST", path+"/attachments",
+		http.Header{"Content-Type": {form.FormDataContentType()}}, &body)
+	s.Require().Equal(http.StatusOK, res.StatusCode, resp)
+	attachment := struct {
+		Data model.T_Attachment `json:"data"`
+	}{}
+	s.Require().Nil(json.Unmarshal([]byte(resp), &attachment))
+	s.Require().Equal("image/png", attachment.Data.ContentType)
+	s.Require().Equal(int64(len(png)), attachment.Data.Size)
+	attachmentPath := fmt.Sprintf("%s/attachments/%d", path, attachment.Data.AttachmentID)
+
+	res, resp = utils.TestRequestWithHeader(s.T(), s.testServer, "GET", attachmentPath, http.Header{"Range": {"bytes=0-7"}}, nil)
+	s.Require().Equal(http.StatusPartialContent, res.StatusCode)
+	s.Require().Equal(string(png[:8]), resp)
+
+	attachments, err := s.grpcClient.ListAttachments(s.context, &model.ListAttachmentsRequest{TaskId: ct.Data.TaskID})
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(attachments.Attachments))
+	s.Require().Equal(attachment.Data.SHA256, attachments.Attachments[0].Sha256)
+
+	// Purging the task removes the content it alone referred to
+	blob := filepath.Join(s.attachments, attachment.Data.SHA256[:2], attachment.Data.SHA256)
+	s.Require().FileExists(blob)
+	res, _ = utils.TestRequest(s.T(), s.testServer, "DELETE", path, nil)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	res, _ = utils.TestRequest(s.T(), s.testServer, "DELETE", path+"/purge", nil)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	s.Require().NoFileExists(blob)
+	res, _ = utils.TestRequest(s.T(), s.testServer, "GET", attachmentPath, nil)
+	s.Require().Equal(http.StatusNotFound, res.StatusCode)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
 }
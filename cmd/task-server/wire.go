@@ -4,7 +4,6 @@
 package main
 
 import (
-	"context"
 	"github.com/go-kratos/kratos/v2/log"
 	"qantas.com/task/internal/biz"
 	conf "qantas.com/task/internal/conf"
@@ -15,6 +14,6 @@ import (
 	"github.com/google/wire"
 )
 
-func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger, ctx context.Context) (server.IServer, func(), error) {
-	panic(wire.Build(server.ProviderSet, data.ProviderSet, biz.ProviderSet, service.ProviderSet))
+func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger) (*app, func(), error) {
+	panic(wire.Build(server.ProviderSet, data.ProviderSet, biz.ProviderSet, service.ProviderSet, newApp))
 }
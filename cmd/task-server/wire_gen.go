@@ -7,7 +7,6 @@
 package main
 
 import (
-	"context"
 	"github.com/go-kratos/kratos/v2/log"
 	"qantas.com/task/internal/biz"
 	"qantas.com/task/internal/conf"
@@ -18,17 +17,32 @@ import (
 
 // Injectors from wire.go:
 
-func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger, ctx context.Context) (server.IServer, func(), error) {
+func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger) (*app, func(), error) {
 	dataData, cleanup, err := data.NewData(confData, logger)
 	if err != nil {
 		return nil, nil, err
 	}
 	iTaskRepo := data.NewTaskRepo(dataData, logger)
-	taskUsecase := biz.NewTaskUsecase(iTaskRepo, logger)
+	policy, err := server.NewPolicy(confServer, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	taskUsecase := biz.NewTaskUsecase(iTaskRepo, policy, logger)
 	taskService := service.NewTaskService(taskUsecase, logger)
-	iTaskHTTPHandler := server.NewTaskHTTPHandler(taskService, logger, ctx)
-	iServer := server.NewHTTPServer(confServer, logger, iTaskHTTPHandler)
-	return iServer, func() {
+	authenticator, err := server.NewAuthenticator(confServer, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	iTaskHTTPHandler := server.NewTaskHTTPHandler(taskService, logger)
+	httpServer := server.NewHTTPServer(confServer, logger, authenticator, iTaskHTTPHandler)
+	taskServiceServer := server.NewTaskGRPCHandler(taskService, logger)
+	grpcServer := server.NewGRPCServer(confServer, logger, authenticator, taskServiceServer)
+	iReminderNotifier := biz.NewLogReminderNotifier(logger)
+	scheduler := biz.NewScheduler(taskUsecase, iReminderNotifier, logger)
+	mainApp := newApp(httpServer, grpcServer, scheduler)
+	return mainApp, func() {
 		cleanup()
 	}, nil
 }
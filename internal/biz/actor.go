@@ -0,0 +1,49 @@
+package biz
+
+import "context"
+
+type actorKey struct{}
+
+type principalKey struct{}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject identifies the caller: the principal of its API key, or the
+	// configured claim of its token.
+	Subject string
+	// Method is how the caller authenticated, "api_key" or "jwt".
+	Method string
+	// ReadOnly callers may only read tasks.
+	ReadOnly bool
+	// Tenant is the only tenant the caller may use. Empty lets it use any tenant.
+	Tenant string
+}
+
+// WithActor returns a context carrying the user who makes the request, which
+// is recorded in the revisions the request produces.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the subject of the authenticated principal, or else
+// the user set by WithActor, or "" when it is unknown. A user named by the
+// request cannot stand in for the one it authenticated as.
+func ActorFromContext(ctx context.Context) string {
+	if p := PrincipalFromContext(ctx); p != nil {
+		return p.Subject
+	}
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// WithPrincipal returns a context carrying the authenticated caller of the request.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal set by WithPrincipal, or nil when
+// the request is not authenticated.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
@@ -0,0 +1,120 @@
+package biz
+
+import (
+	"context"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// MaxAttachmentNameLength bounds the length of the name of an attachment, in bytes.
+const MaxAttachmentNameLength = 255
+
+// IAttachmentStore keeps the content of attachments as blobs named by the hex
+// SHA-256 of their content, so that content attached twice is stored once.
+type IAttachmentStore interface {
+	// Stage reads the content from r and sniffs its type. Content larger than the
+	// limit of the store fails with ATTACHMENT_TOO_LARGE. The content is not a blob
+	// until it is stored, so that no blob exists before an attachment refers to it.
+	Stage(ctx context.Context, r io.Reader) (IStagedBlob, error)
+	// Open returns the content of a blob, which the caller must close.
+	Open(sum string) (io.ReadSeekCloser, error)
+	// Delete removes a blob. Removing one which is not stored changes nothing.
+	Delete(sum string) error
+	// List returns the hashes of every stored blob.
+	List() ([]string, error)
+}
+
+// IStagedBlob is content read by an attachment store, which is either stored as a
+// blob or discarded.
+type IStagedBlob interface {
+	Blob() model.Blob
+	// Store makes the content the blob named by its hash. Content already stored is
+	// left as it is.
+	Store() error
+	// Discard drops the content unless it has been stored, and may be called after Store.
+	Discard() error
+}
+
+// NormalizeAttachmentName returns the name of an attached file without the
+// directories some clients send along with it.
+func NormalizeAttachmentName(name string) (string, error) {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "" || name == "." || name == "..":
+		return "", model.ErrorAttachmentInvalid("%s: the file has no name", encoder.ATTACHMENT_INVALID)
+	case len(name) > MaxAttachmentNameLength:
+		return "", model.ErrorAttachmentInvalid("%s: name is longer than %d bytes", encoder.ATTACHMENT_INVALID, MaxAttachmentNameLength)
+	case !utf8.ValidString(name) || strings.IndexFunc(name, unicode.IsControl) >= 0:
+		return "", model.ErrorAttachmentInvalid("%s: name %q is not printable text", encoder.ATTACHMENT_INVALID, name)
+	}
+	return name, nil
+}
+
+// CreateAttachment attaches the content read from r to a live task, as the user of
+// the request.
+func (uc *TaskUsecase) CreateAttachment(ctx context.Context, id uint64, name string, r io.Reader) (*model.T_Attachment, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: CreateAttachment: %q on %v", name, id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: CreateAttachment - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	name, err = NormalizeAttachmentName(name)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: CreateAttachment - %v", err)
+		return nil, err
+	}
+	return uc.repo.CreateAttachment(ctx, id, name, r)
+}
+
+func (uc *TaskUsecase) GetAttachment(ctx context.Context, id uint64, attachmentID uint64) (*model.T_Attachment, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetAttachment: %v on %v", attachmentID, id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetAttachment(ctx, id, attachmentID)
+}
+
+// OpenAttachment returns an attachment along with its content, which the caller must close.
+func (uc *TaskUsecase) OpenAttachment(ctx context.Context, id uint64, attachmentID uint64) (*model.T_Attachment, io.ReadSeekCloser, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: OpenAttachment: %v on %v", attachmentID, id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return uc.repo.OpenAttachment(ctx, id, attachmentID)
+}
+
+func (uc *TaskUsecase) ListAttachments(ctx context.Context, id uint64) ([]model.T_Attachment, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListAttachments: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: ListAttachments - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	return uc.repo.ListAttachments(ctx, id)
+}
+
+func (uc *TaskUsecase) DeleteAttachment(ctx context.Context, id uint64, attachmentID uint64) error {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: DeleteAttachment: %v on %v", attachmentID, id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskUpdate, id)
+	if err != nil {
+		return err
+	}
+	return uc.repo.DeleteAttachment(ctx, id, attachmentID)
+}
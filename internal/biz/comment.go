@@ -0,0 +1,211 @@
+package biz
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// MaxCommentLength bounds the length of the body of a comment, in bytes.
+const MaxCommentLength = 16384
+
+// mentionPattern matches an @mention along with the character before it, which
+// keeps e-mail addresses in the text from being taken for mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@(\w[\w.@+-]*)`)
+
+// Mentions returns the users the text mentions as @user, sorted without duplicates.
+// Dots and dashes ending a mention, such as the full stop of a sentence, are not
+// part of the user.
+func Mentions(text string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		user := strings.TrimRight(m[1], ".-")
+		if user == "" || len(user) > MaxUserLength || seen[user] {
+			continue
+		}
+		seen[user] = true
+		result = append(result, user)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// normalizeComment trims the body of a comment, and takes its mentions from it.
+func normalizeComment(c *model.Comment) error {
+	body := strings.TrimSpace(c.Body)
+	if body == "" {
+		return model.ErrorCommentInvalid("%s: comment is empty", encoder.COMMENT_INVALID)
+	}
+	if len(body) > MaxCommentLength {
+		return model.ErrorCommentInvalid("%s: comment is longer than %d bytes", encoder.COMMENT_INVALID, MaxCommentLength)
+	}
+	c.Body, c.Mentions = body, Mentions(body)
+	return nil
+}
+
+// checkAuthor rejects a user who did not write the comment. A comment written
+// without a user is open to everyone.
+func checkAuthor(ctx context.Context, c *model.T_Comment) error {
+	if c.Author == "" {
+		return nil
+	}
+	actor := ActorFromContext(ctx)
+	if actor == c.Author {
+		return nil
+	}
+	if actor == "" {
+		return model.ErrorCommentForbidden("%s: comment %d has an author, and the request names no user", encoder.COMMENT_NOT_AUTHOR, c.CommentID)
+	}
+	return model.ErrorCommentForbidden("%s: %s is not the author of comment %d", encoder.COMMENT_NOT_AUTHOR, actor, c.CommentID)
+}
+
+// CreateComment comments on a live task as the user of the request.
+func (uc *TaskUsecase) CreateComment(ctx context.Context, id uint64, c *model.Comment) (*model.T_Comment, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: CreateComment: on %v, %+v", id, *c)
+	ctx, err := uc.authorizeTask(ctx, PermissionCommentWrite, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: CreateComment - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	if err := normalizeComment(c); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: CreateComment - %v", err)
+		return nil, err
+	}
+	return uc.repo.CreateComment(ctx, id, c)
+}
+
+func (uc *TaskUsecase) GetComment(ctx context.Context, id uint64, commentID uint64) (*model.T_Comment, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetComment: %v on %v", commentID, id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.GetComment(ctx, id, commentID)
+}
+
+func (uc *TaskUsecase) ListComments(ctx context.Context, id uint64) ([]model.T_Comment, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListComments: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: ListComments - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	return uc.repo.ListComments(ctx, id)
+}
+
+// UpdateComment edits a comment if it is still at the given version, or
+// unconditionally when the version is 0. Only the author may edit a comment.
+func (uc *TaskUsecase) UpdateComment(ctx context.Context, id uint64, commentID uint64, c *model.Comment, version uint64) (*model.T_Comment, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: UpdateComment: %v on %v, %+v, version %d", commentID, id, *c, version)
+	ctx, err := uc.authorizeTask(ctx, PermissionCommentWrite, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeComment(c); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: UpdateComment - %v", err)
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		stored, err := uc.checkedComment(ctx, id, commentID, version)
+		if err != nil {
+			return nil, err
+		}
+
+		// The repo only edits the version checked above
+		result, err := uc.repo.UpdateComment(ctx, id, commentID, c, stored.Version)
+		if version == 0 && model.IsCommentVersionMismatch(err) && attempt < maxTransitionAttempts {
+			continue
+		}
+		return result, err
+	}
+}
+
+// DeleteComment removes a comment if it is still at the given version, or
+// unconditionally when the version is 0. Only the author may delete a comment.
+func (uc *TaskUsecase) DeleteComment(ctx context.Context, id uint64, commentID uint64, version uint64) error {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: DeleteComment: %v on %v, version %d", commentID, id, version)
+	ctx, err := uc.authorizeTask(ctx, PermissionCommentWrite, id)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		stored, err := uc.checkedComment(ctx, id, commentID, version)
+		if err != nil {
+			return err
+		}
+
+		// The repo only deletes the version checked above
+		err = uc.repo.DeleteComment(ctx, id, commentID, stored.Version)
+		if version == 0 && model.IsCommentVersionMismatch(err) && attempt < maxTransitionAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+// checkedComment returns the stored comment, provided it is at the given version,
+// unless that is 0, and the user of the request wrote it.
+func (uc *TaskUsecase) checkedComment(ctx context.Context, id uint64, commentID uint64, version uint64) (*model.T_Comment, error) {
+	stored, err := uc.repo.GetComment(ctx, id, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && version != stored.Version {
+		return nil, model.ErrorCommentVersionMismatch(string(encoder.COMMENT_MODIFIED))
+	}
+	if err := checkAuthor(ctx, stored); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: comment %v - %v", commentID, err)
+		return nil, err
+	}
+	return stored, nil
+}
+
+// ListActivity returns the activity feed of a task: its revisions and the comments
+// on it, oldest first. A revision comes before a comment made at the same time.
+func (uc *TaskUsecase) ListActivity(ctx context.Context, id uint64) ([]model.T_Activity, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListActivity: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: ListActivity - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+
+	revs, err := uc.repo.History(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := uc.repo.ListComments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.T_Activity, 0, len(revs)+len(comments))
+	for len(revs) > 0 || len(comments) > 0 {
+		if len(comments) == 0 || len(revs) > 0 && (revs[0].ChangedAt == nil || !revs[0].ChangedAt.After(*comments[0].CreatedAt)) {
+			rev := revs[0]
+			result = append(result, model.T_Activity{Kind: model.ActivityRevision, At: rev.ChangedAt, Actor: rev.Actor, Revision: &rev})
+			revs = revs[1:]
+		} else {
+			c := comments[0]
+			result = append(result, model.T_Activity{Kind: model.ActivityComment, At: c.CreatedAt, Actor: c.Author, Comment: &c})
+			comments = comments[1:]
+		}
+	}
+	return result, nil
+}
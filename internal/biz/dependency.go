@@ -0,0 +1,64 @@
+package biz
+
+import (
+	"context"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// StartsWork reports whether moving to the status means working on the task,
+// which its dependencies hold up until they are done.
+func StartsWork(to model.TaskStatus) bool {
+	return to == model.StatusInProgress || to == model.StatusDone
+}
+
+func (uc *TaskUsecase) AddTaskDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: AddTaskDependency: %v on %v", id, dependsOn)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 || dependsOn == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: AddTaskDependency - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	return uc.repo.AddDependency(ctx, id, dependsOn)
+}
+
+func (uc *TaskUsecase) RemoveTaskDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: RemoveTaskDependency: %v on %v", id, dependsOn)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 || dependsOn == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: RemoveTaskDependency - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	return uc.repo.RemoveDependency(ctx, id, dependsOn)
+}
+
+// ListReadyTasks lists the tasks still to be finished whose dependencies are all done.
+func (uc *TaskUsecase) ListReadyTasks(ctx context.Context, q *TaskQuery) (*TaskPage, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListReadyTasks: %+v", *q)
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	q.Trashed = false
+	q.Ready = true
+	if err := q.Validate(); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: ListReadyTasks - %v", err)
+		return nil, err
+	}
+	return uc.repo.List(ctx, q)
+}
+
+// GetTaskGraph returns every task in topological order, dependencies first.
+func (uc *TaskUsecase) GetTaskGraph(ctx context.Context) ([]model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetTaskGraph")
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	return uc.repo.TopologicalOrder(ctx)
+}
@@ -0,0 +1,82 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"qantas.com/task/model"
+)
+
+// DefaultDueWithin is how far ahead ListDueTasks looks when no window is given.
+const DefaultDueWithin = 24 * time.Hour
+
+// matchDue reports whether the task is due in the window of the query, and is
+// still to be finished when the query asks for it.
+func (q *TaskQuery) matchDue(t *model.T_Task) bool {
+	if q.Unfinished && t.Finished() {
+		return false
+	}
+	if q.DueAfter == nil && q.DueBefore == nil {
+		return true
+	}
+	if t.DueAt == nil {
+		return false
+	}
+	if q.DueAfter != nil && t.DueAt.Before(*q.DueAfter) {
+		return false
+	}
+	if q.DueBefore != nil && !t.DueAt.Before(*q.DueBefore) {
+		return false
+	}
+	return true
+}
+
+// ListOverdueTasks lists the live tasks still to be finished whose due date has
+// passed, by default the most overdue first.
+func (uc *TaskUsecase) ListOverdueTasks(ctx context.Context, q *TaskQuery) (*TaskPage, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListOverdueTasks: %+v", *q)
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	q.Trashed = false
+	q.Unfinished = true
+	q.DueAfter, q.DueBefore = nil, &now
+	if q.SortBy == "" {
+		q.SortBy = SortByDueAt
+	}
+	if err := q.Validate(); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: ListOverdueTasks - %v", err)
+		return nil, err
+	}
+	return uc.repo.List(ctx, q)
+}
+
+// ListDueTasks lists the live tasks still to be finished which fall due within
+// the given time from now, by default the soonest due first.
+func (uc *TaskUsecase) ListDueTasks(ctx context.Context, q *TaskQuery, within time.Duration) (*TaskPage, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListDueTasks: %+v, within %s", *q, within)
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	if within == 0 {
+		within = DefaultDueWithin
+	}
+	if within < 0 {
+		return nil, queryError("within must be positive")
+	}
+
+	now := time.Now()
+	until := now.Add(within)
+	q.Trashed = false
+	q.Unfinished = true
+	q.DueAfter, q.DueBefore = &now, &until
+	if q.SortBy == "" {
+		q.SortBy = SortByDueAt
+	}
+	if err := q.Validate(); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: ListDueTasks - %v", err)
+		return nil, err
+	}
+	return uc.repo.List(ctx, q)
+}
@@ -0,0 +1,575 @@
+package biz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// MaxFilterLength bounds the length of a filter, in bytes.
+const MaxFilterLength = 1024
+
+type FilterField string
+
+const (
+	FilterTaskID    FilterField = "taskID"
+	FilterParentID  FilterField = "parentID"
+	FilterSeriesID  FilterField = "seriesID"
+	FilterName      FilterField = "name"
+	FilterContent   FilterField = "content"
+	FilterStatus    FilterField = "status"
+	FilterTag       FilterField = "tag"
+	FilterOwner     FilterField = "owner"
+	FilterAssignee  FilterField = "assignee"
+	FilterCreatedBy FilterField = "createdBy"
+	FilterCreatedAt FilterField = "createdAt"
+	FilterUpdatedAt FilterField = "updatedAt"
+	FilterDueAt     FilterField = "dueAt"
+	FilterRemindAt  FilterField = "remindAt"
+)
+
+type FilterOp string
+
+const (
+	FilterEq FilterOp = "="
+	FilterNe FilterOp = "!="
+	FilterLt FilterOp = "<"
+	FilterLe FilterOp = "<="
+	FilterGt FilterOp = ">"
+	FilterGe FilterOp = ">="
+	// FilterLike matches a glob pattern, in which * stands for any text and ? for
+	// any single character, ignoring case.
+	FilterLike FilterOp = "~"
+)
+
+type filterKind int
+
+const (
+	kindNumber filterKind = iota
+	kindText
+	kindStatus
+	kindTag
+	kindUser
+	kindTime
+)
+
+// filterFields maps every field to the kind of its values.
+var filterFields = map[FilterField]filterKind{
+	FilterTaskID:    kindNumber,
+	FilterParentID:  kindNumber,
+	FilterSeriesID:  kindNumber,
+	FilterName:      kindText,
+	FilterContent:   kindText,
+	FilterStatus:    kindStatus,
+	FilterTag:       kindTag,
+	FilterOwner:     kindText,
+	FilterAssignee:  kindUser,
+	FilterCreatedBy: kindText,
+	FilterCreatedAt: kindTime,
+	FilterUpdatedAt: kindTime,
+	FilterDueAt:     kindTime,
+	FilterRemindAt:  kindTime,
+}
+
+// filterOps lists the operators every kind of field supports.
+var filterOps = map[filterKind][]FilterOp{
+	kindNumber: {FilterEq, FilterNe, FilterLt, FilterLe, FilterGt, FilterGe},
+	kindText:   {FilterEq, FilterNe, FilterLt, FilterLe, FilterGt, FilterGe, FilterLike},
+	kindStatus: {FilterEq, FilterNe},
+	kindTag:    {FilterEq, FilterNe, FilterLike},
+	kindUser:   {FilterEq, FilterNe, FilterLike},
+	kindTime:   {FilterEq, FilterNe, FilterLt, FilterLe, FilterGt, FilterGe},
+}
+
+// FilterClause is a comparison of a field of a task with a value. Value is
+// normalized: tags are lower cased and times are in RFC 3339.
+type FilterClause struct {
+	Field FilterField
+	Op    FilterOp
+	Value string
+
+	number uint64
+	time   time.Time
+}
+
+// Number returns the value of a comparison on taskID, parentID or seriesID.
+func (c FilterClause) Number() uint64 {
+	return c.number
+}
+
+// Filter is a compiled filter expression, such as
+//
+//	status = "todo" AND createdAt > "2026-01-01" AND (name ~ "deploy*" OR tag = ops)
+//
+// Comparisons are combined with AND, OR, NOT and parentheses; AND binds tighter
+// than OR. Values are "quoted", with \" and \\ escapes, unless they are a single
+// word. Times are RFC 3339 timestamps or dates, taken as midnight UTC.
+type Filter struct {
+	root *filterNode
+}
+
+type filterNode struct {
+	op       string // "AND", "OR", "NOT" or "" for a comparison
+	children []*filterNode
+	clause   *FilterClause
+}
+
+// ParseFilter compiles a filter. Its errors point at the offending part of the filter.
+func ParseFilter(filter string) (*Filter, error) {
+	if len(filter) > MaxFilterLength {
+		return nil, filterError("filter must be at most %d bytes", MaxFilterLength)
+	}
+	items, err := lexFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, filterError("filter is empty")
+	}
+
+	p := &filterParser{items: items, end: len(filter)}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if item := p.peek(); item != nil {
+		return nil, filterError("unexpected %s at offset %d, expected AND, OR or the end of the filter", item, item.offset)
+	}
+	return &Filter{root: root}, nil
+}
+
+// Match reports whether the task passes the filter.
+func (f *Filter) Match(t *model.T_Task) bool {
+	return f.root.match(t)
+}
+
+// Required returns the comparisons every task passing the filter satisfies: those
+// joined by AND at the top of the filter. A repo may look them up in its indexes
+// to narrow down the tasks it has to match the filter against.
+func (f *Filter) Required() []FilterClause {
+	return f.root.required()
+}
+
+func (n *filterNode) required() []FilterClause {
+	switch n.op {
+	case "":
+		return []FilterClause{*n.clause}
+	case "AND":
+		var result []FilterClause
+		for _, child := range n.children {
+			result = append(result, child.required()...)
+		}
+		return result
+	}
+	return nil
+}
+
+func (n *filterNode) match(t *model.T_Task) bool {
+	switch n.op {
+	case "AND":
+		for _, child := range n.children {
+			if !child.match(t) {
+				return false
+			}
+		}
+		return true
+	case "OR":
+		for _, child := range n.children {
+			if child.match(t) {
+				return true
+			}
+		}
+		return false
+	case "NOT":
+		return !n.children[0].match(t)
+	}
+	return n.clause.match(t)
+}
+
+func (c *FilterClause) match(t *model.T_Task) bool {
+	switch c.Field {
+	case FilterTaskID:
+		return compare(c.Op, compareNumber(t.TaskID, c.number))
+	case FilterParentID:
+		return compare(c.Op, compareNumber(t.ParentID, c.number))
+	case FilterSeriesID:
+		return compare(c.Op, compareNumber(t.SeriesID, c.number))
+	case FilterName:
+		return c.matchText(t.Name)
+	case FilterContent:
+		return c.matchText(t.Content)
+	case FilterStatus:
+		return compare(c.Op, strings.Compare(string(t.GetStatus()), c.Value))
+	case FilterTag:
+		return c.matchList(t.Tags)
+	case FilterOwner:
+		return c.matchText(t.Owner)
+	case FilterAssignee:
+		return c.matchList(t.Assignees)
+	case FilterCreatedBy:
+		return c.matchText(t.CreatedBy)
+	case FilterCreatedAt:
+		return c.matchTime(t.CreatedAt)
+	case FilterUpdatedAt:
+		return c.matchTime(t.UpdatedAt)
+	case FilterDueAt:
+		return c.matchTime(t.DueAt)
+	case FilterRemindAt:
+		return c.matchTime(t.RemindAt)
+	}
+	return false
+}
+
+// matchList reports whether one of the values matches, or none does for !=.
+func (c *FilterClause) matchList(values []string) bool {
+	found := false
+	for _, v := range values {
+		if c.Op == FilterLike && like(c.Value, v) || c.Op != FilterLike && v == c.Value {
+			found = true
+			break
+		}
+	}
+	return found != (c.Op == FilterNe)
+}
+
+func (c *FilterClause) matchText(s string) bool {
+	if c.Op == FilterLike {
+		return like(c.Value, s)
+	}
+	return compare(c.Op, strings.Compare(s, c.Value))
+}
+
+// matchTime treats a time which was never set as unequal to every value.
+func (c *FilterClause) matchTime(t *time.Time) bool {
+	if t == nil {
+		return c.Op == FilterNe
+	}
+	return compare(c.Op, compareTime(t, &c.time))
+}
+
+// like matches a glob pattern against the whole of s, ignoring case. The pattern
+// is lower cased when the filter is parsed.
+func like(pattern, s string) bool {
+	p, t := []rune(pattern), []rune(strings.ToLower(s))
+	// star is the position of the last * seen in the pattern, and mark the
+	// position in s it has been matched up to, to backtrack to on a mismatch
+	pi, ti, star, mark := 0, 0, -1, 0
+	for ti < len(t) {
+		switch {
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, ti
+			pi++
+		case pi < len(p) && (p[pi] == '?' || p[pi] == t[ti]):
+			pi++
+			ti++
+		case star >= 0:
+			mark++
+			pi, ti = star+1, mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+func compareNumber(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compare reports whether the result of a comparison satisfies the operator.
+func compare(op FilterOp, c int) bool {
+	switch op {
+	case FilterEq:
+		return c == 0
+	case FilterNe:
+		return c != 0
+	case FilterLt:
+		return c < 0
+	case FilterLe:
+		return c <= 0
+	case FilterGt:
+		return c > 0
+	case FilterGe:
+		return c >= 0
+	}
+	return false
+}
+
+type filterItemKind int
+
+const (
+	itemWord filterItemKind = iota
+	itemString
+	itemOp
+	itemParen
+)
+
+type filterItem struct {
+	kind   filterItemKind
+	text   string
+	offset int
+}
+
+func (i *filterItem) String() string {
+	return strconv.Quote(i.text)
+}
+
+// lexFilter splits a filter into words, quoted strings, operators and parentheses.
+func lexFilter(filter string) ([]*filterItem, error) {
+	var items []*filterItem
+	for i := 0; i < len(filter); {
+		r, size := utf8.DecodeRuneInString(filter[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(' || r == ')':
+			items = append(items, &filterItem{kind: itemParen, text: string(r), offset: i})
+			i += size
+		case strings.ContainsRune("=!<>~", r):
+			op := filter[i : i+1]
+			if i+1 < len(filter) && filter[i+1] == '=' && r != '=' && r != '~' {
+				op = filter[i : i+2]
+			}
+			if op == "!" {
+				return nil, filterError("unexpected \"!\" at offset %d, expected \"!=\"", i)
+			}
+			items = append(items, &filterItem{kind: itemOp, text: op, offset: i})
+			i += len(op)
+		case r == '"':
+			s, n, err := lexString(filter[i:])
+			if err != nil {
+				return nil, filterError("%s at offset %d", err.Error(), i)
+			}
+			items = append(items, &filterItem{kind: itemString, text: s, offset: i})
+			i += n
+		default:
+			end := strings.IndexFunc(filter[i:], func(r rune) bool {
+				return unicode.IsSpace(r) || strings.ContainsRune(`()=!<>~"`, r)
+			})
+			if end < 0 {
+				end = len(filter) - i
+			}
+			items = append(items, &filterItem{kind: itemWord, text: filter[i : i+end], offset: i})
+			i += end
+		}
+	}
+	return items, nil
+}
+
+// lexString reads the quoted string at the start of s, and returns it unquoted
+// with the number of bytes it takes up.
+func lexString(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 == len(s) || (s[i+1] != '"' && s[i+1] != '\\') {
+				return "", 0, fmt.Errorf("invalid escape in string, only \\\" and \\\\ are allowed")
+			}
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// filterParser is a recursive descent parser of the grammar:
+//
+//	or         = and { "OR" and }
+//	and        = unary { "AND" unary }
+//	unary      = "NOT" unary | primary
+//	primary    = "(" or ")" | comparison
+//	comparison = field operator value
+type filterParser struct {
+	items []*filterItem
+	pos   int
+	end   int
+}
+
+func (p *filterParser) peek() *filterItem {
+	if p.pos < len(p.items) {
+		return p.items[p.pos]
+	}
+	return nil
+}
+
+func (p *filterParser) keyword(word string) bool {
+	item := p.peek()
+	return item != nil && item.kind == itemWord && item.text == word
+}
+
+// next returns the next item, failing with what was expected at the end of the filter.
+func (p *filterParser) next(expected string) (*filterItem, error) {
+	item := p.peek()
+	if item == nil {
+		return nil, filterError("filter ends unexpectedly at offset %d, expected %s", p.end, expected)
+	}
+	p.pos++
+	return item, nil
+}
+
+func (p *filterParser) or() (*filterNode, error) {
+	return p.chain("OR", p.and)
+}
+
+func (p *filterParser) and() (*filterNode, error) {
+	return p.chain("AND", p.unary)
+}
+
+// chain parses operands joined by the keyword into a single node.
+func (p *filterParser) chain(keyword string, operand func() (*filterNode, error)) (*filterNode, error) {
+	node, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.keyword(keyword) {
+		return node, nil
+	}
+
+	node = &filterNode{op: keyword, children: []*filterNode{node}}
+	for p.keyword(keyword) {
+		p.pos++
+		child, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		node.children = append(node.children, child)
+	}
+	return node, nil
+}
+
+func (p *filterParser) unary() (*filterNode, error) {
+	if p.keyword("NOT") {
+		p.pos++
+		child, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &filterNode{op: "NOT", children: []*filterNode{child}}, nil
+	}
+	return p.primary()
+}
+
+func (p *filterParser) primary() (*filterNode, error) {
+	item, err := p.next("a field or \"(\"")
+	if err != nil {
+		return nil, err
+	}
+
+	if item.kind == itemParen && item.text == "(" {
+		node, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		closing, err := p.next("\")\"")
+		if err != nil {
+			return nil, err
+		}
+		if closing.kind != itemParen || closing.text != ")" {
+			return nil, filterError("unexpected %s at offset %d, expected \")\"", closing, closing.offset)
+		}
+		return node, nil
+	}
+	if item.kind != itemWord || item.text == "AND" || item.text == "OR" {
+		return nil, filterError("unexpected %s at offset %d, expected a field or \"(\"", item, item.offset)
+	}
+
+	clause, err := p.comparison(item)
+	if err != nil {
+		return nil, err
+	}
+	return &filterNode{clause: clause}, nil
+}
+
+func (p *filterParser) comparison(field *filterItem) (*FilterClause, error) {
+	kind, ok := filterFields[FilterField(field.text)]
+	if !ok {
+		return nil, filterError("unknown field %s at offset %d, expected one of taskID, parentID, seriesID, name, content, status, tag, owner, assignee, createdBy, createdAt, updatedAt, dueAt, remindAt", field, field.offset)
+	}
+
+	op, err := p.next("an operator")
+	if err != nil {
+		return nil, err
+	}
+	if op.kind != itemOp {
+		return nil, filterError("unexpected %s at offset %d, expected an operator", op, op.offset)
+	}
+	supported := false
+	for _, o := range filterOps[kind] {
+		supported = supported || o == FilterOp(op.text)
+	}
+	if !supported {
+		return nil, filterError("operator %s at offset %d cannot be used with %s", op, op.offset, field.text)
+	}
+
+	value, err := p.next("a value")
+	if err != nil {
+		return nil, err
+	}
+	if value.kind != itemWord && value.kind != itemString {
+		return nil, filterError("unexpected %s at offset %d, expected a value", value, value.offset)
+	}
+
+	clause := &FilterClause{Field: FilterField(field.text), Op: FilterOp(op.text), Value: value.text}
+	if err := clause.compile(kind); err != nil {
+		return nil, filterError("%s at offset %d", err.Error(), value.offset)
+	}
+	return clause, nil
+}
+
+// compile checks the value of the clause against the kind of its field, and normalizes it.
+func (c *FilterClause) compile(kind filterKind) error {
+	switch kind {
+	case kindNumber:
+		n, err := strconv.ParseUint(c.Value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s needs a number, got %q", c.Field, c.Value)
+		}
+		c.number = n
+	case kindStatus:
+		if !model.TaskStatus(c.Value).Valid() {
+			return fmt.Errorf("status must be one of todo, in_progress, blocked, done, cancelled, got %q", c.Value)
+		}
+	case kindTag:
+		tag, err := NormalizeTag(c.Value)
+		if err != nil {
+			return fmt.Errorf("tag %q is invalid", c.Value)
+		}
+		c.Value = tag
+	case kindTime:
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", c.Value); err != nil {
+				return fmt.Errorf("%s needs a date or an RFC 3339 timestamp, got %q", c.Field, c.Value)
+			}
+		}
+		c.time = t
+		c.Value = t.Format(time.RFC3339Nano)
+	}
+
+	if c.Op == FilterLike {
+		c.Value = strings.ToLower(c.Value)
+	}
+	return nil
+}
+
+func filterError(format string, args ...interface{}) error {
+	return model.ErrorTaskInvalidFilter("%s: %s", encoder.TASK_FILTER_INVALID, fmt.Sprintf(format, args...))
+}
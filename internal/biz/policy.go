@@ -0,0 +1,238 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// Permission is an operation the access policy may allow.
+type Permission string
+
+const (
+	// PermissionTaskRead covers every read of tasks, their history, tags and series.
+	PermissionTaskRead   Permission = "task.read"
+	PermissionTaskCreate Permission = "task.create"
+	// PermissionTaskUpdate covers updates, reverts, transitions, reassignments, dependencies
+	// and attachments.
+	PermissionTaskUpdate Permission = "task.update"
+	// PermissionTaskDelete covers deleting tasks and restoring them from the trash.
+	PermissionTaskDelete Permission = "task.delete"
+	PermissionTaskPurge  Permission = "task.purge"
+	// PermissionTaskClear allows removing every task at once, see ClearTasks.
+	PermissionTaskClear Permission = "task.clear"
+	PermissionTagRename Permission = "tag.rename"
+	// PermissionSeriesWrite covers creating, updating and stopping series.
+	PermissionSeriesWrite   Permission = "series.write"
+	PermissionPolicyExplain Permission = "policy.explain"
+	// PermissionTenantAdmin covers creating, listing and deleting tenants.
+	PermissionTenantAdmin Permission = "tenant.admin"
+	// PermissionProjectWrite covers creating, updating and deleting projects.
+	PermissionProjectWrite Permission = "project.write"
+	// PermissionCommentWrite covers commenting on tasks, and editing and deleting one's own comments.
+	PermissionCommentWrite Permission = "comment.write"
+)
+
+// Permissions lists every permission, in the order they are documented.
+var Permissions = []Permission{
+	PermissionTaskRead, PermissionTaskCreate, PermissionTaskUpdate, PermissionTaskDelete,
+	PermissionTaskPurge, PermissionTaskClear, PermissionTagRename, PermissionSeriesWrite,
+	PermissionPolicyExplain, PermissionTenantAdmin, PermissionProjectWrite, PermissionCommentWrite,
+}
+
+// AnySubject in a grant gives its role to everyone, unauthenticated callers included.
+const AnySubject = "*"
+
+// Valid reports whether the permission is a known one.
+func (p Permission) Valid() bool {
+	for _, known := range Permissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy says who may do what. Roles name sets of permissions, in which "*" stands
+// for every permission and "task.*" for every permission of tasks, and grants give
+// roles to subjects. Whatever no grant allows is denied.
+type Policy struct {
+	Roles  map[string][]string `yaml:"roles"`
+	Grants []model.PolicyGrant `yaml:"grants"`
+}
+
+// ParsePolicy reads a policy from YAML, or from JSON which is YAML too. Fields the
+// policy does not know, permissions which do not exist and grants of undefined
+// roles are rejected, so that a typo does not silently deny or allow anything.
+func ParsePolicy(data []byte) (*Policy, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	p := &Policy{}
+	if err := dec.Decode(p); err != nil {
+		return nil, err
+	}
+	for role, perms := range p.Roles {
+		for _, perm := range perms {
+			if !permissionPattern(perm) {
+				return nil, fmt.Errorf("role %s: unknown permission %q", role, perm)
+			}
+		}
+	}
+	for i, g := range p.Grants {
+		if g.Subject == "" {
+			return nil, fmt.Errorf("grant %d: subject is empty", i)
+		}
+		if _, ok := p.Roles[g.Role]; !ok {
+			return nil, fmt.Errorf("grant %d: unknown role %q", i, g.Role)
+		}
+	}
+	return p, nil
+}
+
+// permissionPattern reports whether a permission of a role names a permission, or
+// some of them with a wildcard.
+func permissionPattern(pattern string) bool {
+	for _, p := range Permissions {
+		if permits(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// permits reports whether a permission of a role covers the permission.
+func permits(pattern string, p Permission) bool {
+	if pattern == "*" || pattern == string(p) {
+		return true
+	}
+	prefix := strings.TrimSuffix(pattern, "*")
+	return strings.HasSuffix(pattern, ".*") && strings.HasPrefix(string(p), prefix)
+}
+
+// Decide tells whether the policy allows the subject the permission in the project.
+// Grants without a project apply in every project. An empty subject is an
+// unauthenticated caller, whom only the grants to AnySubject apply to.
+func (p *Policy) Decide(subject string, perm Permission, project string) *model.T_PolicyDecision {
+	d := &model.T_PolicyDecision{Subject: subject, Permission: string(perm), Project: project}
+	who := subject
+	if who == "" {
+		who = "an unauthenticated caller"
+	}
+
+	var roles []string
+	for i, g := range p.Grants {
+		if g.Subject != AnySubject && g.Subject != subject || g.Project != "" && g.Project != project {
+			continue
+		}
+		roles = append(roles, g.Role)
+		for _, pattern := range p.Roles[g.Role] {
+			if permits(pattern, perm) {
+				d.Allowed = true
+				d.Grant = &p.Grants[i]
+				d.Reason = fmt.Sprintf("%s has role %s, which allows %s", who, g.Role, perm)
+				if g.Project != "" {
+					d.Reason += " in project " + g.Project
+				}
+				return d
+			}
+		}
+	}
+
+	if len(roles) == 0 {
+		d.Reason = fmt.Sprintf("no grant applies to %s", who)
+	} else {
+		sort.Strings(roles)
+		d.Reason = fmt.Sprintf("%s has roles %s, none of which allows %s", who, strings.Join(roles, ", "), perm)
+	}
+	if project != "" {
+		d.Reason += " in project " + project
+	}
+	return d
+}
+
+// policySubject returns who the policy decides for: the authenticated principal.
+// The user named by X-Actor is not trusted, since anyone can name any user.
+func policySubject(ctx context.Context) string {
+	if p := PrincipalFromContext(ctx); p != nil {
+		return p.Subject
+	}
+	return ""
+}
+
+// authorize checks that the policy allows the caller the permission in the project.
+// Everything is allowed when no policy is configured.
+func (uc *TaskUsecase) authorize(ctx context.Context, perm Permission, project string) error {
+	if uc.policy == nil {
+		return nil
+	}
+	d := uc.policy.Decide(policySubject(ctx), perm, project)
+	if !d.Allowed {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: %s denied - %s", perm, d.Reason)
+		return model.ErrorForbidden("%s: %s", encoder.ACTION_DENIED, d.Reason)
+	}
+	return nil
+}
+
+type taskCheckKey struct{}
+
+// TaskCheck is a policy check on a task which depends on the project the task is
+// on. The repo makes it under the lock of the operation, so that a concurrent move
+// cannot slip in between the check and the operation.
+type TaskCheck struct {
+	TaskID uint64
+	// Allow checks the permission in the project the task is on, which is empty
+	// when there is no such task.
+	Allow func(project string) error
+}
+
+// WithTaskCheck returns a context carrying a policy check on a task, for the repo
+// to make.
+func WithTaskCheck(ctx context.Context, c *TaskCheck) context.Context {
+	return context.WithValue(ctx, taskCheckKey{}, c)
+}
+
+// TaskCheckFromContext returns the check set by WithTaskCheck, or nil when there
+// is none.
+func TaskCheckFromContext(ctx context.Context) *TaskCheck {
+	c, _ := ctx.Value(taskCheckKey{}).(*TaskCheck)
+	return c
+}
+
+// authorizeTask checks that the policy allows the caller the permission on the task,
+// either everywhere or in the project whose board the task is on, in the trash as
+// well, where a task keeps the board it was on. When no grant allows it everywhere,
+// the returned context carries the check for the repo to make, see TaskCheck.
+func (uc *TaskUsecase) authorizeTask(ctx context.Context, perm Permission, id uint64) (context.Context, error) {
+	if uc.policy == nil || uc.policy.Decide(policySubject(ctx), perm, "").Allowed {
+		return ctx, nil
+	}
+	return WithTaskCheck(ctx, &TaskCheck{TaskID: id, Allow: func(project string) error {
+		return uc.authorize(ctx, perm, project)
+	}}), nil
+}
+
+// ExplainPolicy tells whether the policy would allow the subject the permission in
+// the project, and why, without doing anything. Only the callers allowed
+// policy.explain may ask.
+func (uc *TaskUsecase) ExplainPolicy(ctx context.Context, subject string, perm Permission, project string) (*model.T_PolicyDecision, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ExplainPolicy: %s for %q in %q", perm, subject, project)
+	if err := uc.authorize(ctx, PermissionPolicyExplain, ""); err != nil {
+		return nil, err
+	}
+	if !perm.Valid() {
+		return nil, model.ErrorBadRequest("%s: unknown permission %q", encoder.PERMISSION_INVALID, perm)
+	}
+
+	if uc.policy == nil {
+		return &model.T_PolicyDecision{Subject: subject, Permission: string(perm), Project: project, Allowed: true,
+			Reason: "no access policy is configured, everything is allowed"}, nil
+	}
+	return uc.policy.Decide(subject, perm, project), nil
+}
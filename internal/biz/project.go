@@ -0,0 +1,184 @@
+package biz
+
+import (
+	"context"
+	"strings"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// MaxProjectLength bounds the length of a project ID, and of a column name, in bytes.
+const MaxProjectLength = 64
+
+// NormalizeProject trims and lower cases a project ID. Project IDs follow the same
+// rules as tenant IDs, so that they can be named by grants of the access policy.
+func NormalizeProject(project string) (string, error) {
+	p := strings.ToLower(strings.TrimSpace(project))
+	if p == "" {
+		return "", model.ErrorProjectInvalid("%s: project is empty", encoder.PROJECT_INVALID)
+	}
+	if len(p) > MaxProjectLength {
+		return "", model.ErrorProjectInvalid("%s: project %q is longer than %d bytes", encoder.PROJECT_INVALID, p, MaxProjectLength)
+	}
+	if !tenantPattern.MatchString(p) {
+		return "", model.ErrorProjectInvalid("%s: project %q may only hold letters, digits, dashes and underscores", encoder.PROJECT_INVALID, p)
+	}
+	return p, nil
+}
+
+// NormalizeColumns trims the column names of a project, which must have at least
+// one column and no two of the same name. Their order is kept.
+func NormalizeColumns(columns []string) ([]string, error) {
+	if len(columns) == 0 {
+		return nil, model.ErrorProjectInvalid("%s: project has no columns", encoder.PROJECT_INVALID)
+	}
+
+	seen := make(map[string]bool, len(columns))
+	result := make([]string, 0, len(columns))
+	for _, column := range columns {
+		c := strings.TrimSpace(column)
+		if c == "" {
+			return nil, model.ErrorProjectInvalid("%s: column is empty", encoder.PROJECT_INVALID)
+		}
+		if len(c) > MaxProjectLength {
+			return nil, model.ErrorProjectInvalid("%s: column %q is longer than %d bytes", encoder.PROJECT_INVALID, c, MaxProjectLength)
+		}
+		if seen[c] {
+			return nil, model.ErrorProjectInvalid("%s: column %q is listed twice", encoder.PROJECT_INVALID, c)
+		}
+		seen[c] = true
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// normalizeProject normalizes the ID and the columns of a project.
+func normalizeProject(p *model.Project) error {
+	id, err := NormalizeProject(p.ProjectID)
+	if err != nil {
+		return err
+	}
+	columns, err := NormalizeColumns(p.Columns)
+	if err != nil {
+		return err
+	}
+	p.ProjectID, p.Name, p.Columns = id, strings.TrimSpace(p.Name), columns
+	return nil
+}
+
+// CreateProject creates a project with an empty board.
+func (uc *TaskUsecase) CreateProject(ctx context.Context, p *model.Project) (*model.T_Project, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: CreateProject: %+v", *p)
+	if err := normalizeProject(p); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: CreateProject - %v", err)
+		return nil, err
+	}
+	if err := uc.authorize(ctx, PermissionProjectWrite, p.ProjectID); err != nil {
+		return nil, err
+	}
+	return uc.repo.CreateProject(ctx, p)
+}
+
+func (uc *TaskUsecase) GetProject(ctx context.Context, id string) (*model.T_Project, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetProject: %s", id)
+	project, err := NormalizeProject(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authorize(ctx, PermissionTaskRead, project); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetProject(ctx, project)
+}
+
+func (uc *TaskUsecase) ListProjects(ctx context.Context) ([]model.T_Project, error) {
+	uc.log.WithContext(ctx).Info("TaskUsecase: ListProjects")
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListProjects(ctx)
+}
+
+// UpdateProject renames the project or changes its columns if it is still at the
+// given version, or unconditionally when the version is 0. Columns can be added
+// and reordered at will, but only removed once they hold no live tasks.
+func (uc *TaskUsecase) UpdateProject(ctx context.Context, p *model.Project, version uint64) (*model.T_Project, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: UpdateProject: %+v, version %d", *p, version)
+	if err := normalizeProject(p); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: UpdateProject - %v", err)
+		return nil, err
+	}
+	if err := uc.authorize(ctx, PermissionProjectWrite, p.ProjectID); err != nil {
+		return nil, err
+	}
+	return uc.repo.UpdateProject(ctx, p, version)
+}
+
+// DeleteProject removes a project which holds no live tasks. Its trashed tasks are
+// taken off its board when they are restored.
+func (uc *TaskUsecase) DeleteProject(ctx context.Context, id string) error {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: DeleteProject: %s", id)
+	project, err := NormalizeProject(id)
+	if err != nil {
+		return err
+	}
+	if err := uc.authorize(ctx, PermissionProjectWrite, project); err != nil {
+		return err
+	}
+	return uc.repo.DeleteProject(ctx, project)
+}
+
+// GetBoard returns the board of the project: its live tasks grouped by column, each
+// column from top to bottom.
+func (uc *TaskUsecase) GetBoard(ctx context.Context, id string) (*model.T_Board, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetBoard: %s", id)
+	project, err := NormalizeProject(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authorize(ctx, PermissionTaskRead, project); err != nil {
+		return nil, err
+	}
+	return uc.repo.Board(ctx, project)
+}
+
+// MoveTask places the task on the board of a project, or takes it off its board
+// when the move names no project, if the task is still at the given version or
+// unconditionally when the version is 0. The caller needs task.update both in the
+// project the task leaves and in the one it enters.
+func (uc *TaskUsecase) MoveTask(ctx context.Context, id uint64, m *model.TaskMove, version uint64) (*model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: MoveTask: %v to %+v, version %d", id, *m, version)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: MoveTask - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+
+	if m.ProjectID == "" {
+		if m.Column != "" || m.Before != 0 || m.After != 0 {
+			return nil, model.ErrorProjectInvalid("%s: a task taken off its board has no column", encoder.PROJECT_INVALID)
+		}
+		return uc.repo.Move(ctx, id, m, version)
+	}
+
+	project, err := NormalizeProject(m.ProjectID)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: MoveTask - %v", err)
+		return nil, err
+	}
+	if err := uc.authorize(ctx, PermissionTaskUpdate, project); err != nil {
+		return nil, err
+	}
+	switch {
+	case m.Before != 0 && m.After != 0:
+		return nil, model.ErrorProjectInvalid("%s: a task goes either before or after another one", encoder.PROJECT_INVALID)
+	case m.Before == id || m.After == id:
+		return nil, model.ErrorProjectInvalid("%s: a task cannot be placed next to itself", encoder.PROJECT_INVALID)
+	}
+	m.ProjectID, m.Column = project, strings.TrimSpace(m.Column)
+	return uc.repo.Move(ctx, id, m, version)
+}
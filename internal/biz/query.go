@@ -0,0 +1,268 @@
+package biz
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+type TaskSortField string
+
+const (
+	SortByTaskID    TaskSortField = "taskID"
+	SortByCreatedAt TaskSortField = "createdAt"
+	SortByUpdatedAt TaskSortField = "updatedAt"
+	SortByName      TaskSortField = "name"
+	// SortByDueAt lists the tasks without a due date first.
+	SortByDueAt TaskSortField = "dueAt"
+)
+
+// TaskQuery filters, orders and pages a task listing. Pages are cut with a
+// keyset cursor, so tasks created while a client walks through the pages never
+// shift the tasks it has not seen yet. Ties on the sort field are broken by
+// task ID, which makes the order total and deterministic.
+type TaskQuery struct {
+	PageSize      int
+	Cursor        string
+	SortBy        TaskSortField
+	Descending    bool
+	NamePrefix    string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Tags only lists the tasks carrying the tags, all of them or any of them
+	// depending on TagMatch. The repo looks them up in its tag index.
+	Tags     []string
+	TagMatch TagMatch
+	// Trashed lists the logically deleted tasks instead of the live ones.
+	Trashed bool
+	// Ready only lists the live tasks still to be finished whose dependencies
+	// are all done. It is applied by the repo, which sees the other tasks.
+	Ready bool
+	// Filter only lists the tasks passing a filter expression, see Filter.
+	Filter string
+	// DueAfter and DueBefore only list the tasks due in between, see ListDueTasks.
+	DueAfter  *time.Time
+	DueBefore *time.Time
+	// Unfinished only lists the tasks which are neither done nor cancelled.
+	Unfinished bool
+	// User only lists the tasks the user has the Role in, see ListUserTasks. The
+	// repo looks them up in its user index.
+	User string
+	Role UserRole
+
+	after  *taskCursor
+	filter *Filter
+}
+
+// TaskPage is a single page of a task listing. NextCursor is empty on the last page.
+type TaskPage struct {
+	Tasks      []model.T_Task
+	NextCursor string
+}
+
+// taskCursor is the position of the last task of a page, serialized as the opaque cursor.
+type taskCursor struct {
+	SortBy     TaskSortField `json:"s"`
+	Descending bool          `json:"d,omitempty"`
+	TaskID     uint64        `json:"i"`
+	Name       string        `json:"n,omitempty"`
+	Time       *time.Time    `json:"t,omitempty"`
+}
+
+// Validate checks the query, fills in the defaults and decodes the cursor.
+func (q *TaskQuery) Validate() error {
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize < 0 || q.PageSize > MaxPageSize {
+		return queryError("pageSize must be between 1 and %d", MaxPageSize)
+	}
+
+	if q.SortBy == "" {
+		q.SortBy = SortByTaskID
+	}
+	switch q.SortBy {
+	case SortByTaskID, SortByCreatedAt, SortByUpdatedAt, SortByName, SortByDueAt:
+	default:
+		return queryError("sortBy must be one of taskID, createdAt, updatedAt, name, dueAt")
+	}
+
+	if q.CreatedAfter != nil && q.CreatedBefore != nil && !q.CreatedAfter.Before(*q.CreatedBefore) {
+		return queryError("createdAfter must be before createdBefore")
+	}
+
+	tags, err := NormalizeTags(q.Tags)
+	if err != nil {
+		return queryError("%s", errors.FromError(err).Message)
+	}
+	q.Tags = tags
+	if q.TagMatch == "" {
+		q.TagMatch = TagMatchAll
+	}
+	switch q.TagMatch {
+	case TagMatchAll, TagMatchAny:
+	default:
+		return queryError("tagMatch must be all or any")
+	}
+
+	if q.User == "" && q.Role != "" {
+		return queryError("role needs a user")
+	}
+	switch q.Role {
+	case "", UserRoleOwner, UserRoleAssignee, UserRoleCreator:
+	default:
+		return queryError("role must be owner, assignee or creator")
+	}
+
+	q.filter = nil
+	if q.Filter != "" {
+		f, err := ParseFilter(q.Filter)
+		if err != nil {
+			return err
+		}
+		q.filter = f
+	}
+
+	q.after = nil
+	if q.Cursor != "" {
+		b, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+		if err != nil {
+			return queryError("malformed cursor")
+		}
+		var c taskCursor
+		if err := json.Unmarshal(b, &c); err != nil {
+			return queryError("malformed cursor")
+		}
+		if c.SortBy != q.SortBy || c.Descending != q.Descending {
+			return queryError("cursor does not match sortBy and order")
+		}
+		q.after = &c
+	}
+
+	return nil
+}
+
+// Match reports whether the task passes the filters of the query and lies after the cursor.
+func (q *TaskQuery) Match(t *model.T_Task) bool {
+	if (t.DeletedAt != nil) != q.Trashed {
+		return false
+	}
+	if q.NamePrefix != "" && !strings.HasPrefix(t.Name, q.NamePrefix) {
+		return false
+	}
+	if q.CreatedAfter != nil && (t.CreatedAt == nil || !t.CreatedAt.After(*q.CreatedAfter)) {
+		return false
+	}
+	if q.CreatedBefore != nil && (t.CreatedAt == nil || !t.CreatedAt.Before(*q.CreatedBefore)) {
+		return false
+	}
+	if !q.matchTags(t) || !q.matchDue(t) || !q.matchUser(t) {
+		return false
+	}
+	if q.filter != nil && !q.filter.Match(t) {
+		return false
+	}
+	if q.after != nil && !q.less(*q.after, q.key(t)) {
+		return false
+	}
+	return true
+}
+
+// FilterClauses returns the comparisons of the filter every listed task satisfies,
+// for a repo to look up in its indexes. They are matched again by Match.
+func (q *TaskQuery) FilterClauses() []FilterClause {
+	if q.filter == nil {
+		return nil
+	}
+	return q.filter.Required()
+}
+
+// less reports whether the task at a sorts before the task at b.
+func (q *TaskQuery) less(a, b taskCursor) bool {
+	c := 0
+	switch q.SortBy {
+	case SortByCreatedAt, SortByUpdatedAt, SortByDueAt:
+		c = compareTime(a.Time, b.Time)
+	case SortByName:
+		c = strings.Compare(a.Name, b.Name)
+	}
+	if c == 0 {
+		switch {
+		case a.TaskID < b.TaskID:
+			c = -1
+		case a.TaskID > b.TaskID:
+			c = 1
+		}
+	}
+	if q.Descending {
+		return c > 0
+	}
+	return c < 0
+}
+
+// Sort orders tasks in place by the sort field of the query.
+func (q *TaskQuery) Sort(tasks []model.T_Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		return q.less(q.key(&tasks[i]), q.key(&tasks[j]))
+	})
+}
+
+// Page cuts the first page out of the sorted matches, with the cursor of the next one.
+// A query which has not been validated has no page size, and returns every match.
+func (q *TaskQuery) Page(sorted []model.T_Task) *TaskPage {
+	if q.PageSize <= 0 || len(sorted) <= q.PageSize {
+		return &TaskPage{Tasks: sorted}
+	}
+
+	tasks := sorted[:q.PageSize]
+	b, _ := json.Marshal(q.key(&tasks[len(tasks)-1]))
+	return &TaskPage{Tasks: tasks, NextCursor: base64.RawURLEncoding.EncodeToString(b)}
+}
+
+func (q *TaskQuery) key(t *model.T_Task) taskCursor {
+	c := taskCursor{SortBy: q.SortBy, Descending: q.Descending, TaskID: t.TaskID}
+	switch q.SortBy {
+	case SortByCreatedAt:
+		c.Time = t.CreatedAt
+	case SortByUpdatedAt:
+		c.Time = t.UpdatedAt
+	case SortByDueAt:
+		c.Time = t.DueAt
+	case SortByName:
+		c.Name = t.Name
+	}
+	return c
+}
+
+// compareTime orders never-set timestamps before every set one.
+func compareTime(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case a.Before(*b):
+		return -1
+	case a.After(*b):
+		return 1
+	}
+	return 0
+}
+
+func queryError(format string, args ...interface{}) error {
+	return model.ErrorTaskQueryInvalid("%s: %s", encoder.TASK_QUERY_INVALID, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,502 @@
+package biz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+const (
+	// MaxRuleLength bounds the length of the rule of a series, in bytes.
+	MaxRuleLength = 256
+	// recurrenceHorizon bounds how far ahead the next occurrence of a rule is looked
+	// for. A rule with none that close, such as FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30,
+	// has run out of occurrences.
+	recurrenceHorizon = 100 * 366 * 24 * time.Hour
+)
+
+// Schedule gives the times a series recurs at.
+type Schedule interface {
+	// Next returns the first occurrence after the given time, or false if there are no more.
+	Next(after time.Time) (time.Time, bool)
+	// Count is the number of occurrences the rule is limited to, 0 if it is not.
+	Count() uint64
+}
+
+// ParseSchedule parses the rule of a series starting at the given time. Rules
+// starting with FREQ= or RRULE: are iCalendar recurrence rules, anything else a
+// cron expression.
+//
+// Of RRULE, FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL, COUNT, UNTIL,
+// BYDAY, BYMONTHDAY and BYMONTH are supported. BYDAY takes an ordinal such as 1MO
+// or -1FR in monthly rules only. Occurrences fall at the time of day of the start.
+//
+// Cron expressions have the five fields minute, hour, day of month, month and day
+// of week, with lists, ranges, steps and the names of months and days, or are one
+// of @hourly, @daily, @weekly, @monthly and @yearly.
+func ParseSchedule(rule string, start time.Time) (Schedule, error) {
+	rule = strings.TrimSpace(rule)
+	switch {
+	case rule == "":
+		return nil, ruleError("rule is empty")
+	case len(rule) > MaxRuleLength:
+		return nil, ruleError("rule must be at most %d bytes", MaxRuleLength)
+	case strings.HasPrefix(strings.ToUpper(rule), "RRULE:"):
+		return parseRRule(rule[len("RRULE:"):], start)
+	case strings.HasPrefix(strings.ToUpper(rule), "FREQ="):
+		return parseRRule(rule, start)
+	default:
+		return parseCron(rule, start)
+	}
+}
+
+func ruleError(format string, args ...interface{}) error {
+	return model.ErrorSeriesInvalidRule("%s: %s", encoder.SERIES_RULE_INVALID, fmt.Sprintf(format, args...))
+}
+
+type frequency int
+
+const (
+	daily frequency = iota
+	weekly
+	monthly
+	yearly
+)
+
+var frequencies = map[string]frequency{"DAILY": daily, "WEEKLY": weekly, "MONTHLY": monthly, "YEARLY": yearly}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// byDay is a day of the week in BYDAY. A nonzero ordinal picks the nth such day
+// of the month, counting from its end when negative.
+type byDay struct {
+	weekday time.Weekday
+	ordinal int
+}
+
+// rrule is a parsed iCalendar recurrence rule. Its occurrences are found a day at
+// a time, which is plenty fast for rules recurring at most daily.
+type rrule struct {
+	start      time.Time
+	freq       frequency
+	interval   int
+	count      uint64
+	until      *time.Time
+	byDay      []byDay
+	byMonthDay []int
+	byMonth    map[time.Month]bool
+}
+
+func parseRRule(rule string, start time.Time) (*rrule, error) {
+	r := &rrule{start: start, interval: 1}
+	seen := make(map[string]bool)
+	hasFreq := false
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return nil, ruleError("malformed part %q", part)
+		}
+		if seen[name] {
+			return nil, ruleError("%s is given twice", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			r.freq, ok = frequencies[value]
+			if !ok {
+				return nil, ruleError("unsupported FREQ %s, expected DAILY, WEEKLY, MONTHLY or YEARLY", value)
+			}
+			hasFreq = true
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err != nil || r.interval < 1 || r.interval > 1000 {
+				return nil, ruleError("INTERVAL must be between 1 and 1000")
+			}
+		case "COUNT":
+			r.count, err = strconv.ParseUint(value, 10, 64)
+			if err != nil || r.count == 0 {
+				return nil, ruleError("COUNT must be a positive number")
+			}
+		case "UNTIL":
+			until, err := parseUntil(value, start.Location())
+			if err != nil {
+				return nil, err
+			}
+			r.until = &until
+		case "BYDAY":
+			r.byDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseNumbers(name, value, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseNumbers(name, value, 1, 12)
+			r.byMonth = make(map[time.Month]bool)
+			for _, m := range months {
+				r.byMonth[time.Month(m)] = true
+			}
+		default:
+			return nil, ruleError("unsupported part %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case !hasFreq:
+		return nil, ruleError("FREQ is missing")
+	case r.count != 0 && r.until != nil:
+		return nil, ruleError("COUNT and UNTIL cannot be given together")
+	}
+	for _, d := range r.byDay {
+		if d.ordinal != 0 && r.freq != monthly {
+			return nil, ruleError("BYDAY takes an ordinal in monthly rules only")
+		}
+	}
+	return r, nil
+}
+
+// parseUntil reads UNTIL as a UTC date-time such as 20261231T235959Z, a local one
+// or a date, which includes the whole day.
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return time.Time{}, ruleError("invalid UNTIL %s", value)
+}
+
+func parseByDay(value string) ([]byDay, error) {
+	var days []byDay
+	for _, v := range strings.Split(value, ",") {
+		if len(v) < 2 {
+			return nil, ruleError("invalid BYDAY %s", v)
+		}
+		weekday, ok := weekdays[v[len(v)-2:]]
+		if !ok {
+			return nil, ruleError("invalid BYDAY %s", v)
+		}
+		d := byDay{weekday: weekday}
+		if n := v[:len(v)-2]; n != "" {
+			ordinal, err := strconv.Atoi(n)
+			if err != nil || ordinal == 0 || ordinal < -5 || ordinal > 5 {
+				return nil, ruleError("invalid BYDAY %s", v)
+			}
+			d.ordinal = ordinal
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+func parseNumbers(name, value string, min, max int) ([]int, error) {
+	var numbers []int
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, ruleError("invalid %s %s", name, v)
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
+
+func (r *rrule) Count() uint64 {
+	return r.count
+}
+
+func (r *rrule) Next(after time.Time) (time.Time, bool) {
+	loc := r.start.Location()
+	from := r.start
+	if after.After(from) {
+		from = after.In(loc)
+	}
+
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	for end := from.Add(recurrenceHorizon); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if !r.matches(day) {
+			continue
+		}
+		at := time.Date(day.Year(), day.Month(), day.Day(), r.start.Hour(), r.start.Minute(), r.start.Second(), 0, loc)
+		if at.Before(r.start) || !at.After(after) {
+			continue
+		}
+		if r.until != nil && at.After(*r.until) {
+			return time.Time{}, false
+		}
+		return at, true
+	}
+	return time.Time{}, false
+}
+
+// matches reports whether the rule recurs on the given day.
+func (r *rrule) matches(day time.Time) bool {
+	if len(r.byMonth) > 0 && !r.byMonth[day.Month()] {
+		return false
+	}
+
+	start := r.start
+	switch r.freq {
+	case daily:
+		return daysBetween(start, day)%r.interval == 0 && r.matchesDay(day, false)
+	case weekly:
+		// Weeks start on Monday, as they do by default in iCalendar
+		weeks := (daysBetween(start, day) + mondayOffset(start)) / 7
+		return weeks%r.interval == 0 && r.matchesDay(day, true)
+	case monthly:
+		months := (day.Year()-start.Year())*12 + int(day.Month()-start.Month())
+		return months%r.interval == 0 && r.matchesMonthDay(day)
+	default:
+		if day.Year()-start.Year() < 0 || (day.Year()-start.Year())%r.interval != 0 {
+			return false
+		}
+		// A yearly rule with no BY parts recurs on the anniversary of the start
+		if len(r.byMonth) == 0 && len(r.byDay) == 0 && len(r.byMonthDay) == 0 && day.Month() != start.Month() {
+			return false
+		}
+		return r.matchesMonthDay(day)
+	}
+}
+
+// matchesDay checks BYDAY and BYMONTHDAY. Weekly rules without either recur on
+// the weekday of the start.
+func (r *rrule) matchesDay(day time.Time, weekly bool) bool {
+	if len(r.byMonthDay) > 0 && !r.matchesByMonthDay(day) {
+		return false
+	}
+	if len(r.byDay) > 0 {
+		return r.matchesByDay(day)
+	}
+	return !weekly || len(r.byMonthDay) > 0 || day.Weekday() == r.start.Weekday()
+}
+
+// matchesMonthDay checks BYMONTHDAY and BYDAY within the month. Rules without
+// either recur on the day of the month of the start, skipping the months which
+// are too short for it.
+func (r *rrule) matchesMonthDay(day time.Time) bool {
+	if len(r.byMonthDay) == 0 && len(r.byDay) == 0 {
+		return day.Day() == r.start.Day()
+	}
+	if len(r.byMonthDay) > 0 && !r.matchesByMonthDay(day) {
+		return false
+	}
+	return len(r.byDay) == 0 || r.matchesByDay(day)
+}
+
+func (r *rrule) matchesByMonthDay(day time.Time) bool {
+	last := daysIn(day)
+	for _, d := range r.byMonthDay {
+		if d == day.Day() || d < 0 && last+d+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rrule) matchesByDay(day time.Time) bool {
+	for _, d := range r.byDay {
+		if d.weekday != day.Weekday() {
+			continue
+		}
+		switch {
+		case d.ordinal == 0:
+			return true
+		case d.ordinal > 0 && (day.Day()-1)/7+1 == d.ordinal:
+			return true
+		case d.ordinal < 0 && (daysIn(day)-day.Day())/7+1 == -d.ordinal:
+			return true
+		}
+	}
+	return false
+}
+
+// daysBetween counts the calendar days from the day of a to the day of b.
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// mondayOffset counts the days from the Monday of the week of t to t.
+func mondayOffset(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+// daysIn returns the number of days of the month of t.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// cronField is the set of values a field of a cron expression matches. any is set
+// for *, which matters for the days, see cron.matchesDay.
+type cronField struct {
+	values map[int]bool
+	any    bool
+}
+
+// cron is a parsed cron expression.
+type cron struct {
+	start                                time.Time
+	minute, hour, monthDay, month, wkday cronField
+}
+
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+var cronMonths = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+var cronDays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+func parseCron(rule string, start time.Time) (*cron, error) {
+	if macro, ok := cronMacros[strings.ToLower(rule)]; ok {
+		rule = macro
+	}
+	fields := strings.Fields(rule)
+	if len(fields) != 5 {
+		return nil, ruleError("a cron expression has 5 fields, got %d", len(fields))
+	}
+
+	c := &cron{start: start}
+	var err error
+	if c.minute, err = parseCronField("minute", fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if c.hour, err = parseCronField("hour", fields[1], 0, 23, nil); err != nil {
+		return nil, err
+	}
+	if c.monthDay, err = parseCronField("day of month", fields[2], 1, 31, nil); err != nil {
+		return nil, err
+	}
+	if c.month, err = parseCronField("month", fields[3], 1, 12, cronMonths); err != nil {
+		return nil, err
+	}
+	// Sunday is both 0 and 7
+	if c.wkday, err = parseCronField("day of week", fields[4], 0, 7, cronDays); err != nil {
+		return nil, err
+	}
+	if c.wkday.values[7] {
+		c.wkday.values[0] = true
+	}
+	return c, nil
+}
+
+// parseCronField parses a comma separated list of *, values and ranges, each with
+// an optional /step. names spell the values from min on.
+func parseCronField(name, field string, min, max int, names []string) (cronField, error) {
+	f := cronField{values: make(map[int]bool)}
+	value := func(s string) (int, error) {
+		for i, n := range names {
+			if strings.EqualFold(s, n) {
+				return min + i, nil
+			}
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < min || v > max {
+			return 0, ruleError("invalid %s %q", name, s)
+		}
+		return v, nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return cronField{}, ruleError("invalid %s step %q", name, stepText)
+			}
+		}
+
+		lo, hi := min, max
+		switch from, to, isRange := strings.Cut(rng, "-"); {
+		case rng == "*":
+			f.any = f.any || !hasStep
+		case isRange:
+			var err error
+			if lo, err = value(from); err != nil {
+				return cronField{}, err
+			}
+			if hi, err = value(to); err != nil {
+				return cronField{}, err
+			}
+			if lo > hi {
+				return cronField{}, ruleError("invalid %s range %q", name, rng)
+			}
+		default:
+			v, err := value(rng)
+			if err != nil {
+				return cronField{}, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			f.values[v] = true
+		}
+	}
+	return f, nil
+}
+
+func (c *cron) Count() uint64 {
+	return 0
+}
+
+// Next steps through the calendar, skipping whole months, days and hours which
+// do not match.
+func (c *cron) Next(after time.Time) (time.Time, bool) {
+	loc := c.start.Location()
+	if c.start.After(after) {
+		after = c.start.Add(-time.Nanosecond)
+	}
+	t := after.In(loc).Truncate(time.Minute).Add(time.Minute)
+
+	for end := t.Add(recurrenceHorizon); !t.After(end); {
+		switch {
+		case !c.month.values[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !c.hour.values[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !c.minute.values[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// matchesDay follows cron in matching either field of the day when both are
+// restricted, and the restricted one otherwise.
+func (c *cron) matchesDay(t time.Time) bool {
+	monthDay, weekday := c.monthDay.values[t.Day()], c.wkday.values[int(t.Weekday())]
+	if !c.monthDay.any && !c.wkday.any {
+		return monthDay || weekday
+	}
+	return monthDay && weekday
+}
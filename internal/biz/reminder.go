@@ -0,0 +1,67 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"qantas.com/task/model"
+)
+
+// IReminderNotifier delivers the reminders of tasks.
+type IReminderNotifier interface {
+	Notify(ctx context.Context, r model.T_Reminder) error
+}
+
+type logReminderNotifier struct {
+	log *log.Helper
+}
+
+// NewLogReminderNotifier returns the notifier which writes every reminder to the log.
+func NewLogReminderNotifier(logger log.Logger) IReminderNotifier {
+	return &logReminderNotifier{log: log.NewHelper(logger)}
+}
+
+func (n *logReminderNotifier) Notify(ctx context.Context, r model.T_Reminder) error {
+	n.log.WithContext(ctx).Infof("reminder: task %d %q is due at %v", r.TaskID, r.Name, r.DueAt)
+	return nil
+}
+
+// SendReminders sends the reminders of every tenant due at now, and returns the
+// time of the next reminder to come. A reminder is only recorded as sent once the
+// notifier has taken it, so one which fails is sent again on the next call.
+func (uc *TaskUsecase) SendReminders(ctx context.Context, notifier IReminderNotifier, now time.Time) (*time.Time, error) {
+	return uc.forEachTenant(ctx, func(ctx context.Context) (*time.Time, error) {
+		return uc.sendReminders(ctx, notifier, now)
+	})
+}
+
+// sendReminders sends the reminders of the tenant of the context due at now.
+func (uc *TaskUsecase) sendReminders(ctx context.Context, notifier IReminderNotifier, now time.Time) (*time.Time, error) {
+	due, next, err := uc.repo.DueReminders(ctx, now)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: SendReminders - %v", err)
+		return nil, err
+	}
+
+	for _, t := range due {
+		r := model.T_Reminder{TaskID: t.TaskID, Name: t.Name, DueAt: t.DueAt, RemindAt: *t.RemindAt, SentAt: now}
+		if err := notifier.Notify(ctx, r); err != nil {
+			uc.log.WithContext(ctx).Errorf("TaskUsecase: SendReminders - task %d: %v", t.TaskID, err)
+			continue
+		}
+		if err := uc.repo.MarkReminded(ctx, t.TaskID, now); err != nil {
+			uc.log.WithContext(ctx).Errorf("TaskUsecase: SendReminders - task %d: %v", t.TaskID, err)
+		}
+	}
+	return next, nil
+}
+
+// withReminder passes the result of a write through, waking the scheduler up when
+// the task written has a reminder, which may fall due before the one it waits for.
+func (uc *TaskUsecase) withReminder(task *model.T_Task, err error) (*model.T_Task, error) {
+	if err == nil && task != nil && task.RemindAt != nil {
+		uc.wake()
+	}
+	return task, err
+}
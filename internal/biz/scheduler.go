@@ -0,0 +1,90 @@
+package biz
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// maxSchedulerWait bounds how long the scheduler sleeps, so that the work which
+// failed is retried and a changed wall clock is caught up with.
+const maxSchedulerWait = time.Minute
+
+// Scheduler sends the reminders of the tasks and creates the occurrences of the
+// series as they fall due. It is run alongside the servers, and like them serves
+// until it is stopped.
+type Scheduler struct {
+	uc       *TaskUsecase
+	notifier IReminderNotifier
+	log      *log.Helper
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func NewScheduler(uc *TaskUsecase, notifier IReminderNotifier, logger log.Logger) *Scheduler {
+	return &Scheduler{
+		uc:       uc,
+		notifier: notifier,
+		log:      log.NewHelper(logger),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Run does the work due, and then sleeps until more is, a task or series changes
+// or Stop is called. The work which fell due while the process was down is done as
+// soon as it runs.
+func (s *Scheduler) Run() error {
+	defer close(s.done)
+
+	for {
+		ctx, now := context.Background(), time.Now()
+		next, err := s.uc.SendReminders(ctx, s.notifier, now)
+		if err != nil {
+			s.log.Errorf("reminders: %v", err)
+		}
+		nextSeries, err := s.uc.AdvanceSeries(ctx, now)
+		if err != nil {
+			s.log.Errorf("series: %v", err)
+		}
+
+		wait := maxSchedulerWait
+		if next = earliest(next, nextSeries); next != nil && time.Until(*next) < wait {
+			wait = time.Until(*next)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return nil
+		case <-s.uc.changes:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// Stop stops the scheduler and waits for the work in progress, until ctx is done.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wake wakes the scheduler up, as the work it waits for may have changed.
+func (uc *TaskUsecase) wake() {
+	select {
+	case uc.changes <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,306 @@
+package biz
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"qantas.com/task/model"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	// MaxSearchLength bounds the length of a search query, in bytes.
+	MaxSearchLength = 256
+)
+
+type SearchOp int
+
+const (
+	// SearchTerm matches a word.
+	SearchTerm SearchOp = iota
+	// SearchPrefix matches any word starting with the term.
+	SearchPrefix
+	// SearchPhrase matches the terms next to each other, in order.
+	SearchPhrase
+	SearchAnd
+	SearchOr
+	SearchNot
+)
+
+// SearchNode is a node of a parsed search query. Terms are normalized like the
+// words of the tasks they are matched against.
+type SearchNode struct {
+	Op       SearchOp
+	Terms    []string
+	Children []*SearchNode
+}
+
+// SearchQuery is a full-text search over the name and content of the live tasks.
+// The query is written as words, "quoted phrases" and prefix* terms combined with
+// AND (implied between terms), OR, NOT or a leading -, and parentheses.
+type SearchQuery struct {
+	Query string
+	Limit int
+
+	root *SearchNode
+}
+
+// Root returns the parsed query. It is nil before the query has been validated.
+func (q *SearchQuery) Root() *SearchNode {
+	return q.root
+}
+
+// Validate checks the limit, fills in its default and parses the query.
+func (q *SearchQuery) Validate() error {
+	if q.Limit == 0 {
+		q.Limit = DefaultSearchLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxSearchLimit {
+		return queryError("limit must be between 1 and %d", MaxSearchLimit)
+	}
+	if len(q.Query) > MaxSearchLength {
+		return queryError("q must be at most %d bytes", MaxSearchLength)
+	}
+
+	root, err := parseSearch(q.Query)
+	if err != nil {
+		return err
+	}
+	q.root = root
+	return nil
+}
+
+// Token is a word of a text, lower cased, with where it starts and ends in the text.
+type Token struct {
+	Word       string
+	Start, End int
+}
+
+// Tokenize splits a text into words: runs of letters and digits.
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, Token{Word: strings.ToLower(text[start:i]), Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, Token{Word: strings.ToLower(text[start:]), Start: start, End: len(text)})
+	}
+	return tokens
+}
+
+// searchParser is a recursive descent parser of the grammar:
+//
+//	or      = and { "OR" and }
+//	and     = unary { [ "AND" ] unary }
+//	unary   = ( "NOT" | "-" ) unary | primary
+//	primary = "(" or ")" | phrase | word | word "*"
+type searchParser struct {
+	items []string
+	pos   int
+}
+
+func parseSearch(query string) (*SearchNode, error) {
+	items, err := lexSearch(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, queryError("q is empty")
+	}
+
+	p := &searchParser{items: items}
+	node, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.items) {
+		return nil, queryError("unexpected %q in q", p.items[p.pos])
+	}
+	return node, nil
+}
+
+// lexSearch splits a query into parentheses, quoted phrases, operators and words.
+func lexSearch(query string) ([]string, error) {
+	var items []string
+	for i := 0; i < len(query); {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(' || r == ')':
+			items = append(items, string(r))
+			i += size
+		case r == '-' && i+1 < len(query) && !unicode.IsSpace(rune(query[i+1])):
+			items = append(items, "-")
+			i += size
+		case r == '"':
+			end := strings.IndexByte(query[i+1:], '"')
+			if end < 0 {
+				return nil, queryError("unterminated phrase in q")
+			}
+			items = append(items, query[i:i+end+2])
+			i += end + 2
+		default:
+			end := strings.IndexFunc(query[i:], func(r rune) bool { return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' })
+			if end < 0 {
+				end = len(query) - i
+			}
+			items = append(items, query[i:i+end])
+			i += end
+		}
+	}
+	return items, nil
+}
+
+func (p *searchParser) peek() string {
+	if p.pos < len(p.items) {
+		return p.items[p.pos]
+	}
+	return ""
+}
+
+func (p *searchParser) or() (*SearchNode, error) {
+	node, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "OR" {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		node = combine(SearchOr, node, right)
+	}
+	return node, nil
+}
+
+func (p *searchParser) and() (*SearchNode, error) {
+	node, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek() {
+		case "", "OR", ")":
+			return node, nil
+		case "AND":
+			p.pos++
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		node = combine(SearchAnd, node, right)
+	}
+}
+
+func (p *searchParser) unary() (*SearchNode, error) {
+	switch p.peek() {
+	case "NOT", "-":
+		p.pos++
+		child, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &SearchNode{Op: SearchNot, Children: []*SearchNode{child}}, nil
+	}
+	return p.primary()
+}
+
+func (p *searchParser) primary() (*SearchNode, error) {
+	item := p.peek()
+	p.pos++
+
+	switch {
+	case item == "":
+		return nil, queryError("q ends unexpectedly")
+	case item == "(":
+		node, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, queryError("missing ) in q")
+		}
+		p.pos++
+		return node, nil
+	case item == ")" || item == "AND" || item == "OR":
+		return nil, queryError("unexpected %q in q", item)
+	case strings.HasPrefix(item, `"`):
+		return words(SearchPhrase, item, Tokenize(strings.Trim(item, `"`)))
+	case strings.HasSuffix(item, "*"):
+		tokens := Tokenize(strings.TrimSuffix(item, "*"))
+		if len(tokens) != 1 {
+			return nil, queryError("invalid prefix %q in q", item)
+		}
+		return &SearchNode{Op: SearchPrefix, Terms: []string{tokens[0].Word}}, nil
+	default:
+		// A word such as e-mail is made of several terms, which are searched as a phrase
+		return words(SearchTerm, item, Tokenize(item))
+	}
+}
+
+// words returns the node matching the terms of a word or phrase.
+func words(op SearchOp, item string, tokens []Token) (*SearchNode, error) {
+	if len(tokens) == 0 {
+		return nil, queryError("%q in q has no words", item)
+	}
+	if len(tokens) > 1 {
+		op = SearchPhrase
+	}
+	node := &SearchNode{Op: op}
+	for _, t := range tokens {
+		node.Terms = append(node.Terms, t.Word)
+	}
+	return node, nil
+}
+
+// combine joins two nodes with an operator, flattening chains of the same operator.
+func combine(op SearchOp, left, right *SearchNode) *SearchNode {
+	if left.Op == op {
+		left.Children = append(left.Children, right)
+		return left
+	}
+	return &SearchNode{Op: op, Children: []*SearchNode{left, right}}
+}
+
+// Matched returns the terms whose matches are highlighted: those of the query
+// which do not sit below a NOT.
+func (n *SearchNode) Matched() []*SearchNode {
+	switch n.Op {
+	case SearchTerm, SearchPrefix, SearchPhrase:
+		return []*SearchNode{n}
+	case SearchNot:
+		return nil
+	}
+	var result []*SearchNode
+	for _, child := range n.Children {
+		result = append(result, child.Matched()...)
+	}
+	return result
+}
+
+// SearchTasks returns the live tasks matching the query, most relevant first.
+func (uc *TaskUsecase) SearchTasks(ctx context.Context, q *SearchQuery) ([]model.T_SearchResult, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: SearchTasks: %+v", *q)
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	if err := q.Validate(); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: SearchTasks - %v", err)
+		return nil, err
+	}
+	return uc.repo.Search(ctx, q)
+}
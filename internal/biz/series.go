@@ -0,0 +1,207 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// CreateSeries starts a recurring task, and creates its first occurrence due at
+// the first time of its rule at or after its start. The start defaults to now.
+func (uc *TaskUsecase) CreateSeries(ctx context.Context, s *model.Series) (*model.T_Series, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: CreateSeries: %v", *s)
+	if err := uc.authorize(ctx, PermissionSeriesWrite, ""); err != nil {
+		return nil, err
+	}
+	if s.Start == nil {
+		now := time.Now().Truncate(time.Second)
+		s.Start = &now
+	}
+	if err := uc.checkSeries(ctx, s); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: CreateSeries - %v", err)
+		return nil, err
+	}
+
+	schedule, _ := ParseSchedule(s.Rule, *s.Start)
+	first, ok := schedule.Next(s.Start.Add(-time.Nanosecond))
+	if !ok {
+		return nil, ruleError("rule has no occurrences after the start")
+	}
+
+	result, err := uc.repo.CreateSeries(ctx, s, first)
+	if err != nil {
+		return nil, err
+	}
+	uc.wake()
+	return result, nil
+}
+
+func (uc *TaskUsecase) GetSeriesByID(ctx context.Context, id uint64) (*model.T_Series, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetSeriesByID: %v", id)
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetSeries(ctx, id)
+}
+
+func (uc *TaskUsecase) ListSeries(ctx context.Context) ([]model.T_Series, error) {
+	uc.log.WithContext(ctx).Info("TaskUsecase: ListSeries")
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListSeries(ctx)
+}
+
+// UpdateSeries changes the series if it is still at the given version, or
+// unconditionally when the version is 0. Its name, content, parent and tags are
+// carried over to the occurrences which are not finished yet, while a changed rule
+// or start applies from the next occurrence on. The start is kept when not given.
+func (uc *TaskUsecase) UpdateSeries(ctx context.Context, s *model.Series, version uint64) (*model.T_Series, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: UpdateSeries: %v, version %d", *s, version)
+	if err := uc.authorize(ctx, PermissionSeriesWrite, ""); err != nil {
+		return nil, err
+	}
+	if s.SeriesID == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: UpdateSeries - Series ID not specified")
+		return nil, model.ErrorBadRequest("%s: %s", encoder.REQUEST_BODY_INVALID, "series id not specified")
+	}
+	if s.Start == nil {
+		stored, err := uc.repo.GetSeries(ctx, s.SeriesID)
+		if err != nil {
+			return nil, err
+		}
+		s.Start = stored.Start
+	}
+	if err := uc.checkSeries(ctx, s); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: UpdateSeries - %v", err)
+		return nil, err
+	}
+
+	result, err := uc.repo.UpdateSeries(ctx, s, version)
+	if err != nil {
+		return nil, err
+	}
+	uc.wake()
+	return result, nil
+}
+
+// StopSeries stops creating occurrences of the series. The occurrences it already
+// has are left as they are.
+func (uc *TaskUsecase) StopSeries(ctx context.Context, id uint64) (*model.T_Series, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: StopSeries: %v", id)
+	if err := uc.authorize(ctx, PermissionSeriesWrite, ""); err != nil {
+		return nil, err
+	}
+	return uc.repo.StopSeries(ctx, id)
+}
+
+// checkSeries normalizes the tags of the series, and checks its parent and rule.
+// The occurrences of a series take its parent, so the parent must not be one of
+// them or below one.
+func (uc *TaskUsecase) checkSeries(ctx context.Context, s *model.Series) error {
+	tags, err := NormalizeTags(s.Tags)
+	if err != nil {
+		return err
+	}
+	s.Tags = tags
+	if err := uc.checkParent(ctx, 0, s.ParentID); err != nil {
+		return err
+	}
+
+	visited := map[uint64]bool{}
+	for ancestor := s.ParentID; s.SeriesID != 0 && ancestor != 0 && !visited[ancestor]; {
+		visited[ancestor] = true
+
+		task, err := uc.repo.Get(ctx, ancestor)
+		if err != nil {
+			return err
+		}
+		if task.SeriesID == s.SeriesID {
+			return model.ErrorTaskInvalidParent("%s: task %d is an occurrence of series %d", encoder.TASK_PARENT_INVALID, ancestor, s.SeriesID)
+		}
+		ancestor = task.ParentID
+	}
+
+	_, err = ParseSchedule(s.Rule, *s.Start)
+	return err
+}
+
+// AdvanceSeries creates the next occurrence of every series, of every tenant,
+// whose current one is finished, deleted or past its time, and returns the time
+// the next series falls due, if any. A series which has run out of occurrences is
+// stopped.
+func (uc *TaskUsecase) AdvanceSeries(ctx context.Context, now time.Time) (*time.Time, error) {
+	return uc.forEachTenant(ctx, func(ctx context.Context) (*time.Time, error) {
+		return uc.advanceSeries(ctx, now)
+	})
+}
+
+// advanceSeries advances the series of the tenant of the context due at now.
+func (uc *TaskUsecase) advanceSeries(ctx context.Context, now time.Time) (*time.Time, error) {
+	due, next, err := uc.repo.DueSeries(ctx, now)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: AdvanceSeries - %v", err)
+		return nil, err
+	}
+
+	for _, s := range due {
+		advanced, err := uc.advance(ctx, s, now)
+		if err != nil {
+			uc.log.WithContext(ctx).Errorf("TaskUsecase: AdvanceSeries - series %d: %v", s.SeriesID, err)
+			continue
+		}
+		if !advanced.Stopped() {
+			next = earliest(next, advanced.CurrentAt)
+		}
+	}
+	return next, nil
+}
+
+// advance creates the occurrence of the series following its current one. The
+// next occurrence is due at the first time of the rule after both the current one
+// and now, so the periods missed while the server was down are skipped.
+func (uc *TaskUsecase) advance(ctx context.Context, s model.T_Series, now time.Time) (*model.T_Series, error) {
+	schedule, err := ParseSchedule(s.Rule, *s.Start)
+	if err != nil {
+		return nil, err
+	}
+
+	var next *time.Time
+	if count := schedule.Count(); count == 0 || s.Occurrences < count {
+		after := now
+		if s.CurrentAt != nil && s.CurrentAt.After(now) {
+			after = *s.CurrentAt
+		}
+		if at, ok := schedule.Next(after); ok {
+			next = &at
+		}
+	}
+	return uc.repo.AdvanceSeries(ctx, s.SeriesID, s.Version, next)
+}
+
+// advanceFinished creates the next occurrence as soon as the current occurrence of
+// a series is finished, rather than when the scheduler next runs. Failures are left
+// to the scheduler to retry.
+func (uc *TaskUsecase) advanceFinished(ctx context.Context, task *model.T_Task) {
+	if task == nil || task.SeriesID == 0 || !task.Finished() {
+		return
+	}
+	s, err := uc.repo.GetSeries(ctx, task.SeriesID)
+	if err != nil || s.Stopped() || s.CurrentTaskID != task.TaskID {
+		return
+	}
+	if _, err := uc.advance(ctx, *s, time.Now()); err != nil {
+		uc.log.WithContext(ctx).Warnf("TaskUsecase: advance series %d - %v", s.SeriesID, err)
+		uc.wake()
+	}
+}
+
+// earliest returns the earlier of two optional times.
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || b != nil && b.Before(*a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,81 @@
+package biz
+
+import (
+	"context"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// transitions lists the statuses a task may move to from each status. Finished
+// tasks can be reopened, everything else goes through the workflow.
+var transitions = map[model.TaskStatus][]model.TaskStatus{
+	model.StatusTodo:       {model.StatusInProgress, model.StatusBlocked, model.StatusCancelled},
+	model.StatusInProgress: {model.StatusTodo, model.StatusBlocked, model.StatusDone, model.StatusCancelled},
+	model.StatusBlocked:    {model.StatusTodo, model.StatusInProgress, model.StatusCancelled},
+	model.StatusDone:       {model.StatusInProgress},
+	model.StatusCancelled:  {model.StatusTodo},
+}
+
+// maxTransitionAttempts bounds how often an unconditional transition is retried
+// when another change to the task wins the race.
+const maxTransitionAttempts = 3
+
+// CanTransition reports whether the workflow allows moving a task from one status to another.
+func CanTransition(from, to model.TaskStatus) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTask moves the task to another status if the workflow allows it, and
+// only starts or finishes it once its dependencies are done. The task must still
+// be at the given version, unless the version is 0, and only its owner and
+// assignees may move it.
+func (uc *TaskUsecase) TransitionTask(ctx context.Context, id uint64, to model.TaskStatus, version uint64) (*model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: TransitionTask: %v to %s, version %d", id, to, version)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: TransitionTask - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	if !to.Valid() {
+		return nil, model.ErrorInvalidTransition("%s: unknown status %q", encoder.TASK_INVALID_TRANSITION, to)
+	}
+
+	for attempt := 1; ; attempt++ {
+		task, err := uc.repo.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if version != 0 && version != task.Version {
+			return nil, model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH))
+		}
+
+		if err := uc.checkAssigned(ctx, task); err != nil {
+			return nil, err
+		}
+
+		from := task.GetStatus()
+		if !CanTransition(from, to) {
+			return nil, model.ErrorInvalidTransition("%s: from %s to %s", encoder.TASK_INVALID_TRANSITION, from, to)
+		}
+
+		// The repo only applies the transition to the version checked above, and
+		// checks the dependencies as it does, since they may be reopened meanwhile
+		result, err := uc.repo.Transition(ctx, id, to, task.Version)
+		if version == 0 && model.IsTaskVersionMismatch(err) && attempt < maxTransitionAttempts {
+			continue
+		}
+		if err == nil {
+			uc.advanceFinished(ctx, result)
+		}
+		return uc.withReminder(result, err)
+	}
+}
@@ -0,0 +1,123 @@
+package biz
+
+import (
+	"context"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// checkParent makes sure a task can be placed under the given parent: the parent
+// must be a live task, and the task must not be one of its own ancestors. A task
+// which is being created has the ID 0. This only fails early: the repo checks the
+// parent again as it stores the task, since it may change in the meantime.
+func (uc *TaskUsecase) checkParent(ctx context.Context, id uint64, parentID uint64) error {
+	if parentID == 0 {
+		return nil
+	}
+	if parentID == id {
+		return model.ErrorTaskInvalidParent("%s: task %d cannot be its own parent", encoder.TASK_PARENT_INVALID, id)
+	}
+
+	visited := map[uint64]bool{}
+	for ancestor := parentID; ancestor != 0 && !visited[ancestor]; {
+		visited[ancestor] = true
+
+		task, err := uc.repo.Get(ctx, ancestor)
+		if model.IsTaskNotFound(err) {
+			return model.ErrorTaskInvalidParent("%s: parent task %d does not exist", encoder.TASK_PARENT_INVALID, ancestor)
+		}
+		if err != nil {
+			return err
+		}
+
+		if id != 0 && task.ParentID == id {
+			return model.ErrorTaskInvalidParent("%s: task %d is an ancestor of task %d", encoder.TASK_PARENT_INVALID, id, parentID)
+		}
+		ancestor = task.ParentID
+	}
+	return nil
+}
+
+func (uc *TaskUsecase) GetTaskChildren(ctx context.Context, id uint64) ([]model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetTaskChildren: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: GetTaskChildren - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	return uc.repo.Children(ctx, id)
+}
+
+// GetTaskTree returns the task with all its live subtasks, nested.
+func (uc *TaskUsecase) GetTaskTree(ctx context.Context, id uint64) (*model.T_TaskNode, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetTaskTree: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: GetTaskTree - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+
+	task, err := uc.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &model.T_TaskNode{T_Task: *task}
+	if err := uc.growTree(ctx, root, map[uint64]bool{id: true}); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// growTree adds the subtasks below the node. Tasks already in the tree are skipped
+// in case the hierarchy changes while it is being read.
+func (uc *TaskUsecase) growTree(ctx context.Context, node *model.T_TaskNode, visited map[uint64]bool) error {
+	children, err := uc.repo.Children(ctx, node.TaskID)
+	if model.IsTaskNotFound(err) {
+		// Deleted since its parent was read
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if visited[child.TaskID] {
+			continue
+		}
+		visited[child.TaskID] = true
+
+		n := model.T_TaskNode{T_Task: child}
+		if err := uc.growTree(ctx, &n, visited); err != nil {
+			return err
+		}
+		node.Children = append(node.Children, n)
+	}
+	return nil
+}
+
+// DeleteTaskTree logically deletes the task along with all its subtasks, and
+// returns how many tasks were deleted. DeleteTaskByID rejects a task which has subtasks.
+func (uc *TaskUsecase) DeleteTaskTree(ctx context.Context, id uint64) (int, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: DeleteTaskTree: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskDelete, id)
+	if err != nil {
+		return 0, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: DeleteTaskTree - Task ID not specified")
+		return 0, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	n, err := uc.repo.DeleteCascade(ctx, id)
+	if n > 0 {
+		uc.wake()
+	}
+	return n, err
+}
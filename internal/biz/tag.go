@@ -0,0 +1,102 @@
+package biz
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// MaxTagLength bounds the length of a tag, in bytes.
+const MaxTagLength = 64
+
+type TagMatch string
+
+const (
+	// TagMatchAll lists the tasks carrying every tag of the query.
+	TagMatchAll TagMatch = "all"
+	// TagMatchAny lists the tasks carrying at least one tag of the query.
+	TagMatchAny TagMatch = "any"
+)
+
+// NormalizeTag trims and lower cases a tag, so that tags differing only in case
+// or surrounding spaces are the same tag.
+func NormalizeTag(tag string) (string, error) {
+	t := strings.ToLower(strings.TrimSpace(tag))
+	if t == "" {
+		return "", model.ErrorTaskInvalidTag("%s: tag is empty", encoder.TASK_TAG_INVALID)
+	}
+	if len(t) > MaxTagLength {
+		return "", model.ErrorTaskInvalidTag("%s: tag %q is longer than %d bytes", encoder.TASK_TAG_INVALID, t, MaxTagLength)
+	}
+	return t, nil
+}
+
+// NormalizeTags normalizes every tag, and returns them sorted without duplicates.
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// matchTags reports whether the task carries the tags of the query.
+func (q *TaskQuery) matchTags(t *model.T_Task) bool {
+	if len(q.Tags) == 0 {
+		return true
+	}
+
+	matched := 0
+	for _, tag := range q.Tags {
+		i := sort.SearchStrings(t.Tags, tag)
+		if i < len(t.Tags) && t.Tags[i] == tag {
+			matched++
+		}
+	}
+	if q.TagMatch == TagMatchAny {
+		return matched > 0
+	}
+	return matched == len(q.Tags)
+}
+
+func (uc *TaskUsecase) ListTags(ctx context.Context) ([]model.T_TagCount, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListTags")
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	return uc.repo.Tags(ctx)
+}
+
+// RenameTag renames a tag on every task, merging it into the new tag where a task
+// carries both, and returns how many tasks were changed.
+func (uc *TaskUsecase) RenameTag(ctx context.Context, from string, to string) (int, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: RenameTag: %q to %q", from, to)
+	if err := uc.authorize(ctx, PermissionTagRename, ""); err != nil {
+		return 0, err
+	}
+	from, err := NormalizeTag(from)
+	if err != nil {
+		return 0, err
+	}
+	to, err = NormalizeTag(to)
+	if err != nil {
+		return 0, err
+	}
+	return uc.repo.RenameTag(ctx, from, to)
+}
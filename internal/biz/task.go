@@ -2,6 +2,8 @@ package biz
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/go-kratos/kratos/v2/log"
 	"qantas.com/task/internal/encoder"
@@ -11,28 +13,157 @@ import (
 type ITaskRepo interface {
 	Create(context.Context, *model.Task) (*model.T_Task, error)
 	Get(context.Context, uint64) (*model.T_Task, error)
-	Update(context.Context, *model.Task) (*model.T_Task, error)
+	// Update replaces the task if its version is still the given one. A version of 0
+	// updates the task whatever its version is.
+	Update(context.Context, *model.Task, uint64) (*model.T_Task, error)
 	Delete(context.Context, uint64) error
-	List(context.Context) ([]model.T_Task, error)
+	List(context.Context, *TaskQuery) (*TaskPage, error)
 	Empty(context.Context) error
+	// Restore brings a logically deleted task back.
+	Restore(context.Context, uint64) (*model.T_Task, error)
+	// Purge permanently removes a logically deleted task.
+	Purge(context.Context, uint64) error
+	// PurgeDeletedBefore permanently removes the tasks of every tenant deleted before
+	// the given time, and returns how many were removed.
+	PurgeDeletedBefore(context.Context, time.Time) (int, error)
+	// History returns every revision of a task, oldest first.
+	History(context.Context, uint64) ([]model.T_Revision, error)
+	// Revision returns a single revision of a task.
+	Revision(ctx context.Context, id uint64, rev uint64) (*model.T_Revision, error)
+	// Revert brings a task back to the state of a prior revision, as a new revision.
+	Revert(ctx context.Context, id uint64, rev uint64) (*model.T_Task, error)
+	// Children returns the subtasks of a task which have not been deleted, in the order of their IDs.
+	Children(ctx context.Context, id uint64) ([]model.T_Task, error)
+	// DeleteCascade logically deletes a task together with all its subtasks, and
+	// returns how many tasks were deleted. Delete rejects a task which has subtasks.
+	DeleteCascade(ctx context.Context, id uint64) (int, error)
+	// AddDependency makes a task depend on another live task, unless that would close
+	// a cycle. Adding a dependency the task already has changes nothing.
+	AddDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error)
+	// RemoveDependency drops a dependency of a task. Removing one it does not have changes nothing.
+	RemoveDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error)
+	// TopologicalOrder returns every live task, each after all of its dependencies,
+	// ties broken by task ID.
+	TopologicalOrder(ctx context.Context) ([]model.T_Task, error)
+	// Tags counts the live tasks carrying each tag, in the order of the tags.
+	Tags(ctx context.Context) ([]model.T_TagCount, error)
+	// RenameTag renames a tag on every task carrying it, deleted ones included, and
+	// returns how many tasks were changed.
+	RenameTag(ctx context.Context, from string, to string) (int, error)
+	// Search returns the live tasks matching a validated search query, most relevant
+	// first, from the full-text index of their names and contents.
+	Search(ctx context.Context, q *SearchQuery) ([]model.T_SearchResult, error)
+	// DueReminders returns the live tasks whose reminder is due at the given time and
+	// has not been sent, earliest first, with the time of the next reminder to come.
+	DueReminders(ctx context.Context, now time.Time) ([]model.T_Task, *time.Time, error)
+	// MarkReminded records the reminder of a task as sent at the given time.
+	MarkReminded(ctx context.Context, id uint64, at time.Time) error
+	// CreateSeries stores a new series together with its first occurrence, due at the given time.
+	CreateSeries(ctx context.Context, s *model.Series, first time.Time) (*model.T_Series, error)
+	GetSeries(ctx context.Context, id uint64) (*model.T_Series, error)
+	// ListSeries returns every series, in the order of their IDs.
+	ListSeries(ctx context.Context) ([]model.T_Series, error)
+	// UpdateSeries replaces the series if its version is still the given one, or whatever
+	// it is when the version is 0, and applies it to its live occurrences which are not finished.
+	UpdateSeries(ctx context.Context, s *model.Series, version uint64) (*model.T_Series, error)
+	// StopSeries stops a series. Stopping a stopped series changes nothing.
+	StopSeries(ctx context.Context, id uint64) (*model.T_Series, error)
+	// AdvanceSeries creates the next occurrence of a series, due at the given time, if
+	// the series is still at the given version and running. A nil time stops the series.
+	AdvanceSeries(ctx context.Context, id uint64, version uint64, next *time.Time) (*model.T_Series, error)
+	// DueSeries returns the running series whose current occurrence is finished, deleted
+	// or due at the given time, in the order of their IDs, with the time the next one is due.
+	DueSeries(ctx context.Context, now time.Time) ([]model.T_Series, *time.Time, error)
+	// Reassign replaces the owner and the assignees of the task if it is still at the
+	// given version. A version of 0 reassigns the task whatever its version is.
+	Reassign(ctx context.Context, id uint64, owner string, assignees []string, version uint64) (*model.T_Task, error)
+	// Transition moves the task to another status if it is still at the given version.
+	// The workflow is not checked here, see TaskUsecase.TransitionTask.
+	Transition(ctx context.Context, id uint64, status model.TaskStatus, version uint64) (*model.T_Task, error)
+	// CreateTenant adds a tenant, with no tasks and its own sequence of IDs.
+	CreateTenant(ctx context.Context, t *model.Tenant) (*model.T_Tenant, error)
+	// ListTenants returns every tenant with what it stores, in the order of their IDs.
+	ListTenants(ctx context.Context) ([]model.T_Tenant, error)
+	// DeleteTenant removes a tenant with everything it stores.
+	DeleteTenant(ctx context.Context, tenant string) error
+	// CreateProject stores a new project, with no tasks on its board.
+	CreateProject(ctx context.Context, p *model.Project) (*model.T_Project, error)
+	GetProject(ctx context.Context, id string) (*model.T_Project, error)
+	// ListProjects returns every project, in the order of their IDs.
+	ListProjects(ctx context.Context) ([]model.T_Project, error)
+	// UpdateProject replaces the project if its version is still the given one, or whatever
+	// it is when the version is 0. A column holding live tasks cannot be removed.
+	UpdateProject(ctx context.Context, p *model.Project, version uint64) (*model.T_Project, error)
+	// DeleteProject removes a project which holds no live tasks.
+	DeleteProject(ctx context.Context, id string) error
+	// Board returns a project with its live tasks grouped by column, each column in
+	// the order of their ranks.
+	Board(ctx context.Context, id string) (*model.T_Board, error)
+	// Move places the task on the board of a project as the move says, or takes it off
+	// its board when the move names no project, if the task is still at the given
+	// version. A version of 0 moves the task whatever its version is.
+	Move(ctx context.Context, id uint64, m *model.TaskMove, version uint64) (*model.T_Task, error)
+	// CreateComment stores a new comment on a live task, written by the user of the request.
+	CreateComment(ctx context.Context, id uint64, c *model.Comment) (*model.T_Comment, error)
+	GetComment(ctx context.Context, id uint64, commentID uint64) (*model.T_Comment, error)
+	// ListComments returns every comment on a task, deleted tasks included, oldest first.
+	ListComments(ctx context.Context, id uint64) ([]model.T_Comment, error)
+	// UpdateComment replaces the comment if its version is still the given one, or
+	// whatever it is when the version is 0. The author is not checked here.
+	UpdateComment(ctx context.Context, id uint64, commentID uint64, c *model.Comment, version uint64) (*model.T_Comment, error)
+	// DeleteComment removes the comment if its version is still the given one, or
+	// whatever it is when the version is 0.
+	DeleteComment(ctx context.Context, id uint64, commentID uint64, version uint64) error
+	// CreateAttachment stores the content read from r as an attachment of a live
+	// task, added by the user of the request.
+	CreateAttachment(ctx context.Context, id uint64, name string, r io.Reader) (*model.T_Attachment, error)
+	GetAttachment(ctx context.Context, id uint64, attachmentID uint64) (*model.T_Attachment, error)
+	// OpenAttachment returns an attachment along with its content, which the caller must close.
+	OpenAttachment(ctx context.Context, id uint64, attachmentID uint64) (*model.T_Attachment, io.ReadSeekCloser, error)
+	// ListAttachments returns every attachment of a task, deleted tasks included, oldest first.
+	ListAttachments(ctx context.Context, id uint64) ([]model.T_Attachment, error)
+	// DeleteAttachment removes an attachment of a live task, along with its content
+	// unless another attachment shares it.
+	DeleteAttachment(ctx context.Context, id uint64, attachmentID uint64) error
 }
 
 type TaskUsecase struct {
 	repo ITaskRepo
-	log  *log.Helper
+	// policy says who may do what. Everything is allowed when it is nil.
+	policy *Policy
+	log    *log.Helper
+	// changes wakes the Scheduler up when a task with a reminder or a series changes.
+	changes chan struct{}
 }
 
-func NewTaskUsecase(repo ITaskRepo, logger log.Logger) *TaskUsecase {
-	return &TaskUsecase{repo: repo, log: log.NewHelper(logger)}
+func NewTaskUsecase(repo ITaskRepo, policy *Policy, logger log.Logger) *TaskUsecase {
+	return &TaskUsecase{repo: repo, policy: policy, log: log.NewHelper(logger), changes: make(chan struct{}, 1)}
 }
 
 func (uc *TaskUsecase) CreateTask(ctx context.Context, t *model.Task) (*model.T_Task, error) {
 	uc.log.WithContext(ctx).Infof("TaskUsecase: CreateTask: %v", *t)
-	return uc.repo.Create(ctx, t)
+	if err := uc.authorize(ctx, PermissionTaskCreate, ""); err != nil {
+		return nil, err
+	}
+	tags, err := NormalizeTags(t.Tags)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: CreateTask - %v", err)
+		return nil, err
+	}
+	t.Tags = tags
+	if err := uc.checkParent(ctx, 0, t.ParentID); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: CreateTask - %v", err)
+		return nil, err
+	}
+	return uc.withReminder(uc.repo.Create(ctx, t))
 }
 
 func (uc *TaskUsecase) GetTaskByID(ctx context.Context, id uint64) (*model.T_Task, error) {
 	uc.log.WithContext(ctx).Infof("TaskUsecase: GetTaskByID: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
 	if id == 0 {
 		uc.log.WithContext(ctx).Error("TaskUsecase: GetTaskByID - Task ID not specified")
 		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
@@ -41,30 +172,156 @@ func (uc *TaskUsecase) GetTaskByID(ctx context.Context, id uint64) (*model.T_Tas
 	return uc.repo.Get(ctx, id)
 }
 
+// DeleteTaskByID logically deletes a task which has no live subtasks, see DeleteTaskTree.
 func (uc *TaskUsecase) DeleteTaskByID(ctx context.Context, id uint64) error {
 	uc.log.WithContext(ctx).Infof("TaskUsecase: DeleteTaskByID: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskDelete, id)
+	if err != nil {
+		return err
+	}
 	if id == 0 {
 		uc.log.WithContext(ctx).Error("TaskUsecase: DeleteTaskByID - Task ID not specified")
 		return model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
 	}
-	return uc.repo.Delete(ctx, id)
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	// The task may be the current occurrence of a series, which is then skipped
+	uc.wake()
+	return nil
 }
 
-func (uc *TaskUsecase) UpdateTaskByID(ctx context.Context, t *model.Task) (*model.T_Task, error) {
-	uc.log.WithContext(ctx).Infof("TaskUsecase: UpdateTaskByID: %v", *t)
+// UpdateTaskByID updates the task if it is still at the given version, or
+// unconditionally when the version is 0.
+func (uc *TaskUsecase) UpdateTaskByID(ctx context.Context, t *model.Task, version uint64) (*model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: UpdateTaskByID: %v, version %d", *t, version)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskUpdate, t.TaskID)
+	if err != nil {
+		return nil, err
+	}
 	if t.TaskID == 0 {
 		uc.log.WithContext(ctx).Error("TaskUsecase: UpdateTaskByID - Task ID not specified")
 		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
 	}
-	return uc.repo.Update(ctx, t)
+	tags, err := NormalizeTags(t.Tags)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: UpdateTaskByID - %v", err)
+		return nil, err
+	}
+	t.Tags = tags
+	if err := uc.checkParent(ctx, t.TaskID, t.ParentID); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: UpdateTaskByID - %v", err)
+		return nil, err
+	}
+	return uc.withReminder(uc.repo.Update(ctx, t, version))
 }
 
-func (uc *TaskUsecase) ListTasks(ctx context.Context) ([]model.T_Task, error) {
-	uc.log.WithContext(ctx).Infof("TaskUsecase: ListTasks")
-	return uc.repo.List(ctx)
+func (uc *TaskUsecase) ListTasks(ctx context.Context, q *TaskQuery) (*TaskPage, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListTasks: %+v", *q)
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	if err := q.Validate(); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: ListTasks - %v", err)
+		return nil, err
+	}
+	return uc.repo.List(ctx, q)
+}
+
+func (uc *TaskUsecase) ListDeletedTasks(ctx context.Context, q *TaskQuery) (*TaskPage, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListDeletedTasks: %+v", *q)
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	q.Trashed = true
+	if err := q.Validate(); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: ListDeletedTasks - %v", err)
+		return nil, err
+	}
+	return uc.repo.List(ctx, q)
+}
+
+func (uc *TaskUsecase) RestoreTaskByID(ctx context.Context, id uint64) (*model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: RestoreTaskByID: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskDelete, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: RestoreTaskByID - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	return uc.withReminder(uc.repo.Restore(ctx, id))
+}
+
+func (uc *TaskUsecase) PurgeTaskByID(ctx context.Context, id uint64) error {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: PurgeTaskByID: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskPurge, id)
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: PurgeTaskByID - Task ID not specified")
+		return model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	return uc.repo.Purge(ctx, id)
+}
+
+func (uc *TaskUsecase) GetTaskHistory(ctx context.Context, id uint64) ([]model.T_Revision, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetTaskHistory: %v", id)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: GetTaskHistory - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	return uc.repo.History(ctx, id)
+}
+
+func (uc *TaskUsecase) GetTaskRevision(ctx context.Context, id uint64, rev uint64) (*model.T_Revision, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: GetTaskRevision: %v, revision %d", id, rev)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskRead, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: GetTaskRevision - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+	return uc.repo.Revision(ctx, id, rev)
+}
+
+// RevertTaskByID brings the task back to the state of a prior revision. The
+// revert is itself recorded as a new revision, so it can be undone in turn.
+func (uc *TaskUsecase) RevertTaskByID(ctx context.Context, id uint64, rev uint64) (*model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: RevertTaskByID: %v, revision %d", id, rev)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: RevertTaskByID - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+
+	// The parent the task had back then may have gone, or moved below the task
+	target, err := uc.repo.Revision(ctx, id, rev)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkParent(ctx, id, target.Task.ParentID); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: RevertTaskByID - %v", err)
+		return nil, err
+	}
+	return uc.withReminder(uc.repo.Revert(ctx, id, rev))
 }
 
 func (uc *TaskUsecase) ClearTasks(ctx context.Context) error {
 	uc.log.WithContext(ctx).Infof("ClearTasks")
+	if err := uc.authorize(ctx, PermissionTaskClear, ""); err != nil {
+		return err
+	}
 	return uc.repo.Empty(ctx)
 }
@@ -2,7 +2,10 @@ package biz_test
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -45,7 +48,7 @@ func (uts *BizTestSuite) Test_CreateTask_Success() {
 	uts.taskRepoMock.On("Create", mock.Anything, mock.Anything).Return(
 		&mT_Task, nil)
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	retTask, err := taskUseCase.CreateTask(uts.context,
 		&model.Task{TaskID: 2, Name: "user", Content: "content"})
 
@@ -57,7 +60,7 @@ func (uts *BizTestSuite) Test_CreateTask_DatabaseCreationError() {
 	uts.taskRepoMock.On("Create", mock.Anything, mock.Anything).Return(
 		nil, model.ErrorTaskCreationError(string(encoder.TASK_CREATION_ERROR)))
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	retTask, err := taskUseCase.CreateTask(uts.context,
 		&model.Task{TaskID: 2, Name: "user", Content: "content"})
 
@@ -76,7 +79,7 @@ func (uts *BizTestSuite) Test_GetTaskByID_Success() {
 	uts.taskRepoMock.On("Get", mock.Anything, mock.Anything).Return(
 		&mT_Task, nil)
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	retTask, err := taskUseCase.GetTaskByID(uts.context, 2)
 
 	uts.Require().Nil(err)
@@ -87,7 +90,7 @@ func (uts *BizTestSuite) Test_GetTaskByID_DatabaseNotFoundError() {
 	uts.taskRepoMock.On("Get", mock.Anything, mock.Anything).Return(
 		nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST)))
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	retTask, err := taskUseCase.GetTaskByID(uts.context, 2)
 
 	se := new(errors.Error)
@@ -98,7 +101,7 @@ func (uts *BizTestSuite) Test_GetTaskByID_DatabaseNotFoundError() {
 
 func (uts *BizTestSuite) Test_GetTaskByID_TaskIdNotSpecified() {
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	retTask, err := taskUseCase.GetTaskByID(uts.context, 0)
 
 	se := new(errors.Error)
@@ -113,24 +116,38 @@ func (uts *BizTestSuite) Test_UpdateTaskByID_Success() {
 	mT_Task := model.T_Task{Task: model.Task{TaskID: 2, Name: "user_updated", Content: "content_updated"},
 		T_Internal: model.T_Internal{CreatedAt: &nt, UpdatedAt: &nt}}
 
-	uts.taskRepoMock.On("Update", mock.Anything, mock.Anything).Return(
+	uts.taskRepoMock.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(
 		&mT_Task, nil)
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	retTask, err := taskUseCase.UpdateTaskByID(uts.context,
-		&model.Task{TaskID: 2, Name: "user", Content: "content"})
+		&model.Task{TaskID: 2, Name: "user", Content: "content"}, 0)
 
 	uts.Require().Nil(err)
 	uts.Require().Equal(mT_Task, *retTask)
 }
 
+func (uts *BizTestSuite) Test_UpdateTaskByID_VersionMismatch() {
+	uts.taskRepoMock.On("Update", mock.Anything, mock.Anything, uint64(3)).Return(
+		nil, model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH)))
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.UpdateTaskByID(uts.context,
+		&model.Task{TaskID: 2, Name: "user", Content: "content"}, 3)
+
+	se := new(errors.Error)
+	uts.Require().True(errors.As(err, &se))
+	uts.Require().True(model.IsTaskVersionMismatch(se))
+	uts.Require().Nil(retTask)
+}
+
 func (uts *BizTestSuite) Test_UpdateTaskByID_DatabaseTaskNotFound() {
-	uts.taskRepoMock.On("Update", mock.Anything, mock.Anything).Return(
+	uts.taskRepoMock.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(
 		nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST)))
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	retTask, err := taskUseCase.UpdateTaskByID(uts.context,
-		&model.Task{TaskID: 2, Name: "user", Content: "content"})
+		&model.Task{TaskID: 2, Name: "user", Content: "content"}, 0)
 
 	se := new(errors.Error)
 	uts.Require().True(errors.As(err, &se))
@@ -139,9 +156,9 @@ func (uts *BizTestSuite) Test_UpdateTaskByID_DatabaseTaskNotFound() {
 }
 
 func (uts *BizTestSuite) Test_UpdateTaskByID_TaskIdNotSpecified() {
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	retTask, err := taskUseCase.UpdateTaskByID(uts.context,
-		&model.Task{Name: "user", Content: "content"})
+		&model.Task{Name: "user", Content: "content"}, 0)
 
 	se := new(errors.Error)
 	uts.Require().True(errors.As(err, &se))
@@ -152,7 +169,7 @@ func (uts *BizTestSuite) Test_UpdateTaskByID_TaskIdNotSpecified() {
 func (uts *BizTestSuite) Test_DeleteTaskByID_Success() {
 	uts.taskRepoMock.On("Delete", mock.Anything, mock.Anything).Return(nil)
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	err := taskUseCase.DeleteTaskByID(uts.context, 3)
 
 	uts.Require().Nil(err)
@@ -162,7 +179,7 @@ func (uts *BizTestSuite) Test_DeleteTaskByID_DatabaseTaskNotFound() {
 	uts.taskRepoMock.On("Delete", mock.Anything, mock.Anything).Return(
 		model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST)))
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	err := taskUseCase.DeleteTaskByID(uts.context, 3)
 
 	se := new(errors.Error)
@@ -171,7 +188,7 @@ func (uts *BizTestSuite) Test_DeleteTaskByID_DatabaseTaskNotFound() {
 }
 
 func (uts *BizTestSuite) Test_DeleteTaskByID_TaskIdNotSpecified() {
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
 	err := taskUseCase.DeleteTaskByID(uts.context, 0)
 
 	se := new(errors.Error)
@@ -188,12 +205,13 @@ func (uts *BizTestSuite) Test_ListTasks_Success() {
 		T_Internal: model.T_Internal{CreatedAt: &nt}}
 
 	uts.taskRepoMock.On("List", mock.Anything, mock.Anything).Return(
-		[]model.T_Task{mT_Task1, mT_Task2}, nil)
+		&biz.TaskPage{Tasks: []model.T_Task{mT_Task1, mT_Task2}}, nil)
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
-	retTasks, err := taskUseCase.ListTasks(uts.context)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retPage, err := taskUseCase.ListTasks(uts.context, &biz.TaskQuery{})
 
 	uts.Require().Nil(err)
+	retTasks := retPage.Tasks
 	if retTasks[0].TaskID == 1 {
 		uts.Require().Equal(retTasks[0], mT_Task1)
 		uts.Require().Equal(retTasks[1], mT_Task2)
@@ -207,11 +225,1387 @@ func (uts *BizTestSuite) Test_ListTasks_DatabaseError() {
 	uts.taskRepoMock.On("List", mock.Anything, mock.Anything).Return(
 		nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST)))
 
-	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, uts.logger)
-	retTasks, err := taskUseCase.ListTasks(uts.context)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retPage, err := taskUseCase.ListTasks(uts.context, &biz.TaskQuery{})
 
-	uts.Require().Nil(retTasks)
+	uts.Require().Nil(retPage)
 	se := new(errors.Error)
 	uts.Require().True(errors.As(err, &se))
 	uts.Require().True(model.IsTaskNotFound(se))
 }
+
+func (uts *BizTestSuite) Test_ListTasks_InvalidQuery() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	for _, q := range []biz.TaskQuery{
+		{PageSize: -1},
+		{PageSize: biz.MaxPageSize + 1},
+		{SortBy: "content"},
+		{Cursor: "not a cursor"},
+	} {
+		retPage, err := taskUseCase.ListTasks(uts.context, &q)
+
+		uts.Require().Nil(retPage)
+		se := new(errors.Error)
+		uts.Require().True(errors.As(err, &se))
+		uts.Require().True(model.IsTaskQueryInvalid(se))
+	}
+	uts.taskRepoMock.AssertNotCalled(uts.T(), "List", mock.Anything, mock.Anything)
+}
+
+func (uts *BizTestSuite) Test_ListDeletedTasks_OnlyTrashed() {
+	uts.taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return q.Trashed
+	})).Return(&biz.TaskPage{}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retPage, err := taskUseCase.ListDeletedTasks(uts.context, &biz.TaskQuery{})
+
+	uts.Require().Nil(err)
+	uts.Require().NotNil(retPage)
+}
+
+func (uts *BizTestSuite) Test_RestoreTaskByID_Success() {
+	nt := time.Now()
+	mT_Task := model.T_Task{Task: model.Task{TaskID: 3, Name: "user", Content: "content"},
+		T_Internal: model.T_Internal{CreatedAt: &nt, UpdatedAt: &nt}}
+	uts.taskRepoMock.On("Restore", mock.Anything, uint64(3)).Return(&mT_Task, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.RestoreTaskByID(uts.context, 3)
+
+	uts.Require().Nil(err)
+	uts.Require().Equal(mT_Task, *retTask)
+}
+
+func (uts *BizTestSuite) Test_RestoreTaskByID_TaskIdNotSpecified() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.RestoreTaskByID(uts.context, 0)
+
+	se := new(errors.Error)
+	uts.Require().True(errors.As(err, &se))
+	uts.Require().True(model.IsTaskIdUnspecified(se))
+	uts.Require().Nil(retTask)
+}
+
+func (uts *BizTestSuite) Test_PurgeTaskByID_TaskNotDeleted() {
+	uts.taskRepoMock.On("Purge", mock.Anything, uint64(3)).Return(
+		model.ErrorTaskNotDeleted(string(encoder.TASK_NOT_DELETED)))
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	err := taskUseCase.PurgeTaskByID(uts.context, 3)
+
+	se := new(errors.Error)
+	uts.Require().True(errors.As(err, &se))
+	uts.Require().True(model.IsTaskNotDeleted(se))
+}
+
+func (uts *BizTestSuite) Test_PurgeTaskByID_TaskIdNotSpecified() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	err := taskUseCase.PurgeTaskByID(uts.context, 0)
+
+	se := new(errors.Error)
+	uts.Require().True(errors.As(err, &se))
+	uts.Require().True(model.IsTaskIdUnspecified(se))
+}
+
+func (uts *BizTestSuite) Test_GetTaskHistory_Success() {
+	nt := time.Now()
+	revisions := []model.T_Revision{
+		{TaskID: 3, Revision: 1, Action: model.RevisionCreated, ChangedAt: &nt, Task: model.Task{TaskID: 3, Name: "user"}},
+		{TaskID: 3, Revision: 2, Action: model.RevisionUpdated, ChangedAt: &nt, Task: model.Task{TaskID: 3, Name: "user2"}},
+	}
+	uts.taskRepoMock.On("History", mock.Anything, uint64(3)).Return(revisions, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retRevisions, err := taskUseCase.GetTaskHistory(uts.context, 3)
+
+	uts.Require().Nil(err)
+	uts.Require().Equal(revisions, retRevisions)
+}
+
+func (uts *BizTestSuite) Test_GetTaskRevision_RevisionNotFound() {
+	uts.taskRepoMock.On("Revision", mock.Anything, uint64(3), uint64(7)).Return(
+		nil, model.ErrorTaskRevisionNotFound(string(encoder.TASK_REVISION_NOT_EXIST)))
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retRevision, err := taskUseCase.GetTaskRevision(uts.context, 3, 7)
+
+	se := new(errors.Error)
+	uts.Require().True(errors.As(err, &se))
+	uts.Require().True(model.IsTaskRevisionNotFound(se))
+	uts.Require().Nil(retRevision)
+}
+
+func (uts *BizTestSuite) Test_RevertTaskByID_Success() {
+	nt := time.Now()
+	mT_Task := model.T_Task{Task: model.Task{TaskID: 3, Name: "user", Content: "content"},
+		T_Internal: model.T_Internal{CreatedAt: &nt, UpdatedAt: &nt, Version: 4}}
+	uts.taskRepoMock.On("Revision", mock.Anything, uint64(3), uint64(1)).Return(
+		&model.T_Revision{TaskID: 3, Revision: 1, Task: mT_Task.Task}, nil)
+	uts.taskRepoMock.On("Revert", mock.Anything, uint64(3), uint64(1)).Return(&mT_Task, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.RevertTaskByID(uts.context, 3, 1)
+
+	uts.Require().Nil(err)
+	uts.Require().Equal(mT_Task, *retTask)
+}
+
+func (uts *BizTestSuite) Test_RevertTaskByID_TaskIdNotSpecified() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.RevertTaskByID(uts.context, 0, 1)
+
+	se := new(errors.Error)
+	uts.Require().True(errors.As(err, &se))
+	uts.Require().True(model.IsTaskIdUnspecified(se))
+	uts.Require().Nil(retTask)
+}
+
+func (uts *BizTestSuite) Test_TransitionTask_Success() {
+	current := &model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 3, Status: model.StatusTodo}}
+	moved := &model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 4, Status: model.StatusInProgress}}
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(current, nil)
+	uts.taskRepoMock.On("Transition", mock.Anything, uint64(2), model.StatusInProgress, uint64(3)).Return(moved, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.TransitionTask(uts.context, 2, model.StatusInProgress, 0)
+
+	uts.Require().Nil(err)
+	uts.Require().Equal(moved, retTask)
+}
+
+func (uts *BizTestSuite) Test_TransitionTask_NotAllowed() {
+	for _, scenario := range []struct {
+		from model.TaskStatus
+		to   model.TaskStatus
+	}{
+		{model.StatusTodo, model.StatusDone},
+		{model.StatusTodo, model.StatusTodo},
+		{model.StatusDone, model.StatusCancelled},
+		{model.StatusCancelled, model.StatusInProgress},
+		{model.StatusBlocked, model.StatusDone},
+		// Tasks stored without a status are still to do
+		{"", model.StatusDone},
+	} {
+		taskRepoMock := mocks.TaskRepo{}
+		taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(
+			&model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 1, Status: scenario.from}}, nil)
+
+		taskUseCase := biz.NewTaskUsecase(&taskRepoMock, nil, uts.logger)
+		_, err := taskUseCase.TransitionTask(uts.context, 2, scenario.to, 0)
+
+		uts.Require().True(model.IsInvalidTransition(err), "%s to %s", scenario.from, scenario.to)
+		taskRepoMock.AssertNotCalled(uts.T(), "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func (uts *BizTestSuite) Test_TransitionTask_UnknownStatus() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	_, err := taskUseCase.TransitionTask(uts.context, 2, "started", 0)
+
+	uts.Require().True(model.IsInvalidTransition(err))
+	uts.Require().Equal("task cannot move to the given status: unknown status \"started\"", errors.FromError(err).Message)
+}
+
+func (uts *BizTestSuite) Test_TransitionTask_VersionMismatch() {
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(
+		&model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 3, Status: model.StatusTodo}}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	_, err := taskUseCase.TransitionTask(uts.context, 2, model.StatusInProgress, 2)
+
+	uts.Require().True(model.IsTaskVersionMismatch(err))
+	uts.taskRepoMock.AssertNotCalled(uts.T(), "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (uts *BizTestSuite) Test_TransitionTask_RetriesLostRace() {
+	// Someone else changes the task between the check and the transition
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(
+		&model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 3, Status: model.StatusTodo}}, nil).Once()
+	uts.taskRepoMock.On("Transition", mock.Anything, uint64(2), model.StatusBlocked, uint64(3)).Return(
+		nil, model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH))).Once()
+
+	// The workflow is checked again against the new status
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(
+		&model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 4, Status: model.StatusDone}}, nil).Once()
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	_, err := taskUseCase.TransitionTask(uts.context, 2, model.StatusBlocked, 0)
+
+	uts.Require().True(model.IsInvalidTransition(err))
+	uts.taskRepoMock.AssertExpectations(uts.T())
+}
+
+func (uts *BizTestSuite) Test_TransitionTask_OnlyOwnerOrAssignee() {
+	current := &model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 3, Status: model.StatusTodo,
+		Owner: "alice", Assignees: []string{"bob", "carol"}}}
+	moved := &model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 4, Status: model.StatusInProgress,
+		Owner: "alice", Assignees: []string{"bob", "carol"}}}
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(current, nil)
+	uts.taskRepoMock.On("Transition", mock.Anything, uint64(2), model.StatusInProgress, uint64(3)).Return(moved, nil)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	for _, scenario := range []struct {
+		actor string
+		want  string
+	}{
+		{actor: "alice"},
+		{actor: "carol"},
+		{actor: "dave", want: "task can only be changed by its owner or assignees: dave is neither the owner nor an assignee of task 2"},
+		{actor: "Alice", want: "task can only be changed by its owner or assignees: Alice is neither the owner nor an assignee of task 2"},
+		{actor: "", want: "task can only be changed by its owner or assignees: task 2 is assigned, and the request names no user"},
+	} {
+		_, err := taskUseCase.TransitionTask(biz.WithActor(uts.context, scenario.actor), 2, model.StatusInProgress, 0)
+		if scenario.want == "" {
+			uts.Require().Nil(err, scenario.actor)
+			continue
+		}
+		uts.Require().True(model.IsTaskForbidden(err), scenario.actor)
+		uts.Require().Equal(403, int(errors.FromError(err).Code))
+		uts.Require().Equal(scenario.want, errors.FromError(err).Message)
+	}
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "Transition", 2)
+}
+
+func (uts *BizTestSuite) Test_ReassignTask() {
+	current := &model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 3, Owner: "alice"}}
+	reassigned := &model.T_Task{Task: model.Task{TaskID: 2}, T_Internal: model.T_Internal{Version: 4, Owner: "bob",
+		Assignees: []string{"carol", "dave"}}}
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(current, nil)
+	uts.taskRepoMock.On("Reassign", mock.Anything, uint64(2), "bob", []string{"carol", "dave"}, uint64(3)).Return(reassigned, nil)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	// Users are trimmed, and assignees sorted without duplicates
+	a := &model.TaskAssignment{Owner: " bob ", Assignees: []string{"dave", "carol", "dave"}}
+	retTask, err := taskUseCase.ReassignTask(biz.WithActor(uts.context, "alice"), 2, a, 0)
+	uts.Require().Nil(err)
+	uts.Require().Equal(reassigned, retTask)
+
+	_, err = taskUseCase.ReassignTask(biz.WithActor(uts.context, "bob"), 2, a, 0)
+	uts.Require().True(model.IsTaskForbidden(err))
+	_, err = taskUseCase.ReassignTask(biz.WithActor(uts.context, "alice"), 2, a, 2)
+	uts.Require().True(model.IsTaskVersionMismatch(err))
+	_, err = taskUseCase.ReassignTask(uts.context, 2, &model.TaskAssignment{Assignees: []string{" "}}, 0)
+	uts.Require().True(model.IsTaskInvalidUser(err))
+	_, err = taskUseCase.ReassignTask(uts.context, 0, a, 0)
+	uts.Require().True(model.IsTaskIdUnspecified(err))
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "Reassign", 1)
+}
+
+func (uts *BizTestSuite) Test_ListUserTasks() {
+	uts.taskRepoMock.On("List", mock.Anything, mock.Anything).Return(&biz.TaskPage{}, nil)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	q := &biz.TaskQuery{Trashed: true}
+	_, err := taskUseCase.ListUserTasks(uts.context, " alice ", biz.UserRoleAssignee, q)
+	uts.Require().Nil(err)
+	uts.Require().Equal("alice", q.User)
+	uts.Require().False(q.Trashed)
+
+	owned := &model.T_Task{T_Internal: model.T_Internal{CreatedBy: "bob", Owner: "alice"}}
+	assigned := &model.T_Task{T_Internal: model.T_Internal{CreatedBy: "alice", Owner: "bob", Assignees: []string{"alice", "carol"}}}
+	created := &model.T_Task{T_Internal: model.T_Internal{CreatedBy: "alice", Owner: "bob"}}
+	for _, scenario := range []struct {
+		role biz.UserRole
+		want []bool
+	}{
+		{role: "", want: []bool{true, true, false}},
+		{role: biz.UserRoleOwner, want: []bool{true, false, false}},
+		{role: biz.UserRoleAssignee, want: []bool{false, true, false}},
+		{role: biz.UserRoleCreator, want: []bool{false, true, true}},
+	} {
+		q := &biz.TaskQuery{User: "alice", Role: scenario.role}
+		uts.Require().Nil(q.Validate())
+		uts.Require().Equal(scenario.want, []bool{q.Match(owned), q.Match(assigned), q.Match(created)}, scenario.role)
+	}
+
+	_, err = taskUseCase.ListUserTasks(uts.context, "alice", "watcher", &biz.TaskQuery{})
+	uts.Require().True(model.IsTaskQueryInvalid(err))
+	_, err = taskUseCase.ListUserTasks(uts.context, "", "", &biz.TaskQuery{})
+	uts.Require().True(model.IsTaskInvalidUser(err))
+}
+
+func (uts *BizTestSuite) Test_TransitionTask_TaskIdNotSpecified() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	_, err := taskUseCase.TransitionTask(uts.context, 0, model.StatusDone, 0)
+
+	uts.Require().True(model.IsTaskIdUnspecified(err))
+}
+
+func (uts *BizTestSuite) Test_CreateTask_Subtask() {
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(1)).Return(&model.T_Task{Task: model.Task{TaskID: 1}}, nil)
+	uts.taskRepoMock.On("Create", mock.Anything, mock.Anything).Return(
+		&model.T_Task{Task: model.Task{TaskID: 2, ParentID: 1}}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.CreateTask(uts.context, &model.Task{Name: "sub", ParentID: 1})
+
+	uts.Require().Nil(err)
+	uts.Require().Equal(uint64(1), retTask.ParentID)
+}
+
+func (uts *BizTestSuite) Test_CreateTask_ParentNotExist() {
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(9)).Return(nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST)))
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.CreateTask(uts.context, &model.Task{Name: "sub", ParentID: 9})
+
+	uts.Require().True(model.IsTaskInvalidParent(err))
+	uts.Require().Equal("task parent is invalid: parent task 9 does not exist", errors.FromError(err).Message)
+	uts.Require().Nil(retTask)
+	uts.taskRepoMock.AssertNotCalled(uts.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (uts *BizTestSuite) Test_UpdateTaskByID_ParentCycle() {
+	// 1 <- 2 <- 3, then 1 is moved below 3
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(3)).Return(&model.T_Task{Task: model.Task{TaskID: 3, ParentID: 2}}, nil)
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(&model.T_Task{Task: model.Task{TaskID: 2, ParentID: 1}}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	for _, scenario := range []struct {
+		name    string
+		task    model.Task
+		message string
+	}{
+		{name: "self", task: model.Task{TaskID: 1, ParentID: 1}, message: "task parent is invalid: task 1 cannot be its own parent"},
+		{name: "descendant", task: model.Task{TaskID: 1, ParentID: 3}, message: "task parent is invalid: task 1 is an ancestor of task 3"},
+	} {
+		uts.Run(scenario.name, func() {
+			retTask, err := taskUseCase.UpdateTaskByID(uts.context, &scenario.task, 0)
+
+			uts.Require().True(model.IsTaskInvalidParent(err))
+			uts.Require().Equal(scenario.message, errors.FromError(err).Message)
+			uts.Require().Nil(retTask)
+		})
+	}
+	uts.taskRepoMock.AssertNotCalled(uts.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (uts *BizTestSuite) Test_RevertTaskByID_ParentGone() {
+	uts.taskRepoMock.On("Revision", mock.Anything, uint64(3), uint64(1)).Return(
+		&model.T_Revision{TaskID: 3, Revision: 1, Task: model.Task{TaskID: 3, ParentID: 2}}, nil)
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(nil, model.ErrorTaskNotFound(string(encoder.TASK_DELETED)))
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.RevertTaskByID(uts.context, 3, 1)
+
+	uts.Require().True(model.IsTaskInvalidParent(err))
+	uts.Require().Nil(retTask)
+	uts.taskRepoMock.AssertNotCalled(uts.T(), "Revert", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (uts *BizTestSuite) Test_GetTaskTree() {
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(1)).Return(&model.T_Task{Task: model.Task{TaskID: 1}}, nil)
+	uts.taskRepoMock.On("Children", mock.Anything, uint64(1)).Return(
+		[]model.T_Task{{Task: model.Task{TaskID: 2, ParentID: 1}}, {Task: model.Task{TaskID: 3, ParentID: 1}}}, nil)
+	uts.taskRepoMock.On("Children", mock.Anything, uint64(2)).Return(
+		[]model.T_Task{{Task: model.Task{TaskID: 4, ParentID: 2}}}, nil)
+	uts.taskRepoMock.On("Children", mock.Anything, uint64(3)).Return([]model.T_Task{}, nil)
+	uts.taskRepoMock.On("Children", mock.Anything, uint64(4)).Return([]model.T_Task{}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	tree, err := taskUseCase.GetTaskTree(uts.context, 1)
+
+	uts.Require().Nil(err)
+	uts.Require().Equal(uint64(1), tree.TaskID)
+	uts.Require().Len(tree.Children, 2)
+	uts.Require().Equal(uint64(2), tree.Children[0].TaskID)
+	uts.Require().Equal(uint64(4), tree.Children[0].Children[0].TaskID)
+	uts.Require().Equal(uint64(3), tree.Children[1].TaskID)
+	uts.Require().Empty(tree.Children[1].Children)
+}
+
+func (uts *BizTestSuite) Test_DeleteTaskTree_TaskIdNotSpecified() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	n, err := taskUseCase.DeleteTaskTree(uts.context, 0)
+
+	uts.Require().True(model.IsTaskIdUnspecified(err))
+	uts.Require().Zero(n)
+}
+
+func (uts *BizTestSuite) Test_TransitionTask_Blocked() {
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(3)).Return(
+		&model.T_Task{Task: model.Task{TaskID: 3}, T_Internal: model.T_Internal{Version: 1, Status: model.StatusTodo, DependsOn: []uint64{1, 2}}}, nil)
+	uts.taskRepoMock.On("Transition", mock.Anything, uint64(3), model.StatusInProgress, uint64(1)).Return(
+		nil, model.ErrorTaskBlocked("%s: %d", encoder.TASK_BLOCKED, 2))
+	uts.taskRepoMock.On("Transition", mock.Anything, uint64(3), model.StatusBlocked, uint64(1)).Return(
+		&model.T_Task{Task: model.Task{TaskID: 3}, T_Internal: model.T_Internal{Version: 2, Status: model.StatusBlocked}}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	// The repo checks the dependencies, and its answer is final
+	_, err := taskUseCase.TransitionTask(uts.context, 3, model.StatusInProgress, 0)
+	uts.Require().True(model.IsTaskBlocked(err))
+	uts.Require().Equal("task is waiting on dependencies which are not done: 2", errors.FromError(err).Message)
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "Transition", 1)
+
+	retTask, err := taskUseCase.TransitionTask(uts.context, 3, model.StatusBlocked, 0)
+	uts.Require().Nil(err)
+	uts.Require().Equal(model.StatusBlocked, retTask.Status)
+}
+
+func (uts *BizTestSuite) Test_ListReadyTasks() {
+	uts.taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return q.Ready && !q.Trashed
+	})).Return(&biz.TaskPage{Tasks: []model.T_Task{{Task: model.Task{TaskID: 1}}}}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	page, err := taskUseCase.ListReadyTasks(uts.context, &biz.TaskQuery{Trashed: true})
+
+	uts.Require().Nil(err)
+	uts.Require().Len(page.Tasks, 1)
+}
+
+func (uts *BizTestSuite) Test_AddTaskDependency_TaskIdNotSpecified() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	retTask, err := taskUseCase.AddTaskDependency(uts.context, 1, 0)
+
+	uts.Require().True(model.IsTaskIdUnspecified(err))
+	uts.Require().Nil(retTask)
+}
+
+func (uts *BizTestSuite) Test_NormalizeTags() {
+	for _, scenario := range []struct {
+		name  string
+		tags  []string
+		want  []string
+		valid bool
+	}{
+		{name: "none", tags: nil, want: nil, valid: true},
+		{name: "case and spaces", tags: []string{" Urgent", "bug ", "URGENT"}, want: []string{"bug", "urgent"}, valid: true},
+		{name: "empty", tags: []string{"bug", "  "}},
+		{name: "too long", tags: []string{strings.Repeat("x", biz.MaxTagLength+1)}},
+	} {
+		uts.Run(scenario.name, func() {
+			tags, err := biz.NormalizeTags(scenario.tags)
+			if !scenario.valid {
+				uts.Require().True(model.IsTaskInvalidTag(err))
+				return
+			}
+			uts.Require().Nil(err)
+			uts.Require().Equal(scenario.want, tags)
+		})
+	}
+}
+
+func (uts *BizTestSuite) Test_CreateTask_NormalizesTags() {
+	uts.taskRepoMock.On("Create", mock.Anything, mock.MatchedBy(func(t *model.Task) bool {
+		return reflect.DeepEqual([]string{"bug", "urgent"}, t.Tags)
+	})).Return(&model.T_Task{Task: model.Task{TaskID: 1, Tags: []string{"bug", "urgent"}}}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	_, err := taskUseCase.CreateTask(uts.context, &model.Task{Name: "task", Tags: []string{"Urgent", "bug"}})
+
+	uts.Require().Nil(err)
+	uts.taskRepoMock.AssertExpectations(uts.T())
+}
+
+func (uts *BizTestSuite) Test_ListTasks_TagQuery() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	_, err := taskUseCase.ListTasks(uts.context, &biz.TaskQuery{Tags: []string{"bug"}, TagMatch: "some"})
+	uts.Require().True(model.IsTaskQueryInvalid(err))
+
+	_, err = taskUseCase.ListTasks(uts.context, &biz.TaskQuery{Tags: []string{""}})
+	uts.Require().True(model.IsTaskQueryInvalid(err))
+
+	uts.taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return reflect.DeepEqual([]string{"bug"}, q.Tags) && q.TagMatch == biz.TagMatchAll
+	})).Return(&biz.TaskPage{}, nil)
+	_, err = taskUseCase.ListTasks(uts.context, &biz.TaskQuery{Tags: []string{"BUG", "bug"}})
+	uts.Require().Nil(err)
+}
+
+func (uts *BizTestSuite) Test_RenameTag() {
+	uts.taskRepoMock.On("RenameTag", mock.Anything, "defect", "bug").Return(3, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	n, err := taskUseCase.RenameTag(uts.context, "Defect", " BUG")
+	uts.Require().Nil(err)
+	uts.Require().Equal(3, n)
+
+	_, err = taskUseCase.RenameTag(uts.context, "defect", "")
+	uts.Require().True(model.IsTaskInvalidTag(err))
+}
+
+func (uts *BizTestSuite) Test_SearchQuery_Parse() {
+	term := func(op biz.SearchOp, terms ...string) *biz.SearchNode {
+		return &biz.SearchNode{Op: op, Terms: terms}
+	}
+	node := func(op biz.SearchOp, children ...*biz.SearchNode) *biz.SearchNode {
+		return &biz.SearchNode{Op: op, Children: children}
+	}
+
+	for _, scenario := range []struct {
+		name  string
+		query string
+		want  *biz.SearchNode
+	}{
+		{name: "word", query: "Login", want: term(biz.SearchTerm, "login")},
+		{name: "implied and", query: "login bug", want: node(biz.SearchAnd, term(biz.SearchTerm, "login"), term(biz.SearchTerm, "bug"))},
+		{name: "phrase", query: `"Login  bug"`, want: term(biz.SearchPhrase, "login", "bug")},
+		{name: "compound word", query: "e-mail", want: term(biz.SearchPhrase, "e", "mail")},
+		{name: "prefix", query: "log*", want: term(biz.SearchPrefix, "log")},
+		{name: "or binds looser than and", query: "a b OR c", want: node(biz.SearchOr,
+			node(biz.SearchAnd, term(biz.SearchTerm, "a"), term(biz.SearchTerm, "b")), term(biz.SearchTerm, "c"))},
+		{name: "not", query: "a -b NOT c", want: node(biz.SearchAnd,
+			term(biz.SearchTerm, "a"), node(biz.SearchNot, term(biz.SearchTerm, "b")), node(biz.SearchNot, term(biz.SearchTerm, "c")))},
+		{name: "parentheses", query: "a AND (b OR c)", want: node(biz.SearchAnd,
+			term(biz.SearchTerm, "a"), node(biz.SearchOr, term(biz.SearchTerm, "b"), term(biz.SearchTerm, "c")))},
+		{name: "empty", query: "  "},
+		{name: "unterminated phrase", query: `"login bug`},
+		{name: "unbalanced", query: "(a OR b"},
+		{name: "dangling operator", query: "a OR"},
+		{name: "no words", query: "a ..."},
+		{name: "too long", query: strings.Repeat("a ", biz.MaxSearchLength)},
+	} {
+		uts.Run(scenario.name, func() {
+			q := &biz.SearchQuery{Query: scenario.query}
+			err := q.Validate()
+			if scenario.want == nil {
+				uts.Require().True(model.IsTaskQueryInvalid(err))
+				return
+			}
+			uts.Require().Nil(err)
+			uts.Require().Equal(scenario.want, q.Root())
+			uts.Require().Equal(biz.DefaultSearchLimit, q.Limit)
+		})
+	}
+}
+
+func (uts *BizTestSuite) Test_SearchTasks() {
+	uts.taskRepoMock.On("Search", mock.Anything, mock.MatchedBy(func(q *biz.SearchQuery) bool {
+		return q.Root() != nil && q.Limit == 5
+	})).Return([]model.T_SearchResult{{Task: model.T_Task{Task: model.Task{TaskID: 1}}, Score: 1}}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	results, err := taskUseCase.SearchTasks(uts.context, &biz.SearchQuery{Query: "login", Limit: 5})
+	uts.Require().Nil(err)
+	uts.Require().Equal(1, len(results))
+
+	_, err = taskUseCase.SearchTasks(uts.context, &biz.SearchQuery{Query: "login", Limit: biz.MaxSearchLimit + 1})
+	uts.Require().True(model.IsTaskQueryInvalid(err))
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "Search", 1)
+}
+
+func (uts *BizTestSuite) Test_ParseFilter() {
+	created := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	task := &model.T_Task{
+		Task: model.Task{TaskID: 7, Name: "Deploy API", Content: `say "hi"`, ParentID: 3, Tags: []string{"ops", "urgent"}},
+		T_Internal: model.T_Internal{CreatedAt: &created, Status: model.StatusInProgress,
+			CreatedBy: "alice", Owner: "bob", Assignees: []string{"carol", "dave"}},
+	}
+
+	for _, scenario := range []struct {
+		filter string
+		want   bool
+	}{
+		{filter: `status = "in_progress"`, want: true},
+		{filter: `status != in_progress`, want: false},
+		{filter: `taskID >= 7 AND taskID < 8`, want: true},
+		{filter: `parentID = 3`, want: true},
+		{filter: `name ~ "deploy*"`, want: true},
+		{filter: `name ~ "de?loy"`, want: false},
+		{filter: `name = "Deploy API"`, want: true},
+		{filter: `name < "E"`, want: true},
+		{filter: `content = "say \"hi\""`, want: true},
+		{filter: `tag = URGENT`, want: true},
+		{filter: `tag != ops`, want: false},
+		{filter: `tag ~ "urg*"`, want: true},
+		{filter: `owner = bob`, want: true},
+		{filter: `owner = Bob`, want: false},
+		{filter: `assignee = dave AND createdBy = alice`, want: true},
+		{filter: `assignee != carol`, want: false},
+		{filter: `assignee ~ "ca*"`, want: true},
+		{filter: `createdAt > "2026-01-01" AND createdAt < "2026-02-01T11:00:00+01:00"`, want: false},
+		{filter: `createdAt > 2026-01-01 AND createdAt <= 2026-02-01T10:00:00Z`, want: true},
+		{filter: `updatedAt < 2030-01-01`, want: false},
+		{filter: `updatedAt != 2030-01-01`, want: true},
+		{filter: `status = done OR tag = ops AND taskID = 7`, want: true},
+		{filter: `(status = done OR tag = ops) AND taskID = 8`, want: false},
+		{filter: `NOT status = done AND NOT (tag = ops AND tag = misc)`, want: true},
+	} {
+		uts.Run(scenario.filter, func() {
+			f, err := biz.ParseFilter(scenario.filter)
+			uts.Require().Nil(err)
+			uts.Require().Equal(scenario.want, f.Match(task))
+		})
+	}
+}
+
+func (uts *BizTestSuite) Test_ParseFilter_Errors() {
+	for _, scenario := range []struct {
+		filter string
+		want   string
+	}{
+		{filter: ``, want: "filter is empty"},
+		{filter: `stauts = todo`, want: `unknown field "stauts" at offset 0, expected one of taskID, parentID, seriesID, name, content, status, tag, owner, assignee, createdBy, createdAt, updatedAt, dueAt, remindAt`},
+		{filter: `status todo`, want: `unexpected "todo" at offset 7, expected an operator`},
+		{filter: `status =`, want: "filter ends unexpectedly at offset 8, expected a value"},
+		{filter: `status ~ "t*"`, want: `operator "~" at offset 7 cannot be used with status`},
+		{filter: `status = open`, want: `status must be one of todo, in_progress, blocked, done, cancelled, got "open" at offset 9`},
+		{filter: `taskID > ten`, want: `taskID needs a number, got "ten" at offset 9`},
+		{filter: `createdAt > yesterday`, want: `createdAt needs a date or an RFC 3339 timestamp, got "yesterday" at offset 12`},
+		{filter: `name = "deploy`, want: "unterminated string at offset 7"},
+		{filter: `name ! "deploy"`, want: `unexpected "!" at offset 5, expected "!="`},
+		{filter: `(tag = a OR tag = b`, want: `filter ends unexpectedly at offset 19, expected ")"`},
+		{filter: `tag = a tag = b`, want: `unexpected "tag" at offset 8, expected AND, OR or the end of the filter`},
+		{filter: `tag = a AND OR tag = b`, want: `unexpected "OR" at offset 12, expected a field or "("`},
+	} {
+		uts.Run(scenario.filter, func() {
+			_, err := biz.ParseFilter(scenario.filter)
+			uts.Require().True(model.IsTaskInvalidFilter(err))
+			uts.Require().Equal(string(encoder.TASK_FILTER_INVALID)+": "+scenario.want, errors.FromError(err).Message)
+		})
+	}
+}
+
+func (uts *BizTestSuite) Test_ListTasks_Filter() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	_, err := taskUseCase.ListTasks(uts.context, &biz.TaskQuery{Filter: "status = "})
+	uts.Require().True(model.IsTaskInvalidFilter(err))
+
+	uts.taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return reflect.DeepEqual([]biz.FilterClause{
+			{Field: biz.FilterTag, Op: biz.FilterEq, Value: "ops"},
+			{Field: biz.FilterName, Op: biz.FilterLike, Value: "deploy*"},
+		}, q.FilterClauses())
+	})).Return(&biz.TaskPage{}, nil)
+	_, err = taskUseCase.ListTasks(uts.context, &biz.TaskQuery{Filter: `tag = Ops AND (name ~ "Deploy*") AND (status = done OR status = todo)`})
+	uts.Require().Nil(err)
+}
+
+// reminderRecorder is a notifier which passes the reminders on to a channel, and
+// fails on the tasks listed in fail.
+type reminderRecorder struct {
+	sent chan model.T_Reminder
+	fail map[uint64]bool
+}
+
+func (n *reminderRecorder) Notify(_ context.Context, r model.T_Reminder) error {
+	if n.fail[r.TaskID] {
+		return errors.New(500, "NOTIFIER_DOWN", "notifier is down")
+	}
+	n.sent <- r
+	return nil
+}
+
+func (uts *BizTestSuite) Test_SendReminders() {
+	now := time.Now()
+	next := now.Add(time.Hour)
+	due := []model.T_Task{
+		{Task: model.Task{TaskID: 1, Name: "call", RemindAt: &now}},
+		{Task: model.Task{TaskID: 2, Name: "write", RemindAt: &now, DueAt: &next}},
+	}
+	uts.taskRepoMock.On("ListTenants", mock.Anything).Return([]model.T_Tenant{{Tenant: model.Tenant{TenantID: biz.DefaultTenant}}}, nil)
+	uts.taskRepoMock.On("DueReminders", mock.Anything, now).Return(due, &next, nil)
+	uts.taskRepoMock.On("MarkReminded", mock.Anything, uint64(2), now).Return(nil)
+
+	// The reminder which fails is not marked as sent, so it is sent again later
+	notifier := &reminderRecorder{sent: make(chan model.T_Reminder, 2), fail: map[uint64]bool{1: true}}
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	at, err := taskUseCase.SendReminders(uts.context, notifier, now)
+
+	uts.Require().Nil(err)
+	uts.Require().Equal(&next, at)
+	uts.Require().Equal(model.T_Reminder{TaskID: 2, Name: "write", DueAt: &next, RemindAt: now, SentAt: now}, <-notifier.sent)
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "MarkReminded", 1)
+}
+
+func (uts *BizTestSuite) Test_Scheduler_WakesUpOnChange() {
+	remindAt := time.Now()
+	task := model.T_Task{Task: model.Task{TaskID: 1, Name: "call", RemindAt: &remindAt}}
+
+	uts.taskRepoMock.On("ListTenants", mock.Anything).Return([]model.T_Tenant{{Tenant: model.Tenant{TenantID: biz.DefaultTenant}}}, nil)
+	// Nothing is due until the task is created
+	uts.taskRepoMock.On("DueReminders", mock.Anything, mock.Anything).Return([]model.T_Task{}, nil, nil).Once()
+	uts.taskRepoMock.On("DueReminders", mock.Anything, mock.Anything).Return([]model.T_Task{task}, nil, nil).Once()
+	uts.taskRepoMock.On("DueReminders", mock.Anything, mock.Anything).Return([]model.T_Task{}, nil, nil)
+	uts.taskRepoMock.On("MarkReminded", mock.Anything, uint64(1), mock.Anything).Return(nil)
+	uts.taskRepoMock.On("Create", mock.Anything, mock.Anything).Return(&task, nil)
+	uts.taskRepoMock.On("DueSeries", mock.Anything, mock.Anything).Return([]model.T_Series{}, nil, nil)
+
+	notifier := &reminderRecorder{sent: make(chan model.T_Reminder, 1)}
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	scheduler := biz.NewScheduler(taskUseCase, notifier, uts.logger)
+	go scheduler.Run()
+
+	_, err := taskUseCase.CreateTask(uts.context, &model.Task{Name: "call", RemindAt: &remindAt})
+	uts.Require().Nil(err)
+
+	select {
+	case r := <-notifier.sent:
+		uts.Require().Equal(uint64(1), r.TaskID)
+	case <-time.After(5 * time.Second):
+		uts.FailNow("the reminder was not sent")
+	}
+
+	ctx, cancel := context.WithTimeout(uts.context, 5*time.Second)
+	defer cancel()
+	uts.Require().Nil(scheduler.Stop(ctx))
+}
+
+func (uts *BizTestSuite) Test_ListDueTasks() {
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	_, err := taskUseCase.ListDueTasks(uts.context, &biz.TaskQuery{}, -time.Hour)
+	uts.Require().True(model.IsTaskQueryInvalid(err))
+
+	uts.taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return q.Unfinished && q.SortBy == biz.SortByDueAt && q.DueBefore.Sub(*q.DueAfter) == biz.DefaultDueWithin
+	})).Return(&biz.TaskPage{}, nil)
+	_, err = taskUseCase.ListDueTasks(uts.context, &biz.TaskQuery{}, 0)
+	uts.Require().Nil(err)
+}
+
+func (uts *BizTestSuite) Test_ListOverdueTasks() {
+	uts.taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return q.Unfinished && q.DueAfter == nil && !q.DueBefore.After(time.Now()) && q.SortBy == biz.SortByName
+	})).Return(&biz.TaskPage{}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	_, err := taskUseCase.ListOverdueTasks(uts.context, &biz.TaskQuery{SortBy: biz.SortByName})
+	uts.Require().Nil(err)
+
+	// Only the tasks due in the window pass
+	now := time.Now()
+	q := &biz.TaskQuery{DueBefore: &now, Unfinished: true}
+	uts.Require().Nil(q.Validate())
+	past := now.Add(-time.Hour)
+	uts.Require().True(q.Match(&model.T_Task{Task: model.Task{DueAt: &past}}))
+	uts.Require().False(q.Match(&model.T_Task{Task: model.Task{DueAt: &past}, T_Internal: model.T_Internal{Status: model.StatusDone}}))
+	uts.Require().False(q.Match(&model.T_Task{}))
+}
+
+func Test_ParseSchedule(t *testing.T) {
+	// A Monday
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d, hour, min int) time.Time {
+		return time.Date(2026, month, d, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		rule string
+		want []time.Time
+	}{
+		{rule: "FREQ=DAILY", want: []time.Time{day(1, 5, 9, 0), day(1, 6, 9, 0), day(1, 7, 9, 0)}},
+		{rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", want: []time.Time{day(1, 5, 9, 0), day(1, 8, 9, 0), day(1, 19, 9, 0)}},
+		{rule: "freq=monthly;byday=-1fr", want: []time.Time{day(1, 30, 9, 0), day(2, 27, 9, 0), day(3, 27, 9, 0)}},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=31", want: []time.Time{day(1, 31, 9, 0), day(3, 31, 9, 0), day(5, 31, 9, 0)}},
+		{rule: "RRULE:FREQ=YEARLY;COUNT=2", want: []time.Time{day(1, 5, 9, 0), start.AddDate(1, 0, 0)}},
+		{rule: "FREQ=DAILY;UNTIL=20260106", want: []time.Time{day(1, 5, 9, 0), day(1, 6, 9, 0)}},
+		{rule: "30 8 * * 1-5", want: []time.Time{day(1, 6, 8, 30), day(1, 7, 8, 30), day(1, 8, 8, 30)}},
+		{rule: "0 0 1,15 * *", want: []time.Time{day(1, 15, 0, 0), day(2, 1, 0, 0), day(2, 15, 0, 0)}},
+		{rule: "@weekly", want: []time.Time{day(1, 11, 0, 0), day(1, 18, 0, 0), day(1, 25, 0, 0)}},
+		// Both days of a cron expression restricted match either
+		{rule: "0 12 13 * FRI", want: []time.Time{day(1, 9, 12, 0), day(1, 13, 12, 0), day(1, 16, 12, 0)}},
+		{rule: "*/20 9 * * *", want: []time.Time{day(1, 5, 9, 0), day(1, 5, 9, 20), day(1, 5, 9, 40), day(1, 6, 9, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			schedule, err := biz.ParseSchedule(tt.rule, start)
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+
+			var got []time.Time
+			for at, ok := schedule.Next(start.Add(-time.Nanosecond)); ok && len(got) < len(tt.want); at, ok = schedule.Next(at) {
+				got = append(got, at)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("occurrences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseSchedule_Errors(t *testing.T) {
+	rules := []string{
+		"",
+		"FREQ=HOURLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260101",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYSETPOS=1",
+		"* * * *",
+		"61 * * * *",
+		"0 0 */0 * *",
+		"0 0 * * SAT-MON",
+	}
+	for _, rule := range rules {
+		if _, err := biz.ParseSchedule(rule, time.Now()); !model.IsSeriesInvalidRule(err) {
+			t.Errorf("ParseSchedule(%q) error = %v, want an invalid rule", rule, err)
+		}
+	}
+}
+
+func (uts *BizTestSuite) Test_CreateSeries() {
+	// A Thursday, so the first weekly occurrence is the Monday after
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	first := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	series := model.T_Series{Series: model.Series{SeriesID: 1, Rule: "FREQ=WEEKLY;BYDAY=MO", Start: &start}, CurrentTaskID: 1, CurrentAt: &first}
+	uts.taskRepoMock.On("CreateSeries", mock.Anything, mock.MatchedBy(func(s *model.Series) bool {
+		return reflect.DeepEqual(s.Tags, []string{"chores"})
+	}), first).Return(&series, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	result, err := taskUseCase.CreateSeries(uts.context, &model.Series{Rule: "FREQ=WEEKLY;BYDAY=MO", Start: &start, Name: "bins", Tags: []string{"Chores"}})
+	uts.Require().Nil(err)
+	uts.Require().Equal(&series, result)
+
+	_, err = taskUseCase.CreateSeries(uts.context, &model.Series{Rule: "FREQ=FORTNIGHTLY", Name: "bins"})
+	uts.Require().True(model.IsSeriesInvalidRule(err))
+
+	// February never has a 30th
+	_, err = taskUseCase.CreateSeries(uts.context, &model.Series{Rule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", Name: "bins"})
+	uts.Require().True(model.IsSeriesInvalidRule(err))
+}
+
+func (uts *BizTestSuite) Test_AdvanceSeries() {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 1, 7, 10, 0, 0, 0, time.UTC)
+	next := time.Date(2026, 1, 8, 9, 0, 0, 0, time.UTC)
+	later := now.Add(48 * time.Hour)
+	due := []model.T_Series{
+		{Series: model.Series{SeriesID: 1, Rule: "FREQ=DAILY", Start: &start}, Version: 3, Occurrences: 1, CurrentAt: &start},
+		{Series: model.Series{SeriesID: 2, Rule: "FREQ=DAILY;COUNT=2", Start: &start}, Version: 4, Occurrences: 2, CurrentAt: &start},
+	}
+	uts.taskRepoMock.On("ListTenants", mock.Anything).Return([]model.T_Tenant{{Tenant: model.Tenant{TenantID: biz.DefaultTenant}}}, nil)
+	uts.taskRepoMock.On("DueSeries", mock.Anything, now).Return(due, &later, nil)
+
+	// The occurrences missed on the 6th and 7th are skipped, and the series which
+	// has had all its occurrences is stopped
+	advanced := model.T_Series{Series: due[0].Series, Version: 4, Occurrences: 2, CurrentAt: &next}
+	uts.taskRepoMock.On("AdvanceSeries", mock.Anything, uint64(1), uint64(3), &next).Return(&advanced, nil)
+	stopped := model.T_Series{Series: due[1].Series, Version: 5, Occurrences: 2, CurrentAt: &start, StoppedAt: &now}
+	uts.taskRepoMock.On("AdvanceSeries", mock.Anything, uint64(2), uint64(4), (*time.Time)(nil)).Return(&stopped, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	at, err := taskUseCase.AdvanceSeries(uts.context, now)
+	uts.Require().Nil(err)
+	uts.Require().Equal(&next, at)
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "AdvanceSeries", 2)
+}
+
+func (uts *BizTestSuite) Test_TransitionTask_AdvancesSeries() {
+	start := time.Now().Truncate(time.Second).Add(time.Hour)
+	next := start.AddDate(0, 0, 1)
+	task := model.T_Task{Task: model.Task{TaskID: 7}, T_Internal: model.T_Internal{Version: 2, Status: model.StatusInProgress, SeriesID: 1}}
+	done := model.T_Task{Task: model.Task{TaskID: 7}, T_Internal: model.T_Internal{Version: 3, Status: model.StatusDone, SeriesID: 1}}
+	series := model.T_Series{Series: model.Series{SeriesID: 1, Rule: "FREQ=DAILY", Start: &start}, Version: 1, CurrentTaskID: 7, CurrentAt: &start}
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(7)).Return(&task, nil)
+	uts.taskRepoMock.On("Transition", mock.Anything, uint64(7), model.StatusDone, uint64(2)).Return(&done, nil)
+	uts.taskRepoMock.On("GetSeries", mock.Anything, uint64(1)).Return(&series, nil)
+
+	// Finished ahead of time, the next occurrence follows the current one
+	uts.taskRepoMock.On("AdvanceSeries", mock.Anything, uint64(1), uint64(1), &next).Return(&series, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	result, err := taskUseCase.TransitionTask(uts.context, 7, model.StatusDone, 0)
+	uts.Require().Nil(err)
+	uts.Require().Equal(&done, result)
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "AdvanceSeries", 1)
+}
+
+const testPolicy = `
+roles:
+  admin: ["*"]
+  editor: ["task.*", "series.write"]
+  viewer: [task.read]
+grants:
+  - subject: "*"
+    role: viewer
+  - subject: alice
+    role: admin
+  - subject: bob
+    role: editor
+    project: apollo
+`
+
+func (uts *BizTestSuite) Test_ParsePolicy() {
+	p, err := biz.ParsePolicy([]byte(testPolicy))
+	uts.Require().Nil(err)
+	uts.Require().Len(p.Roles, 3)
+	uts.Require().Len(p.Grants, 3)
+
+	// JSON is YAML too
+	p, err = biz.ParsePolicy([]byte(`{"roles": {"viewer": ["task.read"]}, "grants": [{"subject": "*", "role": "viewer"}]}`))
+	uts.Require().Nil(err)
+	uts.Require().Equal([]model.PolicyGrant{{Subject: "*", Role: "viewer"}}, p.Grants)
+
+	for _, scenario := range []struct {
+		policy string
+		err    string
+	}{
+		{policy: "roles: {viewer: [task.view]}", err: `role viewer: unknown permission "task.view"`},
+		{policy: "roles: {viewer: [tasks.*]}", err: `role viewer: unknown permission "tasks.*"`},
+		{policy: "roles: {viewer: [task.read]}\ngrants: [{subject: bob, role: editor}]", err: `grant 0: unknown role "editor"`},
+		{policy: "roles: {viewer: [task.read]}\ngrants: [{role: viewer}]", err: "grant 0: subject is empty"},
+		{policy: "roles: {viewer: [task.read]}\nusers: [bob]", err: "field users not found"},
+	} {
+		_, err := biz.ParsePolicy([]byte(scenario.policy))
+		uts.Require().ErrorContains(err, scenario.err, scenario.policy)
+	}
+}
+
+func (uts *BizTestSuite) Test_Policy_Decide() {
+	p, err := biz.ParsePolicy([]byte(testPolicy))
+	uts.Require().Nil(err)
+
+	for _, scenario := range []struct {
+		subject string
+		perm    biz.Permission
+		project string
+		allowed bool
+		reason  string
+	}{
+		{subject: "alice", perm: biz.PermissionTaskClear, allowed: true, reason: "alice has role admin, which allows task.clear"},
+		{subject: "", perm: biz.PermissionTaskRead, allowed: true, reason: "an unauthenticated caller has role viewer, which allows task.read"},
+		{subject: "", perm: biz.PermissionTaskCreate, reason: "an unauthenticated caller has roles viewer, none of which allows task.create"},
+		{subject: "bob", perm: biz.PermissionTaskDelete, project: "apollo", allowed: true, reason: "bob has role editor, which allows task.delete in project apollo"},
+		{subject: "bob", perm: biz.PermissionTaskDelete, project: "gemini", reason: "bob has roles viewer, none of which allows task.delete in project gemini"},
+		{subject: "bob", perm: biz.PermissionTaskDelete, reason: "bob has roles viewer, none of which allows task.delete"},
+		{subject: "bob", perm: biz.PermissionTagRename, project: "apollo", reason: "bob has roles editor, viewer, none of which allows tag.rename in project apollo"},
+	} {
+		d := p.Decide(scenario.subject, scenario.perm, scenario.project)
+		uts.Require().Equal(scenario.allowed, d.Allowed, scenario.reason)
+		uts.Require().Equal(scenario.reason, d.Reason)
+		uts.Require().Equal(scenario.allowed, d.Grant != nil)
+	}
+
+	// Without grants to everyone, nothing applies to strangers
+	p, err = biz.ParsePolicy([]byte("roles: {admin: ['*']}\ngrants: [{subject: alice, role: admin}]"))
+	uts.Require().Nil(err)
+	uts.Require().Equal("no grant applies to mallory", p.Decide("mallory", biz.PermissionTaskRead, "").Reason)
+}
+
+func (uts *BizTestSuite) Test_Policy_Authorize() {
+	p, err := biz.ParsePolicy([]byte(testPolicy))
+	uts.Require().Nil(err)
+	uts.taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(&model.T_Task{Task: model.Task{TaskID: 2}}, nil)
+	uts.taskRepoMock.On("Empty", mock.Anything).Return(nil)
+	uts.taskRepoMock.On("Purge", mock.Anything, uint64(2)).Return(func(ctx context.Context, _ uint64) error {
+		return allowOn(ctx, nil)
+	})
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, p, uts.logger)
+
+	alice := biz.WithPrincipal(uts.context, &biz.Principal{Subject: "alice"})
+	bob := biz.WithPrincipal(uts.context, &biz.Principal{Subject: "bob"})
+
+	_, err = taskUseCase.GetTaskByID(bob, 2)
+	uts.Require().Nil(err)
+	uts.Require().Nil(taskUseCase.ClearTasks(alice))
+
+	// Denied operations never reach the repo
+	err = taskUseCase.ClearTasks(bob)
+	uts.Require().True(model.IsForbidden(err))
+	uts.Require().Equal("action is not allowed by the access policy: bob has roles viewer, none of which allows task.clear",
+		errors.FromError(err).Message)
+	_, err = taskUseCase.CreateTask(uts.context, &model.Task{Name: "user"})
+	uts.Require().True(model.IsForbidden(err))
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "Empty", 1)
+
+	// Unless they are on a task, whose project only the repo knows
+	err = taskUseCase.PurgeTaskByID(bob, 2)
+	uts.Require().True(model.IsForbidden(err))
+
+	// Naming a user is not authenticating as that user
+	err = taskUseCase.ClearTasks(biz.WithActor(uts.context, "alice"))
+	uts.Require().True(model.IsForbidden(err))
+}
+
+// allowOn makes the policy check the context carries, as the repo does under its
+// lock, with tasks on the boards of the projects.
+func allowOn(ctx context.Context, projects map[uint64]string) error {
+	if c := biz.TaskCheckFromContext(ctx); c != nil {
+		return c.Allow(projects[c.TaskID])
+	}
+	return nil
+}
+
+func (uts *BizTestSuite) Test_Policy_AuthorizeTask() {
+	p, err := biz.ParsePolicy([]byte(testPolicy))
+	uts.Require().Nil(err)
+	// Task 4 is in the trash, where it keeps the board it was on
+	projects := map[uint64]string{3: "apollo", 4: "apollo", 5: "gemini"}
+	trashed := model.T_Task{Task: model.Task{TaskID: 4}, T_Internal: model.T_Internal{ProjectID: "apollo"}}
+	uts.taskRepoMock.On("History", mock.Anything, mock.Anything).Return(func(ctx context.Context, _ uint64) ([]model.T_Revision, error) {
+		return []model.T_Revision{}, allowOn(ctx, projects)
+	})
+	uts.taskRepoMock.On("Children", mock.Anything, uint64(3)).Return(func(ctx context.Context, _ uint64) ([]model.T_Task, error) {
+		return []model.T_Task{}, allowOn(ctx, projects)
+	})
+	uts.taskRepoMock.On("DeleteCascade", mock.Anything, mock.Anything).Return(func(ctx context.Context, _ uint64) (int, error) {
+		if err := allowOn(ctx, projects); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	})
+	uts.taskRepoMock.On("Restore", mock.Anything, uint64(4)).Return(func(ctx context.Context, _ uint64) (*model.T_Task, error) {
+		return &trashed, allowOn(ctx, projects)
+	})
+	uts.taskRepoMock.On("Purge", mock.Anything, uint64(4)).Return(func(ctx context.Context, _ uint64) error {
+		return allowOn(ctx, projects)
+	})
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, p, uts.logger)
+	bob := biz.WithPrincipal(uts.context, &biz.Principal{Subject: "bob"})
+
+	// Bob is an editor on the board of apollo, for its deleted tasks as well
+	_, err = taskUseCase.GetTaskHistory(bob, 3)
+	uts.Require().Nil(err)
+	_, err = taskUseCase.GetTaskChildren(bob, 3)
+	uts.Require().Nil(err)
+	_, err = taskUseCase.DeleteTaskTree(bob, 3)
+	uts.Require().Nil(err)
+	_, err = taskUseCase.RestoreTaskByID(bob, 4)
+	uts.Require().Nil(err)
+	uts.Require().Nil(taskUseCase.PurgeTaskByID(bob, 4))
+
+	// And a viewer elsewhere
+	_, err = taskUseCase.GetTaskHistory(bob, 5)
+	uts.Require().Nil(err)
+	_, err = taskUseCase.DeleteTaskTree(bob, 5)
+	uts.Require().True(model.IsForbidden(err))
+
+	// A grant everywhere leaves nothing for the repo to check
+	alice := biz.WithPrincipal(uts.context, &biz.Principal{Subject: "alice"})
+	uts.taskRepoMock.On("Delete", mock.Anything, uint64(5)).Return(func(ctx context.Context, _ uint64) error {
+		uts.Require().Nil(biz.TaskCheckFromContext(ctx))
+		return nil
+	})
+	uts.Require().Nil(taskUseCase.DeleteTaskByID(alice, 5))
+}
+
+func (uts *BizTestSuite) Test_ExplainPolicy() {
+	p, err := biz.ParsePolicy([]byte(testPolicy))
+	uts.Require().Nil(err)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, p, uts.logger)
+	alice := biz.WithPrincipal(uts.context, &biz.Principal{Subject: "alice"})
+
+	d, err := taskUseCase.ExplainPolicy(alice, "bob", biz.PermissionTaskUpdate, "apollo")
+	uts.Require().Nil(err)
+	uts.Require().True(d.Allowed)
+	uts.Require().Equal(&model.PolicyGrant{Subject: "bob", Role: "editor", Project: "apollo"}, d.Grant)
+
+	_, err = taskUseCase.ExplainPolicy(alice, "bob", "task.view", "")
+	uts.Require().True(model.IsBadRequest(err))
+
+	// Only admins may ask
+	_, err = taskUseCase.ExplainPolicy(biz.WithPrincipal(uts.context, &biz.Principal{Subject: "bob"}), "bob", biz.PermissionTaskRead, "")
+	uts.Require().True(model.IsForbidden(err))
+
+	// Without a policy everything is allowed
+	d, err = biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger).ExplainPolicy(uts.context, "bob", biz.PermissionTaskPurge, "")
+	uts.Require().Nil(err)
+	uts.Require().True(d.Allowed)
+}
+
+func (uts *BizTestSuite) Test_TenantFromContext() {
+	uts.Require().Equal(biz.DefaultTenant, biz.TenantFromContext(uts.context))
+
+	ctx := biz.WithTenant(uts.context, " Acme ")
+	uts.Require().Equal("acme", biz.TenantFromContext(ctx))
+
+	// The tenant a principal is bound to wins over the one the request names
+	ctx = biz.WithPrincipal(ctx, &biz.Principal{Subject: "alice", Tenant: "beta"})
+	uts.Require().Equal("beta", biz.TenantFromContext(ctx))
+}
+
+func (uts *BizTestSuite) Test_NormalizeTenant() {
+	tests := []struct {
+		tenant string
+		want   string
+		valid  bool
+	}{
+		{"acme", "acme", true},
+		{" Team-7_ops ", "team-7_ops", true},
+		{"", "", false},
+		{"-acme", "", false},
+		{"acme corp", "", false},
+		{"acme/ops", "", false},
+		{strings.Repeat("a", biz.MaxTenantLength+1), "", false},
+	}
+	for _, tt := range tests {
+		got, err := biz.NormalizeTenant(tt.tenant)
+		uts.Require().Equal(tt.valid, err == nil, tt.tenant)
+		uts.Require().Equal(tt.want, got, tt.tenant)
+		if !tt.valid {
+			uts.Require().True(model.IsTenantInvalid(err), tt.tenant)
+		}
+	}
+}
+
+func (uts *BizTestSuite) Test_CreateTenant() {
+	created := &model.T_Tenant{Tenant: model.Tenant{TenantID: "acme", MaxTasks: 100}}
+	uts.taskRepoMock.On("CreateTenant", mock.Anything, &model.Tenant{TenantID: "acme", MaxTasks: 100}).Return(created, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	result, err := taskUseCase.CreateTenant(uts.context, &model.Tenant{TenantID: "ACME", MaxTasks: 100})
+	uts.Require().Nil(err)
+	uts.Require().Equal(created, result)
+
+	_, err = taskUseCase.CreateTenant(uts.context, &model.Tenant{TenantID: "a b"})
+	uts.Require().True(model.IsTenantInvalid(err))
+	_, err = taskUseCase.CreateTenant(uts.context, &model.Tenant{TenantID: "acme", MaxSeries: -1})
+	uts.Require().True(model.IsTenantInvalid(err))
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "CreateTenant", 1)
+}
+
+func (uts *BizTestSuite) Test_DeleteTenant() {
+	uts.taskRepoMock.On("DeleteTenant", mock.Anything, "acme").Return(nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	uts.Require().Nil(taskUseCase.DeleteTenant(uts.context, "Acme"))
+
+	// The default tenant always exists
+	err := taskUseCase.DeleteTenant(uts.context, biz.DefaultTenant)
+	uts.Require().True(model.IsTenantInvalid(err))
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "DeleteTenant", 1)
+}
+
+func (uts *BizTestSuite) Test_Tenants_NeedTenantAdmin() {
+	p, err := biz.ParsePolicy([]byte(testPolicy))
+	uts.Require().Nil(err)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, p, uts.logger)
+	uts.taskRepoMock.On("ListTenants", mock.Anything).Return([]model.T_Tenant{}, nil)
+
+	_, err = taskUseCase.ListTenants(biz.WithPrincipal(uts.context, &biz.Principal{Subject: "bob"}))
+	uts.Require().True(model.IsForbidden(err))
+	_, err = taskUseCase.ListTenants(biz.WithPrincipal(uts.context, &biz.Principal{Subject: "alice"}))
+	uts.Require().Nil(err)
+}
+
+func (uts *BizTestSuite) Test_AdvanceSeries_EveryTenant() {
+	now := time.Now()
+	soon, later := now.Add(time.Hour), now.Add(2*time.Hour)
+	uts.taskRepoMock.On("ListTenants", mock.Anything).Return([]model.T_Tenant{
+		{Tenant: model.Tenant{TenantID: "acme"}}, {Tenant: model.Tenant{TenantID: biz.DefaultTenant}}}, nil)
+	uts.taskRepoMock.On("DueSeries", mock.MatchedBy(func(ctx context.Context) bool {
+		return biz.TenantFromContext(ctx) == "acme"
+	}), now).Return([]model.T_Series{}, &soon, nil)
+	uts.taskRepoMock.On("DueSeries", mock.MatchedBy(func(ctx context.Context) bool {
+		return biz.TenantFromContext(ctx) == biz.DefaultTenant
+	}), now).Return([]model.T_Series{}, &later, nil)
+
+	// The series of every tenant are advanced, and the earliest of them is next
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	at, err := taskUseCase.AdvanceSeries(uts.context, now)
+	uts.Require().Nil(err)
+	uts.Require().Equal(&soon, at)
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "DueSeries", 2)
+}
+
+func (uts *BizTestSuite) Test_CreateProject() {
+	created := &model.T_Project{Project: model.Project{ProjectID: "apollo", Name: "Apollo", Columns: []string{"todo", "done"}}}
+	uts.taskRepoMock.On("CreateProject", mock.Anything, &created.Project).Return(created, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	result, err := taskUseCase.CreateProject(uts.context, &model.Project{ProjectID: " Apollo", Name: "Apollo ", Columns: []string{"todo", " done"}})
+	uts.Require().Nil(err)
+	uts.Require().Equal(created, result)
+
+	for _, p := range []model.Project{
+		{ProjectID: "a/b", Columns: []string{"todo"}},
+		{ProjectID: "apollo"},
+		{ProjectID: "apollo", Columns: []string{"todo", " "}},
+		{ProjectID: "apollo", Columns: []string{"todo", "todo "}},
+	} {
+		_, err := taskUseCase.CreateProject(uts.context, &p)
+		uts.Require().True(model.IsProjectInvalid(err), "%+v", p)
+	}
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "CreateProject", 1)
+}
+
+func (uts *BizTestSuite) Test_MoveTask() {
+	moved := &model.T_Task{Task: model.Task{TaskID: 1}, T_Internal: model.T_Internal{ProjectID: "apollo", Column: "done"}}
+	uts.taskRepoMock.On("Move", mock.Anything, uint64(1), &model.TaskMove{ProjectID: "apollo", Column: "done", After: 2}, uint64(3)).Return(moved, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	result, err := taskUseCase.MoveTask(uts.context, 1, &model.TaskMove{ProjectID: "APOLLO", Column: " done", After: 2}, 3)
+	uts.Require().Nil(err)
+	uts.Require().Equal(moved, result)
+
+	for _, m := range []model.TaskMove{
+		{ProjectID: "apollo", Before: 2, After: 3},
+		{ProjectID: "apollo", Before: 1},
+		{Column: "done"},
+	} {
+		_, err := taskUseCase.MoveTask(uts.context, 1, &m, 0)
+		uts.Require().True(model.IsProjectInvalid(err), "%+v", m)
+	}
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "Move", 1)
+}
+
+func (uts *BizTestSuite) Test_Policy_ProjectGrants() {
+	p, err := biz.ParsePolicy([]byte(testPolicy))
+	uts.Require().Nil(err)
+	projects := map[uint64]string{1: "apollo", 2: "gemini"}
+	uts.taskRepoMock.On("Delete", mock.Anything, mock.Anything).Return(func(ctx context.Context, _ uint64) error {
+		return allowOn(ctx, projects)
+	})
+	uts.taskRepoMock.On("Move", mock.Anything, mock.Anything, mock.Anything, uint64(0)).Return(
+		func(ctx context.Context, _ uint64, _ *model.TaskMove, _ uint64) (*model.T_Task, error) {
+			return &model.T_Task{}, allowOn(ctx, projects)
+		})
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, p, uts.logger)
+	bob := biz.WithPrincipal(uts.context, &biz.Principal{Subject: "bob"})
+
+	// The grants of bob in apollo apply to the tasks on its board
+	uts.Require().Nil(taskUseCase.DeleteTaskByID(bob, 1))
+	uts.Require().True(model.IsForbidden(taskUseCase.DeleteTaskByID(bob, 2)))
+
+	_, err = taskUseCase.MoveTask(bob, 1, &model.TaskMove{ProjectID: "apollo", Column: "done"}, 0)
+	uts.Require().Nil(err)
+	_, err = taskUseCase.MoveTask(bob, 1, &model.TaskMove{ProjectID: "gemini"}, 0)
+	uts.Require().True(model.IsForbidden(err))
+	// The board a task leaves is only known to the repo, which checks it
+	_, err = taskUseCase.MoveTask(bob, 2, &model.TaskMove{ProjectID: "apollo"}, 0)
+	uts.Require().True(model.IsForbidden(err))
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "Move", 2)
+}
+
+func Test_Mentions(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "no one", want: nil},
+		{text: "@bob please look", want: []string{"bob"}},
+		{text: "thanks @carol and @bob, cc @bob.", want: []string{"bob", "carol"}},
+		{text: "ask @alice@example.com (@dave-)", want: []string{"alice@example.com", "dave"}},
+		{text: "mail alice@example.com or @@eve", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := biz.Mentions(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Mentions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func (uts *BizTestSuite) Test_CreateComment() {
+	created := &model.T_Comment{CommentID: 1, TaskID: 2}
+	uts.taskRepoMock.On("CreateComment", mock.Anything, uint64(2), &model.Comment{Body: "ping @bob", Mentions: []string{"bob"}}).Return(created, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	result, err := taskUseCase.CreateComment(uts.context, 2, &model.Comment{Body: " ping @bob\n", Mentions: []string{"mallory"}})
+	uts.Require().Nil(err)
+	uts.Require().Equal(created, result)
+
+	_, err = taskUseCase.CreateComment(uts.context, 2, &model.Comment{Body: " "})
+	uts.Require().True(model.IsCommentInvalid(err))
+	_, err = taskUseCase.CreateComment(uts.context, 0, &model.Comment{Body: "hi"})
+	uts.Require().True(model.IsTaskIdUnspecified(err))
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "CreateComment", 1)
+}
+
+func (uts *BizTestSuite) Test_UpdateComment_OnlyAuthor() {
+	stored := &model.T_Comment{CommentID: 1, TaskID: 2, Author: "alice", Version: 3}
+	uts.taskRepoMock.On("GetComment", mock.Anything, uint64(2), uint64(1)).Return(stored, nil)
+	uts.taskRepoMock.On("UpdateComment", mock.Anything, uint64(2), uint64(1), mock.Anything, uint64(3)).Return(stored, nil)
+	uts.taskRepoMock.On("DeleteComment", mock.Anything, uint64(2), uint64(1), uint64(3)).Return(nil)
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+
+	for _, ctx := range []context.Context{uts.context, biz.WithActor(uts.context, "bob")} {
+		_, err := taskUseCase.UpdateComment(ctx, 2, 1, &model.Comment{Body: "edited"}, 0)
+		uts.Require().True(model.IsCommentForbidden(err))
+		uts.Require().True(model.IsCommentForbidden(taskUseCase.DeleteComment(ctx, 2, 1, 0)))
+	}
+
+	alice := biz.WithActor(uts.context, "alice")
+	_, err := taskUseCase.UpdateComment(alice, 2, 1, &model.Comment{Body: "edited"}, 2)
+	uts.Require().True(model.IsCommentVersionMismatch(err))
+	_, err = taskUseCase.UpdateComment(alice, 2, 1, &model.Comment{Body: "edited"}, 0)
+	uts.Require().Nil(err)
+	uts.Require().Nil(taskUseCase.DeleteComment(alice, 2, 1, 3))
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "UpdateComment", 1)
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "DeleteComment", 1)
+}
+
+func (uts *BizTestSuite) Test_ListActivity() {
+	at := func(min int) *time.Time {
+		t := time.Date(2026, 3, 2, 9, min, 0, 0, time.UTC)
+		return &t
+	}
+	uts.taskRepoMock.On("History", mock.Anything, uint64(1)).Return([]model.T_Revision{
+		{TaskID: 1, Revision: 1, Action: model.RevisionCreated, Actor: "alice", ChangedAt: at(0)},
+		{TaskID: 1, Revision: 2, Action: model.RevisionTransitioned, Actor: "bob", ChangedAt: at(5)},
+		{TaskID: 1, Revision: 3, Action: model.RevisionDeleted, Actor: "alice", ChangedAt: at(9)},
+	}, nil)
+	uts.taskRepoMock.On("ListComments", mock.Anything, uint64(1)).Return([]model.T_Comment{
+		{CommentID: 4, TaskID: 1, Author: "bob", CreatedAt: at(3)},
+		{CommentID: 7, TaskID: 1, Author: "carol", CreatedAt: at(5)},
+		{CommentID: 8, TaskID: 1, Author: "alice", CreatedAt: at(12)},
+	}, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	activity, err := taskUseCase.ListActivity(uts.context, 1)
+	uts.Require().Nil(err)
+
+	var got []string
+	for _, a := range activity {
+		switch a.Kind {
+		case model.ActivityRevision:
+			got = append(got, fmt.Sprintf("%s by %s", a.Revision.Action, a.Actor))
+		case model.ActivityComment:
+			got = append(got, fmt.Sprintf("comment %d by %s", a.Comment.CommentID, a.Actor))
+		}
+	}
+	uts.Require().Equal([]string{
+		"created by alice", "comment 4 by bob", "transitioned by bob", "comment 7 by carol", "deleted by alice", "comment 8 by alice",
+	}, got)
+}
+
+func Test_NormalizeAttachmentName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "notes.txt", want: "notes.txt"},
+		{name: " C:\\Users\\bob\\plan v2.pdf ", want: "plan v2.pdf"},
+		{name: "../../etc/passwd", want: "passwd"},
+		{name: "dir/", wantErr: true},
+		{name: "..", wantErr: true},
+		{name: "bad\nname", wantErr: true},
+		{name: strings.Repeat("x", biz.MaxAttachmentNameLength+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := biz.NormalizeAttachmentName(tt.name)
+			if tt.wantErr {
+				if !model.IsAttachmentInvalid(err) {
+					t.Errorf("NormalizeAttachmentName() error = %v, want ATTACHMENT_INVALID", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeAttachmentName() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func (uts *BizTestSuite) Test_CreateAttachment() {
+	created := &model.T_Attachment{AttachmentID: 1, TaskID: 2, Name: "plan.pdf"}
+	content := strings.NewReader("%PDF-1.7")
+	uts.taskRepoMock.On("CreateAttachment", mock.Anything, uint64(2), "plan.pdf", content).Return(created, nil)
+
+	taskUseCase := biz.NewTaskUsecase(&uts.taskRepoMock, nil, uts.logger)
+	result, err := taskUseCase.CreateAttachment(uts.context, 2, "/tmp/plan.pdf", content)
+	uts.Require().Nil(err)
+	uts.Require().Equal(created, result)
+
+	_, err = taskUseCase.CreateAttachment(uts.context, 2, "", content)
+	uts.Require().True(model.IsAttachmentInvalid(err))
+	_, err = taskUseCase.CreateAttachment(uts.context, 0, "plan.pdf", content)
+	uts.Require().True(model.IsTaskIdUnspecified(err))
+	uts.taskRepoMock.AssertNumberOfCalls(uts.T(), "CreateAttachment", 1)
+}
@@ -0,0 +1,133 @@
+package biz
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// DefaultTenant is the tenant of the requests which name none. It always exists.
+const DefaultTenant = "default"
+
+// MaxTenantLength bounds the length of a tenant ID, in bytes.
+const MaxTenantLength = 64
+
+// tenantPattern keeps tenant IDs usable in URLs and headers as they are.
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+type tenantKey struct{}
+
+// NormalizeTenant trims and lower cases a tenant ID, and checks that it is made of
+// letters, digits, dashes and underscores.
+func NormalizeTenant(tenant string) (string, error) {
+	t := strings.ToLower(strings.TrimSpace(tenant))
+	if t == "" {
+		return "", model.ErrorTenantInvalid("%s: tenant is empty", encoder.TENANT_INVALID)
+	}
+	if len(t) > MaxTenantLength {
+		return "", model.ErrorTenantInvalid("%s: tenant %q is longer than %d bytes", encoder.TENANT_INVALID, t, MaxTenantLength)
+	}
+	if !tenantPattern.MatchString(t) {
+		return "", model.ErrorTenantInvalid("%s: tenant %q may only hold letters, digits, dashes and underscores", encoder.TENANT_INVALID, t)
+	}
+	return t, nil
+}
+
+// WithTenant returns a context carrying the tenant a request names, whose tasks
+// the request works on.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of the authenticated principal, or else the
+// tenant set by WithTenant, or DefaultTenant when neither is set. It is not
+// normalized, so an invalid tenant is simply one which does not exist.
+func TenantFromContext(ctx context.Context) string {
+	if p := PrincipalFromContext(ctx); p != nil && p.Tenant != "" {
+		return p.Tenant
+	}
+	if tenant, _ := ctx.Value(tenantKey{}).(string); tenant != "" {
+		return strings.ToLower(strings.TrimSpace(tenant))
+	}
+	return DefaultTenant
+}
+
+// CreateTenant registers a tenant with its quotas. It starts out without tasks.
+func (uc *TaskUsecase) CreateTenant(ctx context.Context, t *model.Tenant) (*model.T_Tenant, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: CreateTenant: %+v", *t)
+	if err := uc.authorizeTenantAdmin(ctx); err != nil {
+		return nil, err
+	}
+	id, err := NormalizeTenant(t.TenantID)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: CreateTenant - %v", err)
+		return nil, err
+	}
+	if t.MaxTasks < 0 || t.MaxSeries < 0 {
+		return nil, model.ErrorTenantInvalid("%s: quotas cannot be negative", encoder.TENANT_INVALID)
+	}
+	t.TenantID = id
+	return uc.repo.CreateTenant(ctx, t)
+}
+
+// ListTenants lists every tenant, the default one included, with what it stores.
+func (uc *TaskUsecase) ListTenants(ctx context.Context) ([]model.T_Tenant, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListTenants")
+	if err := uc.authorizeTenantAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListTenants(ctx)
+}
+
+// DeleteTenant removes a tenant along with all its tasks and series, for good.
+// The default tenant cannot be deleted.
+func (uc *TaskUsecase) DeleteTenant(ctx context.Context, tenant string) error {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: DeleteTenant: %s", tenant)
+	if err := uc.authorizeTenantAdmin(ctx); err != nil {
+		return err
+	}
+	id, err := NormalizeTenant(tenant)
+	if err != nil {
+		return err
+	}
+	if id == DefaultTenant {
+		return model.ErrorTenantInvalid("%s: the %s tenant cannot be deleted", encoder.TENANT_INVALID, DefaultTenant)
+	}
+	return uc.repo.DeleteTenant(ctx, id)
+}
+
+// authorizeTenantAdmin checks that the caller may administer tenants. A principal
+// bound to a tenant may not, whatever the policy says, as the tenants are what
+// keeps it apart from the others.
+func (uc *TaskUsecase) authorizeTenantAdmin(ctx context.Context) error {
+	if p := PrincipalFromContext(ctx); p != nil && p.Tenant != "" {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: %s denied - %s is bound to tenant %s", PermissionTenantAdmin, p.Subject, p.Tenant)
+		return model.ErrorForbidden("%s: %s is bound to tenant %s", encoder.ACTION_DENIED, p.Subject, p.Tenant)
+	}
+	return uc.authorize(ctx, PermissionTenantAdmin, "")
+}
+
+// forEachTenant runs fn for every tenant, in a context naming it, and returns the
+// earliest of the times fn returns. Background jobs use it to serve every tenant.
+func (uc *TaskUsecase) forEachTenant(ctx context.Context, fn func(ctx context.Context) (*time.Time, error)) (*time.Time, error) {
+	tenants, err := uc.repo.ListTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var next *time.Time
+	for _, t := range tenants {
+		at, err := fn(WithTenant(ctx, t.TenantID))
+		if err != nil {
+			return next, err
+		}
+		if at != nil && (next == nil || at.Before(*next)) {
+			next = at
+		}
+	}
+	return next, nil
+}
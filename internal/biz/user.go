@@ -0,0 +1,163 @@
+package biz
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// MaxUserLength bounds the length of a user ID, in bytes.
+const MaxUserLength = 128
+
+// UserRole is the part a user plays in a task.
+type UserRole string
+
+const (
+	UserRoleOwner    UserRole = "owner"
+	UserRoleAssignee UserRole = "assignee"
+	UserRoleCreator  UserRole = "creator"
+)
+
+// NormalizeUser trims a user ID. User IDs are case sensitive.
+func NormalizeUser(user string) (string, error) {
+	u := strings.TrimSpace(user)
+	if u == "" {
+		return "", model.ErrorTaskInvalidUser("%s: user is empty", encoder.TASK_USER_INVALID)
+	}
+	if len(u) > MaxUserLength {
+		return "", model.ErrorTaskInvalidUser("%s: user %q is longer than %d bytes", encoder.TASK_USER_INVALID, u, MaxUserLength)
+	}
+	return u, nil
+}
+
+// NormalizeUsers normalizes every user, and returns them sorted without duplicates.
+func NormalizeUsers(users []string) ([]string, error) {
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(users))
+	result := make([]string, 0, len(users))
+	for _, user := range users {
+		u, err := NormalizeUser(user)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[u] {
+			seen[u] = true
+			result = append(result, u)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// matchUser reports whether the user of the query has its role in the task, or
+// owns or is assigned the task when no role is given.
+func (q *TaskQuery) matchUser(t *model.T_Task) bool {
+	if q.User == "" {
+		return true
+	}
+
+	switch q.Role {
+	case UserRoleOwner:
+		return t.Owner == q.User
+	case UserRoleAssignee:
+		i := sort.SearchStrings(t.Assignees, q.User)
+		return i < len(t.Assignees) && t.Assignees[i] == q.User
+	case UserRoleCreator:
+		return t.CreatedBy == q.User
+	}
+	return t.Assigned(q.User)
+}
+
+// ListUserTasks lists the live tasks the user owns or is assigned, or only those
+// the user has the given role in.
+func (uc *TaskUsecase) ListUserTasks(ctx context.Context, user string, role UserRole, q *TaskQuery) (*TaskPage, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ListUserTasks: %s as %q, %+v", user, role, *q)
+	if err := uc.authorize(ctx, PermissionTaskRead, ""); err != nil {
+		return nil, err
+	}
+	u, err := NormalizeUser(user)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: ListUserTasks - %v", err)
+		return nil, err
+	}
+	q.Trashed = false
+	q.User, q.Role = u, role
+	if err := q.Validate(); err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: ListUserTasks - %v", err)
+		return nil, err
+	}
+	return uc.repo.List(ctx, q)
+}
+
+// ReassignTask replaces the owner and the assignees of the task if it is still at
+// the given version, or unconditionally when the version is 0. Only its owner and
+// assignees may reassign a task, see checkAssigned.
+func (uc *TaskUsecase) ReassignTask(ctx context.Context, id uint64, a *model.TaskAssignment, version uint64) (*model.T_Task, error) {
+	uc.log.WithContext(ctx).Infof("TaskUsecase: ReassignTask: %v to %+v, version %d", id, *a, version)
+	ctx, err := uc.authorizeTask(ctx, PermissionTaskUpdate, id)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		uc.log.WithContext(ctx).Error("TaskUsecase: ReassignTask - Task ID not specified")
+		return nil, model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED))
+	}
+
+	var owner string
+	if a.Owner != "" {
+		u, err := NormalizeUser(a.Owner)
+		if err != nil {
+			uc.log.WithContext(ctx).Errorf("TaskUsecase: ReassignTask - %v", err)
+			return nil, err
+		}
+		owner = u
+	}
+	assignees, err := NormalizeUsers(a.Assignees)
+	if err != nil {
+		uc.log.WithContext(ctx).Errorf("TaskUsecase: ReassignTask - %v", err)
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		task, err := uc.repo.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if version != 0 && version != task.Version {
+			return nil, model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH))
+		}
+		if err := uc.checkAssigned(ctx, task); err != nil {
+			uc.log.WithContext(ctx).Errorf("TaskUsecase: ReassignTask - %v", err)
+			return nil, err
+		}
+
+		// The repo only reassigns the version checked above
+		result, err := uc.repo.Reassign(ctx, id, owner, assignees, task.Version)
+		if version == 0 && model.IsTaskVersionMismatch(err) && attempt < maxTransitionAttempts {
+			continue
+		}
+		return result, err
+	}
+}
+
+// checkAssigned rejects a user who neither owns nor is assigned the task. A task
+// with neither an owner nor assignees is open to everyone.
+func (uc *TaskUsecase) checkAssigned(ctx context.Context, task *model.T_Task) error {
+	if task.Unassigned() {
+		return nil
+	}
+	actor := ActorFromContext(ctx)
+	if task.Assigned(actor) {
+		return nil
+	}
+	if actor == "" {
+		return model.ErrorTaskForbidden("%s: task %d is assigned, and the request names no user", encoder.TASK_ACTION_FORBIDDEN, task.TaskID)
+	}
+	return model.ErrorTaskForbidden("%s: %s is neither the owner nor an assignee of task %d", encoder.TASK_ACTION_FORBIDDEN, actor, task.TaskID)
+}
@@ -0,0 +1,273 @@
+package data
+
+import (
+	"context"
+	"io"
+	"mime"
+	"path/filepath"
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) CreateAttachment(ctx context.Context, id uint64, name string, content io.Reader) (*model.T_Attachment, error) {
+	// The content is read before the lock is taken, and only stored once it is held
+	staged, err := r.data.stage(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	defer staged.Discard()
+
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err = r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.attachmentCreated(ctx, id, name, staged)
+	if err != nil {
+		return nil, err
+	}
+	r.data.apply(walEntry{Op: walOpAttachment, Tenant: r.tenant, Attachment: &val})
+
+	return &val, nil
+}
+
+func (r *taskRepo) GetAttachment(ctx context.Context, id uint64, attachmentID uint64) (*model.T_Attachment, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.storedAttachment(id, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &val, nil
+}
+
+func (r *taskRepo) OpenAttachment(ctx context.Context, id uint64, attachmentID uint64) (*model.T_Attachment, io.ReadSeekCloser, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	val, err := r.storedAttachment(id, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.data.blobs == nil {
+		return nil, nil, model.ErrorAttachmentsDisabled(string(encoder.ATTACHMENTS_DISABLED))
+	}
+	// An open blob can still be read once it has been removed
+	content, err := r.data.blobs.Open(val.SHA256)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &val, content, nil
+}
+
+func (r *taskRepo) ListAttachments(ctx context.Context, id uint64) ([]model.T_Attachment, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Task not exist
+	if _, ok := r.space.tasks[id]; !ok {
+		return nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	return append([]model.T_Attachment{}, r.space.attachments[id]...), nil
+}
+
+func (r *taskRepo) DeleteAttachment(ctx context.Context, id uint64, attachmentID uint64) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := r.attachmentDeleted(id, attachmentID); err != nil {
+		return err
+	}
+	r.data.apply(walEntry{Op: walOpDropAttachment, Tenant: r.tenant, TaskID: id, AttachmentID: attachmentID})
+
+	return nil
+}
+
+// storedAttachment returns the stored attachment of the task.
+func (r *taskRepo) storedAttachment(id uint64, attachmentID uint64) (model.T_Attachment, error) {
+	// Task not exist
+	if _, ok := r.space.tasks[id]; !ok {
+		return model.T_Attachment{}, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	for _, a := range r.space.attachments[id] {
+		if a.AttachmentID == attachmentID {
+			return a, nil
+		}
+	}
+
+	// Attachment not exist
+	return model.T_Attachment{}, model.ErrorAttachmentNotFound("%s: %d", encoder.ATTACHMENT_NOT_EXIST, attachmentID)
+}
+
+// attachmentCreated stores the staged content as a blob, provided the task is live,
+// and returns the record of the new attachment with the next ID of the attachment
+// sequence.
+func (r *taskRepo) attachmentCreated(ctx context.Context, id uint64, name string, staged biz.IStagedBlob) (model.T_Attachment, error) {
+	if _, err := r.current(id); err != nil {
+		return model.T_Attachment{}, err
+	}
+	if err := staged.Store(); err != nil {
+		r.log.WithContext(ctx).Errorf("taskRepo: store attachment - %v", err)
+		return model.T_Attachment{}, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	blob := staged.Blob()
+	// Sniffing only recognises some formats, the others are told by their extension
+	if blob.ContentType == "application/octet-stream" {
+		if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+			blob.ContentType = t
+		}
+	}
+
+	nt := time.Now()
+	return model.T_Attachment{
+		AttachmentID: r.space.attachmentIndex + 1,
+		TaskID:       id,
+		Name:         name,
+		Blob:         blob,
+		CreatedBy:    biz.ActorFromContext(ctx),
+		CreatedAt:    &nt,
+	}, nil
+}
+
+// attachmentDeleted checks that the attachment can be removed from the task, which
+// must be live.
+func (r *taskRepo) attachmentDeleted(id uint64, attachmentID uint64) error {
+	if _, err := r.current(id); err != nil {
+		return err
+	}
+	_, err := r.storedAttachment(id, attachmentID)
+	return err
+}
+
+// stage reads the content of a new attachment into the attachment store.
+func (d *Data) stage(ctx context.Context, content io.Reader) (biz.IStagedBlob, error) {
+	if d.blobs == nil {
+		return nil, model.ErrorAttachmentsDisabled(string(encoder.ATTACHMENTS_DISABLED))
+	}
+	return d.blobs.Stage(ctx, content)
+}
+
+// dropped returns the attachments the entry removes from the tenant, whose blobs
+// may then be left to no attachment. The caller must hold the write lock.
+func (d *Data) dropped(tenant string, e walEntry) []model.T_Attachment {
+	s := d.spaces[tenant]
+	if d.blobs == nil || s == nil {
+		return nil
+	}
+
+	switch e.Op {
+	case walOpEmpty, walOpDropTenant:
+		var result []model.T_Attachment
+		for _, attachments := range s.attachments {
+			result = append(result, attachments...)
+		}
+		return result
+	case walOpPurge:
+		return s.attachments[e.TaskID]
+	case walOpDropAttachment:
+		for _, a := range s.attachments[e.TaskID] {
+			if a.AttachmentID == e.AttachmentID {
+				return []model.T_Attachment{a}
+			}
+		}
+	}
+	return nil
+}
+
+// release removes the blobs of the attachments which no attachment of any tenant
+// refers to any more. Failures are only logged: the blobs left behind are swept
+// when the server next starts. The caller must hold the write lock.
+func (d *Data) release(attachments []model.T_Attachment) {
+	if len(attachments) == 0 {
+		return
+	}
+
+	referenced := d.referenced()
+	for _, a := range attachments {
+		if referenced[a.SHA256] {
+			continue
+		}
+		referenced[a.SHA256] = true
+		if err := d.blobs.Delete(a.SHA256); err != nil {
+			d.log.Errorf("failed to remove blob %s: %v", a.SHA256, err)
+		}
+	}
+}
+
+// sweep removes every blob which no attachment refers to, and returns how many it
+// removed. Those are left behind by a crash between storing or releasing a blob and
+// logging the change, and are all the blobs when the log is not configured.
+func (d *Data) sweep() (int, error) {
+	sums, err := d.blobs.List()
+	if err != nil {
+		return 0, err
+	}
+
+	referenced := d.referenced()
+	swept := 0
+	for _, sum := range sums {
+		if referenced[sum] {
+			continue
+		}
+		if err := d.blobs.Delete(sum); err != nil {
+			return swept, err
+		}
+		swept++
+	}
+	return swept, nil
+}
+
+// referenced returns the hashes of the blobs the attachments of every tenant refer to.
+func (d *Data) referenced() map[string]bool {
+	result := make(map[string]bool)
+	for _, s := range d.spaces {
+		for _, attachments := range s.attachments {
+			for _, a := range attachments {
+				result[a.SHA256] = true
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+package data_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/require"
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/conf"
+	"qantas.com/task/internal/data"
+	"qantas.com/task/model"
+)
+
+// blobs returns the files of the blobs in the attachment store at path.
+func blobs(t *testing.T, path string) []string {
+	files, err := filepath.Glob(filepath.Join(path, "*", "*"))
+	require.Nil(t, err)
+	return files
+}
+
+func Test_Attachments(t *testing.T) {
+	requires := require.New(t)
+	logger := log.NewFilter(log.With(log.NewStdLogger(os.Stdout)), log.FilterLevel(log.LevelError))
+	ctx := biz.WithActor(context.Background(), "alice")
+	path := t.TempDir()
+
+	dataRepo, cleanup, err := data.NewData(&conf.Data{Attachments: &conf.Data_Attachments{Path: path, MaxSize: 16}}, logger)
+	requires.Nil(err)
+	defer cleanup()
+	taskRepo := data.NewTaskRepo(dataRepo, logger)
+	for i := 0; i < 2; i++ {
+		_, err := taskRepo.Create(ctx, &model.Task{Name: "launch"})
+		requires.Nil(err)
+	}
+
+	a1, err := taskRepo.CreateAttachment(ctx, 1, "notes.txt", strings.NewReader("hello"))
+	requires.Nil(err)
+	sum := sha256.Sum256([]byte("hello"))
+	requires.Equal(uint64(1), a1.AttachmentID)
+	requires.Equal(hex.EncodeToString(sum[:]), a1.SHA256)
+	requires.Equal(int64(5), a1.Size)
+	requires.Equal("text/plain; charset=utf-8", a1.ContentType)
+	requires.Equal("alice", a1.CreatedBy)
+
+	// The same content attached twice is stored once, and its type comes from the
+	// extension when sniffing cannot tell it
+	a2, err := taskRepo.CreateAttachment(ctx, 2, "copy.txt", strings.NewReader("hello"))
+	requires.Nil(err)
+	requires.Equal(a1.SHA256, a2.SHA256)
+	a3, err := taskRepo.CreateAttachment(ctx, 2, "page.pdf", strings.NewReader("\x00\x01binary"))
+	requires.Nil(err)
+	requires.Equal("application/pdf", a3.ContentType)
+	requires.Equal(2, len(blobs(t, path)))
+
+	// Content over the limit and content for a task which is gone are not kept
+	_, err = taskRepo.CreateAttachment(ctx, 1, "big.txt", strings.NewReader(strings.Repeat("x", 17)))
+	requires.True(model.IsAttachmentTooLarge(err))
+	_, err = taskRepo.CreateAttachment(ctx, 9, "lost.txt", strings.NewReader("lost"))
+	requires.True(model.IsTaskNotFound(err))
+	requires.Equal(2, len(blobs(t, path)))
+	staged, err := filepath.Glob(filepath.Join(path, ".staged-*"))
+	requires.Nil(err)
+	requires.Empty(staged)
+
+	attachment, content, err := taskRepo.OpenAttachment(ctx, 1, a1.AttachmentID)
+	requires.Nil(err)
+	body, err := io.ReadAll(content)
+	requires.Nil(err)
+	requires.Nil(content.Close())
+	requires.Equal("hello", string(body))
+	requires.Equal(*a1, *attachment)
+	_, _, err = taskRepo.OpenAttachment(ctx, 2, a1.AttachmentID)
+	requires.True(model.IsAttachmentNotFound(err))
+
+	// A blob goes with the last attachment referring to it
+	requires.Nil(taskRepo.DeleteAttachment(ctx, 1, a1.AttachmentID))
+	requires.Equal(2, len(blobs(t, path)))
+	requires.Nil(taskRepo.Delete(ctx, 2))
+	requires.True(model.IsTaskNotFound(taskRepo.DeleteAttachment(ctx, 2, a2.AttachmentID)))
+	attachments, err := taskRepo.ListAttachments(ctx, 2)
+	requires.Nil(err)
+	requires.Equal([]model.T_Attachment{*a2, *a3}, attachments)
+	requires.Nil(taskRepo.Purge(ctx, 2))
+	requires.Empty(blobs(t, path))
+}
+
+func (s *DataSourceTestSuite) Test_Attachments_Disabled() {
+	s.createTasks(1)
+
+	_, err := s.taskRepo.CreateAttachment(s.context, 1, "notes.txt", strings.NewReader("hello"))
+	s.Require().True(model.IsAttachmentsDisabled(err))
+	attachments, err := s.taskRepo.ListAttachments(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Empty(attachments)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsAttachments() {
+	path := filepath.Join(s.T().TempDir(), "attachments")
+	s.conf.Attachments = &conf.Data_Attachments{Path: path}
+	taskRepo, _ := s.open()
+
+	_, err := taskRepo.Create(s.context, &model.Task{Name: "launch"})
+	s.Require().Nil(err)
+	for _, content := range []string{"checklist", "flight plan"} {
+		_, err = taskRepo.CreateAttachment(s.context, 1, "plan.txt", strings.NewReader(content))
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(taskRepo.DeleteAttachment(s.context, 1, 1))
+	s.Require().Equal(1, len(blobs(s.T(), path)))
+
+	// A blob no attachment refers to, as a crash leaves behind, is swept on restart
+	sum := sha256.Sum256([]byte("orphan"))
+	orphan := hex.EncodeToString(sum[:])
+	s.Require().Nil(os.MkdirAll(filepath.Join(path, orphan[:2]), 0o755))
+	s.Require().Nil(os.WriteFile(filepath.Join(path, orphan[:2], orphan), []byte("orphan"), 0o644))
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	attachments, err := taskRepo.ListAttachments(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(attachments))
+	s.Require().Equal(uint64(2), attachments[0].AttachmentID)
+	_, content, err := taskRepo.OpenAttachment(s.context, 1, 2)
+	s.Require().Nil(err)
+	body, err := io.ReadAll(content)
+	s.Require().Nil(err)
+	s.Require().Nil(content.Close())
+	s.Require().Equal("flight plan", string(body))
+	s.Require().Equal(1, len(blobs(s.T(), path)))
+
+	// The sequence carries on after the last attachment, not after the ones left
+	a, err := taskRepo.CreateAttachment(s.context, 1, "go.txt", strings.NewReader("go"))
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(3), a.AttachmentID)
+}
@@ -0,0 +1,258 @@
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/conf"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// defaultMaxAttachmentSize bounds the size of an attachment when the configuration does not.
+const defaultMaxAttachmentSize = 10 << 20
+
+// stagedPrefix starts the names of the files content is staged in.
+const stagedPrefix = ".staged-"
+
+// localBlobStore is the attachment store on the local disk. Every blob is a file
+// named by its hash, in a directory named by the first two characters of the hash,
+// and content is staged in files next to those directories.
+type localBlobStore struct {
+	path    string
+	maxSize int64
+}
+
+func openBlobStore(c *conf.Data_Attachments) (*localBlobStore, error) {
+	maxSize := c.GetMaxSize()
+	if maxSize == 0 {
+		maxSize = defaultMaxAttachmentSize
+	}
+
+	if err := os.MkdirAll(c.GetPath(), 0o755); err != nil {
+		return nil, fmt.Errorf("open attachment store %s: %w", c.GetPath(), err)
+	}
+	// Content staged when the server stopped belongs to no attachment
+	staged, err := filepath.Glob(filepath.Join(c.GetPath(), stagedPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range staged {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("open attachment store %s: %w", c.GetPath(), err)
+		}
+	}
+
+	return &localBlobStore{path: c.GetPath(), maxSize: maxSize}, nil
+}
+
+func (s *localBlobStore) Stage(ctx context.Context, r io.Reader) (biz.IStagedBlob, error) {
+	file, err := os.CreateTemp(s.path, stagedPrefix+"*")
+	if err != nil {
+		return nil, model.ErrorTaskStorageError("%s: %v", encoder.TASK_STORAGE_FAILURE, err)
+	}
+	staged := &stagedBlob{store: s, file: file.Name()}
+
+	hash := sha256.New()
+	head := &sniffer{}
+	src := &contextReader{ctx: ctx, r: r}
+	n, err := io.Copy(io.MultiWriter(file, hash, head), io.LimitReader(src, s.maxSize+1))
+	// The content must be on the disk before an attachment which is logged refers to it
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	switch {
+	case src.err != nil:
+		staged.Discard()
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, model.ErrorAttachmentInvalid("%s: %v", encoder.ATTACHMENT_INVALID, src.err)
+	case err != nil:
+		staged.Discard()
+		return nil, model.ErrorTaskStorageError("%s: %v", encoder.TASK_STORAGE_FAILURE, err)
+	case n > s.maxSize:
+		staged.Discard()
+		return nil, model.ErrorAttachmentTooLarge("%s: the limit is %d bytes", encoder.ATTACHMENT_TOO_LARGE, s.maxSize)
+	}
+
+	staged.blob = model.Blob{
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		Size:        n,
+		ContentType: http.DetectContentType(head.buf),
+	}
+	return staged, nil
+}
+
+func (s *localBlobStore) Open(sum string) (io.ReadSeekCloser, error) {
+	if !validSum(sum) {
+		return nil, model.ErrorAttachmentNotFound("%s: blob %s", encoder.ATTACHMENT_NOT_EXIST, sum)
+	}
+	file, err := os.Open(s.blobPath(sum))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, model.ErrorAttachmentNotFound("%s: blob %s", encoder.ATTACHMENT_NOT_EXIST, sum)
+	}
+	if err != nil {
+		return nil, model.ErrorTaskStorageError("%s: %v", encoder.TASK_STORAGE_FAILURE, err)
+	}
+	return file, nil
+}
+
+func (s *localBlobStore) Delete(sum string) error {
+	if !validSum(sum) {
+		return nil
+	}
+	if err := os.Remove(s.blobPath(sum)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	// The directory only goes once it is empty
+	_ = os.Remove(filepath.Dir(s.blobPath(sum)))
+	return nil
+}
+
+func (s *localBlobStore) List() ([]string, error) {
+	dirs, err := os.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, dir := range dirs {
+		if !dir.IsDir() || len(dir.Name()) != 2 {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(s.path, dir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if validSum(f.Name()) && f.Name()[:2] == dir.Name() {
+				result = append(result, f.Name())
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *localBlobStore) blobPath(sum string) string {
+	return filepath.Join(s.path, sum[:2], sum)
+}
+
+// validSum reports whether the hash is the lowercase hex SHA-256 blobs are named by,
+// which keeps hashes from naming files outside the store.
+func validSum(sum string) bool {
+	if len(sum) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range sum {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// stagedBlob is content staged in a file of a localBlobStore.
+type stagedBlob struct {
+	store  *localBlobStore
+	file   string
+	blob   model.Blob
+	stored bool
+}
+
+func (b *stagedBlob) Blob() model.Blob {
+	return b.blob
+}
+
+func (b *stagedBlob) Store() error {
+	if b.stored {
+		return nil
+	}
+	path := b.store.blobPath(b.blob.SHA256)
+	if _, err := os.Stat(path); err == nil {
+		b.stored = true
+		return b.remove()
+	}
+	dir := filepath.Dir(path)
+	_, err := os.Stat(dir)
+	created := errors.Is(err, fs.ErrNotExist)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(b.file, path); err != nil {
+		return err
+	}
+	b.stored = true
+
+	// As in wal.compact, the rename is only durable once the directory is synced,
+	// and so is a directory just made in the store
+	if err := syncDir(dir); err != nil {
+		return err
+	}
+	if created {
+		return syncDir(b.store.path)
+	}
+	return nil
+}
+
+func (b *stagedBlob) Discard() error {
+	if b.stored {
+		return nil
+	}
+	return b.remove()
+}
+
+func (b *stagedBlob) remove() error {
+	if err := os.Remove(b.file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// sniffer keeps the head of the content written to it, as much as sniffing its
+// type looks at.
+type sniffer struct {
+	buf []byte
+}
+
+func (s *sniffer) Write(p []byte) (int, error) {
+	if room := 512 - len(s.buf); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		s.buf = append(s.buf, p[:room]...)
+	}
+	return len(p), nil
+}
+
+// contextReader reads from r until the context is done, and records the error
+// reading ends with, which tells the errors of the client from those of the disk.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+	err error
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		c.err = err
+		return 0, err
+	}
+	n, err := c.r.Read(p)
+	if err != nil && err != io.EOF {
+		c.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,169 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) CreateComment(ctx context.Context, id uint64, c *model.Comment) (*model.T_Comment, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.commentCreated(ctx, id, c)
+	if err != nil {
+		return nil, err
+	}
+	r.data.apply(walEntry{Op: walOpComment, Tenant: r.tenant, Comment: &val})
+
+	return &val, nil
+}
+
+func (r *taskRepo) GetComment(ctx context.Context, id uint64, commentID uint64) (*model.T_Comment, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.storedComment(id, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &val, nil
+}
+
+func (r *taskRepo) ListComments(ctx context.Context, id uint64) ([]model.T_Comment, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Task not exist
+	if _, ok := r.space.tasks[id]; !ok {
+		return nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	return append([]model.T_Comment{}, r.space.comments[id]...), nil
+}
+
+func (r *taskRepo) UpdateComment(ctx context.Context, id uint64, commentID uint64, c *model.Comment, version uint64) (*model.T_Comment, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.commentUpdated(id, commentID, c, version)
+	if err != nil {
+		return nil, err
+	}
+	r.data.apply(walEntry{Op: walOpComment, Tenant: r.tenant, Comment: &val})
+
+	return &val, nil
+}
+
+func (r *taskRepo) DeleteComment(ctx context.Context, id uint64, commentID uint64, version uint64) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.commentUpdated(id, commentID, nil, version); err != nil {
+		return err
+	}
+	r.data.apply(walEntry{Op: walOpDropComment, Tenant: r.tenant, TaskID: id, CommentID: commentID})
+
+	return nil
+}
+
+// storedComment returns the stored comment on the task.
+func (r *taskRepo) storedComment(id uint64, commentID uint64) (model.T_Comment, error) {
+	// Task not exist
+	if _, ok := r.space.tasks[id]; !ok {
+		return model.T_Comment{}, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	for _, c := range r.space.comments[id] {
+		if c.CommentID == commentID {
+			return c, nil
+		}
+	}
+
+	// Comment not exist
+	return model.T_Comment{}, model.ErrorCommentNotFound("%s: %d", encoder.COMMENT_NOT_EXIST, commentID)
+}
+
+// commentCreated returns the record of a new comment on the task, provided the
+// task is live, with the next ID of the comment sequence.
+func (r *taskRepo) commentCreated(ctx context.Context, id uint64, c *model.Comment) (model.T_Comment, error) {
+	if _, err := r.current(id); err != nil {
+		return model.T_Comment{}, err
+	}
+
+	nt := time.Now()
+	return model.T_Comment{
+		CommentID: r.space.commentIndex + 1,
+		TaskID:    id,
+		Comment:   *c,
+		Author:    biz.ActorFromContext(ctx),
+		CreatedAt: &nt,
+		Version:   1,
+	}, nil
+}
+
+// commentUpdated returns the stored comment with c applied over it, provided its
+// task is live and it is still at the given version. A version of 0 skips the
+// check, and a nil c only checks.
+func (r *taskRepo) commentUpdated(id uint64, commentID uint64, c *model.Comment, version uint64) (model.T_Comment, error) {
+	if _, err := r.current(id); err != nil {
+		return model.T_Comment{}, err
+	}
+	val, err := r.storedComment(id, commentID)
+	if err != nil {
+		return model.T_Comment{}, err
+	}
+
+	// Comment has been changed since the client read it
+	if version != 0 && version != val.Version {
+		return model.T_Comment{}, model.ErrorCommentVersionMismatch(string(encoder.COMMENT_MODIFIED))
+	}
+
+	if c != nil {
+		val.Comment = *c
+		nt := time.Now()
+		val.UpdatedAt = &nt
+		val.Version++
+	}
+
+	return val, nil
+}
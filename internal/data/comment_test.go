@@ -0,0 +1,86 @@
+package data_test
+
+import (
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) Test_Comments() {
+	s.createTasks(2)
+	ctx := biz.WithActor(s.context, "alice")
+
+	c1, err := s.taskRepo.CreateComment(ctx, 1, &model.Comment{Body: "first", Mentions: []string{"bob"}})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(1), c1.CommentID)
+	s.Require().Equal("alice", c1.Author)
+	s.Require().Equal(uint64(1), c1.Version)
+	// Comment IDs are shared by the tasks of a tenant
+	c2, err := s.taskRepo.CreateComment(ctx, 2, &model.Comment{Body: "other"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(2), c2.CommentID)
+	c3, err := s.taskRepo.CreateComment(ctx, 1, &model.Comment{Body: "second"})
+	s.Require().Nil(err)
+
+	_, err = s.taskRepo.GetComment(s.context, 2, c1.CommentID)
+	s.Require().True(model.IsCommentNotFound(err))
+	_, err = s.taskRepo.CreateComment(ctx, 9, &model.Comment{Body: "nowhere"})
+	s.Require().True(model.IsTaskNotFound(err))
+
+	_, err = s.taskRepo.UpdateComment(s.context, 1, c1.CommentID, &model.Comment{Body: "first!"}, 2)
+	s.Require().True(model.IsCommentVersionMismatch(err))
+	c1, err = s.taskRepo.UpdateComment(s.context, 1, c1.CommentID, &model.Comment{Body: "first!"}, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(2), c1.Version)
+	s.Require().NotNil(c1.UpdatedAt)
+	s.Require().Equal("alice", c1.Author)
+
+	s.Require().Nil(s.taskRepo.DeleteComment(s.context, 1, c3.CommentID, 0))
+	comments, err := s.taskRepo.ListComments(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal([]model.T_Comment{*c1}, comments)
+
+	// The comments of a trashed task can be read but not changed, and go with the task when it is purged
+	s.Require().Nil(s.taskRepo.Delete(s.context, 1))
+	_, err = s.taskRepo.CreateComment(ctx, 1, &model.Comment{Body: "late"})
+	s.Require().True(model.IsTaskNotFound(err))
+	s.Require().True(model.IsTaskNotFound(s.taskRepo.DeleteComment(s.context, 1, c1.CommentID, 0)))
+	comments, err = s.taskRepo.ListComments(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(comments))
+
+	s.Require().Nil(s.taskRepo.Purge(s.context, 1))
+	_, err = s.taskRepo.ListComments(s.context, 1)
+	s.Require().True(model.IsTaskNotFound(err))
+	comments, err = s.taskRepo.ListComments(s.context, 2)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(comments))
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsComments() {
+	taskRepo, _ := s.open()
+
+	_, err := taskRepo.Create(s.context, &model.Task{Name: "launch"})
+	s.Require().Nil(err)
+	for _, body := range []string{"go", "no go", "go!"} {
+		_, err = taskRepo.CreateComment(s.context, 1, &model.Comment{Body: body})
+		s.Require().Nil(err)
+	}
+	_, err = taskRepo.UpdateComment(s.context, 1, 3, &model.Comment{Body: "go for launch"}, 1)
+	s.Require().Nil(err)
+	s.Require().Nil(taskRepo.DeleteComment(s.context, 1, 2, 0))
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	comments, err := taskRepo.ListComments(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(comments))
+	s.Require().Equal("go", comments[0].Body)
+	s.Require().Equal("go for launch", comments[1].Body)
+	s.Require().Equal(uint64(2), comments[1].Version)
+
+	// The sequence carries on after the last comment, not after the ones left
+	c, err := taskRepo.CreateComment(s.context, 1, &model.Comment{Body: "liftoff"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(4), c.CommentID)
+}
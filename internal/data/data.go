@@ -1,21 +1,506 @@
 package data
 
 import (
+	"context"
+	"sort"
+	"sync"
+
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/google/wire"
+	"qantas.com/task/internal/biz"
 	"qantas.com/task/internal/conf"
+	"qantas.com/task/internal/encoder"
 	"qantas.com/task/model"
 )
 
 var ProviderSet = wire.NewSet(NewData, NewTaskRepo)
 
 type Data struct {
-	tasks map[uint64]model.T_Task
+	// mu guards every field up to wal. Repos hold the write lock for the whole
+	// read-modify-write of a mutation, so ID allocation and the log append
+	// happen atomically with the change they belong to.
+	mu rwLock
+	// tenants holds every tenant, the default one included, and spaces what each
+	// of them stores.
+	tenants map[string]model.T_Tenant
+	spaces  map[string]*space
+	wal     *wal
+	// blobs keeps the content of attachments, and is nil when they are not enabled.
+	// It is only set once the log has been replayed, so that replaying never
+	// removes a blob.
+	blobs biz.IAttachmentStore
+	log   *log.Helper
+
+	trash     *conf.Data_Trash
+	retention sync.Once
+	stop      chan struct{}
+	jobs      sync.WaitGroup
+}
+
+// space holds the tasks, series, projects, comments and attachments of a tenant,
+// along with their indexes and ID sequences. The spaces of the tenants share nothing.
+type space struct {
+	tasks     map[uint64]model.T_Task
+	revisions map[uint64][]model.T_Revision
+	// children indexes the IDs of the subtasks of every task, deleted ones included.
+	children map[uint64]map[uint64]struct{}
+	// tags indexes the IDs of the tasks carrying every tag, deleted ones included.
+	tags map[string]map[uint64]struct{}
+	// users indexes the IDs of the tasks every user created, owns or is assigned,
+	// deleted ones included.
+	users map[string]map[uint64]struct{}
+	// text is the full-text index of the names and contents of the live tasks.
+	text  *textIndex
+	index uint64
+	// series holds the recurring tasks, and occurrences indexes the IDs of the tasks
+	// of every series, deleted ones included.
+	series      map[uint64]model.T_Series
+	occurrences map[uint64]map[uint64]struct{}
+	seriesIndex uint64
+	// projects holds the projects, and boards indexes the IDs of the tasks placed on
+	// the board of every project, deleted ones included.
+	projects map[string]model.T_Project
+	boards   map[string]map[uint64]struct{}
+	// comments holds the comments on every task, oldest first, deleted tasks included.
+	comments     map[uint64][]model.T_Comment
+	commentIndex uint64
+	// attachments holds the attachments of every task, oldest first, deleted tasks included.
+	attachments     map[uint64][]model.T_Attachment
+	attachmentIndex uint64
+}
+
+func newSpace() *space {
+	return &space{
+		tasks:       make(map[uint64]model.T_Task),
+		revisions:   make(map[uint64][]model.T_Revision),
+		children:    make(map[uint64]map[uint64]struct{}),
+		tags:        make(map[string]map[uint64]struct{}),
+		users:       make(map[string]map[uint64]struct{}),
+		text:        newTextIndex(),
+		series:      make(map[uint64]model.T_Series),
+		occurrences: make(map[uint64]map[uint64]struct{}),
+		projects:    make(map[string]model.T_Project),
+		boards:      make(map[string]map[uint64]struct{}),
+		comments:    make(map[uint64][]model.T_Comment),
+		attachments: make(map[uint64][]model.T_Attachment),
+	}
 }
 
 func NewData(c *conf.Data, logger log.Logger) (*Data, func(), error) {
+	helper := log.NewHelper(logger)
+	d := &Data{
+		tenants: map[string]model.T_Tenant{biz.DefaultTenant: {Tenant: model.Tenant{TenantID: biz.DefaultTenant}}},
+		spaces:  map[string]*space{biz.DefaultTenant: newSpace()},
+		trash:   c.GetTrash(),
+		stop:    make(chan struct{}),
+		log:     helper,
+	}
+
+	var blobs *localBlobStore
+	if c.GetAttachments().GetPath() != "" {
+		var err error
+		if blobs, err = openBlobStore(c.GetAttachments()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if c.GetWal().GetPath() != "" {
+		w, err := openWAL(c.GetWal(), logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := d.restore(w); err != nil {
+			w.close()
+			return nil, nil, err
+		}
+		d.wal = w
+		tasks := 0
+		for _, s := range d.spaces {
+			tasks += len(s.tasks)
+		}
+		helper.Infof("restored %d tasks of %d tenants from %s", tasks, len(d.tenants), w.path)
+	}
+
+	if blobs != nil {
+		d.blobs = blobs
+		swept, err := d.sweep()
+		if err != nil {
+			helper.Errorf("failed to sweep the attachment store: %v", err)
+		} else if swept > 0 {
+			helper.Infof("removed %d blobs no attachment refers to from %s", swept, blobs.path)
+		}
+	}
+
 	cleanup := func() {
-		log.NewHelper(logger).Info("closing the data resources")
+		helper.Info("closing the data resources")
+		close(d.stop)
+		d.jobs.Wait()
+
+		if d.wal != nil {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+
+			if err := d.wal.compact(d.snapshot()); err != nil {
+				helper.Errorf("failed to compact the wal: %v", err)
+			}
+			if err := d.wal.close(); err != nil {
+				helper.Errorf("failed to close the wal: %v", err)
+			}
+		}
+	}
+	return d, cleanup, nil
+}
+
+// lock takes the write lock for a repo operation. The operation is abandoned when
+// its context is canceled or expires, whether it is still waiting for the lock or
+// holds it by then.
+func (d *Data) lock(ctx context.Context) error {
+	if err := d.mu.lock(ctx); err != nil {
+		return contextError(ctx)
+	}
+	if err := contextError(ctx); err != nil {
+		d.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// rlock is lock for operations which only read.
+func (d *Data) rlock(ctx context.Context) error {
+	if err := d.mu.rlock(ctx); err != nil {
+		return contextError(ctx)
+	}
+	if err := contextError(ctx); err != nil {
+		d.mu.RUnlock()
+		return err
+	}
+	return nil
+}
+
+// contextError reports a context which is done as a task error.
+func contextError(ctx context.Context) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return model.ErrorTaskDbTimeout(string(encoder.TASK_DATABASE_TIMEOUT))
+	default:
+		return model.ErrorRequestCanceled(string(encoder.REQUEST_CANCELED))
+	}
+}
+
+// apply performs a logged mutation on the in-memory state.
+func (d *Data) apply(e walEntry) {
+	tenant := e.Tenant
+	// The entries logged before there were tenants belong to the default one
+	if tenant == "" {
+		tenant = biz.DefaultTenant
+	}
+
+	dropped := d.dropped(tenant, e)
+	defer d.release(dropped)
+
+	switch e.Op {
+	case walOpTenant:
+		d.tenants[tenant] = *e.TenantRecord
+		if d.spaces[tenant] == nil {
+			d.spaces[tenant] = newSpace()
+		}
+	case walOpDropTenant:
+		delete(d.tenants, tenant)
+		delete(d.spaces, tenant)
+	default:
+		if s := d.spaces[tenant]; s != nil {
+			s.apply(e)
+		}
+	}
+}
+
+// apply performs a logged mutation of the tasks, series, projects, comments or
+// attachments of the space.
+func (s *space) apply(e walEntry) {
+	switch e.Op {
+	case walOpPut:
+		s.put(e)
+	case walOpSeries:
+		s.series[e.Series.SeriesID] = *e.Series
+		if e.Series.SeriesID > s.seriesIndex {
+			s.seriesIndex = e.Series.SeriesID
+		}
+		if e.Task != nil {
+			s.put(e)
+		}
+	case walOpProject:
+		s.projects[e.Project.ProjectID] = *e.Project
+	case walOpDropProject:
+		delete(s.projects, e.ProjectID)
+	case walOpComment:
+		s.putComment(*e.Comment)
+	case walOpDropComment:
+		comments := s.comments[e.TaskID]
+		for i, c := range comments {
+			if c.CommentID == e.CommentID {
+				s.comments[e.TaskID] = append(comments[:i:i], comments[i+1:]...)
+				break
+			}
+		}
+	case walOpAttachment:
+		s.putAttachment(*e.Attachment)
+	case walOpDropAttachment:
+		attachments := s.attachments[e.TaskID]
+		for i, a := range attachments {
+			if a.AttachmentID == e.AttachmentID {
+				s.attachments[e.TaskID] = append(attachments[:i:i], attachments[i+1:]...)
+				break
+			}
+		}
+	case walOpEmpty:
+		// Projects are not tasks, so they outlive the tasks on their boards
+		projects := s.projects
+		*s = *newSpace()
+		s.projects = projects
+	case walOpPurge:
+		if old, ok := s.tasks[e.TaskID]; ok {
+			s.unlink(old)
+		}
+		delete(s.tasks, e.TaskID)
+		delete(s.revisions, e.TaskID)
+		delete(s.comments, e.TaskID)
+		delete(s.attachments, e.TaskID)
+	}
+}
+
+// putAttachment stores the attachment, unless it is stored already. Attachments
+// do not change once stored.
+func (s *space) putAttachment(a model.T_Attachment) {
+	if a.AttachmentID > s.attachmentIndex {
+		s.attachmentIndex = a.AttachmentID
+	}
+	for _, stored := range s.attachments[a.TaskID] {
+		if stored.AttachmentID == a.AttachmentID {
+			return
+		}
+	}
+	s.attachments[a.TaskID] = append(s.attachments[a.TaskID], a)
+}
+
+// putComment stores the comment, in place of the one with its ID if there is one.
+func (s *space) putComment(c model.T_Comment) {
+	if c.CommentID > s.commentIndex {
+		s.commentIndex = c.CommentID
+	}
+	comments := s.comments[c.TaskID]
+	for i := range comments {
+		if comments[i].CommentID == c.CommentID {
+			// The stored slice is shared with readers, so it is copied rather than changed
+			comments = append([]model.T_Comment{}, comments...)
+			comments[i] = c
+			s.comments[c.TaskID] = comments
+			return
+		}
+	}
+	s.comments[c.TaskID] = append(comments, c)
+}
+
+// put stores the task of the entry, along with its revision.
+func (s *space) put(e walEntry) {
+	if old, ok := s.tasks[e.Task.TaskID]; ok {
+		s.unlink(old)
+	}
+	s.tasks[e.Task.TaskID] = *e.Task
+	s.link(*e.Task)
+	if e.Task.TaskID > s.index {
+		s.index = e.Task.TaskID
+	}
+	// A replayed entry which the snapshot already holds is not recorded twice
+	revs := s.revisions[e.Task.TaskID]
+	if e.Revision != nil && (len(revs) == 0 || revs[len(revs)-1].Revision < e.Revision.Revision) {
+		s.revisions[e.Task.TaskID] = append(revs, *e.Revision)
+	}
+}
+
+// link adds the task to the children index of its parent, to the tag and user
+// indexes, to the occurrences of its series, to the board of its project and, unless
+// it has been deleted, to the full-text index.
+func (s *space) link(t model.T_Task) {
+	if t.DeletedAt == nil {
+		s.text.add(t)
+	}
+
+	if t.ProjectID != "" {
+		if s.boards[t.ProjectID] == nil {
+			s.boards[t.ProjectID] = make(map[uint64]struct{})
+		}
+		s.boards[t.ProjectID][t.TaskID] = struct{}{}
+	}
+
+	if t.SeriesID != 0 {
+		if s.occurrences[t.SeriesID] == nil {
+			s.occurrences[t.SeriesID] = make(map[uint64]struct{})
+		}
+		s.occurrences[t.SeriesID][t.TaskID] = struct{}{}
+	}
+
+	for _, tag := range t.Tags {
+		if s.tags[tag] == nil {
+			s.tags[tag] = make(map[uint64]struct{})
+		}
+		s.tags[tag][t.TaskID] = struct{}{}
+	}
+
+	for _, user := range taskUsers(t) {
+		if s.users[user] == nil {
+			s.users[user] = make(map[uint64]struct{})
+		}
+		s.users[user][t.TaskID] = struct{}{}
+	}
+
+	if t.ParentID == 0 {
+		return
+	}
+	if s.children[t.ParentID] == nil {
+		s.children[t.ParentID] = make(map[uint64]struct{})
+	}
+	s.children[t.ParentID][t.TaskID] = struct{}{}
+}
+
+// unlink removes the task from the children index of its parent, the tag and user
+// indexes, the occurrences of its series, the board of its project and the
+// full-text index.
+func (s *space) unlink(t model.T_Task) {
+	s.text.remove(t.TaskID)
+
+	if t.ProjectID != "" {
+		delete(s.boards[t.ProjectID], t.TaskID)
+		if len(s.boards[t.ProjectID]) == 0 {
+			delete(s.boards, t.ProjectID)
+		}
+	}
+
+	if t.SeriesID != 0 {
+		delete(s.occurrences[t.SeriesID], t.TaskID)
+		if len(s.occurrences[t.SeriesID]) == 0 {
+			delete(s.occurrences, t.SeriesID)
+		}
+	}
+
+	for _, tag := range t.Tags {
+		delete(s.tags[tag], t.TaskID)
+		if len(s.tags[tag]) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+
+	for _, user := range taskUsers(t) {
+		delete(s.users[user], t.TaskID)
+		if len(s.users[user]) == 0 {
+			delete(s.users, user)
+		}
+	}
+
+	if t.ParentID == 0 {
+		return
+	}
+	delete(s.children[t.ParentID], t.TaskID)
+	if len(s.children[t.ParentID]) == 0 {
+		delete(s.children, t.ParentID)
+	}
+}
+
+func (d *Data) restore(w *wal) error {
+	s, err := w.readSnapshot()
+	if err != nil {
+		return err
+	}
+	if s != nil {
+		d.spaces[biz.DefaultTenant].load(s.walSpace)
+		for _, t := range s.Tenants {
+			d.tenants[t.Tenant.TenantID] = t.Tenant
+			d.spaces[t.Tenant.TenantID] = newSpace()
+			d.spaces[t.Tenant.TenantID].load(t.walSpace)
+		}
+	}
+	return w.replay(d.apply)
+}
+
+// load fills the space in from a snapshot of it.
+func (s *space) load(snapshot walSpace) {
+	s.index = snapshot.Index
+	for _, t := range snapshot.Tasks {
+		s.tasks[t.TaskID] = t
+		s.link(t)
+	}
+	for _, rev := range snapshot.Revisions {
+		s.revisions[rev.TaskID] = append(s.revisions[rev.TaskID], rev)
+	}
+	s.seriesIndex = snapshot.SeriesIndex
+	for _, series := range snapshot.Series {
+		s.series[series.SeriesID] = series
+	}
+	for _, p := range snapshot.Projects {
+		s.projects[p.ProjectID] = p
+	}
+	s.commentIndex = snapshot.CommentIndex
+	for _, c := range snapshot.Comments {
+		s.comments[c.TaskID] = append(s.comments[c.TaskID], c)
+	}
+	s.attachmentIndex = snapshot.AttachmentIndex
+	for _, a := range snapshot.Attachments {
+		s.attachments[a.TaskID] = append(s.attachments[a.TaskID], a)
+	}
+}
+
+// snapshot folds every tenant into a snapshot. The default tenant is kept at the
+// top of it, where the snapshots written before there were tenants have it.
+func (d *Data) snapshot() walSnapshot {
+	s := walSnapshot{walSpace: d.spaces[biz.DefaultTenant].snapshot()}
+	for _, id := range d.tenantIDs() {
+		if id != biz.DefaultTenant {
+			s.Tenants = append(s.Tenants, walTenant{Tenant: d.tenants[id], walSpace: d.spaces[id].snapshot()})
+		}
+	}
+	return s
+}
+
+func (s *space) snapshot() walSpace {
+	snapshot := walSpace{Index: s.index, Tasks: make([]model.T_Task, 0, len(s.tasks))}
+	for _, t := range s.tasks {
+		snapshot.Tasks = append(snapshot.Tasks, t)
+	}
+	sort.Slice(snapshot.Tasks, func(i, j int) bool { return snapshot.Tasks[i].TaskID < snapshot.Tasks[j].TaskID })
+	for _, t := range snapshot.Tasks {
+		snapshot.Revisions = append(snapshot.Revisions, s.revisions[t.TaskID]...)
+	}
+
+	snapshot.SeriesIndex = s.seriesIndex
+	for _, series := range s.series {
+		snapshot.Series = append(snapshot.Series, series)
+	}
+	sort.Slice(snapshot.Series, func(i, j int) bool { return snapshot.Series[i].SeriesID < snapshot.Series[j].SeriesID })
+
+	for _, p := range s.projects {
+		snapshot.Projects = append(snapshot.Projects, p)
+	}
+	sort.Slice(snapshot.Projects, func(i, j int) bool { return snapshot.Projects[i].ProjectID < snapshot.Projects[j].ProjectID })
+
+	snapshot.CommentIndex = s.commentIndex
+	for _, t := range snapshot.Tasks {
+		snapshot.Comments = append(snapshot.Comments, s.comments[t.TaskID]...)
+	}
+
+	snapshot.AttachmentIndex = s.attachmentIndex
+	for _, t := range snapshot.Tasks {
+		snapshot.Attachments = append(snapshot.Attachments, s.attachments[t.TaskID]...)
+	}
+	return snapshot
+}
+
+// tenantIDs returns the IDs of every tenant, in order.
+func (d *Data) tenantIDs() []string {
+	ids := make([]string, 0, len(d.tenants))
+	for id := range d.tenants {
+		ids = append(ids, id)
 	}
-	return &Data{tasks: make(map[uint64]model.T_Task)}, cleanup, nil
+	sort.Strings(ids)
+	return ids
 }
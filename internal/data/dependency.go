@@ -0,0 +1,239 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) AddDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, changed, err := r.dependencyAdded(id, dependsOn)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		r.data.apply(r.put(ctx, val, model.RevisionUpdated))
+	}
+
+	return &val, nil
+}
+
+func (r *taskRepo) RemoveDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, changed, err := r.dependencyRemoved(id, dependsOn)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		r.data.apply(r.put(ctx, val, model.RevisionUpdated))
+	}
+
+	return &val, nil
+}
+
+func (r *taskRepo) TopologicalOrder(ctx context.Context) ([]model.T_Task, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kahn's algorithm, always taking the lowest ID among the tasks whose
+	// dependencies have all been placed, so the order is deterministic
+	pending := map[uint64]int{}
+	dependents := map[uint64][]uint64{}
+	for id, task := range r.space.tasks {
+		if task.DeletedAt != nil {
+			continue
+		}
+		pending[id] += 0
+		for _, dep := range task.DependsOn {
+			if r.live(dep) {
+				pending[id]++
+				dependents[dep] = append(dependents[dep], id)
+			}
+		}
+	}
+
+	var next []uint64
+	for id, n := range pending {
+		if n == 0 {
+			next = append(next, id)
+		}
+	}
+
+	result := make([]model.T_Task, 0, len(pending))
+	for len(next) > 0 {
+		sort.Slice(next, func(i, j int) bool { return next[i] < next[j] })
+		id := next[0]
+		next = next[1:]
+		result = append(result, r.space.tasks[id])
+
+		for _, dependent := range dependents[id] {
+			if pending[dependent]--; pending[dependent] == 0 {
+				next = append(next, dependent)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// dependencyAdded returns the stored record depending on one more task, and
+// whether it changed. A dependency which would close a cycle is rejected, with
+// deleted tasks taken into account since they can be restored.
+func (r *taskRepo) dependencyAdded(id uint64, dependsOn uint64) (model.T_Task, bool, error) {
+	val, err := r.current(id)
+	if err != nil {
+		return model.T_Task{}, false, err
+	}
+
+	if dependsOn == id {
+		return model.T_Task{}, false, model.ErrorTaskInvalidDependency("%s: task %d cannot depend on itself", encoder.TASK_DEPENDENCY_INVALID, id)
+	}
+	if !r.live(dependsOn) {
+		return model.T_Task{}, false, model.ErrorTaskInvalidDependency("%s: task %d does not exist", encoder.TASK_DEPENDENCY_INVALID, dependsOn)
+	}
+
+	i := sort.Search(len(val.DependsOn), func(i int) bool { return val.DependsOn[i] >= dependsOn })
+	if i < len(val.DependsOn) && val.DependsOn[i] == dependsOn {
+		return val, false, nil
+	}
+	if r.dependsOn(dependsOn, id) {
+		return model.T_Task{}, false, model.ErrorTaskDependencyCycle("%s: task %d already depends on task %d", encoder.TASK_DEPENDENCY_CYCLE, dependsOn, id)
+	}
+
+	deps := make([]uint64, 0, len(val.DependsOn)+1)
+	deps = append(deps, val.DependsOn[:i]...)
+	deps = append(deps, dependsOn)
+	val.DependsOn = append(deps, val.DependsOn[i:]...)
+
+	r.touch(&val)
+	return val, true, nil
+}
+
+// dependencyRemoved returns the stored record without the given dependency, and
+// whether it changed.
+func (r *taskRepo) dependencyRemoved(id uint64, dependsOn uint64) (model.T_Task, bool, error) {
+	val, err := r.current(id)
+	if err != nil {
+		return model.T_Task{}, false, err
+	}
+
+	deps := make([]uint64, 0, len(val.DependsOn))
+	for _, dep := range val.DependsOn {
+		if dep != dependsOn {
+			deps = append(deps, dep)
+		}
+	}
+	if len(deps) == len(val.DependsOn) {
+		return val, false, nil
+	}
+	if len(deps) == 0 {
+		deps = nil
+	}
+	val.DependsOn = deps
+
+	r.touch(&val)
+	return val, true, nil
+}
+
+// current returns the stored record of a live task.
+func (r *taskRepo) current(id uint64) (model.T_Task, error) {
+	val, ok := r.space.tasks[id]
+
+	// Task not exist
+	if !ok {
+		return model.T_Task{}, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	// Task has been deleted
+	if val.DeletedAt != nil {
+		return model.T_Task{}, model.ErrorTaskNotFound(string(encoder.TASK_DELETED))
+	}
+
+	return val, nil
+}
+
+// touch marks the record as changed now.
+func (r *taskRepo) touch(val *model.T_Task) {
+	nt := time.Now()
+	val.UpdatedAt = &nt
+	val.Version++
+}
+
+// dependsOn reports whether a task depends on another one, directly or through
+// other tasks. The caller must hold the lock.
+func (r *taskRepo) dependsOn(id uint64, target uint64) bool {
+	visited := map[uint64]bool{id: true}
+	stack := []uint64{id}
+	for len(stack) > 0 {
+		task := r.space.tasks[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		for _, dep := range task.DependsOn {
+			if dep == target {
+				return true
+			}
+			if !visited[dep] {
+				visited[dep] = true
+				stack = append(stack, dep)
+			}
+		}
+	}
+	return false
+}
+
+// live reports whether the task exists and has not been deleted.
+func (r *taskRepo) live(id uint64) bool {
+	task, ok := r.space.tasks[id]
+	return ok && task.DeletedAt == nil
+}
+
+// ready reports whether every live dependency of the task is done, and the task
+// itself is still to be finished.
+func (r *taskRepo) ready(task *model.T_Task) bool {
+	switch task.GetStatus() {
+	case model.StatusDone, model.StatusCancelled:
+		return false
+	}
+	return len(r.pending(task)) == 0
+}
+
+// pending returns the IDs of the live dependencies of the task which are not done
+// yet. Deleted dependencies no longer hold it up.
+func (r *taskRepo) pending(task *model.T_Task) []string {
+	var result []string
+	for _, dep := range task.DependsOn {
+		if d, ok := r.space.tasks[dep]; ok && d.DeletedAt == nil && d.GetStatus() != model.StatusDone {
+			result = append(result, fmt.Sprint(dep))
+		}
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+package data_test
+
+import (
+	"github.com/go-kratos/kratos/v2/errors"
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) createTasks(n int) {
+	for i := 1; i <= n; i++ {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: "task"})
+		s.Require().Nil(err)
+	}
+}
+
+func (s *DataSourceTestSuite) Test_AddDependency() {
+	s.createTasks(3)
+
+	task, err := s.taskRepo.AddDependency(s.context, 1, 3)
+	s.Require().Nil(err)
+	task, err = s.taskRepo.AddDependency(s.context, 1, 2)
+	s.Require().Nil(err)
+	s.Require().Equal([]uint64{2, 3}, task.DependsOn)
+	s.Require().Equal(uint64(3), task.Version)
+
+	// Adding it again changes nothing
+	task, err = s.taskRepo.AddDependency(s.context, 1, 2)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(3), task.Version)
+
+	// An update keeps the dependencies
+	task, err = s.taskRepo.Update(s.context, &model.Task{TaskID: 1, Name: "renamed"}, 0)
+	s.Require().Nil(err)
+	s.Require().Equal([]uint64{2, 3}, task.DependsOn)
+
+	history, err := s.taskRepo.History(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal([]model.T_Change{{Field: "dependsOn", From: []uint64{3}, To: []uint64{2, 3}}}, history[2].Changes)
+
+	task, err = s.taskRepo.RemoveDependency(s.context, 1, 3)
+	s.Require().Nil(err)
+	s.Require().Equal([]uint64{2}, task.DependsOn)
+	task, err = s.taskRepo.RemoveDependency(s.context, 1, 2)
+	s.Require().Nil(err)
+	s.Require().Nil(task.DependsOn)
+
+	// Removing one it does not have changes nothing
+	task, err = s.taskRepo.RemoveDependency(s.context, 1, 2)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(6), task.Version)
+}
+
+func (s *DataSourceTestSuite) Test_AddDependency_Rejected() {
+	s.createTasks(4)
+	s.Require().Nil(s.taskRepo.Delete(s.context, 4))
+
+	// 1 -> 2 -> 3
+	_, err := s.taskRepo.AddDependency(s.context, 1, 2)
+	s.Require().Nil(err)
+	_, err = s.taskRepo.AddDependency(s.context, 2, 3)
+	s.Require().Nil(err)
+
+	for _, scenario := range []struct {
+		name      string
+		id, dep   uint64
+		rejection func(error) bool
+	}{
+		{name: "itself", id: 1, dep: 1, rejection: model.IsTaskInvalidDependency},
+		{name: "missing", id: 1, dep: 9, rejection: model.IsTaskInvalidDependency},
+		{name: "deleted", id: 1, dep: 4, rejection: model.IsTaskInvalidDependency},
+		{name: "direct cycle", id: 2, dep: 1, rejection: model.IsTaskDependencyCycle},
+		{name: "indirect cycle", id: 3, dep: 1, rejection: model.IsTaskDependencyCycle},
+		{name: "deleted task", id: 4, dep: 1, rejection: model.IsTaskNotFound},
+	} {
+		s.Run(scenario.name, func() {
+			task, err := s.taskRepo.AddDependency(s.context, scenario.id, scenario.dep)
+			s.Require().True(scenario.rejection(err), "%v", err)
+			s.Require().Nil(task)
+		})
+	}
+
+	// A deleted task still counts, since it can be restored
+	s.Require().Nil(s.taskRepo.Delete(s.context, 2))
+	_, err = s.taskRepo.AddDependency(s.context, 3, 1)
+	s.Require().True(model.IsTaskDependencyCycle(err))
+}
+
+func (s *DataSourceTestSuite) Test_ListTask_Ready() {
+	s.createTasks(4)
+
+	// 2 waits on 1, 3 waits on 1 and 2, 4 waits on nothing
+	for _, edge := range [][2]uint64{{2, 1}, {3, 1}, {3, 2}} {
+		_, err := s.taskRepo.AddDependency(s.context, edge[0], edge[1])
+		s.Require().Nil(err)
+	}
+
+	ready := func() []uint64 {
+		page, err := s.taskRepo.List(s.context, &biz.TaskQuery{Ready: true})
+		s.Require().Nil(err)
+		var ids []uint64
+		for _, task := range page.Tasks {
+			ids = append(ids, task.TaskID)
+		}
+		return ids
+	}
+	s.Require().Equal([]uint64{1, 4}, ready())
+
+	for _, status := range []model.TaskStatus{model.StatusInProgress, model.StatusDone} {
+		_, err := s.taskRepo.Transition(s.context, 1, status, 0)
+		s.Require().Nil(err)
+	}
+	s.Require().Equal([]uint64{2, 4}, ready())
+
+	// A deleted dependency no longer holds a task up
+	s.Require().Nil(s.taskRepo.Delete(s.context, 2))
+	s.Require().Equal([]uint64{3, 4}, ready())
+}
+
+func (s *DataSourceTestSuite) Test_Transition_Blocked() {
+	s.createTasks(4)
+
+	// 3 waits on 1, 2 and 4, of which 1 is done and 4 deleted
+	for _, dep := range []uint64{1, 2, 4} {
+		_, err := s.taskRepo.AddDependency(s.context, 3, dep)
+		s.Require().Nil(err)
+	}
+	for _, status := range []model.TaskStatus{model.StatusInProgress, model.StatusDone} {
+		_, err := s.taskRepo.Transition(s.context, 1, status, 0)
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(s.taskRepo.Delete(s.context, 4))
+
+	// Dependencies only hold up starting the work
+	for _, status := range []model.TaskStatus{model.StatusInProgress, model.StatusDone} {
+		_, err := s.taskRepo.Transition(s.context, 3, status, 0)
+		s.Require().True(model.IsTaskBlocked(err))
+		s.Require().Equal("task is waiting on dependencies which are not done: 2", errors.FromError(err).Message)
+	}
+	task, err := s.taskRepo.Transition(s.context, 3, model.StatusBlocked, 0)
+	s.Require().Nil(err)
+	s.Require().Equal(model.StatusBlocked, task.Status)
+
+	for _, status := range []model.TaskStatus{model.StatusInProgress, model.StatusDone} {
+		_, err := s.taskRepo.Transition(s.context, 2, status, 0)
+		s.Require().Nil(err)
+	}
+	task, err = s.taskRepo.Transition(s.context, 3, model.StatusInProgress, 0)
+	s.Require().Nil(err)
+	s.Require().Equal(model.StatusInProgress, task.Status)
+}
+
+func (s *DataSourceTestSuite) Test_TopologicalOrder() {
+	s.createTasks(5)
+	s.Require().Nil(s.taskRepo.Delete(s.context, 5))
+
+	// 1 waits on 3 and 4, 3 waits on 2
+	for _, edge := range [][2]uint64{{1, 3}, {1, 4}, {3, 2}} {
+		_, err := s.taskRepo.AddDependency(s.context, edge[0], edge[1])
+		s.Require().Nil(err)
+	}
+
+	tasks, err := s.taskRepo.TopologicalOrder(s.context)
+	s.Require().Nil(err)
+
+	var ids []uint64
+	for _, task := range tasks {
+		ids = append(ids, task.TaskID)
+	}
+	s.Require().Equal([]uint64{2, 3, 4, 1}, ids)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsDependencies() {
+	taskRepo, _ := s.open()
+
+	for i := 0; i < 3; i++ {
+		_, err := taskRepo.Create(s.context, &model.Task{Name: "task"})
+		s.Require().Nil(err)
+	}
+	_, err := taskRepo.AddDependency(s.context, 1, 2)
+	s.Require().Nil(err)
+	_, err = taskRepo.AddDependency(s.context, 2, 3)
+	s.Require().Nil(err)
+	_, err = taskRepo.RemoveDependency(s.context, 2, 3)
+	s.Require().Nil(err)
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	task, err := taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal([]uint64{2}, task.DependsOn)
+	task, err = taskRepo.Get(s.context, 2)
+	s.Require().Nil(err)
+	s.Require().Nil(task.DependsOn)
+
+	_, err = taskRepo.AddDependency(s.context, 2, 1)
+	s.Require().True(model.IsTaskDependencyCycle(err))
+}
@@ -0,0 +1,120 @@
+package data
+
+import (
+	"context"
+	"sync"
+)
+
+// rwLock is a readers-writer lock whose waiters can give up. Unlike with a
+// sync.RWMutex, a waiter which gives up leaves no trace: it neither keeps its place
+// in the queue nor holds back the readers which came after it.
+//
+// Waiting writers keep new readers out, so that a stream of readers cannot starve
+// them.
+type rwLock struct {
+	mu      sync.Mutex
+	readers int
+	writer  bool
+	// writers counts the writers waiting for the lock.
+	writers int
+	// changed is closed, and replaced, whenever the lock may have become free.
+	changed chan struct{}
+}
+
+// Lock takes the write lock, waiting as long as it takes.
+func (l *rwLock) Lock() {
+	_ = l.lock(context.Background())
+}
+
+// Unlock releases the write lock.
+func (l *rwLock) Unlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.writer {
+		panic("data: unlock of unlocked rwLock")
+	}
+	l.writer = false
+	l.notify()
+}
+
+// RLock takes the read lock, waiting as long as it takes.
+func (l *rwLock) RLock() {
+	_ = l.rlock(context.Background())
+}
+
+// RUnlock releases the read lock.
+func (l *rwLock) RUnlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.readers == 0 {
+		panic("data: runlock of unlocked rwLock")
+	}
+	l.readers--
+	if l.readers == 0 {
+		l.notify()
+	}
+}
+
+// lock takes the write lock, or returns the error of the context when it is done
+// first.
+func (l *rwLock) lock(ctx context.Context) error {
+	l.mu.Lock()
+	l.writers++
+	for l.writer || l.readers > 0 {
+		if err := l.wait(ctx); err != nil {
+			l.writers--
+			// The readers held back by this writer may go on
+			l.notify()
+			l.mu.Unlock()
+			return err
+		}
+	}
+	l.writers--
+	l.writer = true
+	l.mu.Unlock()
+	return nil
+}
+
+// rlock takes the read lock, or returns the error of the context when it is done
+// first.
+func (l *rwLock) rlock(ctx context.Context) error {
+	l.mu.Lock()
+	for l.writer || l.writers > 0 {
+		if err := l.wait(ctx); err != nil {
+			l.mu.Unlock()
+			return err
+		}
+	}
+	l.readers++
+	l.mu.Unlock()
+	return nil
+}
+
+// wait releases mu until the lock may have become free or the context is done,
+// and takes it again. It must be called with mu held.
+func (l *rwLock) wait(ctx context.Context) error {
+	if l.changed == nil {
+		l.changed = make(chan struct{})
+	}
+	changed := l.changed
+	l.mu.Unlock()
+
+	var err error
+	select {
+	case <-changed:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	l.mu.Lock()
+	return err
+}
+
+// notify wakes every waiter. It must be called with mu held.
+func (l *rwLock) notify() {
+	if l.changed != nil {
+		close(l.changed)
+		l.changed = nil
+	}
+}
@@ -0,0 +1,337 @@
+package data
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// rankStep is the gap left between the ranks of neighbouring tasks in a column, so
+// that most moves fit a task in between without touching the others.
+const rankStep = 1 << 16
+
+func (r *taskRepo) CreateProject(ctx context.Context, p *model.Project) (*model.T_Project, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.projectCreated(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	r.data.apply(walEntry{Op: walOpProject, Tenant: r.tenant, Project: &val})
+
+	return &val, nil
+}
+
+func (r *taskRepo) GetProject(ctx context.Context, id string) (*model.T_Project, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.storedProject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &val, nil
+}
+
+func (r *taskRepo) ListProjects(ctx context.Context) ([]model.T_Project, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.T_Project, 0, len(r.space.projects))
+	for _, p := range r.space.projects {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ProjectID < result[j].ProjectID })
+
+	return result, nil
+}
+
+func (r *taskRepo) UpdateProject(ctx context.Context, p *model.Project, version uint64) (*model.T_Project, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.projectUpdated(p, version)
+	if err != nil {
+		return nil, err
+	}
+	r.data.apply(walEntry{Op: walOpProject, Tenant: r.tenant, Project: &val})
+
+	return &val, nil
+}
+
+func (r *taskRepo) DeleteProject(ctx context.Context, id string) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := r.projectDeleted(id); err != nil {
+		return err
+	}
+	r.data.apply(walEntry{Op: walOpDropProject, Tenant: r.tenant, ProjectID: id})
+
+	return nil
+}
+
+func (r *taskRepo) Board(ctx context.Context, id string) (*model.T_Board, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := r.storedProject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &model.T_Board{Project: p, Columns: make([]model.T_BoardColumn, 0, len(p.Columns))}
+	for _, column := range p.Columns {
+		board.Columns = append(board.Columns, model.T_BoardColumn{Name: column, Tasks: r.column(id, column, 0)})
+	}
+
+	return board, nil
+}
+
+func (r *taskRepo) Move(ctx context.Context, id uint64, m *model.TaskMove, version uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, rebalanced, changed, err := r.moved(id, m, version)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rebalanced {
+		r.data.apply(walEntry{Op: walOpPut, Tenant: r.tenant, Task: &rebalanced[i]})
+	}
+	if changed {
+		r.data.apply(r.put(ctx, val, model.RevisionMoved))
+	}
+
+	return &val, nil
+}
+
+// storedProject returns the stored project.
+func (r *taskRepo) storedProject(id string) (model.T_Project, error) {
+	val, ok := r.space.projects[id]
+
+	// Project not exist
+	if !ok {
+		return model.T_Project{}, model.ErrorProjectNotFound("%s: %s", encoder.PROJECT_NOT_EXIST, id)
+	}
+
+	return val, nil
+}
+
+// projectCreated returns the record of a new project, provided none has its ID.
+func (r *taskRepo) projectCreated(ctx context.Context, p *model.Project) (model.T_Project, error) {
+	if _, ok := r.space.projects[p.ProjectID]; ok {
+		return model.T_Project{}, model.ErrorProjectAlreadyExists("%s: %s", encoder.PROJECT_EXISTS, p.ProjectID)
+	}
+
+	nt := time.Now()
+	return model.T_Project{Project: *p, CreatedAt: &nt, CreatedBy: biz.ActorFromContext(ctx), Version: 1}, nil
+}
+
+// projectUpdated returns the stored project with p applied over it, provided it is
+// still at the given version and no column it drops holds live tasks. A version of
+// 0 skips the check.
+func (r *taskRepo) projectUpdated(p *model.Project, version uint64) (model.T_Project, error) {
+	val, err := r.storedProject(p.ProjectID)
+	if err != nil {
+		return model.T_Project{}, err
+	}
+
+	// Project has been changed since the client read it
+	if version != 0 && version != val.Version {
+		return model.T_Project{}, model.ErrorProjectVersionMismatch(string(encoder.PROJECT_MODIFIED))
+	}
+
+	for _, column := range val.Columns {
+		if n := len(r.column(p.ProjectID, column, 0)); n > 0 && !p.HasColumn(column) {
+			return model.T_Project{}, model.ErrorProjectNotEmpty("%s: column %q holds %d tasks", encoder.PROJECT_NOT_EMPTY, column, n)
+		}
+	}
+
+	val.Project = *p
+	nt := time.Now()
+	val.UpdatedAt = &nt
+	val.Version++
+
+	return val, nil
+}
+
+// projectDeleted checks that the project exists and holds no live tasks. Its
+// trashed tasks keep naming it, and are taken off its board when restored.
+func (r *taskRepo) projectDeleted(id string) error {
+	if _, err := r.storedProject(id); err != nil {
+		return err
+	}
+
+	for taskID := range r.space.boards[id] {
+		if r.space.tasks[taskID].DeletedAt == nil {
+			return model.ErrorProjectNotEmpty("%s: %s", encoder.PROJECT_NOT_EMPTY, id)
+		}
+	}
+
+	return nil
+}
+
+// column returns the live tasks in the column of the project, but for the task
+// except, from top to bottom.
+func (r *taskRepo) column(project string, column string, except uint64) []model.T_Task {
+	result := make([]model.T_Task, 0)
+	for id := range r.space.boards[project] {
+		task := r.space.tasks[id]
+		if id != except && task.DeletedAt == nil && task.Column == column {
+			result = append(result, task)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Rank != result[j].Rank {
+			return result[i].Rank < result[j].Rank
+		}
+		return result[i].TaskID < result[j].TaskID
+	})
+	return result
+}
+
+// moved returns the stored record placed as the move says, provided the record is
+// still at the given version, and whether it changed. A version of 0 skips the
+// check. The task takes the rank halfway between its new neighbours. When they
+// leave no rank in between, the column is spread out again, and its other tasks
+// whose ranks change are returned to be stored as they are, without a revision.
+func (r *taskRepo) moved(id uint64, m *model.TaskMove, version uint64) (model.T_Task, []model.T_Task, bool, error) {
+	val, err := r.updated(&model.Task{TaskID: id}, version)
+	if err != nil {
+		return model.T_Task{}, nil, false, err
+	}
+	stored := r.space.tasks[id]
+	val.Task = stored.Task
+
+	if m.ProjectID == "" {
+		if stored.ProjectID == "" {
+			return stored, nil, false, nil
+		}
+		val.ProjectID, val.Column, val.Rank = "", "", 0
+		return val, nil, true, nil
+	}
+
+	p, err := r.storedProject(m.ProjectID)
+	if err != nil {
+		return model.T_Task{}, nil, false, err
+	}
+	column := m.Column
+	if column == "" {
+		column = p.Columns[0]
+	}
+	if !p.HasColumn(column) {
+		return model.T_Task{}, nil, false, model.ErrorProjectInvalid("%s: project %s has no column %q", encoder.PROJECT_INVALID, p.ProjectID, column)
+	}
+
+	tasks := r.column(p.ProjectID, column, id)
+	at := len(tasks)
+	neighbour, after := m.Before, false
+	if m.After != 0 {
+		neighbour, after = m.After, true
+	}
+	if neighbour != 0 {
+		at = -1
+		for i, t := range tasks {
+			if t.TaskID == neighbour {
+				at = i
+			}
+		}
+		if at < 0 {
+			return model.T_Task{}, nil, false, model.ErrorProjectInvalid("%s: task %d is not in column %q of project %s",
+				encoder.PROJECT_INVALID, neighbour, column, p.ProjectID)
+		}
+		if after {
+			at++
+		}
+	}
+
+	// A task which is already in its place stays there
+	if stored.ProjectID == p.ProjectID && stored.Column == column &&
+		(at == 0 || tasks[at-1].Rank < stored.Rank) && (at == len(tasks) || stored.Rank < tasks[at].Rank) {
+		return stored, nil, false, nil
+	}
+	val.ProjectID, val.Column = p.ProjectID, column
+
+	var low, high uint64
+	if at > 0 {
+		low = tasks[at-1].Rank
+	}
+	high = low + 2*rankStep
+	if at < len(tasks) {
+		high = tasks[at].Rank
+	}
+	if high-low > 1 {
+		val.Rank = low + (high-low)/2
+		return val, nil, true, nil
+	}
+
+	// No rank is left between the neighbours, so the column is spread out again
+	tasks = append(tasks[:at], append([]model.T_Task{val}, tasks[at:]...)...)
+	var rebalanced []model.T_Task
+	for i, t := range tasks {
+		rank := uint64(i+1) * rankStep
+		if t.TaskID == id {
+			val.Rank = rank
+		} else if t.Rank != rank {
+			t.Rank = rank
+			rebalanced = append(rebalanced, t)
+		}
+	}
+	return val, rebalanced, true, nil
+}
@@ -0,0 +1,210 @@
+package data_test
+
+import (
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+// board returns the IDs of the tasks in every column of the board of the project.
+func (s *DataSourceTestSuite) board(project string) map[string][]uint64 {
+	board, err := s.taskRepo.Board(s.context, project)
+	s.Require().Nil(err)
+
+	result := make(map[string][]uint64)
+	for _, c := range board.Columns {
+		result[c.Name] = []uint64{}
+		for i, t := range c.Tasks {
+			result[c.Name] = append(result[c.Name], t.TaskID)
+			if i > 0 {
+				s.Require().Less(c.Tasks[i-1].Rank, t.Rank)
+			}
+		}
+	}
+	return result
+}
+
+func (s *DataSourceTestSuite) Test_Projects_CreateUpdateDelete() {
+	p, err := s.taskRepo.CreateProject(s.context, &model.Project{ProjectID: "apollo", Columns: []string{"todo", "doing"}})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(1), p.Version)
+	_, err = s.taskRepo.CreateProject(s.context, &model.Project{ProjectID: "apollo", Columns: []string{"todo"}})
+	s.Require().True(model.IsProjectAlreadyExists(err))
+
+	projects, err := s.taskRepo.ListProjects(s.context)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(projects))
+
+	_, err = s.taskRepo.Create(s.context, &model.Task{Name: "launch"})
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Move(s.context, 1, &model.TaskMove{ProjectID: "apollo", Column: "doing"}, 0)
+	s.Require().Nil(err)
+
+	// A column holding tasks cannot be dropped, but the others can change at will
+	_, err = s.taskRepo.UpdateProject(s.context, &model.Project{ProjectID: "apollo", Columns: []string{"todo"}}, 0)
+	s.Require().True(model.IsProjectNotEmpty(err))
+	_, err = s.taskRepo.UpdateProject(s.context, &model.Project{ProjectID: "apollo", Columns: []string{"doing", "done"}}, 2)
+	s.Require().True(model.IsProjectVersionMismatch(err))
+	p, err = s.taskRepo.UpdateProject(s.context, &model.Project{ProjectID: "apollo", Name: "Apollo", Columns: []string{"doing", "done"}}, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(2), p.Version)
+	s.Require().Equal(map[string][]uint64{"doing": {1}, "done": {}}, s.board("apollo"))
+
+	// The project goes once its live tasks are gone, and its trashed tasks come back off its board
+	s.Require().True(model.IsProjectNotEmpty(s.taskRepo.DeleteProject(s.context, "apollo")))
+	s.Require().Nil(s.taskRepo.Delete(s.context, 1))
+	s.Require().Nil(s.taskRepo.DeleteProject(s.context, "apollo"))
+	_, err = s.taskRepo.GetProject(s.context, "apollo")
+	s.Require().True(model.IsProjectNotFound(err))
+	task, err := s.taskRepo.Restore(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal("", task.ProjectID)
+	s.Require().Equal("", task.Column)
+}
+
+func (s *DataSourceTestSuite) Test_Move() {
+	_, err := s.taskRepo.CreateProject(s.context, &model.Project{ProjectID: "gemini", Columns: []string{"todo", "done"}})
+	s.Require().Nil(err)
+	defer s.taskRepo.DeleteProject(s.context, "gemini")
+
+	for i := 0; i < 4; i++ {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: "step"})
+		s.Require().Nil(err)
+		// Tasks go to the bottom of the first column by default
+		_, err = s.taskRepo.Move(s.context, uint64(i+1), &model.TaskMove{ProjectID: "gemini"}, 1)
+		s.Require().Nil(err)
+	}
+	s.Require().Equal(map[string][]uint64{"todo": {1, 2, 3, 4}, "done": {}}, s.board("gemini"))
+
+	_, err = s.taskRepo.Move(s.context, 4, &model.TaskMove{ProjectID: "gemini", Before: 1}, 0)
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Move(s.context, 3, &model.TaskMove{ProjectID: "gemini", After: 4}, 0)
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Move(s.context, 2, &model.TaskMove{ProjectID: "gemini", Column: "done"}, 0)
+	s.Require().Nil(err)
+	s.Require().Equal(map[string][]uint64{"todo": {4, 3, 1}, "done": {2}}, s.board("gemini"))
+
+	// A task already in its place is left as it is
+	task, err := s.taskRepo.Move(s.context, 3, &model.TaskMove{ProjectID: "gemini", Before: 1}, 0)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(3), task.Version)
+
+	revs, err := s.taskRepo.History(s.context, 2)
+	s.Require().Nil(err)
+	s.Require().Equal(3, len(revs))
+	s.Require().Equal(model.RevisionMoved, revs[2].Action)
+	s.Require().Equal("column", revs[2].Changes[0].Field)
+	s.Require().Equal("done", revs[2].Changes[0].To)
+
+	for _, scenario := range []struct {
+		move  model.TaskMove
+		check func(error) bool
+	}{
+		{move: model.TaskMove{ProjectID: "nowhere"}, check: model.IsProjectNotFound},
+		{move: model.TaskMove{ProjectID: "gemini", Column: "doing"}, check: model.IsProjectInvalid},
+		{move: model.TaskMove{ProjectID: "gemini", Column: "done", Before: 4}, check: model.IsProjectInvalid},
+	} {
+		_, err := s.taskRepo.Move(s.context, 1, &scenario.move, 0)
+		s.Require().True(scenario.check(err), "%+v: %v", scenario.move, err)
+	}
+	_, err = s.taskRepo.Move(s.context, 1, &model.TaskMove{ProjectID: "gemini", Column: "done"}, 1)
+	s.Require().True(model.IsTaskVersionMismatch(err))
+
+	// Taking the tasks off the board empties it
+	for id := uint64(1); id <= 4; id++ {
+		task, err := s.taskRepo.Move(s.context, id, &model.TaskMove{}, 0)
+		s.Require().Nil(err)
+		s.Require().Equal(uint64(0), task.Rank)
+	}
+	s.Require().Equal(map[string][]uint64{"todo": {}, "done": {}}, s.board("gemini"))
+}
+
+func (s *DataSourceTestSuite) Test_Move_Rebalances() {
+	_, err := s.taskRepo.CreateProject(s.context, &model.Project{ProjectID: "mercury", Columns: []string{"todo"}})
+	s.Require().Nil(err)
+
+	// Every task goes on top, halving the rank of the top until none is left
+	var want []uint64
+	for id := uint64(1); id <= 40; id++ {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: "urgent"})
+		s.Require().Nil(err)
+		move := model.TaskMove{ProjectID: "mercury", Before: id - 1}
+		task, err := s.taskRepo.Move(s.context, id, &move, 0)
+		s.Require().Nil(err)
+		s.Require().Equal(uint64(2), task.Version)
+		want = append([]uint64{id}, want...)
+	}
+	s.Require().Equal(want, s.board("mercury")["todo"])
+
+	// Spreading the column out again makes no revisions
+	for id := uint64(1); id <= 40; id++ {
+		task, err := s.taskRepo.Get(s.context, id)
+		s.Require().Nil(err)
+		s.Require().Equal(uint64(2), task.Version)
+	}
+
+	for id := uint64(1); id <= 40; id++ {
+		s.Require().Nil(s.taskRepo.Delete(s.context, id))
+	}
+	s.Require().Nil(s.taskRepo.DeleteProject(s.context, "mercury"))
+}
+
+func (s *DataSourceTestSuite) Test_TaskCheck_CurrentProject() {
+	for _, project := range []string{"apollo", "gemini"} {
+		_, err := s.taskRepo.CreateProject(s.context, &model.Project{ProjectID: project, Columns: []string{"todo"}})
+		s.Require().Nil(err)
+	}
+	_, err := s.taskRepo.Create(s.context, &model.Task{Name: "launch"})
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Move(s.context, 1, &model.TaskMove{ProjectID: "apollo"}, 0)
+	s.Require().Nil(err)
+
+	// Only the tasks on the board of apollo may be changed
+	checked := biz.WithTaskCheck(s.context, &biz.TaskCheck{TaskID: 1, Allow: func(project string) error {
+		if project != "apollo" {
+			return model.ErrorForbidden("not in %q", project)
+		}
+		return nil
+	}})
+	_, err = s.taskRepo.Transition(checked, 1, model.StatusInProgress, 0)
+	s.Require().Nil(err)
+
+	// The check sees the project the task is on when the operation runs
+	_, err = s.taskRepo.Move(s.context, 1, &model.TaskMove{ProjectID: "gemini"}, 0)
+	s.Require().Nil(err)
+	s.Require().True(model.IsForbidden(s.taskRepo.Delete(checked, 1)))
+	task, err := s.taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Nil(task.DeletedAt)
+
+	// And the project a trashed task was on
+	_, err = s.taskRepo.Move(s.context, 1, &model.TaskMove{ProjectID: "apollo"}, 0)
+	s.Require().Nil(err)
+	s.Require().Nil(s.taskRepo.Delete(checked, 1))
+	_, err = s.taskRepo.Restore(checked, 1)
+	s.Require().Nil(err)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsProjects() {
+	taskRepo, _ := s.open()
+
+	_, err := taskRepo.CreateProject(s.context, &model.Project{ProjectID: "apollo", Columns: []string{"todo", "done"}})
+	s.Require().Nil(err)
+	_, err = taskRepo.CreateProject(s.context, &model.Project{ProjectID: "gone", Columns: []string{"todo"}})
+	s.Require().Nil(err)
+	s.Require().Nil(taskRepo.DeleteProject(s.context, "gone"))
+	_, err = taskRepo.Create(s.context, &model.Task{Name: "launch"})
+	s.Require().Nil(err)
+	_, err = taskRepo.Move(s.context, 1, &model.TaskMove{ProjectID: "apollo", Column: "done"}, 0)
+	s.Require().Nil(err)
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	board, err := taskRepo.Board(s.context, "apollo")
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(board.Columns))
+	s.Require().Equal(1, len(board.Columns[1].Tasks))
+	s.Require().Equal("launch", board.Columns[1].Tasks[0].Name)
+	_, err = taskRepo.GetProject(s.context, "gone")
+	s.Require().True(model.IsProjectNotFound(err))
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) DueReminders(ctx context.Context, now time.Time) ([]model.T_Task, *time.Time, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	due := make([]model.T_Task, 0)
+	var next *time.Time
+	for _, task := range r.space.tasks {
+		if task.DeletedAt != nil || task.RemindAt == nil || task.Reminded() || task.Finished() {
+			continue
+		}
+		if !task.RemindAt.After(now) {
+			due = append(due, task)
+		} else if next == nil || task.RemindAt.Before(*next) {
+			next = task.RemindAt
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].RemindAt.Equal(*due[j].RemindAt) {
+			return due[i].RemindAt.Before(*due[j].RemindAt)
+		}
+		return due[i].TaskID < due[j].TaskID
+	})
+
+	return due, next, nil
+}
+
+func (r *taskRepo) MarkReminded(ctx context.Context, id uint64, at time.Time) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	val, err := r.reminded(id, at)
+	if err != nil {
+		return err
+	}
+	r.data.apply(walEntry{Op: walOpPut, Tenant: r.tenant, Task: &val})
+
+	return nil
+}
+
+// reminded returns the stored record with its reminder sent at the given time.
+// Sending a reminder is not a change made to the task, so it is neither versioned
+// nor recorded in the history.
+func (r *taskRepo) reminded(id uint64, at time.Time) (model.T_Task, error) {
+	val, err := r.current(id)
+	if err != nil {
+		return model.T_Task{}, err
+	}
+	val.RemindedAt = &at
+	return val, nil
+}
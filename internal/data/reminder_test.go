@@ -0,0 +1,122 @@
+package data_test
+
+import (
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) Test_DueReminders() {
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	for _, task := range []model.Task{
+		{Name: "later", RemindAt: at(time.Hour)},
+		{Name: "late", RemindAt: at(-time.Minute)},
+		{Name: "none"},
+		{Name: "later still", RemindAt: at(2 * time.Hour)},
+		{Name: "latest", RemindAt: at(-time.Hour)},
+		{Name: "done", RemindAt: at(-time.Hour)},
+		{Name: "deleted", RemindAt: at(-time.Hour)},
+	} {
+		_, err := s.taskRepo.Create(s.context, &task)
+		s.Require().Nil(err)
+	}
+	_, err := s.taskRepo.Transition(s.context, 6, model.StatusDone, 0)
+	s.Require().Nil(err)
+	s.Require().Nil(s.taskRepo.Delete(s.context, 7))
+
+	// Only live, unfinished tasks are reminded, earliest first
+	due, next, err := s.taskRepo.DueReminders(s.context, now)
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(due))
+	s.Require().Equal(uint64(5), due[0].TaskID)
+	s.Require().Equal(uint64(2), due[1].TaskID)
+	s.Require().True(next.Equal(*at(time.Hour)))
+
+	// Sending a reminder is not a change to the task
+	s.Require().Nil(s.taskRepo.MarkReminded(s.context, 5, now))
+	task, err := s.taskRepo.Get(s.context, 5)
+	s.Require().Nil(err)
+	s.Require().True(task.RemindedAt.Equal(now))
+	s.Require().Equal(uint64(1), task.Version)
+	history, err := s.taskRepo.History(s.context, 5)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(history))
+
+	due, _, err = s.taskRepo.DueReminders(s.context, now)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(due))
+
+	// Moving the reminder past the one sent schedules it again
+	_, err = s.taskRepo.Update(s.context, &model.Task{TaskID: 5, Name: "latest", RemindAt: at(time.Minute)}, 0)
+	s.Require().Nil(err)
+	due, next, err = s.taskRepo.DueReminders(s.context, now.Add(time.Minute))
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(due))
+	s.Require().Equal(uint64(2), due[0].TaskID)
+	s.Require().Equal(uint64(5), due[1].TaskID)
+	s.Require().True(next.Equal(*at(time.Hour)))
+
+	s.Require().True(model.IsTaskNotFound(s.taskRepo.MarkReminded(s.context, 7, now)))
+}
+
+func (s *DataSourceTestSuite) Test_ListTask_Due() {
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	for _, task := range []model.Task{
+		{Name: "tomorrow", DueAt: at(20 * time.Hour)},
+		{Name: "yesterday", DueAt: at(-20 * time.Hour)},
+		{Name: "next week", DueAt: at(7 * 24 * time.Hour)},
+		{Name: "someday"},
+		{Name: "soon", DueAt: at(time.Hour)},
+	} {
+		_, err := s.taskRepo.Create(s.context, &task)
+		s.Require().Nil(err)
+	}
+	_, err := s.taskRepo.Transition(s.context, 5, model.StatusCancelled, 0)
+	s.Require().Nil(err)
+
+	q := biz.TaskQuery{SortBy: biz.SortByDueAt, DueAfter: &now, DueBefore: at(24 * time.Hour), Unfinished: true}
+	s.Require().Nil(q.Validate())
+	page, err := s.taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(page.Tasks))
+	s.Require().Equal("tomorrow", page.Tasks[0].Name)
+
+	// Tasks without a due date sort first
+	q = biz.TaskQuery{SortBy: biz.SortByDueAt, Descending: true}
+	s.Require().Nil(q.Validate())
+	page, err = s.taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+	var names []string
+	for _, task := range page.Tasks {
+		names = append(names, task.Name)
+	}
+	s.Require().Equal([]string{"next week", "tomorrow", "soon", "yesterday", "someday"}, names)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsRemindersSent() {
+	taskRepo, _ := s.open()
+
+	remindAt := time.Now().Add(-time.Minute)
+	for _, name := range []string{"sent", "pending"} {
+		_, err := taskRepo.Create(s.context, &model.Task{Name: name, RemindAt: &remindAt})
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(taskRepo.MarkReminded(s.context, 1, time.Now()))
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	due, _, err := taskRepo.DueReminders(s.context, time.Now())
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(due))
+	s.Require().Equal("pending", due[0].Name)
+}
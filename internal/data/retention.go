@@ -0,0 +1,48 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"qantas.com/task/internal/biz"
+)
+
+const defaultPurgeInterval = time.Minute
+
+// startRetention runs the job which hard-purges the tasks that have been in the
+// trash for longer than the configured retention. It is a no-op when no
+// retention is configured, and runs at most once per Data.
+func (d *Data) startRetention(repo biz.ITaskRepo, logger *log.Helper) {
+	retention := d.trash.GetRetention().AsDuration()
+	if retention <= 0 {
+		return
+	}
+	interval := d.trash.GetPurgeInterval().AsDuration()
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+
+	d.retention.Do(func() {
+		d.jobs.Add(1)
+		go func() {
+			defer d.jobs.Done()
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-d.stop:
+					return
+				case <-ticker.C:
+					purged, err := repo.PurgeDeletedBefore(context.Background(), time.Now().Add(-retention))
+					if err != nil {
+						logger.Errorf("retention: purged %d tasks before failing - %v", purged, err)
+					} else if purged > 0 {
+						logger.Infof("retention: purged %d tasks deleted more than %s ago", purged, retention)
+					}
+				}
+			}
+		}()
+	})
+}
@@ -0,0 +1,224 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) History(ctx context.Context, id uint64) ([]model.T_Revision, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Task not exist
+	if _, ok := r.space.tasks[id]; !ok {
+		return nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	return append([]model.T_Revision{}, r.space.revisions[id]...), nil
+}
+
+func (r *taskRepo) Revision(ctx context.Context, id uint64, rev uint64) (*model.T_Revision, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.revision(id, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	return &val, nil
+}
+
+func (r *taskRepo) Revert(ctx context.Context, id uint64, rev uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, e, err := r.reverted(ctx, id, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	r.data.apply(e)
+
+	return &val, nil
+}
+
+// put returns the log entry storing task, along with the revision recording how
+// it differs from the stored record. The caller must hold the write lock.
+func (r *taskRepo) put(ctx context.Context, task model.T_Task, action model.RevisionAction) walEntry {
+	rev := model.T_Revision{
+		TaskID:   task.TaskID,
+		Revision: task.Version,
+		Action:   action,
+		Actor:    biz.ActorFromContext(ctx),
+		Task:     task.Task,
+	}
+
+	switch action {
+	case model.RevisionCreated:
+		rev.ChangedAt = task.CreatedAt
+	case model.RevisionDeleted:
+		rev.ChangedAt = task.DeletedAt
+	default:
+		rev.ChangedAt = task.UpdatedAt
+	}
+
+	var prev model.Task
+	stored, ok := r.space.tasks[task.TaskID]
+	if ok {
+		prev = stored.Task
+	}
+	rev.Changes = changes(prev, task.Task)
+
+	// A new task always starts out to do, so only later moves are recorded
+	if ok && stored.GetStatus() != task.GetStatus() {
+		rev.Changes = append(rev.Changes, model.T_Change{Field: "status", From: string(stored.GetStatus()), To: string(task.GetStatus())})
+	}
+	if !reflect.DeepEqual(stored.DependsOn, task.DependsOn) {
+		rev.Changes = append(rev.Changes, model.T_Change{Field: "dependsOn", From: dependencies(stored.DependsOn), To: dependencies(task.DependsOn)})
+	}
+	// A new task is owned by its creator, as CreatedBy records, so only later owners are recorded
+	if ok && stored.Owner != task.Owner {
+		rev.Changes = append(rev.Changes, model.T_Change{Field: "owner", From: stored.Owner, To: task.Owner})
+	}
+	if !reflect.DeepEqual(stored.Assignees, task.Assignees) {
+		rev.Changes = append(rev.Changes, model.T_Change{Field: "assignees", From: users(stored.Assignees), To: users(task.Assignees)})
+	}
+	if stored.ProjectID != task.ProjectID {
+		rev.Changes = append(rev.Changes, model.T_Change{Field: "projectID", From: stored.ProjectID, To: task.ProjectID})
+	}
+	if stored.Column != task.Column {
+		rev.Changes = append(rev.Changes, model.T_Change{Field: "column", From: stored.Column, To: task.Column})
+	}
+	if stored.Rank != task.Rank {
+		rev.Changes = append(rev.Changes, model.T_Change{Field: "rank", From: stored.Rank, To: task.Rank})
+	}
+	sort.Slice(rev.Changes, func(i, j int) bool { return rev.Changes[i].Field < rev.Changes[j].Field })
+
+	return walEntry{Op: walOpPut, Tenant: r.tenant, Task: &task, Revision: &rev}
+}
+
+// dependencies is a list of dependencies as a change value, absent when empty.
+func dependencies(ids []uint64) interface{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+// users is a list of users as a change value, absent when empty.
+func users(list []string) interface{} {
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+// revision looks up a single revision of a task.
+func (r *taskRepo) revision(id uint64, rev uint64) (model.T_Revision, error) {
+	// Task not exist
+	if _, ok := r.space.tasks[id]; !ok {
+		return model.T_Revision{}, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	revisions := r.space.revisions[id]
+	i := sort.Search(len(revisions), func(i int) bool { return revisions[i].Revision >= rev })
+	if i == len(revisions) || revisions[i].Revision != rev {
+		return model.T_Revision{}, model.ErrorTaskRevisionNotFound(string(encoder.TASK_REVISION_NOT_EXIST))
+	}
+
+	return revisions[i], nil
+}
+
+// reverted returns the stored record brought back to the given revision, with
+// the log entry recording the revert.
+func (r *taskRepo) reverted(ctx context.Context, id uint64, rev uint64) (model.T_Task, walEntry, error) {
+	target, err := r.revision(id, rev)
+	if err != nil {
+		return model.T_Task{}, walEntry{}, err
+	}
+
+	// Task has been deleted
+	val := r.space.tasks[id]
+	if val.DeletedAt != nil {
+		return model.T_Task{}, walEntry{}, model.ErrorTaskNotFound(string(encoder.TASK_DELETED))
+	}
+
+	// The parent the task had then may have gone, or become one of its subtasks
+	if err := r.validParent(id, target.Task.ParentID); err != nil {
+		return model.T_Task{}, walEntry{}, err
+	}
+
+	val.Task = target.Task
+	nt := time.Now()
+	val.UpdatedAt = &nt
+	val.Version++
+
+	e := r.put(ctx, val, model.RevisionReverted)
+	e.Revision.RevertedTo = rev
+
+	return val, e, nil
+}
+
+// changes lists the fields which differ between two states of a task, in the
+// order of their names.
+func changes(from, to model.Task) []model.T_Change {
+	before, after := fields(from), fields(to)
+
+	names := make([]string, 0, len(after))
+	for name := range after {
+		names = append(names, name)
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var result []model.T_Change
+	for _, name := range names {
+		if name == "taskID" || reflect.DeepEqual(before[name], after[name]) {
+			continue
+		}
+		result = append(result, model.T_Change{Field: name, From: before[name], To: after[name]})
+	}
+	return result
+}
+
+// fields returns the task as its JSON fields, so every field a task carries is
+// compared without listing them here.
+func fields(t model.Task) map[string]interface{} {
+	b, _ := json.Marshal(t)
+	m := map[string]interface{}{}
+	json.Unmarshal(b, &m)
+	return m
+}
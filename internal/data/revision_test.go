@@ -0,0 +1,103 @@
+package data_test
+
+import (
+	errors "github.com/go-kratos/kratos/v2/errors"
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) Test_History() {
+	ctx := biz.WithActor(s.context, "alice")
+
+	_, err := s.taskRepo.Create(ctx, &model.Task{Name: "user 1", Content: "content 1"})
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Update(biz.WithActor(s.context, "bob"), &model.Task{TaskID: 1, Name: "user 1", Content: "content 2"}, 0)
+	s.Require().Nil(err)
+	s.Require().Nil(s.taskRepo.Delete(ctx, 1))
+	_, err = s.taskRepo.Restore(ctx, 1)
+	s.Require().Nil(err)
+
+	history, err := s.taskRepo.History(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(4, len(history))
+
+	actions := []model.RevisionAction{model.RevisionCreated, model.RevisionUpdated, model.RevisionDeleted, model.RevisionRestored}
+	for i, rev := range history {
+		s.Require().Equal(uint64(i+1), rev.Revision)
+		s.Require().Equal(actions[i], rev.Action)
+		s.Require().NotNil(rev.ChangedAt)
+	}
+
+	// Who changed what
+	s.Require().Equal("alice", history[0].Actor)
+	s.Require().Equal([]model.T_Change{
+		{Field: "content", To: "content 1"},
+		{Field: "name", To: "user 1"},
+	}, history[0].Changes)
+
+	s.Require().Equal("bob", history[1].Actor)
+	s.Require().Equal([]model.T_Change{{Field: "content", From: "content 1", To: "content 2"}}, history[1].Changes)
+	s.Require().Empty(history[2].Changes)
+
+	rev, err := s.taskRepo.Revision(s.context, 1, 2)
+	s.Require().Nil(err)
+	s.Require().Equal(history[1], *rev)
+}
+
+func (s *DataSourceTestSuite) Test_History_NotFound() {
+	se := new(errors.Error)
+
+	_, err := s.taskRepo.History(s.context, 1)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskNotFound(se))
+
+	_, err = s.taskRepo.Create(s.context, &model.Task{Name: "user 1"})
+	s.Require().Nil(err)
+
+	_, err = s.taskRepo.Revision(s.context, 1, 2)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskRevisionNotFound(se))
+
+	// Purging a task drops its history
+	s.Require().Nil(s.taskRepo.Delete(s.context, 1))
+	s.Require().Nil(s.taskRepo.Purge(s.context, 1))
+
+	_, err = s.taskRepo.History(s.context, 1)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskNotFound(se))
+}
+
+func (s *DataSourceTestSuite) Test_Revert() {
+	_, err := s.taskRepo.Create(s.context, &model.Task{Name: "user 1", Content: "content 1"})
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Update(s.context, &model.Task{TaskID: 1, Name: "user 2", Content: "content 2"}, 0)
+	s.Require().Nil(err)
+
+	rt, err := s.taskRepo.Revert(biz.WithActor(s.context, "alice"), 1, 1)
+	s.Require().Nil(err)
+	s.Require().Equal("user 1", rt.Name)
+	s.Require().Equal("content 1", rt.Content)
+	s.Require().Equal(uint64(3), rt.Version)
+
+	// The revert is a revision of its own
+	rev, err := s.taskRepo.Revision(s.context, 1, 3)
+	s.Require().Nil(err)
+	s.Require().Equal(model.RevisionReverted, rev.Action)
+	s.Require().Equal(uint64(1), rev.RevertedTo)
+	s.Require().Equal("alice", rev.Actor)
+	s.Require().Equal([]model.T_Change{
+		{Field: "content", From: "content 2", To: "content 1"},
+		{Field: "name", From: "user 2", To: "user 1"},
+	}, rev.Changes)
+
+	// A deleted task, or a revision which does not exist, can not be reverted to
+	se := new(errors.Error)
+	_, err = s.taskRepo.Revert(s.context, 1, 9)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskRevisionNotFound(se))
+
+	s.Require().Nil(s.taskRepo.Delete(s.context, 1))
+	_, err = s.taskRepo.Revert(s.context, 1, 2)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskNotFound(se))
+}
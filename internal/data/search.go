@@ -0,0 +1,300 @@
+package data
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+const (
+	// nameWeight is how much more a word in the name of a task counts than one in its content.
+	nameWeight = 2
+	// snippetBefore and snippetWords are the words a snippet shows before its first
+	// match, and in all.
+	snippetBefore = 8
+	snippetWords  = 32
+)
+
+// textIndex is the inverted index of the names and contents of the live tasks.
+// The name of a task is indexed at the first positions, followed by a gap and its
+// content, so that a phrase never runs from the name into the content.
+type textIndex struct {
+	// postings holds the positions of every word in every task
+	postings map[string]map[uint64][]int
+	docs     map[uint64]textDoc
+}
+
+type textDoc struct {
+	words   []string
+	nameLen int
+}
+
+func newTextIndex() *textIndex {
+	return &textIndex{postings: make(map[string]map[uint64][]int), docs: make(map[uint64]textDoc)}
+}
+
+func (x *textIndex) add(t model.T_Task) {
+	name, content := biz.Tokenize(t.Name), biz.Tokenize(t.Content)
+	doc := textDoc{nameLen: len(name)}
+
+	add := func(word string, pos int) {
+		if x.postings[word] == nil {
+			x.postings[word] = make(map[uint64][]int)
+		}
+		if len(x.postings[word][t.TaskID]) == 0 {
+			doc.words = append(doc.words, word)
+		}
+		x.postings[word][t.TaskID] = append(x.postings[word][t.TaskID], pos)
+	}
+	for i, token := range name {
+		add(token.Word, i)
+	}
+	for i, token := range content {
+		add(token.Word, len(name)+1+i)
+	}
+
+	x.docs[t.TaskID] = doc
+}
+
+func (x *textIndex) remove(id uint64) {
+	for _, word := range x.docs[id].words {
+		delete(x.postings[word], id)
+		if len(x.postings[word]) == 0 {
+			delete(x.postings, word)
+		}
+	}
+	delete(x.docs, id)
+}
+
+func (r *taskRepo) Search(ctx context.Context, q *biz.SearchQuery) ([]model.T_SearchResult, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := r.space.text.match(q.Root())
+
+	result := make([]model.T_SearchResult, 0, len(scores))
+	for id, score := range scores {
+		result = append(result, model.T_SearchResult{Task: r.space.tasks[id], Score: math.Round(score*1000) / 1000})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Task.TaskID < result[j].Task.TaskID
+	})
+	if len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+
+	// Only the tasks returned are worth the work of a snippet
+	matched := q.Root().Matched()
+	for i := range result {
+		result[i].Snippet = snippet(result[i].Task.Content, matched)
+	}
+
+	return result, nil
+}
+
+// match returns the tasks matching the node, with their scores.
+func (x *textIndex) match(n *biz.SearchNode) map[uint64]float64 {
+	switch n.Op {
+	case biz.SearchTerm:
+		return x.term(n.Terms[0])
+	case biz.SearchPrefix:
+		result := map[uint64]float64{}
+		for word := range x.postings {
+			if strings.HasPrefix(word, n.Terms[0]) {
+				for id, score := range x.term(word) {
+					result[id] += score
+				}
+			}
+		}
+		return result
+	case biz.SearchPhrase:
+		return x.phrase(n.Terms)
+	case biz.SearchNot:
+		excluded := x.match(n.Children[0])
+		result := map[uint64]float64{}
+		for id := range x.docs {
+			if _, ok := excluded[id]; !ok {
+				result[id] = 0
+			}
+		}
+		return result
+	case biz.SearchOr:
+		result := map[uint64]float64{}
+		for _, child := range n.Children {
+			for id, score := range x.match(child) {
+				result[id] += score
+			}
+		}
+		return result
+	}
+
+	// AND keeps the tasks every child matches
+	result := x.match(n.Children[0])
+	for _, child := range n.Children[1:] {
+		scores := x.match(child)
+		for id := range result {
+			if score, ok := scores[id]; ok {
+				result[id] += score
+			} else {
+				delete(result, id)
+			}
+		}
+	}
+	return result
+}
+
+// term scores the tasks containing a word. Repeated words count with diminishing
+// returns, rare words count more than common ones, and names more than contents.
+func (x *textIndex) term(word string) map[uint64]float64 {
+	postings := x.postings[word]
+	result := make(map[uint64]float64, len(postings))
+	for id, positions := range postings {
+		tf := 0.0
+		for _, pos := range positions {
+			if pos < x.docs[id].nameLen {
+				tf += nameWeight
+			} else {
+				tf++
+			}
+		}
+		result[id] = x.idf(len(postings)) * saturate(tf)
+	}
+	return result
+}
+
+// phrase scores the tasks containing the words next to each other, in order.
+func (x *textIndex) phrase(words []string) map[uint64]float64 {
+	result := map[uint64]float64{}
+	for id, starts := range x.postings[words[0]] {
+		tf := 0.0
+		for _, start := range starts {
+			if x.follows(id, start, words[1:]) {
+				tf++
+			}
+		}
+		if tf == 0 {
+			continue
+		}
+
+		idf := 0.0
+		for _, word := range words {
+			idf += x.idf(len(x.postings[word]))
+		}
+		result[id] = idf * saturate(tf)
+	}
+	return result
+}
+
+// follows reports whether the words come right after the position in the task.
+func (x *textIndex) follows(id uint64, pos int, words []string) bool {
+	for i, word := range words {
+		found := false
+		for _, p := range x.postings[word][id] {
+			if p == pos+1+i {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (x *textIndex) idf(docs int) float64 {
+	return math.Log(1 + float64(len(x.docs))/float64(docs))
+}
+
+func saturate(tf float64) float64 {
+	return tf * 2.2 / (tf + 1.2)
+}
+
+// snippet returns an excerpt of the content around its first match, with the
+// matched words marked. Content without a match is excerpted from its start.
+func snippet(content string, matched []*biz.SearchNode) string {
+	tokens := biz.Tokenize(content)
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	marked := make([]bool, len(tokens))
+	first := -1
+	for i := range tokens {
+		for _, n := range matched {
+			if n.Op == biz.SearchPhrase {
+				if matchesPhrase(tokens, i, n.Terms) {
+					for j := range n.Terms {
+						marked[i+j] = true
+					}
+				}
+				continue
+			}
+			if tokens[i].Word == n.Terms[0] || (n.Op == biz.SearchPrefix && strings.HasPrefix(tokens[i].Word, n.Terms[0])) {
+				marked[i] = true
+			}
+		}
+		if marked[i] && first < 0 {
+			first = i
+		}
+	}
+
+	from := 0
+	if first > snippetBefore {
+		from = first - snippetBefore
+	}
+	to := from + snippetWords
+	if to > len(tokens) {
+		to = len(tokens)
+	}
+
+	// An excerpt reaching either end of the content keeps the text around its words
+	var b strings.Builder
+	start, end := 0, len(content)
+	if from > 0 {
+		b.WriteString("…")
+		start = tokens[from].Start
+	}
+	if to < len(tokens) {
+		end = tokens[to-1].End
+	}
+	for i := from; i < to; i++ {
+		if !marked[i] {
+			continue
+		}
+		b.WriteString(content[start:tokens[i].Start])
+		b.WriteString("**" + content[tokens[i].Start:tokens[i].End] + "**")
+		start = tokens[i].End
+	}
+	b.WriteString(content[start:end])
+	if to < len(tokens) {
+		b.WriteString("…")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func matchesPhrase(tokens []biz.Token, i int, words []string) bool {
+	if i+len(words) > len(tokens) {
+		return false
+	}
+	for j, word := range words {
+		if tokens[i+j].Word != word {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package data_test
+
+import (
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) search(query string) []model.T_SearchResult {
+	q := &biz.SearchQuery{Query: query}
+	s.Require().Nil(q.Validate())
+	results, err := s.taskRepo.Search(s.context, q)
+	s.Require().Nil(err)
+	return results
+}
+
+func (s *DataSourceTestSuite) searchIDs(query string) []uint64 {
+	var ids []uint64
+	for _, r := range s.search(query) {
+		ids = append(ids, r.Task.TaskID)
+	}
+	return ids
+}
+
+func (s *DataSourceTestSuite) Test_Search() {
+	for _, task := range []model.Task{
+		{Name: "Fix login bug", Content: "Users cannot log in after the password reset."},
+		{Name: "Write docs", Content: "Document the login flow and the password policy."},
+		{Name: "Release", Content: "Tag the release once the login bug is fixed."},
+		{Name: "Refactor", Content: "Split the reset handler."},
+	} {
+		_, err := s.taskRepo.Create(s.context, &task)
+		s.Require().Nil(err)
+	}
+
+	tests := []struct {
+		query string
+		want  []uint64
+	}{
+		// A word in the name ranks above one in the content
+		{query: "login", want: []uint64{1, 2, 3}},
+		{query: "LOGIN password", want: []uint64{1, 2}},
+		{query: `"login bug"`, want: []uint64{1, 3}},
+		{query: `"bug login"`, want: nil},
+		{query: "pass*", want: []uint64{1, 2}},
+		{query: "docs OR refactor", want: []uint64{2, 4}},
+		{query: "login -bug", want: []uint64{2}},
+		{query: "reset AND NOT (docs OR login)", want: []uint64{4}},
+		{query: "missing", want: nil},
+	}
+	for _, tt := range tests {
+		s.Require().Equal(tt.want, s.searchIDs(tt.query), tt.query)
+	}
+
+	q := &biz.SearchQuery{Query: "login", Limit: 2}
+	s.Require().Nil(q.Validate())
+	results, err := s.taskRepo.Search(s.context, q)
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(results))
+	s.Require().Greater(results[0].Score, results[1].Score)
+}
+
+func (s *DataSourceTestSuite) Test_Search_Snippet() {
+	content := "one two three four five six seven eight nine ten eleven twelve thirteen fourteen fifteen"
+	for _, task := range []model.Task{
+		{Name: "numbers", Content: content},
+		{Name: "empty"},
+	} {
+		_, err := s.taskRepo.Create(s.context, &task)
+		s.Require().Nil(err)
+	}
+
+	results := s.search("twelve OR thir*")
+	s.Require().Equal("…four five six seven eight nine ten eleven **twelve** **thirteen** fourteen fifteen", results[0].Snippet)
+
+	results = s.search(`"two three" -missing`)
+	s.Require().Equal("one **two** **three** four five six seven eight nine ten eleven twelve thirteen fourteen fifteen", results[0].Snippet)
+
+	// Tasks matched by name only excerpt their content from the start
+	results = s.search("numbers OR empty")
+	s.Require().Equal(content, results[0].Snippet)
+	s.Require().Equal("", results[1].Snippet)
+}
+
+func (s *DataSourceTestSuite) Test_Search_FollowsChanges() {
+	_, err := s.taskRepo.Create(s.context, &model.Task{Name: "draft", Content: "old words"})
+	s.Require().Nil(err)
+
+	_, err = s.taskRepo.Update(s.context, &model.Task{TaskID: 1, Name: "final", Content: "new words"}, 0)
+	s.Require().Nil(err)
+	s.Require().Nil(s.searchIDs("draft OR old"))
+	s.Require().Equal([]uint64{1}, s.searchIDs("final new"))
+
+	// Deleted tasks are not found until they are restored
+	s.Require().Nil(s.taskRepo.Delete(s.context, 1))
+	s.Require().Nil(s.searchIDs("final"))
+	s.Require().Nil(s.searchIDs("-missing"))
+
+	_, err = s.taskRepo.Restore(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal([]uint64{1}, s.searchIDs("final"))
+
+	s.Require().Nil(s.taskRepo.Empty(s.context))
+	s.Require().Nil(s.searchIDs("final"))
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_RebuildsSearchIndex() {
+	taskRepo, _ := s.open()
+
+	for _, name := range []string{"alpha release", "beta release"} {
+		_, err := taskRepo.Create(s.context, &model.Task{Name: name})
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(taskRepo.Delete(s.context, 1))
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	q := &biz.SearchQuery{Query: "release"}
+	s.Require().Nil(q.Validate())
+	results, err := taskRepo.Search(s.context, q)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(results))
+	s.Require().Equal(uint64(2), results[0].Task.TaskID)
+}
@@ -0,0 +1,320 @@
+package data
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) CreateSeries(ctx context.Context, s *model.Series, first time.Time) (*model.T_Series, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, e, err := r.seriesCreated(ctx, s, first)
+	if err != nil {
+		return nil, err
+	}
+	r.data.apply(e)
+	s.SeriesID = val.SeriesID
+
+	return &val, nil
+}
+
+func (r *taskRepo) GetSeries(ctx context.Context, id uint64) (*model.T_Series, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.storedSeries(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &val, nil
+}
+
+func (r *taskRepo) ListSeries(ctx context.Context) ([]model.T_Series, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.T_Series, 0, len(r.space.series))
+	for _, s := range r.space.series {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].SeriesID < result[j].SeriesID })
+
+	return result, nil
+}
+
+func (r *taskRepo) UpdateSeries(ctx context.Context, s *model.Series, version uint64) (*model.T_Series, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, tasks, err := r.seriesUpdated(s, version)
+	if err != nil {
+		return nil, err
+	}
+
+	r.data.apply(walEntry{Op: walOpSeries, Tenant: r.tenant, Series: &val})
+	for _, task := range tasks {
+		r.data.apply(r.put(ctx, task, model.RevisionUpdated))
+	}
+
+	return &val, nil
+}
+
+func (r *taskRepo) StopSeries(ctx context.Context, id uint64) (*model.T_Series, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, changed, err := r.seriesStopped(id)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		r.data.apply(walEntry{Op: walOpSeries, Tenant: r.tenant, Series: &val})
+	}
+
+	return &val, nil
+}
+
+func (r *taskRepo) AdvanceSeries(ctx context.Context, id uint64, version uint64, next *time.Time) (*model.T_Series, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, e, err := r.seriesAdvanced(ctx, id, version, next)
+	if err != nil {
+		return nil, err
+	}
+	r.data.apply(e)
+
+	return &val, nil
+}
+
+func (r *taskRepo) DueSeries(ctx context.Context, now time.Time) ([]model.T_Series, *time.Time, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	due := make([]model.T_Series, 0)
+	var next *time.Time
+	for _, s := range r.space.series {
+		if s.Stopped() {
+			continue
+		}
+		current, ok := r.space.tasks[s.CurrentTaskID]
+		if !ok || current.DeletedAt != nil || current.Finished() || !s.CurrentAt.After(now) {
+			due = append(due, s)
+		} else if next == nil || s.CurrentAt.Before(*next) {
+			next = s.CurrentAt
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].SeriesID < due[j].SeriesID })
+
+	return due, next, nil
+}
+
+// storedSeries returns the stored series.
+func (r *taskRepo) storedSeries(id uint64) (model.T_Series, error) {
+	val, ok := r.space.series[id]
+
+	// Series not exist
+	if !ok {
+		return model.T_Series{}, model.ErrorSeriesNotFound(string(encoder.SERIES_NOT_EXIST))
+	}
+
+	return val, nil
+}
+
+// seriesCreated allocates the next series ID, and returns the new series with the
+// log entry storing it along with its first occurrence, provided the quotas of the
+// tenant allow both. Like created, it leaves s as it is. The caller must hold the
+// write lock until the entry is applied.
+func (r *taskRepo) seriesCreated(ctx context.Context, s *model.Series, first time.Time) (model.T_Series, walEntry, error) {
+	if err := r.withinQuota(true); err != nil {
+		return model.T_Series{}, walEntry{}, err
+	}
+
+	occurrence := s.Occurrence(first)
+	task, err := r.created(&occurrence, biz.ActorFromContext(ctx))
+	if err != nil {
+		return model.T_Series{}, walEntry{}, err
+	}
+	series := *s
+	series.SeriesID = r.space.seriesIndex + 1
+	task.SeriesID = series.SeriesID
+
+	val := model.T_Series{Series: series, CreatedAt: task.CreatedAt, CreatedBy: task.CreatedBy, Version: 1,
+		Occurrences: 1, CurrentTaskID: task.TaskID, CurrentAt: &first}
+
+	e := r.put(ctx, task, model.RevisionCreated)
+	e.Op = walOpSeries
+	e.Series = &val
+	return val, e, nil
+}
+
+// seriesUpdated returns the stored series with s applied over it, provided it is
+// still at the given version, together with its live occurrences which are not
+// finished, changed to match. A version of 0 skips the check.
+func (r *taskRepo) seriesUpdated(s *model.Series, version uint64) (model.T_Series, []model.T_Task, error) {
+	val, err := r.storedSeries(s.SeriesID)
+	if err != nil {
+		return model.T_Series{}, nil, err
+	}
+
+	// Series has been changed since the client read it
+	if version != 0 && version != val.Version {
+		return model.T_Series{}, nil, model.ErrorSeriesVersionMismatch(string(encoder.SERIES_VERSION_MISMATCH))
+	}
+
+	val.Series = *s
+	nt := time.Now()
+	val.UpdatedAt = &nt
+	val.Version++
+
+	var tasks []model.T_Task
+	for _, id := range sortedIDs(r.space.occurrences[s.SeriesID]) {
+		task := r.space.tasks[id]
+		if task.DeletedAt != nil || task.Finished() {
+			continue
+		}
+
+		changed := task.Task
+		changed.Name, changed.Content, changed.ParentID, changed.Tags = s.Name, s.Content, s.ParentID, s.Tags
+		if reflect.DeepEqual(changed, task.Task) {
+			continue
+		}
+		task, err := r.updated(&changed, 0)
+		if err != nil {
+			return model.T_Series{}, nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return val, tasks, nil
+}
+
+// seriesStopped returns the stored series stopped, and whether it was running.
+func (r *taskRepo) seriesStopped(id uint64) (model.T_Series, bool, error) {
+	val, err := r.storedSeries(id)
+	if err != nil || val.Stopped() {
+		return val, false, err
+	}
+
+	nt := time.Now()
+	val.StoppedAt = &nt
+	val.UpdatedAt = &nt
+	val.Version++
+
+	return val, true, nil
+}
+
+// seriesAdvanced returns the series with its next occurrence, due at the given
+// time, and the log entry storing both, provided the series is still running at
+// the given version. A nil time stops the series instead. The caller must hold the
+// write lock until the entry is applied.
+func (r *taskRepo) seriesAdvanced(ctx context.Context, id uint64, version uint64, next *time.Time) (model.T_Series, walEntry, error) {
+	val, err := r.storedSeries(id)
+	if err != nil {
+		return model.T_Series{}, walEntry{}, err
+	}
+
+	// Series has been advanced, changed or stopped since the caller read it
+	if version != val.Version || val.Stopped() {
+		return model.T_Series{}, walEntry{}, model.ErrorSeriesVersionMismatch(string(encoder.SERIES_VERSION_MISMATCH))
+	}
+
+	nt := time.Now()
+	val.UpdatedAt = &nt
+	val.Version++
+	if next == nil {
+		val.StoppedAt = &nt
+		return val, walEntry{Op: walOpSeries, Tenant: r.tenant, Series: &val}, nil
+	}
+
+	if err := r.withinQuota(false); err != nil {
+		return model.T_Series{}, walEntry{}, err
+	}
+
+	// The parent of the series may have gone since, in which case the occurrence is a top level task
+	occurrence := val.Occurrence(*next)
+	if parent, ok := r.space.tasks[occurrence.ParentID]; occurrence.ParentID != 0 && (!ok || parent.DeletedAt != nil) {
+		occurrence.ParentID = 0
+	}
+	task, err := r.created(&occurrence, val.CreatedBy)
+	if err != nil {
+		return model.T_Series{}, walEntry{}, err
+	}
+	task.SeriesID = id
+
+	val.Occurrences++
+	val.CurrentTaskID = task.TaskID
+	val.CurrentAt = next
+
+	e := r.put(ctx, task, model.RevisionCreated)
+	e.Op = walOpSeries
+	e.Series = &val
+	return val, e, nil
+}
+
+// sortedIDs returns the IDs of a set in ascending order.
+func sortedIDs(ids map[uint64]struct{}) []uint64 {
+	result := make([]uint64, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
@@ -0,0 +1,125 @@
+package data_test
+
+import (
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) Test_Series() {
+	start := time.Now().Truncate(time.Second)
+	first := start.Add(time.Hour)
+	series, err := s.taskRepo.CreateSeries(s.context, &model.Series{Rule: "FREQ=DAILY", Start: &start, Name: "bins", Tags: []string{"chores"}}, first)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(1), series.SeriesID)
+	s.Require().Equal(uint64(1), series.Occurrences)
+
+	// The first occurrence is created with the series
+	task, err := s.taskRepo.Get(s.context, series.CurrentTaskID)
+	s.Require().Nil(err)
+	s.Require().Equal("bins", task.Name)
+	s.Require().Equal(uint64(1), task.SeriesID)
+	s.Require().True(task.DueAt.Equal(first))
+
+	// Nothing is due until the occurrence is
+	due, next, err := s.taskRepo.DueSeries(s.context, start)
+	s.Require().Nil(err)
+	s.Require().Equal(0, len(due))
+	s.Require().True(next.Equal(first))
+	due, _, err = s.taskRepo.DueSeries(s.context, first)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(due))
+
+	// Advancing is conditional on the version, so it happens once
+	second := first.AddDate(0, 0, 1)
+	series, err = s.taskRepo.AdvanceSeries(s.context, 1, 1, &second)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(2), series.Occurrences)
+	s.Require().Equal(uint64(2), series.CurrentTaskID)
+	_, err = s.taskRepo.AdvanceSeries(s.context, 1, 1, &second)
+	s.Require().True(model.IsSeriesVersionMismatch(err))
+
+	// A finished occurrence makes the series due
+	_, err = s.taskRepo.Transition(s.context, 2, model.StatusDone, 0)
+	s.Require().Nil(err)
+	due, _, err = s.taskRepo.DueSeries(s.context, start)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(due))
+
+	q := biz.TaskQuery{Filter: "seriesID = 1"}
+	s.Require().Nil(q.Validate())
+	page, err := s.taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(page.Tasks))
+
+	_, err = s.taskRepo.StopSeries(s.context, 2)
+	s.Require().True(model.IsSeriesNotFound(err))
+	series, err = s.taskRepo.StopSeries(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().True(series.Stopped())
+	due, next, err = s.taskRepo.DueSeries(s.context, second)
+	s.Require().Nil(err)
+	s.Require().Equal(0, len(due))
+	s.Require().Nil(next)
+}
+
+func (s *DataSourceTestSuite) Test_UpdateSeries() {
+	start := time.Now().Truncate(time.Second)
+	series, err := s.taskRepo.CreateSeries(s.context, &model.Series{Rule: "FREQ=DAILY", Start: &start, Name: "bins"}, start)
+	s.Require().Nil(err)
+	for i := 1; i <= 2; i++ {
+		next := start.AddDate(0, 0, i)
+		series, err = s.taskRepo.AdvanceSeries(s.context, 1, series.Version, &next)
+		s.Require().Nil(err)
+	}
+	_, err = s.taskRepo.Transition(s.context, 1, model.StatusCancelled, 0)
+	s.Require().Nil(err)
+	s.Require().Nil(s.taskRepo.Delete(s.context, 2))
+
+	_, err = s.taskRepo.UpdateSeries(s.context, &model.Series{SeriesID: 1, Rule: "FREQ=DAILY", Start: &start, Name: "recycling"}, 1)
+	s.Require().True(model.IsSeriesVersionMismatch(err))
+	series, err = s.taskRepo.UpdateSeries(s.context, &model.Series{SeriesID: 1, Rule: "FREQ=WEEKLY", Start: &start, Name: "recycling"}, series.Version)
+	s.Require().Nil(err)
+	s.Require().Equal("FREQ=WEEKLY", series.Rule)
+
+	// Only the open occurrence takes the change, keeping its due date
+	page, err := s.taskRepo.List(s.context, &biz.TaskQuery{})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(page.Tasks))
+	s.Require().Equal("bins", page.Tasks[0].Name)
+	s.Require().Equal("recycling", page.Tasks[1].Name)
+	s.Require().True(page.Tasks[1].DueAt.Equal(start.AddDate(0, 0, 2)))
+	s.Require().Equal(uint64(2), page.Tasks[1].Version)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsSeries() {
+	s.conf.Wal.CompactEvery = 3
+	taskRepo, _ := s.open()
+
+	start := time.Now().Truncate(time.Second)
+	for _, name := range []string{"bins", "plants"} {
+		_, err := taskRepo.CreateSeries(s.context, &model.Series{Rule: "@daily", Start: &start, Name: name}, start)
+		s.Require().Nil(err)
+	}
+	next := start.AddDate(0, 0, 1)
+	_, err := taskRepo.AdvanceSeries(s.context, 2, 1, &next)
+	s.Require().Nil(err)
+	_, err = taskRepo.StopSeries(s.context, 1)
+	s.Require().Nil(err)
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	series, err := taskRepo.ListSeries(s.context)
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(series))
+	s.Require().True(series[0].Stopped())
+	s.Require().Equal(uint64(3), series[1].CurrentTaskID)
+
+	// Both ID sequences carry on where they were
+	created, err := taskRepo.CreateSeries(s.context, &model.Series{Rule: "@daily", Start: &start, Name: "post"}, next)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(3), created.SeriesID)
+	s.Require().Equal(uint64(4), created.CurrentTaskID)
+}
@@ -0,0 +1,132 @@
+package data
+
+import (
+	"context"
+	"sort"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) Children(ctx context.Context, id uint64) ([]model.T_Task, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := r.space.tasks[id]
+
+	// Task not exist
+	if !ok {
+		return nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	// Task has been deleted
+	if val.DeletedAt != nil {
+		return nil, model.ErrorTaskNotFound(string(encoder.TASK_DELETED))
+	}
+
+	return r.liveChildren(id), nil
+}
+
+func (r *taskRepo) DeleteCascade(ctx context.Context, id uint64) (int, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := r.subtree(id)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		val, err := r.deleted(id)
+		if err != nil {
+			return 0, err
+		}
+		r.data.apply(r.put(ctx, val, model.RevisionDeleted))
+	}
+
+	return len(ids), nil
+}
+
+// liveChildren returns the subtasks of a task which have not been deleted, in
+// the order of their IDs. The caller must hold the lock.
+func (r *taskRepo) liveChildren(id uint64) []model.T_Task {
+	result := make([]model.T_Task, 0, len(r.space.children[id]))
+	for child := range r.space.children[id] {
+		if task := r.space.tasks[child]; task.DeletedAt == nil {
+			result = append(result, task)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].TaskID < result[j].TaskID })
+	return result
+}
+
+// subtree returns the IDs of a live task and of all its live descendants, every
+// subtask before its parent, so that they can be deleted in that order.
+func (r *taskRepo) subtree(id uint64) ([]uint64, error) {
+	val, ok := r.space.tasks[id]
+
+	// Task not exist
+	if !ok {
+		return nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	// Task has been deleted
+	if val.DeletedAt != nil {
+		return nil, model.ErrorTaskNotFound(string(encoder.TASK_DELETED))
+	}
+
+	var ids []uint64
+	visited := map[uint64]bool{}
+	var walk func(uint64)
+	walk = func(id uint64) {
+		visited[id] = true
+		for _, child := range r.liveChildren(id) {
+			if !visited[child.TaskID] {
+				walk(child.TaskID)
+			}
+		}
+		ids = append(ids, id)
+	}
+	walk(id)
+
+	return ids, nil
+}
+
+// validParent checks that the task can be placed under the given parent: the parent
+// must be a live task, and the task must not be one of its ancestors. A task which
+// is being created has the ID 0. The caller must hold the write lock until the
+// task is applied, so that no other change can make a cycle in the meantime.
+func (r *taskRepo) validParent(id uint64, parentID uint64) error {
+	if parentID == 0 {
+		return nil
+	}
+	if parentID == id {
+		return model.ErrorTaskInvalidParent("%s: task %d cannot be its own parent", encoder.TASK_PARENT_INVALID, id)
+	}
+	if !r.live(parentID) {
+		return model.ErrorTaskInvalidParent("%s: parent task %d does not exist", encoder.TASK_PARENT_INVALID, parentID)
+	}
+
+	visited := map[uint64]bool{}
+	for ancestor := parentID; ancestor != 0 && !visited[ancestor]; ancestor = r.space.tasks[ancestor].ParentID {
+		visited[ancestor] = true
+		if id != 0 && ancestor == id {
+			return model.ErrorTaskInvalidParent("%s: task %d is an ancestor of task %d", encoder.TASK_PARENT_INVALID, id, parentID)
+		}
+	}
+	return nil
+}
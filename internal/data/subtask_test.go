@@ -0,0 +1,163 @@
+package data_test
+
+import (
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) Test_Children() {
+	parent, err := s.taskRepo.Create(s.context, &model.Task{Name: "parent"})
+	s.Require().Nil(err)
+	for _, name := range []string{"child 1", "child 2", "child 3"} {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: name, ParentID: parent.TaskID})
+		s.Require().Nil(err)
+	}
+
+	// Moving a subtask elsewhere takes it out of the children
+	_, err = s.taskRepo.Update(s.context, &model.Task{TaskID: 3, Name: "child 2"}, 0)
+	s.Require().Nil(err)
+
+	children, err := s.taskRepo.Children(s.context, parent.TaskID)
+	s.Require().Nil(err)
+	s.Require().Len(children, 2)
+	s.Require().Equal(uint64(2), children[0].TaskID)
+	s.Require().Equal(uint64(4), children[1].TaskID)
+
+	children, err = s.taskRepo.Children(s.context, 4)
+	s.Require().Nil(err)
+	s.Require().Empty(children)
+
+	_, err = s.taskRepo.Children(s.context, 99)
+	s.Require().True(model.IsTaskNotFound(err))
+}
+
+func (s *DataSourceTestSuite) Test_Delete_RejectsParent() {
+	_, err := s.taskRepo.Create(s.context, &model.Task{Name: "parent"})
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Create(s.context, &model.Task{Name: "child", ParentID: 1})
+	s.Require().Nil(err)
+
+	err = s.taskRepo.Delete(s.context, 1)
+	s.Require().True(model.IsTaskHasChildren(err))
+
+	// Once its subtasks are gone the parent can be deleted
+	s.Require().Nil(s.taskRepo.Delete(s.context, 2))
+	s.Require().Nil(s.taskRepo.Delete(s.context, 1))
+
+	children, err := s.taskRepo.Children(s.context, 1)
+	s.Require().True(model.IsTaskNotFound(err))
+	s.Require().Nil(children)
+}
+
+func (s *DataSourceTestSuite) Test_DeleteCascade() {
+	// 1 <- 2 <- 3, 1 <- 4, and 5 on its own
+	for _, task := range []model.Task{{Name: "1"}, {Name: "2", ParentID: 1}, {Name: "3", ParentID: 2}, {Name: "4", ParentID: 1}, {Name: "5"}} {
+		_, err := s.taskRepo.Create(s.context, &task)
+		s.Require().Nil(err)
+	}
+
+	n, err := s.taskRepo.DeleteCascade(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(4, n)
+
+	for id := uint64(1); id <= 4; id++ {
+		_, err := s.taskRepo.Get(s.context, id)
+		s.Require().True(model.IsTaskNotFound(err), "task %d", id)
+
+		history, err := s.taskRepo.History(s.context, id)
+		s.Require().Nil(err)
+		s.Require().Equal(model.RevisionDeleted, history[len(history)-1].Action)
+	}
+	_, err = s.taskRepo.Get(s.context, 5)
+	s.Require().Nil(err)
+
+	_, err = s.taskRepo.DeleteCascade(s.context, 1)
+	s.Require().True(model.IsTaskNotFound(err))
+}
+
+func (s *DataSourceTestSuite) Test_Restore_Subtask() {
+	for _, task := range []model.Task{{Name: "parent"}, {Name: "child", ParentID: 1}} {
+		_, err := s.taskRepo.Create(s.context, &task)
+		s.Require().Nil(err)
+	}
+	_, err := s.taskRepo.DeleteCascade(s.context, 1)
+	s.Require().Nil(err)
+
+	// A subtask comes back only after its parent
+	_, err = s.taskRepo.Restore(s.context, 2)
+	s.Require().True(model.IsTaskInvalidParent(err))
+
+	_, err = s.taskRepo.Restore(s.context, 1)
+	s.Require().Nil(err)
+	child, err := s.taskRepo.Restore(s.context, 2)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(1), child.ParentID)
+
+	// With its parent purged, a subtask is restored as a top level task
+	_, err = s.taskRepo.DeleteCascade(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Nil(s.taskRepo.Purge(s.context, 1))
+
+	child, err = s.taskRepo.Restore(s.context, 2)
+	s.Require().Nil(err)
+	s.Require().Zero(child.ParentID)
+}
+
+func (s *DataSourceTestSuite) Test_InvalidParent() {
+	// 1 <- 2 <- 3, and 4 deleted
+	for _, task := range []model.Task{{Name: "1"}, {Name: "2", ParentID: 1}, {Name: "3", ParentID: 2}, {Name: "4"}} {
+		_, err := s.taskRepo.Create(s.context, &task)
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(s.taskRepo.Delete(s.context, 4))
+
+	// The parent must be a live task
+	for _, parentID := range []uint64{4, 99} {
+		task := model.Task{Name: "orphan", ParentID: parentID}
+		_, err := s.taskRepo.Create(s.context, &task)
+		s.Require().True(model.IsTaskInvalidParent(err))
+		s.Require().Zero(task.TaskID)
+		_, err = s.taskRepo.Update(s.context, &model.Task{TaskID: 1, Name: "1", ParentID: parentID}, 0)
+		s.Require().True(model.IsTaskInvalidParent(err))
+	}
+
+	// A task can be placed under neither itself nor one of its descendants
+	for _, parentID := range []uint64{1, 2, 3} {
+		_, err := s.taskRepo.Update(s.context, &model.Task{TaskID: 1, Name: "1", ParentID: parentID}, 0)
+		s.Require().True(model.IsTaskInvalidParent(err))
+	}
+
+	// Nor brought back to a parent which has since become its descendant
+	_, err := s.taskRepo.Update(s.context, &model.Task{TaskID: 3, Name: "3"}, 0)
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Update(s.context, &model.Task{TaskID: 2, Name: "2", ParentID: 3}, 0)
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Revert(s.context, 3, 1)
+	s.Require().True(model.IsTaskInvalidParent(err))
+
+	task, err := s.taskRepo.Get(s.context, 3)
+	s.Require().Nil(err)
+	s.Require().Zero(task.ParentID)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsChildren() {
+	taskRepo, _ := s.open()
+
+	for _, task := range []model.Task{{Name: "parent"}, {Name: "child 1", ParentID: 1}, {Name: "child 2", ParentID: 1}, {Name: "grandchild", ParentID: 3}} {
+		_, err := taskRepo.Create(s.context, &task)
+		s.Require().Nil(err)
+	}
+	n, err := taskRepo.DeleteCascade(s.context, 3)
+	s.Require().Nil(err)
+	s.Require().Equal(2, n)
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	children, err := taskRepo.Children(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Len(children, 1)
+	s.Require().Equal(uint64(2), children[0].TaskID)
+
+	err = taskRepo.Delete(s.context, 1)
+	s.Require().True(model.IsTaskHasChildren(err))
+}
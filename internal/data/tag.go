@@ -0,0 +1,134 @@
+package data
+
+import (
+	"context"
+	"sort"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) Tags(ctx context.Context) ([]model.T_TagCount, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.T_TagCount, 0, len(r.space.tags))
+	for tag, ids := range r.space.tags {
+		count := 0
+		for id := range ids {
+			if r.space.tasks[id].DeletedAt == nil {
+				count++
+			}
+		}
+		if count > 0 {
+			result = append(result, model.T_TagCount{Tag: tag, Count: count})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tag < result[j].Tag })
+
+	return result, nil
+}
+
+func (r *taskRepo) RenameTag(ctx context.Context, from string, to string) (int, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tasks, err := r.retagged(from, to)
+	if err != nil {
+		return 0, err
+	}
+	for _, val := range tasks {
+		r.data.apply(r.put(ctx, val, model.RevisionUpdated))
+	}
+
+	return len(tasks), nil
+}
+
+// tagged returns the IDs of the tasks carrying all or any of the tags, from the
+// tag index. The caller must hold the lock.
+func (r *taskRepo) tagged(tags []string, match biz.TagMatch) []uint64 {
+	var ids []uint64
+
+	if match == biz.TagMatchAny {
+		seen := map[uint64]bool{}
+		for _, tag := range tags {
+			for id := range r.space.tags[tag] {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+		return ids
+	}
+
+	// Walk the rarest tag and check the others against the index
+	sets := make([]map[uint64]struct{}, 0, len(tags))
+	for _, tag := range tags {
+		sets = append(sets, r.space.tags[tag])
+	}
+	sort.Slice(sets, func(i, j int) bool { return len(sets[i]) < len(sets[j]) })
+
+	for id := range sets[0] {
+		all := true
+		for _, set := range sets[1:] {
+			if _, ok := set[id]; !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// retagged returns the stored records of every task carrying a tag, with the tag
+// renamed, in the order of their IDs. Where a task already carries the new tag the
+// two are merged.
+func (r *taskRepo) retagged(from string, to string) ([]model.T_Task, error) {
+	ids, ok := r.space.tags[from]
+	if !ok {
+		return nil, model.ErrorTaskTagNotFound("%s: %q", encoder.TASK_TAG_NOT_EXIST, from)
+	}
+	if from == to {
+		return nil, nil
+	}
+
+	result := make([]model.T_Task, 0, len(ids))
+	for id := range ids {
+		val := r.space.tasks[id]
+
+		tags := make([]string, 0, len(val.Tags))
+		for _, tag := range val.Tags {
+			if tag != from && tag != to {
+				tags = append(tags, tag)
+			}
+		}
+		tags = append(tags, to)
+		sort.Strings(tags)
+		val.Tags = tags
+
+		r.touch(&val)
+		result = append(result, val)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].TaskID < result[j].TaskID })
+
+	return result, nil
+}
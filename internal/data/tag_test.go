@@ -0,0 +1,96 @@
+package data_test
+
+import (
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) listTagged(tags []string, match biz.TagMatch) []uint64 {
+	page, err := s.taskRepo.List(s.context, &biz.TaskQuery{Tags: tags, TagMatch: match})
+	s.Require().Nil(err)
+	var ids []uint64
+	for _, task := range page.Tasks {
+		ids = append(ids, task.TaskID)
+	}
+	return ids
+}
+
+func (s *DataSourceTestSuite) Test_ListTask_Tags() {
+	for _, tags := range [][]string{{"bug", "urgent"}, {"bug"}, {"feature", "urgent"}, nil} {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: "task", Tags: tags})
+		s.Require().Nil(err)
+	}
+
+	s.Require().Equal([]uint64{1, 2}, s.listTagged([]string{"bug"}, biz.TagMatchAll))
+	s.Require().Equal([]uint64{1}, s.listTagged([]string{"bug", "urgent"}, biz.TagMatchAll))
+	s.Require().Equal([]uint64{1, 2, 3}, s.listTagged([]string{"bug", "urgent"}, biz.TagMatchAny))
+	s.Require().Nil(s.listTagged([]string{"bug", "missing"}, biz.TagMatchAll))
+
+	// The index follows updates and deletes
+	_, err := s.taskRepo.Update(s.context, &model.Task{TaskID: 1, Name: "task", Tags: []string{"urgent"}}, 0)
+	s.Require().Nil(err)
+	s.Require().Nil(s.taskRepo.Delete(s.context, 2))
+	s.Require().Nil(s.listTagged([]string{"bug"}, biz.TagMatchAll))
+	s.Require().Equal([]uint64{1, 3}, s.listTagged([]string{"urgent"}, biz.TagMatchAll))
+
+	tags, err := s.taskRepo.Tags(s.context)
+	s.Require().Nil(err)
+	s.Require().Equal([]model.T_TagCount{{Tag: "feature", Count: 1}, {Tag: "urgent", Count: 2}}, tags)
+}
+
+func (s *DataSourceTestSuite) Test_RenameTag() {
+	for _, tags := range [][]string{{"bug", "defect"}, {"defect"}, {"bug"}} {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: "task", Tags: tags})
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(s.taskRepo.Delete(s.context, 2))
+
+	// Task 1 carries both, so the tags are merged
+	n, err := s.taskRepo.RenameTag(s.context, "defect", "bug")
+	s.Require().Nil(err)
+	s.Require().Equal(2, n)
+
+	task, err := s.taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal([]string{"bug"}, task.Tags)
+	s.Require().Equal(uint64(2), task.Version)
+
+	history, err := s.taskRepo.History(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal("tags", history[1].Changes[0].Field)
+
+	// Deleted tasks are renamed too, so they come back with the new tag
+	task, err = s.taskRepo.Restore(s.context, 2)
+	s.Require().Nil(err)
+	s.Require().Equal([]string{"bug"}, task.Tags)
+
+	tags, err := s.taskRepo.Tags(s.context)
+	s.Require().Nil(err)
+	s.Require().Equal([]model.T_TagCount{{Tag: "bug", Count: 3}}, tags)
+
+	_, err = s.taskRepo.RenameTag(s.context, "defect", "bug")
+	s.Require().True(model.IsTaskTagNotFound(err))
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_RebuildsTagIndex() {
+	taskRepo, _ := s.open()
+
+	for _, tags := range [][]string{{"bug"}, {"bug", "ui"}} {
+		_, err := taskRepo.Create(s.context, &model.Task{Name: "task", Tags: tags})
+		s.Require().Nil(err)
+	}
+	_, err := taskRepo.RenameTag(s.context, "ui", "frontend")
+	s.Require().Nil(err)
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	page, err := taskRepo.List(s.context, &biz.TaskQuery{Tags: []string{"bug", "frontend"}})
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(page.Tasks))
+	s.Require().Equal(uint64(2), page.Tasks[0].TaskID)
+
+	tags, err := taskRepo.Tags(s.context)
+	s.Require().Nil(err)
+	s.Require().Equal([]model.T_TagCount{{Tag: "bug", Count: 2}, {Tag: "frontend", Count: 1}}, tags)
+}
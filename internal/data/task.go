@@ -2,40 +2,183 @@ package data
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/go-kratos/kratos/v2/log"
-	"golang.org/x/exp/maps"
 	"qantas.com/task/internal/biz"
 	"qantas.com/task/internal/encoder"
 	"qantas.com/task/model"
-	"robpike.io/filter"
 )
 
+// scanCheckEvery is how many tasks a listing scans between checks of its context.
+const scanCheckEvery = 1024
+
 type taskRepo struct {
-	index uint64
-	data  *Data
-	log   *log.Helper
+	data *Data
+	log  *log.Helper
+	// tenant is the tenant the repo has been scoped to by in, and space what it stores.
+	tenant string
+	space  *space
 }
 
+// NewTaskRepo returns the durable task repo when the write-ahead log is configured,
+// and the in-memory one otherwise. It also starts the trash retention job.
 func NewTaskRepo(data *Data, logger log.Logger) biz.ITaskRepo {
-	return &taskRepo{
+	repo := &taskRepo{
 		data: data,
 		log:  log.NewHelper(logger),
 	}
+	var r biz.ITaskRepo = repo
+	if data.wal != nil {
+		r = &durableTaskRepo{taskRepo: repo, wal: data.wal}
+	}
+	data.startRetention(r, repo.log)
+	return r
+}
+
+// in returns the repo scoped to the tenant of the request, which must exist. The
+// caller must hold the lock for as long as it uses the scoped repo. The policy
+// check the request carries on a task is made here, under that lock, against the
+// project the task is on, see biz.TaskCheck.
+func (r *taskRepo) in(ctx context.Context) (*taskRepo, error) {
+	scoped, err := r.scoped(biz.TenantFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if c := biz.TaskCheckFromContext(ctx); c != nil {
+		if err := c.Allow(scoped.space.tasks[c.TaskID].ProjectID); err != nil {
+			return nil, err
+		}
+	}
+	return scoped, nil
+}
+
+// scoped returns the repo scoped to the tenant.
+func (r *taskRepo) scoped(tenant string) (*taskRepo, error) {
+	s, ok := r.data.spaces[tenant]
+	if !ok {
+		return nil, model.ErrorTenantNotFound("%s: %s", encoder.TENANT_NOT_EXIST, tenant)
+	}
+	return &taskRepo{data: r.data, log: r.log, tenant: tenant, space: s}, nil
 }
 
-func (r *taskRepo) List(context.Context) ([]model.T_Task, error) {
-	tasks := maps.Values(r.data.tasks)
-	result := filter.Choose(tasks, func(task model.T_Task) bool {
-		return task.DeletedAt == nil
-	}).([]model.T_Task)
+func (r *taskRepo) List(ctx context.Context, q *biz.TaskQuery) (*biz.TaskPage, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.T_Task, 0)
+	scanned := 0
+	visit := func(task *model.T_Task) error {
+		// Give up on a long scan once the caller has stopped waiting
+		if scanned++; scanned%scanCheckEvery == 0 {
+			if err := contextError(ctx); err != nil {
+				return err
+			}
+		}
+		if q.Match(task) && (!q.Ready || r.ready(task)) {
+			result = append(result, *task)
+		}
+		return nil
+	}
 
-	return result, nil
+	// A query on indexed fields only looks at the tasks the indexes return
+	if ids, ok := r.candidates(q); ok {
+		for id := range ids {
+			task, found := r.space.tasks[id]
+			if !found {
+				continue
+			}
+			if err := visit(&task); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for _, task := range r.space.tasks {
+			if err := visit(&task); err != nil {
+				return nil, err
+			}
+		}
+	}
+	q.Sort(result)
+
+	return q.Page(result), nil
+}
+
+// candidates returns the IDs of the tasks the indexes narrow a query down to: those
+// of the user of the query, those with its tags, and those passing the comparisons
+// of its filter on tags, users, parents, series and IDs. It returns false when no
+// index applies.
+func (r *taskRepo) candidates(q *biz.TaskQuery) (map[uint64]struct{}, bool) {
+	var result map[uint64]struct{}
+	// The index sets are shared, so they are intersected into new sets
+	narrow := func(ids map[uint64]struct{}) {
+		if ids == nil {
+			ids = map[uint64]struct{}{}
+		}
+		if result == nil {
+			result = ids
+			return
+		}
+		kept := make(map[uint64]struct{})
+		for id := range ids {
+			if _, ok := result[id]; ok {
+				kept[id] = struct{}{}
+			}
+		}
+		result = kept
+	}
+
+	if q.User != "" {
+		narrow(r.space.users[q.User])
+	}
+	if len(q.Tags) > 0 {
+		ids := make(map[uint64]struct{})
+		for _, id := range r.tagged(q.Tags, q.TagMatch) {
+			ids[id] = struct{}{}
+		}
+		narrow(ids)
+	}
+	for _, c := range q.FilterClauses() {
+		if c.Op != biz.FilterEq {
+			continue
+		}
+		switch {
+		case c.Field == biz.FilterTag:
+			narrow(r.space.tags[c.Value])
+		case (c.Field == biz.FilterOwner || c.Field == biz.FilterAssignee || c.Field == biz.FilterCreatedBy) && c.Value != "":
+			narrow(r.space.users[c.Value])
+		case c.Field == biz.FilterParentID && c.Number() != 0:
+			narrow(r.space.children[c.Number()])
+		case c.Field == biz.FilterSeriesID && c.Number() != 0:
+			narrow(r.space.occurrences[c.Number()])
+		case c.Field == biz.FilterTaskID:
+			narrow(map[uint64]struct{}{c.Number(): {}})
+		}
+	}
+
+	return result, result != nil
 }
 
 func (r *taskRepo) Get(ctx context.Context, id uint64) (*model.T_Task, error) {
-	val, ok := r.data.tasks[id]
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := r.space.tasks[id]
 
 	// Task not exist
 	if !ok {
@@ -52,62 +195,353 @@ func (r *taskRepo) Get(ctx context.Context, id uint64) (*model.T_Task, error) {
 }
 
 func (r *taskRepo) Create(ctx context.Context, task *model.Task) (*model.T_Task, error) {
-	r.index++
-	task.TaskID = r.index
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
 
-	nt := time.Now()
-	newEntry := model.T_Task{Task: *task, T_Internal: model.T_Internal{CreatedAt: &nt}}
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withinQuota(false); err != nil {
+		return nil, err
+	}
+
+	newEntry, err := r.created(task, biz.ActorFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	r.data.apply(r.put(ctx, newEntry, model.RevisionCreated))
+	task.TaskID = newEntry.TaskID
 
-	r.data.tasks[task.TaskID] = newEntry
 	return &newEntry, nil
 }
 
-func (r *taskRepo) Update(ctx context.Context, task *model.Task) (*model.T_Task, error) {
+func (r *taskRepo) Update(ctx context.Context, task *model.Task, version uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
 
-	val, ok := r.data.tasks[task.TaskID]
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.updated(task, version)
+	if err != nil {
+		return nil, err
+	}
+
+	r.data.apply(r.put(ctx, val, model.RevisionUpdated))
+
+	return &val, nil
+}
+
+func (r *taskRepo) Delete(ctx context.Context, id uint64) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	val, err := r.deleted(id)
+	if err != nil {
+		return err
+	}
+
+	r.data.apply(r.put(ctx, val, model.RevisionDeleted))
+
+	return nil
+}
+
+func (r *taskRepo) Empty(ctx context.Context) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.data.apply(walEntry{Op: walOpEmpty, Tenant: r.tenant})
+
+	return nil
+}
+
+func (r *taskRepo) Restore(ctx context.Context, id uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.restored(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.data.apply(r.put(ctx, val, model.RevisionRestored))
+
+	return &val, nil
+}
+
+func (r *taskRepo) Purge(ctx context.Context, id uint64) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.trashed(id); err != nil {
+		return err
+	}
+
+	r.data.apply(walEntry{Op: walOpPurge, Tenant: r.tenant, TaskID: id})
+
+	return nil
+}
+
+func (r *taskRepo) Transition(ctx context.Context, id uint64, status model.TaskStatus, version uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.transitioned(id, status, version)
+	if err != nil {
+		return nil, err
+	}
+
+	r.data.apply(r.put(ctx, val, model.RevisionTransitioned))
+
+	return &val, nil
+}
+
+func (r *taskRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.data.mu.Unlock()
+
+	purged := 0
+	for _, scoped := range r.tenantRepos() {
+		for _, id := range scoped.expired(before) {
+			r.data.apply(walEntry{Op: walOpPurge, Tenant: scoped.tenant, TaskID: id})
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// tenantRepos returns the repo scoped to every tenant in turn, in the order of
+// their IDs. The caller must hold the lock.
+func (r *taskRepo) tenantRepos() []*taskRepo {
+	ids := r.data.tenantIDs()
+	result := make([]*taskRepo, 0, len(ids))
+	for _, id := range ids {
+		scoped, _ := r.scoped(id)
+		result = append(result, scoped)
+	}
+	return result
+}
+
+// withinQuota checks that the tenant may store one more task, and one more series
+// as well when series is set.
+func (r *taskRepo) withinQuota(series bool) error {
+	t := r.data.tenants[r.tenant]
+	if t.MaxTasks > 0 && len(r.space.tasks) >= t.MaxTasks {
+		return model.ErrorTenantQuotaExceeded("%s: tenant %s may store %d tasks, trashed ones included until purged", encoder.TENANT_QUOTA_EXCEEDED, r.tenant, t.MaxTasks)
+	}
+	if series && t.MaxSeries > 0 && len(r.space.series) >= t.MaxSeries {
+		return model.ErrorTenantQuotaExceeded("%s: tenant %s may store %d series", encoder.TENANT_QUOTA_EXCEEDED, r.tenant, t.MaxSeries)
+	}
+	return nil
+}
+
+// created allocates the next task ID and returns the new record, created and
+// owned by the given user, provided its parent is valid. The task itself is left
+// as it is, so that the caller only sees the ID once the record is applied. The
+// caller must hold the write lock until then.
+func (r *taskRepo) created(task *model.Task, creator string) (model.T_Task, error) {
+	if err := r.validParent(0, task.ParentID); err != nil {
+		return model.T_Task{}, err
+	}
+	val := *task
+	val.TaskID = r.space.index + 1
+
+	nt := time.Now()
+	return model.T_Task{Task: val, T_Internal: model.T_Internal{CreatedAt: &nt, Version: 1,
+		Status: model.StatusTodo, StatusEnteredAt: map[model.TaskStatus]time.Time{model.StatusTodo: nt},
+		CreatedBy: creator, Owner: creator}}, nil
+}
+
+// updated returns the stored record with task applied over it, provided the
+// record is still at the given version and the parent of task is valid. A version
+// of 0 skips the check.
+func (r *taskRepo) updated(task *model.Task, version uint64) (model.T_Task, error) {
+	val, ok := r.space.tasks[task.TaskID]
 
 	// Task not exist
 	if !ok {
-		return nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+		return model.T_Task{}, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
 	}
 
 	// Task has been deleted
 	if val.DeletedAt != nil {
-		return nil, model.ErrorTaskNotFound(string(encoder.TASK_DELETED))
+		return model.T_Task{}, model.ErrorTaskNotFound(string(encoder.TASK_DELETED))
+	}
+
+	// Task has been changed since the client read it
+	if version != 0 && version != val.Version {
+		return model.T_Task{}, model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH))
+	}
+
+	if err := r.validParent(task.TaskID, task.ParentID); err != nil {
+		return model.T_Task{}, err
 	}
 
 	val.Task = *task
 	nt := time.Now()
 	val.T_Internal.UpdatedAt = &nt
+	val.Version++
 
-	r.data.tasks[task.TaskID] = val
+	return val, nil
+}
 
-	return &val, nil
+// transitioned returns the stored record moved to the given status, provided the
+// record is still at the given version, and its dependencies are done when the
+// status starts the work. A version of 0 skips the check.
+func (r *taskRepo) transitioned(id uint64, status model.TaskStatus, version uint64) (model.T_Task, error) {
+	val, err := r.updated(&model.Task{TaskID: id}, version)
+	if err != nil {
+		return model.T_Task{}, err
+	}
+	val.Task = r.space.tasks[id].Task
+
+	if biz.StartsWork(status) {
+		if pending := r.pending(&val); len(pending) > 0 {
+			return model.T_Task{}, model.ErrorTaskBlocked("%s: %s", encoder.TASK_BLOCKED, strings.Join(pending, ", "))
+		}
+	}
+
+	// The stored map is shared with readers, so it is copied rather than changed
+	entered := make(map[model.TaskStatus]time.Time, len(val.StatusEnteredAt)+1)
+	for s, t := range val.StatusEnteredAt {
+		entered[s] = t
+	}
+	entered[status] = *val.UpdatedAt
+	val.Status = status
+	val.StatusEnteredAt = entered
+
+	return val, nil
 }
 
-func (r *taskRepo) Delete(ctx context.Context, id uint64) error {
-	val, ok := r.data.tasks[id]
+// deleted returns the stored record marked as logically deleted.
+func (r *taskRepo) deleted(id uint64) (model.T_Task, error) {
+	val, ok := r.space.tasks[id]
 
 	// Task not exist
 	if !ok {
-		return model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+		return model.T_Task{}, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
 	}
 
 	// Task has been deleted
 	if val.DeletedAt != nil {
-		return model.ErrorTaskNotFound(string(encoder.TASK_DELETED))
+		return model.T_Task{}, model.ErrorTaskNotFound(string(encoder.TASK_DELETED))
+	}
+
+	// Subtasks would be left without their parent
+	if len(r.liveChildren(id)) > 0 {
+		return model.T_Task{}, model.ErrorTaskHasChildren(string(encoder.TASK_HAS_CHILDREN))
 	}
 
 	nt := time.Now()
 	val.DeletedAt = &nt
-	r.data.tasks[id] = val
+	val.Version++
 
-	return nil
+	return val, nil
 }
 
-func (r *taskRepo) Empty(ctx context.Context) error {
-	r.data.tasks = make(map[uint64]model.T_Task)
-	r.index = 0
+// trashed returns the stored record if it has been logically deleted.
+func (r *taskRepo) trashed(id uint64) (model.T_Task, error) {
+	val, ok := r.space.tasks[id]
 
-	return nil
+	// Task not exist
+	if !ok {
+		return model.T_Task{}, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST))
+	}
+
+	// Task is still live
+	if val.DeletedAt == nil {
+		return model.T_Task{}, model.ErrorTaskNotDeleted(string(encoder.TASK_NOT_DELETED))
+	}
+
+	return val, nil
+}
+
+// restored returns the stored record with the deletion undone. A subtask is only
+// restored once its parent is, and becomes a top level task if its parent is gone.
+// A task whose project or column is gone comes back off the board.
+func (r *taskRepo) restored(id uint64) (model.T_Task, error) {
+	val, err := r.trashed(id)
+	if err != nil {
+		return model.T_Task{}, err
+	}
+
+	if val.ParentID != 0 {
+		parent, ok := r.space.tasks[val.ParentID]
+		switch {
+		case !ok:
+			val.ParentID = 0
+		case parent.DeletedAt != nil:
+			return model.T_Task{}, model.ErrorTaskInvalidParent("%s: parent task %d has been deleted", encoder.TASK_PARENT_INVALID, val.ParentID)
+		}
+	}
+
+	if val.ProjectID != "" {
+		if p, ok := r.space.projects[val.ProjectID]; !ok || !p.HasColumn(val.Column) {
+			val.ProjectID, val.Column, val.Rank = "", "", 0
+		}
+	}
+
+	nt := time.Now()
+	val.DeletedAt = nil
+	val.UpdatedAt = &nt
+	val.Version++
+
+	return val, nil
+}
+
+// expired returns the IDs of the tasks deleted before the given time.
+func (r *taskRepo) expired(before time.Time) []uint64 {
+	var ids []uint64
+	for id, task := range r.space.tasks {
+		if task.DeletedAt != nil && task.DeletedAt.Before(before) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
@@ -0,0 +1,296 @@
+package data_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/require"
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/conf"
+	"qantas.com/task/internal/data"
+	"qantas.com/task/model"
+)
+
+// These tests are meant to be run with -race. They hammer a single repo from
+// many goroutines, the way chi serves concurrent requests.
+
+const (
+	stressWorkers = 16
+	stressTasks   = 50
+)
+
+func stressRepos(t *testing.T) map[string]biz.ITaskRepo {
+	logger := log.NewFilter(log.With(log.NewStdLogger(os.Stdout)), log.FilterLevel(log.LevelError))
+	repos := map[string]biz.ITaskRepo{}
+
+	memory, _, err := data.NewData(&conf.Data{}, logger)
+	require.Nil(t, err)
+	repos["memory"] = data.NewTaskRepo(memory, logger)
+
+	durable, cleanup, err := data.NewData(&conf.Data{Wal: &conf.Data_WAL{
+		Path:         filepath.Join(t.TempDir(), "tasks.wal"),
+		Fsync:        conf.Data_WAL_NEVER,
+		CompactEvery: 64,
+	}}, logger)
+	require.Nil(t, err)
+	t.Cleanup(cleanup)
+	repos["durable"] = data.NewTaskRepo(durable, logger)
+
+	return repos
+}
+
+func Test_Race_Create_UniqueIDs(t *testing.T) {
+	for name, taskRepo := range stressRepos(t) {
+		t.Run(name, func(t *testing.T) {
+			requires := require.New(t)
+			ctx := context.Background()
+
+			ids := make(chan uint64, stressWorkers*stressTasks)
+			var wg sync.WaitGroup
+			for w := 0; w < stressWorkers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < stressTasks; i++ {
+						ct, err := taskRepo.Create(ctx, &model.Task{Name: fmt.Sprintf("worker %d", w), Content: fmt.Sprint(i)})
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						ids <- ct.TaskID
+					}
+				}(w)
+			}
+			wg.Wait()
+			close(ids)
+
+			// Every ID from 1 to N is handed out exactly once
+			seen := map[uint64]bool{}
+			for id := range ids {
+				requires.False(seen[id], "duplicate task ID %d", id)
+				seen[id] = true
+			}
+			requires.Equal(stressWorkers*stressTasks, len(seen))
+			for id := uint64(1); id <= stressWorkers*stressTasks; id++ {
+				requires.True(seen[id], "lost task ID %d", id)
+			}
+
+			page, err := taskRepo.List(ctx, &biz.TaskQuery{})
+			requires.Nil(err)
+			requires.Equal(stressWorkers*stressTasks, len(page.Tasks))
+		})
+	}
+}
+
+func Test_Race_MixedOperations(t *testing.T) {
+	for name, taskRepo := range stressRepos(t) {
+		t.Run(name, func(t *testing.T) {
+			requires := require.New(t)
+			ctx := context.Background()
+
+			// Every worker owns the tasks it creates: it updates all of them and
+			// deletes every other one, while listing and reading the whole store.
+			deleted := make(chan uint64, stressWorkers*stressTasks)
+			kept := make(chan model.T_Task, stressWorkers*stressTasks)
+			var wg sync.WaitGroup
+			for w := 0; w < stressWorkers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < stressTasks; i++ {
+						ct, err := taskRepo.Create(ctx, &model.Task{Name: fmt.Sprintf("worker %d", w)})
+						if err != nil {
+							t.Error(err)
+							return
+						}
+
+						ut, err := taskRepo.Update(ctx, &model.Task{TaskID: ct.TaskID, Name: ct.Name, Content: "updated"}, 0)
+						if err != nil {
+							t.Error(err)
+							return
+						}
+
+						if _, err := taskRepo.List(ctx, &biz.TaskQuery{}); err != nil {
+							t.Error(err)
+							return
+						}
+						if _, err := taskRepo.Get(ctx, ct.TaskID); err != nil {
+							t.Error(err)
+							return
+						}
+
+						if i%2 == 0 {
+							if err := taskRepo.Delete(ctx, ct.TaskID); err != nil {
+								t.Error(err)
+								return
+							}
+							deleted <- ct.TaskID
+						} else {
+							kept <- *ut
+						}
+					}
+				}(w)
+			}
+			wg.Wait()
+			close(deleted)
+			close(kept)
+
+			page, err := taskRepo.List(ctx, &biz.TaskQuery{})
+			requires.Nil(err)
+			listed := map[uint64]model.T_Task{}
+			for _, task := range page.Tasks {
+				listed[task.TaskID] = task
+			}
+
+			requires.Equal(stressWorkers*stressTasks/2, len(listed))
+			for id := range deleted {
+				_, ok := listed[id]
+				requires.False(ok, "deleted task %d is still listed", id)
+			}
+			for ut := range kept {
+				requires.Equal(ut, listed[ut.TaskID])
+			}
+		})
+	}
+}
+
+func Test_Race_ConcurrentDelete_OnlyOneWins(t *testing.T) {
+	for name, taskRepo := range stressRepos(t) {
+		t.Run(name, func(t *testing.T) {
+			requires := require.New(t)
+			ctx := context.Background()
+
+			ct, err := taskRepo.Create(ctx, &model.Task{Name: "contended"})
+			requires.Nil(err)
+
+			var wg sync.WaitGroup
+			results := make(chan error, stressWorkers)
+			for w := 0; w < stressWorkers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					results <- taskRepo.Delete(ctx, ct.TaskID)
+				}()
+			}
+			wg.Wait()
+			close(results)
+
+			succeeded := 0
+			for err := range results {
+				if err == nil {
+					succeeded++
+				} else {
+					requires.True(model.IsTaskNotFound(err))
+				}
+			}
+			requires.Equal(1, succeeded)
+		})
+	}
+}
+
+func Test_Race_ConcurrentReparent_NoCycle(t *testing.T) {
+	for name, taskRepo := range stressRepos(t) {
+		t.Run(name, func(t *testing.T) {
+			requires := require.New(t)
+			ctx := context.Background()
+
+			for i := 0; i < 2; i++ {
+				_, err := taskRepo.Create(ctx, &model.Task{Name: "contended"})
+				requires.Nil(err)
+			}
+
+			// Placing 1 under 2 and 2 under 1 at once would leave both in a cycle
+			var wg sync.WaitGroup
+			results := make(chan error, stressWorkers)
+			for w := 0; w < stressWorkers; w++ {
+				id, parentID := uint64(1+w%2), uint64(2-w%2)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := taskRepo.Update(ctx, &model.Task{TaskID: id, Name: "contended", ParentID: parentID}, 0)
+					results <- err
+				}()
+			}
+			wg.Wait()
+			close(results)
+
+			for err := range results {
+				if err != nil {
+					requires.True(model.IsTaskInvalidParent(err))
+				}
+			}
+			t1, err := taskRepo.Get(ctx, 1)
+			requires.Nil(err)
+			t2, err := taskRepo.Get(ctx, 2)
+			requires.Nil(err)
+			requires.False(t1.ParentID == 2 && t2.ParentID == 1)
+		})
+	}
+}
+
+func Test_Race_Paging_StableUnderConcurrentCreates(t *testing.T) {
+	for name, taskRepo := range stressRepos(t) {
+		t.Run(name, func(t *testing.T) {
+			requires := require.New(t)
+			ctx := context.Background()
+
+			for i := 0; i < stressTasks; i++ {
+				_, err := taskRepo.Create(ctx, &model.Task{Name: fmt.Sprint(i)})
+				requires.Nil(err)
+			}
+
+			// Keep creating tasks while the existing ones are paged through
+			stop := make(chan struct{})
+			var wg sync.WaitGroup
+			for w := 0; w < stressWorkers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						select {
+						case <-stop:
+							return
+						default:
+						}
+						if _, err := taskRepo.Create(ctx, &model.Task{Name: "concurrent"}); err != nil {
+							t.Error(err)
+							return
+						}
+					}
+				}()
+			}
+
+			seen := map[uint64]bool{}
+			var last uint64
+			q := biz.TaskQuery{PageSize: 7}
+			for {
+				requires.Nil(q.Validate())
+				page, err := taskRepo.List(ctx, &q)
+				requires.Nil(err)
+
+				for _, task := range page.Tasks {
+					requires.False(seen[task.TaskID], "task %d listed twice", task.TaskID)
+					requires.Greater(task.TaskID, last)
+					seen[task.TaskID] = true
+					last = task.TaskID
+				}
+				if page.NextCursor == "" || last >= stressTasks*4 {
+					break
+				}
+				q.Cursor = page.NextCursor
+			}
+			close(stop)
+			wg.Wait()
+
+			// None of the tasks which existed before the walk was skipped
+			for id := uint64(1); id <= stressTasks; id++ {
+				requires.True(seen[id], "task %d skipped", id)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"qantas.com/task/internal/biz"
 	"qantas.com/task/internal/conf"
 	"qantas.com/task/internal/data"
@@ -148,7 +149,7 @@ func (s *DataSourceTestSuite) Test_UpdateTask_Success() {
 	// Update a task
 	tt := model.Task{TaskID: ct.TaskID, Name: "user name 2", Content: "content text 2"}
 	startTime := time.Now()
-	ut, err := s.taskRepo.Update(s.context, &tt)
+	ut, err := s.taskRepo.Update(s.context, &tt, 0)
 	endTime := time.Now()
 
 	s.Require().Nil(err)
@@ -163,12 +164,40 @@ func (s *DataSourceTestSuite) Test_UpdateTask_Success() {
 	s.Require().Nil(ut.DeletedAt)
 }
 
+func (s *DataSourceTestSuite) Test_UpdateTask_Version() {
+	ct, err := s.taskRepo.Create(s.context, &model.Task{Name: "user name 1"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(1), ct.Version)
+
+	// An update at the current version succeeds and bumps the version
+	ut, err := s.taskRepo.Update(s.context, &model.Task{TaskID: ct.TaskID, Name: "user name 2"}, ct.Version)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(2), ut.Version)
+
+	// An update at a stale version is rejected and leaves the task untouched
+	se := new(errors.Error)
+	st, err := s.taskRepo.Update(s.context, &model.Task{TaskID: ct.TaskID, Name: "user name 3"}, ct.Version)
+	s.Require().Nil(st)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskVersionMismatch(se))
+
+	gt, err := s.taskRepo.Get(s.context, ct.TaskID)
+	s.Require().Nil(err)
+	s.Require().Equal(*ut, *gt)
+
+	// Deleting and restoring are changes too
+	s.Require().Nil(s.taskRepo.Delete(s.context, ct.TaskID))
+	rt, err := s.taskRepo.Restore(s.context, ct.TaskID)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(4), rt.Version)
+}
+
 func (s *DataSourceTestSuite) Test_UpdateTask_TaskNotFound() {
 	se := new(errors.Error)
 
 	// Update nonexistent task
 	tt := model.Task{TaskID: 0, Name: "user name 2", Content: "content text 2"}
-	ut, err := s.taskRepo.Update(s.context, &tt)
+	ut, err := s.taskRepo.Update(s.context, &tt, 0)
 	s.Require().Nil(ut)
 	s.Require().True(errors.As(err, &se))
 	s.Require().True(model.IsTaskNotFound(se))
@@ -182,7 +211,7 @@ func (s *DataSourceTestSuite) Test_UpdateTask_TaskNotFound() {
 	s.Require().Nil(err)
 
 	tt = model.Task{TaskID: 1, Name: "user name 2", Content: "content text 2"}
-	ut, err = s.taskRepo.Update(s.context, &tt)
+	ut, err = s.taskRepo.Update(s.context, &tt, 0)
 	s.Require().Nil(ut)
 	s.Require().True(errors.As(err, &se))
 	s.Require().True(model.IsTaskNotFound(se))
@@ -233,7 +262,7 @@ func (s *DataSourceTestSuite) Test_ListTask() {
 	s.Require().Nil(err)
 
 	ut := model.Task{TaskID: 2, Content: "content text 2 updated"}
-	ctask2, err := s.taskRepo.Update(s.context, &ut)
+	ctask2, err := s.taskRepo.Update(s.context, &ut, 0)
 	s.Require().Nil(err)
 
 	// Add third task, and delete
@@ -245,17 +274,301 @@ func (s *DataSourceTestSuite) Test_ListTask() {
 	s.Require().Nil(err)
 
 	// Get the list of tasks
-	listTask, err := s.taskRepo.List(s.context)
+	listTask, err := s.taskRepo.List(s.context, &biz.TaskQuery{})
+	s.Require().Nil(err)
+
+	// Verify the returned task list, ordered by task ID
+	s.Require().Equal(2, len(listTask.Tasks))
+	s.Require().Equal(*ctask1, listTask.Tasks[0])
+	s.Require().Equal(*ctask2, listTask.Tasks[1])
+	s.Require().Empty(listTask.NextCursor)
+}
+
+func (s *DataSourceTestSuite) Test_ListTask_Paging() {
+	for i := 1; i <= 5; i++ {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: fmt.Sprintf("user %d", i)})
+		s.Require().Nil(err)
+	}
+
+	// Walk through the pages in descending order
+	var ids []uint64
+	q := biz.TaskQuery{PageSize: 2, Descending: true}
+	for {
+		s.Require().Nil(q.Validate())
+		page, err := s.taskRepo.List(s.context, &q)
+		s.Require().Nil(err)
+
+		for _, task := range page.Tasks {
+			ids = append(ids, task.TaskID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+
+	s.Require().Equal([]uint64{5, 4, 3, 2, 1}, ids)
+}
+
+func (s *DataSourceTestSuite) Test_ListTask_SortAndFilter() {
+	for _, name := range []string{"deploy b", "review", "deploy a", "deploy c"} {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: name})
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(s.taskRepo.Delete(s.context, 4))
+
+	q := biz.TaskQuery{SortBy: biz.SortByName, NamePrefix: "deploy"}
+	s.Require().Nil(q.Validate())
+	page, err := s.taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+
+	s.Require().Equal(2, len(page.Tasks))
+	s.Require().Equal("deploy a", page.Tasks[0].Name)
+	s.Require().Equal("deploy b", page.Tasks[1].Name)
+
+	// Only the tasks created strictly between the first and the last one
+	first, err := s.taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	third, err := s.taskRepo.Get(s.context, 3)
+	s.Require().Nil(err)
+
+	q = biz.TaskQuery{CreatedAfter: first.CreatedAt, CreatedBefore: third.CreatedAt}
+	s.Require().Nil(q.Validate())
+	page, err = s.taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+
+	s.Require().Equal(1, len(page.Tasks))
+	s.Require().Equal(uint64(2), page.Tasks[0].TaskID)
+}
+
+func (s *DataSourceTestSuite) Test_ListTask_Filter() {
+	for _, task := range []model.Task{
+		{Name: "deploy api", Tags: []string{"ops"}},
+		{Name: "deploy web", Tags: []string{"ops", "web"}, ParentID: 1},
+		{Name: "review", Tags: []string{"web"}, ParentID: 1},
+		{Name: "deploy db"},
+	} {
+		_, err := s.taskRepo.Create(s.context, &task)
+		s.Require().Nil(err)
+	}
+	_, err := s.taskRepo.Transition(s.context, 2, model.StatusInProgress, 0)
+	s.Require().Nil(err)
+	s.Require().Nil(s.taskRepo.Delete(s.context, 4))
+
+	// Indexed clauses narrow the scan, and the whole filter still applies
+	tests := []struct {
+		filter string
+		tags   []string
+		want   []uint64
+	}{
+		{filter: `name ~ "deploy*"`, want: []uint64{1, 2}},
+		{filter: `tag = ops AND status = todo`, want: []uint64{1}},
+		{filter: `tag = missing`, want: nil},
+		{filter: `parentID = 1 AND tag = web`, want: []uint64{2, 3}},
+		{filter: `parentID = 0`, want: []uint64{1}},
+		{filter: `taskID = 3 OR taskID = 1`, want: []uint64{1, 3}},
+		{filter: `taskID = 4`, want: nil},
+		{filter: `taskID = 99`, want: nil},
+		{filter: `tag = web AND NOT tag = ops`, want: []uint64{3}},
+		{filter: `name ~ "deploy*"`, tags: []string{"web"}, want: []uint64{2}},
+	}
+	for _, tt := range tests {
+		q := biz.TaskQuery{Filter: tt.filter, Tags: tt.tags}
+		s.Require().Nil(q.Validate())
+		page, err := s.taskRepo.List(s.context, &q)
+		s.Require().Nil(err)
+
+		var ids []uint64
+		for _, task := range page.Tasks {
+			ids = append(ids, task.TaskID)
+		}
+		s.Require().Equal(tt.want, ids, tt.filter)
+	}
+}
+
+func (s *DataSourceTestSuite) Test_ListTask_CursorMismatch() {
+	for i := 1; i <= 3; i++ {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: fmt.Sprintf("user %d", i)})
+		s.Require().Nil(err)
+	}
+
+	q := biz.TaskQuery{PageSize: 1}
+	s.Require().Nil(q.Validate())
+	page, err := s.taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+	s.Require().NotEmpty(page.NextCursor)
+
+	// A cursor only continues the listing it was issued for
+	q = biz.TaskQuery{PageSize: 1, SortBy: biz.SortByName, Cursor: page.NextCursor}
+	err = q.Validate()
+	se := new(errors.Error)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskQueryInvalid(se))
+}
+
+func (s *DataSourceTestSuite) Test_Trash_ListRestorePurge() {
+	for i := 1; i <= 3; i++ {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: fmt.Sprintf("user %d", i)})
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(s.taskRepo.Delete(s.context, 2))
+	s.Require().Nil(s.taskRepo.Delete(s.context, 3))
+
+	// Only the deleted tasks are in the trash
+	q := biz.TaskQuery{Trashed: true}
+	s.Require().Nil(q.Validate())
+	page, err := s.taskRepo.List(s.context, &q)
 	s.Require().Nil(err)
+	s.Require().Equal(2, len(page.Tasks))
+	s.Require().Equal(uint64(2), page.Tasks[0].TaskID)
+	s.Require().Equal(uint64(3), page.Tasks[1].TaskID)
 
-	// Verify the returned task list
-	s.Require().Equal(2, len(listTask))
+	// A restored task is live again
+	rt, err := s.taskRepo.Restore(s.context, 2)
+	s.Require().Nil(err)
+	s.Require().Nil(rt.DeletedAt)
+	s.Require().NotNil(rt.UpdatedAt)
+
+	gt, err := s.taskRepo.Get(s.context, 2)
+	s.Require().Nil(err)
+	s.Require().Equal(*rt, *gt)
+
+	// A purged task is gone for good
+	s.Require().Nil(s.taskRepo.Purge(s.context, 3))
+
+	se := new(errors.Error)
+	_, err = s.taskRepo.Restore(s.context, 3)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskNotFound(se))
+
+	page, err = s.taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+	s.Require().Equal(0, len(page.Tasks))
+
+	// The ID of a purged task is never handed out again
+	ct, err := s.taskRepo.Create(s.context, &model.Task{Name: "user 4"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(4), ct.TaskID)
+}
 
-	if listTask[0].TaskID == 1 {
-		s.Require().Equal(*ctask1, listTask[0])
-		s.Require().Equal(*ctask2, listTask[1])
-	} else {
-		s.Require().Equal(*ctask1, listTask[1])
-		s.Require().Equal(*ctask2, listTask[0])
+func (s *DataSourceTestSuite) Test_Trash_LiveTask() {
+	_, err := s.taskRepo.Create(s.context, &model.Task{Name: "user 1"})
+	s.Require().Nil(err)
+
+	se := new(errors.Error)
+	_, err = s.taskRepo.Restore(s.context, 1)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskNotDeleted(se))
+
+	err = s.taskRepo.Purge(s.context, 1)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskNotDeleted(se))
+
+	err = s.taskRepo.Purge(s.context, 2)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskNotFound(se))
+}
+
+func (s *DataSourceTestSuite) Test_PurgeDeletedBefore() {
+	for i := 1; i <= 3; i++ {
+		_, err := s.taskRepo.Create(s.context, &model.Task{Name: fmt.Sprintf("user %d", i)})
+		s.Require().Nil(err)
 	}
+	s.Require().Nil(s.taskRepo.Delete(s.context, 1))
+	cutoff := time.Now()
+	s.Require().Nil(s.taskRepo.Delete(s.context, 2))
+
+	purged, err := s.taskRepo.PurgeDeletedBefore(s.context, cutoff)
+	s.Require().Nil(err)
+	s.Require().Equal(1, purged)
+
+	q := biz.TaskQuery{Trashed: true}
+	s.Require().Nil(q.Validate())
+	page, err := s.taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(page.Tasks))
+	s.Require().Equal(uint64(2), page.Tasks[0].TaskID)
+}
+
+func (s *DataSourceTestSuite) Test_Transition() {
+	task, err := s.taskRepo.Create(s.context, &model.Task{Name: "user1"})
+	s.Require().Nil(err)
+	s.Require().Equal(model.StatusTodo, task.Status)
+	s.Require().Equal(*task.CreatedAt, task.StatusEnteredAt[model.StatusTodo])
+
+	moved, err := s.taskRepo.Transition(biz.WithActor(s.context, "alice"), task.TaskID, model.StatusInProgress, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(model.StatusInProgress, moved.Status)
+	s.Require().Equal(uint64(2), moved.Version)
+	s.Require().Equal(*moved.UpdatedAt, moved.StatusEnteredAt[model.StatusInProgress])
+	s.Require().Equal(task.StatusEnteredAt[model.StatusTodo], moved.StatusEnteredAt[model.StatusTodo])
+
+	// The task read before is left as it was
+	s.Require().Equal(1, len(task.StatusEnteredAt))
+
+	// A stale version is rejected
+	_, err = s.taskRepo.Transition(s.context, task.TaskID, model.StatusDone, 1)
+	s.Require().True(model.IsTaskVersionMismatch(err))
+
+	// The move is kept in the history
+	revision, err := s.taskRepo.Revision(s.context, task.TaskID, 2)
+	s.Require().Nil(err)
+	s.Require().Equal(model.RevisionTransitioned, revision.Action)
+	s.Require().Equal("alice", revision.Actor)
+	s.Require().Equal([]model.T_Change{{Field: "status", From: "todo", To: "in_progress"}}, revision.Changes)
+
+	// Deleted tasks cannot be moved
+	s.Require().Nil(s.taskRepo.Delete(s.context, task.TaskID))
+	_, err = s.taskRepo.Transition(s.context, task.TaskID, model.StatusDone, 0)
+	s.Require().True(model.IsTaskNotFound(err))
+}
+
+func (s *DataSourceTestSuite) Test_Context_Done() {
+	task, err := s.taskRepo.Create(s.context, &model.Task{Name: "user1"})
+	s.Require().Nil(err)
+
+	expired, cancel := context.WithDeadline(s.context, time.Now().Add(-time.Second))
+	defer cancel()
+	canceled, cancel := context.WithCancel(s.context)
+	cancel()
+
+	// An expired deadline is a database timeout
+	_, err = s.taskRepo.Get(expired, task.TaskID)
+	s.Require().True(model.IsTaskDbTimeout(err))
+	_, err = s.taskRepo.List(expired, &biz.TaskQuery{})
+	s.Require().True(model.IsTaskDbTimeout(err))
+	_, err = s.taskRepo.Update(expired, &model.Task{TaskID: task.TaskID, Name: "user2"}, 0)
+	s.Require().True(model.IsTaskDbTimeout(err))
+
+	// A canceled request is reported as such
+	s.Require().True(model.IsRequestCanceled(s.taskRepo.Delete(canceled, task.TaskID)))
+
+	// Neither change was made
+	got, err := s.taskRepo.Get(s.context, task.TaskID)
+	s.Require().Nil(err)
+	s.Require().Equal("user1", got.Name)
+	s.Require().Equal(uint64(1), got.Version)
+}
+
+func Test_RetentionJob_PurgesExpiredTasks(t *testing.T) {
+	requires := require.New(t)
+	logger := log.NewFilter(log.With(log.NewStdLogger(os.Stdout)), log.FilterLevel(log.LevelError))
+	ctx := context.Background()
+
+	dataRepo, cleanup, err := data.NewData(&conf.Data{Trash: &conf.Data_Trash{
+		Retention:     durationpb.New(time.Millisecond),
+		PurgeInterval: durationpb.New(10 * time.Millisecond),
+	}}, logger)
+	requires.Nil(err)
+	defer cleanup()
+	taskRepo := data.NewTaskRepo(dataRepo, logger)
+
+	_, err = taskRepo.Create(ctx, &model.Task{Name: "user 1"})
+	requires.Nil(err)
+	requires.Nil(taskRepo.Delete(ctx, 1))
+
+	requires.Eventually(func() bool {
+		err := taskRepo.Purge(ctx, 1)
+		return model.IsTaskNotFound(err)
+	}, time.Second, 10*time.Millisecond)
 }
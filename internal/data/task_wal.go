@@ -0,0 +1,749 @@
+package data
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// durableTaskRepo is the task repo that survives restarts. Every mutation is
+// appended to the write-ahead log before it is applied to the in-memory state,
+// and the log is folded into a snapshot once it grows past the configured size.
+type durableTaskRepo struct {
+	*taskRepo
+	wal *wal
+}
+
+// in returns the repo scoped to the tenant of the request, see taskRepo.in.
+func (r *durableTaskRepo) in(ctx context.Context) (*durableTaskRepo, error) {
+	scoped, err := r.taskRepo.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &durableTaskRepo{taskRepo: scoped, wal: r.wal}, nil
+}
+
+func (r *durableTaskRepo) Create(ctx context.Context, task *model.Task) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withinQuota(false); err != nil {
+		return nil, err
+	}
+
+	newEntry, err := r.created(task, biz.ActorFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.commit(ctx, r.put(ctx, newEntry, model.RevisionCreated)); err != nil {
+		return nil, model.ErrorTaskCreationError(string(encoder.TASK_CREATION_ERROR))
+	}
+	task.TaskID = newEntry.TaskID
+
+	return &newEntry, nil
+}
+
+func (r *durableTaskRepo) Update(ctx context.Context, task *model.Task, version uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.updated(task, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.commit(ctx, r.put(ctx, val, model.RevisionUpdated)); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) Delete(ctx context.Context, id uint64) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	val, err := r.deleted(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.commit(ctx, r.put(ctx, val, model.RevisionDeleted)); err != nil {
+		return model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return nil
+}
+
+// DeleteCascade logs the deletion of every task of the subtree separately. A crash
+// part way leaves the subtasks deleted so far in the trash, and the delete can be retried.
+func (r *durableTaskRepo) DeleteCascade(ctx context.Context, id uint64) (int, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := r.subtree(id)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, id := range ids {
+		val, err := r.deleted(id)
+		if err != nil {
+			return i, err
+		}
+		if err := r.commit(ctx, r.put(ctx, val, model.RevisionDeleted)); err != nil {
+			return i, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+		}
+	}
+
+	return len(ids), nil
+}
+
+func (r *durableTaskRepo) Empty(ctx context.Context) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := r.commit(ctx, walEntry{Op: walOpEmpty, Tenant: r.tenant}); err != nil {
+		return model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return nil
+}
+
+func (r *durableTaskRepo) Restore(ctx context.Context, id uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.restored(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.commit(ctx, r.put(ctx, val, model.RevisionRestored)); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) Purge(ctx context.Context, id uint64) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.trashed(id); err != nil {
+		return err
+	}
+
+	if err := r.commit(ctx, walEntry{Op: walOpPurge, Tenant: r.tenant, TaskID: id}); err != nil {
+		return model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return nil
+}
+
+func (r *durableTaskRepo) Transition(ctx context.Context, id uint64, status model.TaskStatus, version uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.transitioned(id, status, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.commit(ctx, r.put(ctx, val, model.RevisionTransitioned)); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) AddDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, changed, err := r.dependencyAdded(id, dependsOn)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := r.commit(ctx, r.put(ctx, val, model.RevisionUpdated)); err != nil {
+			return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+		}
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) Reassign(ctx context.Context, id uint64, owner string, assignees []string, version uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, changed, err := r.reassigned(id, owner, assignees, version)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := r.commit(ctx, r.put(ctx, val, model.RevisionReassigned)); err != nil {
+			return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+		}
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) RemoveDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, changed, err := r.dependencyRemoved(id, dependsOn)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := r.commit(ctx, r.put(ctx, val, model.RevisionUpdated)); err != nil {
+			return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+		}
+	}
+
+	return &val, nil
+}
+
+// RenameTag logs the change to every task separately. A crash part way leaves
+// the tag renamed on some of the tasks, and the rename can be retried.
+func (r *durableTaskRepo) RenameTag(ctx context.Context, from string, to string) (int, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tasks, err := r.retagged(from, to)
+	if err != nil {
+		return 0, err
+	}
+	for i, val := range tasks {
+		if err := r.commit(ctx, r.put(ctx, val, model.RevisionUpdated)); err != nil {
+			return i, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+		}
+	}
+
+	return len(tasks), nil
+}
+
+// MarkReminded logs the reminder as sent, so that it is not sent again after a restart.
+func (r *durableTaskRepo) MarkReminded(ctx context.Context, id uint64, at time.Time) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	val, err := r.reminded(id, at)
+	if err != nil {
+		return err
+	}
+
+	if err := r.commit(ctx, walEntry{Op: walOpPut, Tenant: r.tenant, Task: &val}); err != nil {
+		return model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return nil
+}
+
+func (r *durableTaskRepo) CreateSeries(ctx context.Context, s *model.Series, first time.Time) (*model.T_Series, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, e, err := r.seriesCreated(ctx, s, first)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.commit(ctx, e); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+	s.SeriesID = val.SeriesID
+
+	return &val, nil
+}
+
+// UpdateSeries logs the series first and then every occurrence changed with it. A
+// crash part way leaves some occurrences as they were, and the update can be retried.
+func (r *durableTaskRepo) UpdateSeries(ctx context.Context, s *model.Series, version uint64) (*model.T_Series, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, tasks, err := r.seriesUpdated(s, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.commit(ctx, walEntry{Op: walOpSeries, Tenant: r.tenant, Series: &val}); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+	for _, task := range tasks {
+		if err := r.commit(ctx, r.put(ctx, task, model.RevisionUpdated)); err != nil {
+			return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+		}
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) StopSeries(ctx context.Context, id uint64) (*model.T_Series, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, changed, err := r.seriesStopped(id)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := r.commit(ctx, walEntry{Op: walOpSeries, Tenant: r.tenant, Series: &val}); err != nil {
+			return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+		}
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) AdvanceSeries(ctx context.Context, id uint64, version uint64, next *time.Time) (*model.T_Series, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, e, err := r.seriesAdvanced(ctx, id, version, next)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.commit(ctx, e); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.data.mu.Unlock()
+
+	purged := 0
+	for _, scoped := range r.tenantRepos() {
+		for _, id := range scoped.expired(before) {
+			if err := contextError(ctx); err != nil {
+				return purged, err
+			}
+			if err := r.commit(ctx, walEntry{Op: walOpPurge, Tenant: scoped.tenant, TaskID: id}); err != nil {
+				return purged, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+			}
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (r *durableTaskRepo) CreateTenant(ctx context.Context, t *model.Tenant) (*model.T_Tenant, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	val, err := r.tenantCreated(t)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpTenant, Tenant: val.TenantID, TenantRecord: &val}); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) DeleteTenant(ctx context.Context, tenant string) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	if _, err := r.scoped(tenant); err != nil {
+		return err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpDropTenant, Tenant: tenant}); err != nil {
+		return model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return nil
+}
+
+func (r *durableTaskRepo) CreateProject(ctx context.Context, p *model.Project) (*model.T_Project, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.projectCreated(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpProject, Tenant: r.tenant, Project: &val}); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) UpdateProject(ctx context.Context, p *model.Project, version uint64) (*model.T_Project, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.projectUpdated(p, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpProject, Tenant: r.tenant, Project: &val}); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) DeleteProject(ctx context.Context, id string) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := r.projectDeleted(id); err != nil {
+		return err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpDropProject, Tenant: r.tenant, ProjectID: id}); err != nil {
+		return model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return nil
+}
+
+// Move logs the other tasks of a column which is spread out again, each as it is,
+// before the task moved into it.
+func (r *durableTaskRepo) Move(ctx context.Context, id uint64, m *model.TaskMove, version uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, rebalanced, changed, err := r.moved(id, m, version)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rebalanced {
+		if err := r.commit(ctx, walEntry{Op: walOpPut, Tenant: r.tenant, Task: &rebalanced[i]}); err != nil {
+			return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+		}
+	}
+	if changed {
+		if err := r.commit(ctx, r.put(ctx, val, model.RevisionMoved)); err != nil {
+			return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+		}
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) Revert(ctx context.Context, id uint64, rev uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, e, err := r.reverted(ctx, id, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.commit(ctx, e); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+// commit writes the entry to the log and then applies it. The in-memory state
+// is left untouched when the write fails. The caller must hold the write lock.
+func (r *durableTaskRepo) commit(ctx context.Context, e walEntry) error {
+	if err := r.wal.append(e); err != nil {
+		r.log.WithContext(ctx).Errorf("durableTaskRepo: commit - %v", err)
+		return err
+	}
+
+	r.data.apply(e)
+
+	if r.wal.needsCompaction() {
+		if err := r.wal.compact(r.data.snapshot()); err != nil {
+			// The entry is safely in the log, so only the compaction is retried later.
+			r.log.WithContext(ctx).Warnf("durableTaskRepo: compact - %v", err)
+		}
+	}
+	return nil
+}
+
+func (r *durableTaskRepo) CreateComment(ctx context.Context, id uint64, c *model.Comment) (*model.T_Comment, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.commentCreated(ctx, id, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpComment, Tenant: r.tenant, Comment: &val}); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) UpdateComment(ctx context.Context, id uint64, commentID uint64, c *model.Comment, version uint64) (*model.T_Comment, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.commentUpdated(id, commentID, c, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpComment, Tenant: r.tenant, Comment: &val}); err != nil {
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) DeleteComment(ctx context.Context, id uint64, commentID uint64, version uint64) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.commentUpdated(id, commentID, nil, version); err != nil {
+		return err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpDropComment, Tenant: r.tenant, TaskID: id, CommentID: commentID}); err != nil {
+		return model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return nil
+}
+
+func (r *durableTaskRepo) CreateAttachment(ctx context.Context, id uint64, name string, content io.Reader) (*model.T_Attachment, error) {
+	// The content is read before the lock is taken, and only stored once it is held
+	staged, err := r.data.stage(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	defer staged.Discard()
+
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err = r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := r.attachmentCreated(ctx, id, name, staged)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpAttachment, Tenant: r.tenant, Attachment: &val}); err != nil {
+		// The blob stored for the attachment may belong to no other
+		r.data.release([]model.T_Attachment{val})
+		return nil, model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return &val, nil
+}
+
+func (r *durableTaskRepo) DeleteAttachment(ctx context.Context, id uint64, attachmentID uint64) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := r.attachmentDeleted(id, attachmentID); err != nil {
+		return err
+	}
+	if err := r.commit(ctx, walEntry{Op: walOpDropAttachment, Tenant: r.tenant, TaskID: id, AttachmentID: attachmentID}); err != nil {
+		return model.ErrorTaskStorageError(string(encoder.TASK_STORAGE_FAILURE))
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+package data_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	errors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/suite"
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/conf"
+	"qantas.com/task/internal/data"
+	"qantas.com/task/model"
+)
+
+type DurableDataSourceTestSuite struct {
+	suite.Suite
+	conf    *conf.Data
+	logger  log.Logger
+	context context.Context
+}
+
+func (s *DurableDataSourceTestSuite) SetupTest() {
+	s.conf = &conf.Data{Wal: &conf.Data_WAL{Path: filepath.Join(s.T().TempDir(), "tasks.wal")}}
+	s.logger = log.With(log.NewStdLogger(os.Stdout))
+	s.context = context.Background()
+}
+
+func TestDurableTaskSuite(t *testing.T) {
+	suite.Run(t, new(DurableDataSourceTestSuite))
+}
+
+// open starts a fresh data source on the suite's log, as a restarted server would.
+func (s *DurableDataSourceTestSuite) open() (biz.ITaskRepo, func()) {
+	dataRepo, cleanup, err := data.NewData(s.conf, s.logger)
+	s.Require().Nil(err)
+
+	return data.NewTaskRepo(dataRepo, s.logger), cleanup
+}
+
+func (s *DurableDataSourceTestSuite) Test_NewTaskRepo_Durable() {
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	s.Require().Equal("*data.durableTaskRepo", fmt.Sprint(reflect.TypeOf(taskRepo)))
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_RestoresTasks() {
+	// The cleanup is never called, so the log is replayed without a compaction
+	taskRepo, _ := s.open()
+
+	// Create three tasks, update the second one and delete the third one
+	ct1, err := taskRepo.Create(s.context, &model.Task{Name: "user 1", Content: "content text 1"})
+	s.Require().Nil(err)
+	_, err = taskRepo.Create(s.context, &model.Task{Name: "user 2", Content: "content text 2"})
+	s.Require().Nil(err)
+	ut2, err := taskRepo.Update(s.context, &model.Task{TaskID: 2, Name: "user 2", Content: "content text 2 updated"}, 0)
+	s.Require().Nil(err)
+	_, err = taskRepo.Create(s.context, &model.Task{Name: "user 3", Content: "content text 3"})
+	s.Require().Nil(err)
+	s.Require().Nil(taskRepo.Delete(s.context, 3))
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	st1, err := taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(ct1.Task, st1.Task)
+	s.Require().True(ct1.CreatedAt.Equal(*st1.CreatedAt))
+
+	st2, err := taskRepo.Get(s.context, 2)
+	s.Require().Nil(err)
+	s.Require().Equal("content text 2 updated", st2.Content)
+	s.Require().True(ut2.UpdatedAt.Equal(*st2.UpdatedAt))
+
+	se := new(errors.Error)
+	_, err = taskRepo.Get(s.context, 3)
+	s.Require().True(errors.As(err, &se))
+	s.Require().True(model.IsTaskNotFound(se))
+
+	// The ID sequence continues after the restored tasks
+	ct4, err := taskRepo.Create(s.context, &model.Task{Name: "user 4", Content: "content text 4"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(4), ct4.TaskID)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_AfterCompaction() {
+	s.conf.Wal.CompactEvery = 2
+	taskRepo, cleanup := s.open()
+
+	for i := 1; i <= 5; i++ {
+		_, err := taskRepo.Create(s.context, &model.Task{Name: fmt.Sprintf("user %d", i)})
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(taskRepo.Delete(s.context, 5))
+	cleanup()
+
+	_, err := os.Stat(s.conf.Wal.Path + ".snapshot")
+	s.Require().Nil(err)
+
+	taskRepo, cleanup = s.open()
+	defer cleanup()
+
+	page, err := taskRepo.List(s.context, &biz.TaskQuery{})
+	s.Require().Nil(err)
+	s.Require().Equal(4, len(page.Tasks))
+
+	ct, err := taskRepo.Create(s.context, &model.Task{Name: "user 6"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(6), ct.TaskID)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_AfterEmpty() {
+	taskRepo, cleanup := s.open()
+
+	_, err := taskRepo.Create(s.context, &model.Task{Name: "user 1"})
+	s.Require().Nil(err)
+	s.Require().Nil(taskRepo.Empty(s.context))
+	cleanup()
+
+	taskRepo, cleanup = s.open()
+	defer cleanup()
+
+	page, err := taskRepo.List(s.context, &biz.TaskQuery{})
+	s.Require().Nil(err)
+	s.Require().Equal(0, len(page.Tasks))
+
+	ct, err := taskRepo.Create(s.context, &model.Task{Name: "user 1"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(1), ct.TaskID)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_DiscardsTornEntry() {
+	taskRepo, _ := s.open()
+
+	_, err := taskRepo.Create(s.context, &model.Task{Name: "user 1"})
+	s.Require().Nil(err)
+
+	// Simulate a crash in the middle of writing the next entry
+	f, err := os.OpenFile(s.conf.Wal.Path, os.O_WRONLY|os.O_APPEND, 0o644)
+	s.Require().Nil(err)
+	_, err = f.WriteString(`{"op":"put","task":{"taskID":2,"na`)
+	s.Require().Nil(err)
+	s.Require().Nil(f.Close())
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	page, err := taskRepo.List(s.context, &biz.TaskQuery{})
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(page.Tasks))
+
+	ct, err := taskRepo.Create(s.context, &model.Task{Name: "user 2"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(2), ct.TaskID)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_AfterRestoreAndPurge() {
+	taskRepo, _ := s.open()
+
+	for i := 1; i <= 3; i++ {
+		_, err := taskRepo.Create(s.context, &model.Task{Name: fmt.Sprintf("user %d", i)})
+		s.Require().Nil(err)
+	}
+	s.Require().Nil(taskRepo.Delete(s.context, 1))
+	s.Require().Nil(taskRepo.Delete(s.context, 3))
+	_, err := taskRepo.Restore(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Nil(taskRepo.Purge(s.context, 3))
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	page, err := taskRepo.List(s.context, &biz.TaskQuery{})
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(page.Tasks))
+	s.Require().Equal(uint64(1), page.Tasks[0].TaskID)
+	s.Require().Equal(uint64(2), page.Tasks[1].TaskID)
+
+	page, err = taskRepo.List(s.context, &biz.TaskQuery{Trashed: true})
+	s.Require().Nil(err)
+	s.Require().Equal(0, len(page.Tasks))
+
+	// The purged ID stays retired across the restart
+	ct, err := taskRepo.Create(s.context, &model.Task{Name: "user 4"})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(4), ct.TaskID)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsHistory() {
+	s.conf.Wal.CompactEvery = 3
+	taskRepo, _ := s.open()
+
+	_, err := taskRepo.Create(s.context, &model.Task{Name: "user 1"})
+	s.Require().Nil(err)
+	for i := 2; i <= 4; i++ {
+		_, err = taskRepo.Update(s.context, &model.Task{TaskID: 1, Name: fmt.Sprintf("user %d", i)}, 0)
+		s.Require().Nil(err)
+	}
+	_, err = taskRepo.Revert(s.context, 1, 2)
+	s.Require().Nil(err)
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	history, err := taskRepo.History(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(5, len(history))
+	for i, rev := range history {
+		s.Require().Equal(uint64(i+1), rev.Revision)
+	}
+	s.Require().Equal("user 2", history[4].Task.Name)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_ReplayOverSnapshot() {
+	taskRepo, cleanup := s.open()
+
+	_, err := taskRepo.Create(s.context, &model.Task{Name: "user 1"})
+	s.Require().Nil(err)
+	_, err = taskRepo.Update(s.context, &model.Task{TaskID: 1, Name: "user 2"}, 0)
+	s.Require().Nil(err)
+
+	// Simulate a crash after the snapshot is written but before the log is truncated
+	entries, err := os.ReadFile(s.conf.Wal.Path)
+	s.Require().Nil(err)
+	cleanup()
+	s.Require().Nil(os.WriteFile(s.conf.Wal.Path, entries, 0o644))
+
+	taskRepo, cleanup = s.open()
+	defer cleanup()
+
+	history, err := taskRepo.History(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(history))
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsStatus() {
+	taskRepo, _ := s.open()
+
+	_, err := taskRepo.Create(s.context, &model.Task{Name: "user 1"})
+	s.Require().Nil(err)
+	moved, err := taskRepo.Transition(s.context, 1, model.StatusInProgress, 0)
+	s.Require().Nil(err)
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	task, err := taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(model.StatusInProgress, task.Status)
+	s.Require().True(moved.StatusEnteredAt[model.StatusInProgress].Equal(task.StatusEnteredAt[model.StatusInProgress]))
+	s.Require().Equal(2, len(task.StatusEnteredAt))
+}
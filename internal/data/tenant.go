@@ -0,0 +1,65 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) CreateTenant(ctx context.Context, t *model.Tenant) (*model.T_Tenant, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	val, err := r.tenantCreated(t)
+	if err != nil {
+		return nil, err
+	}
+	r.data.apply(walEntry{Op: walOpTenant, Tenant: val.TenantID, TenantRecord: &val})
+
+	return &val, nil
+}
+
+func (r *taskRepo) ListTenants(ctx context.Context) ([]model.T_Tenant, error) {
+	if err := r.data.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.RUnlock()
+
+	result := make([]model.T_Tenant, 0, len(r.data.tenants))
+	for _, id := range r.data.tenantIDs() {
+		t := r.data.tenants[id]
+		s := r.data.spaces[id]
+		t.Usage = &model.TenantUsage{Tasks: len(s.tasks), Series: len(s.series)}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (r *taskRepo) DeleteTenant(ctx context.Context, tenant string) error {
+	if err := r.data.lock(ctx); err != nil {
+		return err
+	}
+	defer r.data.mu.Unlock()
+
+	if _, err := r.scoped(tenant); err != nil {
+		return err
+	}
+	r.data.apply(walEntry{Op: walOpDropTenant, Tenant: tenant})
+
+	return nil
+}
+
+// tenantCreated returns the record of a new tenant, provided none has its ID.
+func (r *taskRepo) tenantCreated(t *model.Tenant) (model.T_Tenant, error) {
+	if _, ok := r.data.tenants[t.TenantID]; ok {
+		return model.T_Tenant{}, model.ErrorTenantAlreadyExists("%s: %s", encoder.TENANT_EXISTS, t.TenantID)
+	}
+
+	nt := time.Now()
+	return model.T_Tenant{Tenant: *t, CreatedAt: &nt}, nil
+}
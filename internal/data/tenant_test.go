@@ -0,0 +1,182 @@
+package data_test
+
+import (
+	"context"
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) Test_Tenants_AreIsolated() {
+	_, err := s.taskRepo.CreateTenant(s.context, &model.Tenant{TenantID: "acme"})
+	s.Require().Nil(err)
+	defer s.taskRepo.DeleteTenant(s.context, "acme")
+	acme := biz.WithTenant(s.context, "acme")
+
+	// Every tenant has its own sequence of IDs
+	dt, err := s.taskRepo.Create(s.context, &model.Task{Name: "default task", Tags: []string{"shared"}})
+	s.Require().Nil(err)
+	at, err := s.taskRepo.Create(acme, &model.Task{Name: "acme task", Tags: []string{"shared"}})
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(1), dt.TaskID)
+	s.Require().Equal(uint64(1), at.TaskID)
+
+	task, err := s.taskRepo.Get(acme, 1)
+	s.Require().Nil(err)
+	s.Require().Equal("acme task", task.Name)
+
+	// Lists, indexes and searches only see the tasks of the tenant
+	page, err := s.taskRepo.List(acme, &biz.TaskQuery{})
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(page.Tasks))
+	tags, err := s.taskRepo.Tags(acme)
+	s.Require().Nil(err)
+	s.Require().Equal([]model.T_TagCount{{Tag: "shared", Count: 1}}, tags)
+	renamed, err := s.taskRepo.RenameTag(acme, "shared", "mine")
+	s.Require().Nil(err)
+	s.Require().Equal(1, renamed)
+	task, err = s.taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal([]string{"shared"}, task.Tags)
+
+	// Clearing a tenant leaves the others as they are
+	s.Require().Nil(s.taskRepo.Empty(acme))
+	_, err = s.taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	_, err = s.taskRepo.Get(acme, 1)
+	s.Require().True(model.IsTaskNotFound(err))
+}
+
+func (s *DataSourceTestSuite) Test_Tenants_Unknown() {
+	ctx := biz.WithTenant(s.context, "nobody")
+
+	_, err := s.taskRepo.Create(ctx, &model.Task{Name: "lost"})
+	s.Require().True(model.IsTenantNotFound(err))
+	_, err = s.taskRepo.List(ctx, &biz.TaskQuery{})
+	s.Require().True(model.IsTenantNotFound(err))
+	s.Require().True(model.IsTenantNotFound(s.taskRepo.DeleteTenant(s.context, "nobody")))
+}
+
+func (s *DataSourceTestSuite) Test_Tenants_CreateListDelete() {
+	t, err := s.taskRepo.CreateTenant(s.context, &model.Tenant{TenantID: "beta", MaxTasks: 10})
+	s.Require().Nil(err)
+	s.Require().NotNil(t.CreatedAt)
+	_, err = s.taskRepo.CreateTenant(s.context, &model.Tenant{TenantID: "beta"})
+	s.Require().True(model.IsTenantAlreadyExists(err))
+
+	start := time.Now()
+	beta := biz.WithTenant(s.context, "beta")
+	_, err = s.taskRepo.Create(beta, &model.Task{Name: "one"})
+	s.Require().Nil(err)
+	_, err = s.taskRepo.CreateSeries(beta, &model.Series{Rule: "FREQ=DAILY", Start: &start, Name: "daily"}, start)
+	s.Require().Nil(err)
+
+	tenants, err := s.taskRepo.ListTenants(s.context)
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(tenants))
+	s.Require().Equal(biz.DefaultTenant, tenants[1].TenantID)
+	s.Require().Equal("beta", tenants[0].TenantID)
+	s.Require().Equal(10, tenants[0].MaxTasks)
+	s.Require().Equal(&model.TenantUsage{Tasks: 2, Series: 1}, tenants[0].Usage)
+
+	// Deleting a tenant removes what it stores, and its ID can be used again
+	s.Require().Nil(s.taskRepo.DeleteTenant(s.context, "beta"))
+	_, err = s.taskRepo.Get(beta, 1)
+	s.Require().True(model.IsTenantNotFound(err))
+	_, err = s.taskRepo.CreateTenant(s.context, &model.Tenant{TenantID: "beta"})
+	s.Require().Nil(err)
+	defer s.taskRepo.DeleteTenant(s.context, "beta")
+	_, err = s.taskRepo.Get(beta, 1)
+	s.Require().True(model.IsTaskNotFound(err))
+}
+
+func (s *DataSourceTestSuite) Test_Tenants_Quotas() {
+	_, err := s.taskRepo.CreateTenant(s.context, &model.Tenant{TenantID: "small", MaxTasks: 2, MaxSeries: 1})
+	s.Require().Nil(err)
+	defer s.taskRepo.DeleteTenant(s.context, "small")
+	small := biz.WithTenant(s.context, "small")
+
+	start := time.Now()
+	_, err = s.taskRepo.CreateSeries(small, &model.Series{Rule: "FREQ=DAILY", Start: &start, Name: "daily"}, start)
+	s.Require().Nil(err)
+	_, err = s.taskRepo.CreateSeries(small, &model.Series{Rule: "FREQ=DAILY", Start: &start, Name: "again"}, start)
+	s.Require().True(model.IsTenantQuotaExceeded(err))
+
+	// The trashed tasks count until they are purged
+	_, err = s.taskRepo.Create(small, &model.Task{Name: "two"})
+	s.Require().Nil(err)
+	s.Require().Nil(s.taskRepo.Delete(small, 2))
+	_, err = s.taskRepo.Create(small, &model.Task{Name: "three"})
+	s.Require().True(model.IsTenantQuotaExceeded(err))
+	next := start.Add(time.Hour)
+	_, err = s.taskRepo.AdvanceSeries(small, 1, 1, &next)
+	s.Require().True(model.IsTenantQuotaExceeded(err))
+
+	s.Require().Nil(s.taskRepo.Purge(small, 2))
+	_, err = s.taskRepo.Create(small, &model.Task{Name: "three"})
+	s.Require().Nil(err)
+
+	// The default tenant has no quotas
+	for i := 0; i < 3; i++ {
+		_, err = s.taskRepo.Create(s.context, &model.Task{Name: "unbounded"})
+		s.Require().Nil(err)
+	}
+}
+
+func (s *DataSourceTestSuite) Test_Tenants_PurgeDeletedBefore() {
+	_, err := s.taskRepo.CreateTenant(s.context, &model.Tenant{TenantID: "acme"})
+	s.Require().Nil(err)
+	defer s.taskRepo.DeleteTenant(s.context, "acme")
+	acme := biz.WithTenant(s.context, "acme")
+
+	// The retention job purges the trash of every tenant
+	for _, ctx := range []context.Context{s.context, acme} {
+		_, err = s.taskRepo.Create(ctx, &model.Task{Name: "gone"})
+		s.Require().Nil(err)
+		s.Require().Nil(s.taskRepo.Delete(ctx, 1))
+	}
+	purged, err := s.taskRepo.PurgeDeletedBefore(s.context, time.Now().Add(time.Second))
+	s.Require().Nil(err)
+	s.Require().Equal(2, purged)
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsTenants() {
+	// The cleanup is never called, so only the first four entries are compacted
+	s.conf.Wal.CompactEvery = 4
+	taskRepo, _ := s.open()
+
+	_, err := taskRepo.CreateTenant(s.context, &model.Tenant{TenantID: "acme", MaxTasks: 5})
+	s.Require().Nil(err)
+	_, err = taskRepo.CreateTenant(s.context, &model.Tenant{TenantID: "gone"})
+	s.Require().Nil(err)
+	acme := biz.WithTenant(s.context, "acme")
+	_, err = taskRepo.Create(acme, &model.Task{Name: "acme task"})
+	s.Require().Nil(err)
+	_, err = taskRepo.Create(s.context, &model.Task{Name: "default task"})
+	s.Require().Nil(err)
+	s.Require().Nil(taskRepo.DeleteTenant(s.context, "gone"))
+	_, err = taskRepo.Create(acme, &model.Task{Name: "logged after the snapshot"})
+	s.Require().Nil(err)
+
+	// The snapshot holds the tenants, and the entries logged after it are replayed
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	tenants, err := taskRepo.ListTenants(s.context)
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(tenants))
+	s.Require().Equal("acme", tenants[0].TenantID)
+	s.Require().Equal(5, tenants[0].MaxTasks)
+	s.Require().Equal(2, tenants[0].Usage.Tasks)
+	s.Require().Equal(1, tenants[1].Usage.Tasks)
+
+	task, err := taskRepo.Get(acme, 2)
+	s.Require().Nil(err)
+	s.Require().Equal("logged after the snapshot", task.Name)
+	task, err = taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal("default task", task.Name)
+	_, err = taskRepo.Get(biz.WithTenant(s.context, "gone"), 1)
+	s.Require().True(model.IsTenantNotFound(err))
+}
@@ -0,0 +1,61 @@
+package data
+
+import (
+	"context"
+	"reflect"
+
+	"qantas.com/task/model"
+)
+
+func (r *taskRepo) Reassign(ctx context.Context, id uint64, owner string, assignees []string, version uint64) (*model.T_Task, error) {
+	if err := r.data.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.data.mu.Unlock()
+
+	r, err := r.in(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, changed, err := r.reassigned(id, owner, assignees, version)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		r.data.apply(r.put(ctx, val, model.RevisionReassigned))
+	}
+
+	return &val, nil
+}
+
+// reassigned returns the stored record with the given owner and assignees, and
+// whether it changed, provided the record is still at the given version. A version
+// of 0 skips the check.
+func (r *taskRepo) reassigned(id uint64, owner string, assignees []string, version uint64) (model.T_Task, bool, error) {
+	val, err := r.updated(&model.Task{TaskID: id}, version)
+	if err != nil {
+		return model.T_Task{}, false, err
+	}
+	stored := r.space.tasks[id]
+	if stored.Owner == owner && reflect.DeepEqual(stored.Assignees, assignees) {
+		return stored, false, nil
+	}
+
+	val.Task = stored.Task
+	val.Owner = owner
+	val.Assignees = assignees
+	return val, true, nil
+}
+
+// taskUsers lists the users the task is indexed under: its creator, its owner and
+// its assignees. A user may be listed more than once.
+func taskUsers(t model.T_Task) []string {
+	result := make([]string, 0, len(t.Assignees)+2)
+	for _, user := range append([]string{t.CreatedBy, t.Owner}, t.Assignees...) {
+		if user != "" {
+			result = append(result, user)
+		}
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package data_test
+
+import (
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/model"
+)
+
+func (s *DataSourceTestSuite) listUser(user string, role biz.UserRole, filter string) []uint64 {
+	q := biz.TaskQuery{User: user, Role: role, Filter: filter}
+	s.Require().Nil(q.Validate())
+	page, err := s.taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+	var ids []uint64
+	for _, task := range page.Tasks {
+		ids = append(ids, task.TaskID)
+	}
+	return ids
+}
+
+func (s *DataSourceTestSuite) Test_Reassign() {
+	alice := biz.WithActor(s.context, "alice")
+	for _, name := range []string{"deploy", "review"} {
+		_, err := s.taskRepo.Create(alice, &model.Task{Name: name})
+		s.Require().Nil(err)
+	}
+	task, err := s.taskRepo.Get(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal("alice", task.CreatedBy)
+	s.Require().Equal("alice", task.Owner)
+
+	task, err = s.taskRepo.Reassign(biz.WithActor(s.context, "alice"), 1, "bob", []string{"alice", "carol"}, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(2), task.Version)
+	s.Require().Equal("alice", task.CreatedBy)
+	_, err = s.taskRepo.Reassign(s.context, 1, "carol", nil, 1)
+	s.Require().True(model.IsTaskVersionMismatch(err))
+
+	// Reassigning to the same users changes nothing
+	task, err = s.taskRepo.Reassign(s.context, 1, "bob", []string{"alice", "carol"}, 0)
+	s.Require().Nil(err)
+	s.Require().Equal(uint64(2), task.Version)
+
+	history, err := s.taskRepo.History(s.context, 1)
+	s.Require().Nil(err)
+	s.Require().Equal(2, len(history))
+	s.Require().Equal(model.RevisionReassigned, history[1].Action)
+	s.Require().Equal("alice", history[1].Actor)
+	s.Require().Equal([]model.T_Change{
+		{Field: "assignees", To: []string{"alice", "carol"}},
+		{Field: "owner", From: "alice", To: "bob"},
+	}, history[1].Changes)
+
+	// The user index follows reassignments and deletes
+	s.Require().Equal([]uint64{1, 2}, s.listUser("alice", "", ""))
+	s.Require().Equal([]uint64{2}, s.listUser("alice", biz.UserRoleOwner, ""))
+	s.Require().Equal([]uint64{1}, s.listUser("carol", biz.UserRoleAssignee, ""))
+	s.Require().Equal([]uint64{1, 2}, s.listUser("alice", biz.UserRoleCreator, ""))
+	s.Require().Nil(s.listUser("dave", "", ""))
+	s.Require().Equal([]uint64{1}, s.listUser("", "", "owner = bob AND assignee = carol"))
+
+	_, err = s.taskRepo.Reassign(s.context, 1, "", []string{"dave"}, 0)
+	s.Require().Nil(err)
+	s.Require().Nil(s.listUser("carol", "", ""))
+	s.Require().Equal([]uint64{1}, s.listUser("dave", "", ""))
+	s.Require().Nil(s.taskRepo.Delete(s.context, 2))
+	s.Require().Nil(s.listUser("alice", biz.UserRoleOwner, ""))
+
+	_, err = s.taskRepo.Reassign(s.context, 2, "bob", nil, 0)
+	s.Require().True(model.IsTaskNotFound(err))
+}
+
+func (s *DurableDataSourceTestSuite) Test_Restart_KeepsAssignees() {
+	taskRepo, _ := s.open()
+
+	_, err := taskRepo.Create(biz.WithActor(s.context, "alice"), &model.Task{Name: "deploy"})
+	s.Require().Nil(err)
+	_, err = taskRepo.Reassign(s.context, 1, "bob", []string{"carol"}, 0)
+	s.Require().Nil(err)
+
+	taskRepo, cleanup := s.open()
+	defer cleanup()
+
+	q := biz.TaskQuery{User: "carol"}
+	s.Require().Nil(q.Validate())
+	page, err := taskRepo.List(s.context, &q)
+	s.Require().Nil(err)
+	s.Require().Equal(1, len(page.Tasks))
+	s.Require().Equal("alice", page.Tasks[0].CreatedBy)
+	s.Require().Equal("bob", page.Tasks[0].Owner)
+}
@@ -0,0 +1,301 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"qantas.com/task/internal/conf"
+	"qantas.com/task/model"
+)
+
+const defaultCompactEvery = 1000
+
+type walOp string
+
+const (
+	// walOpPut stores the full state of a task, replacing any previous state.
+	walOpPut walOp = "put"
+	// walOpEmpty removes every task and resets the ID sequence.
+	walOpEmpty walOp = "empty"
+	// walOpPurge permanently removes a task. The ID sequence is left as it is.
+	walOpPurge walOp = "purge"
+	// walOpSeries stores the full state of a series, together with the occurrence it
+	// created if any, so that the two are written at once.
+	walOpSeries walOp = "series"
+	// walOpTenant stores a tenant, which starts out with nothing stored.
+	walOpTenant walOp = "tenant"
+	// walOpDropTenant removes a tenant along with everything it stores.
+	walOpDropTenant walOp = "dropTenant"
+	// walOpProject stores the full state of a project.
+	walOpProject walOp = "project"
+	// walOpDropProject removes a project. The tasks on its board are left as they are.
+	walOpDropProject walOp = "dropProject"
+	// walOpComment stores the full state of a comment on a task.
+	walOpComment walOp = "comment"
+	// walOpDropComment removes a comment from a task.
+	walOpDropComment walOp = "dropComment"
+	// walOpAttachment stores an attachment of a task. Its content is in the attachment store.
+	walOpAttachment walOp = "attachment"
+	// walOpDropAttachment removes an attachment from a task.
+	walOpDropAttachment walOp = "dropAttachment"
+)
+
+// walEntry is a single mutation recorded in the write-ahead log. Entries carry
+// the complete resulting state, so replaying an entry twice is harmless.
+type walEntry struct {
+	Op walOp `json:"op"`
+	// Tenant is the tenant the entry changes. The entries without one, logged
+	// before there were tenants, change the default tenant.
+	Tenant string        `json:"tenant,omitempty"`
+	Task   *model.T_Task `json:"task,omitempty"`
+	TaskID uint64        `json:"taskID,omitempty"`
+	// Revision records the change a put makes to the task.
+	Revision     *model.T_Revision   `json:"revision,omitempty"`
+	Series       *model.T_Series     `json:"series,omitempty"`
+	TenantRecord *model.T_Tenant     `json:"tenantRecord,omitempty"`
+	Project      *model.T_Project    `json:"project,omitempty"`
+	ProjectID    string              `json:"projectID,omitempty"`
+	Comment      *model.T_Comment    `json:"comment,omitempty"`
+	CommentID    uint64              `json:"commentID,omitempty"`
+	Attachment   *model.T_Attachment `json:"attachment,omitempty"`
+	AttachmentID uint64              `json:"attachmentID,omitempty"`
+}
+
+// walSnapshot is the compacted state the log is folded into. The default tenant
+// is inline, and the others follow it.
+type walSnapshot struct {
+	walSpace
+	Tenants []walTenant `json:"tenants,omitempty"`
+}
+
+// walTenant is a tenant other than the default one, with what it stores.
+type walTenant struct {
+	Tenant model.T_Tenant `json:"tenant"`
+	walSpace
+}
+
+// walSpace is the compacted state of the tasks, series, projects, comments and
+// attachments of a tenant.
+type walSpace struct {
+	Index     uint64             `json:"index"`
+	Tasks     []model.T_Task     `json:"tasks"`
+	Revisions []model.T_Revision `json:"revisions,omitempty"`
+	// SeriesIndex is the ID sequence of the series, apart from the one of the tasks.
+	SeriesIndex uint64            `json:"seriesIndex,omitempty"`
+	Series      []model.T_Series  `json:"series,omitempty"`
+	Projects    []model.T_Project `json:"projects,omitempty"`
+	// CommentIndex is the ID sequence of the comments.
+	CommentIndex uint64            `json:"commentIndex,omitempty"`
+	Comments     []model.T_Comment `json:"comments,omitempty"`
+	// AttachmentIndex is the ID sequence of the attachments.
+	AttachmentIndex uint64               `json:"attachmentIndex,omitempty"`
+	Attachments     []model.T_Attachment `json:"attachments,omitempty"`
+}
+
+// wal is an append-only JSON lines log backed by a snapshot file.
+type wal struct {
+	path         string
+	snapshotPath string
+	fsync        conf.Data_WAL_Fsync
+	compactEvery int
+	file         *os.File
+	entries      int
+	log          *log.Helper
+}
+
+func openWAL(c *conf.Data_WAL, logger log.Logger) (*wal, error) {
+	compactEvery := int(c.GetCompactEvery())
+	if compactEvery == 0 {
+		compactEvery = defaultCompactEvery
+	}
+
+	file, err := os.OpenFile(c.GetPath(), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open wal %s: %w", c.GetPath(), err)
+	}
+
+	return &wal{
+		path:         c.GetPath(),
+		snapshotPath: c.GetPath() + ".snapshot",
+		fsync:        c.GetFsync(),
+		compactEvery: compactEvery,
+		file:         file,
+		log:          log.NewHelper(logger),
+	}, nil
+}
+
+// readSnapshot returns the last compacted state, or nil if the log has never been compacted.
+func (w *wal) readSnapshot() (*walSnapshot, error) {
+	b, err := os.ReadFile(w.snapshotPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read wal snapshot %s: %w", w.snapshotPath, err)
+	}
+
+	var s walSnapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("decode wal snapshot %s: %w", w.snapshotPath, err)
+	}
+	return &s, nil
+}
+
+// replay calls fn for every entry in the log. A torn entry at the end of the
+// log, left behind by a crash in the middle of a write, is truncated away.
+func (w *wal) replay(fn func(walEntry)) error {
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(w.file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) > 0 {
+				w.log.Warnf("wal: discarding torn entry at offset %d of %s", offset, w.path)
+				return w.file.Truncate(offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read wal %s: %w", w.path, err)
+		}
+
+		var entry walEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("decode wal %s at offset %d: %w", w.path, offset, err)
+		}
+		fn(entry)
+
+		offset += int64(len(line))
+		w.entries++
+	}
+}
+
+// append writes the entry at the end of the log. An entry which fails to be written
+// is cut off again, so that no part of it is left for the next one to follow.
+func (w *wal) append(entry walEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	offset, err := w.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("append wal %s: %w", w.path, err)
+	}
+	if err := w.write(append(b, '\n')); err != nil {
+		w.rewind(offset)
+		return err
+	}
+
+	w.entries++
+	return nil
+}
+
+func (w *wal) write(b []byte) error {
+	if _, err := w.file.Write(b); err != nil {
+		return fmt.Errorf("append wal %s: %w", w.path, err)
+	}
+	if w.fsync == conf.Data_WAL_ALWAYS {
+		if err := w.file.Sync(); err != nil {
+			return fmt.Errorf("sync wal %s: %w", w.path, err)
+		}
+	}
+	return nil
+}
+
+// rewind cuts the log back to the offset. Should that fail as well, the torn entry
+// is discarded when the log is next replayed.
+func (w *wal) rewind(offset int64) {
+	if err := w.file.Truncate(offset); err != nil {
+		w.log.Errorf("wal: failed to truncate %s to offset %d: %v", w.path, offset, err)
+		return
+	}
+	if _, err := w.file.Seek(offset, io.SeekStart); err != nil {
+		w.log.Errorf("wal: failed to seek %s to offset %d: %v", w.path, offset, err)
+	}
+}
+
+func (w *wal) needsCompaction() bool {
+	return w.entries >= w.compactEvery
+}
+
+// compact writes the snapshot and then truncates the log. If the process dies
+// in between, the stale entries are replayed on top of the snapshot, which
+// yields the same state because every op, from put and empty to purge and the
+// drops of tenants, projects, comments and attachments, is idempotent when the
+// entries are applied in log order.
+func (w *wal) compact(s walSnapshot) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	tmp := w.snapshotPath + ".tmp"
+	if err := writeFileSync(tmp, b); err != nil {
+		return fmt.Errorf("write wal snapshot %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, w.snapshotPath); err != nil {
+		return fmt.Errorf("rename wal snapshot %s: %w", tmp, err)
+	}
+	// The log must not be truncated before the rename is durable
+	if err := syncDir(filepath.Dir(w.snapshotPath)); err != nil {
+		return fmt.Errorf("sync wal snapshot %s: %w", w.snapshotPath, err)
+	}
+
+	if err := w.file.Truncate(0); err != nil {
+		return fmt.Errorf("truncate wal %s: %w", w.path, err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("sync wal %s: %w", w.path, err)
+	}
+
+	w.entries = 0
+	return nil
+}
+
+func (w *wal) close() error {
+	if err := w.file.Sync(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// syncDir flushes the entries of the directory, such as a file renamed into it.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	if err := dir.Sync(); err != nil {
+		dir.Close()
+		return err
+	}
+	return dir.Close()
+}
+
+func writeFileSync(path string, b []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
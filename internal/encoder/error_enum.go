@@ -3,9 +3,53 @@ package encoder
 type ErrorMessage string
 
 const (
-	TASK_NOT_EXIST        ErrorMessage = "task does not exist"
-	TASK_DELETED          ErrorMessage = "task has been logically deleted"
-	TASK_ID_NOT_SPECIFIED ErrorMessage = "task id not specified"
-	TASK_CREATION_ERROR   ErrorMessage = "task is failed to be created"
-	TASK_DATABASE_TIMEOUT ErrorMessage = "task database timeout"
+	TASK_NOT_EXIST          ErrorMessage = "task does not exist"
+	TASK_DELETED            ErrorMessage = "task has been logically deleted"
+	TASK_ID_NOT_SPECIFIED   ErrorMessage = "task id not specified"
+	TASK_CREATION_ERROR     ErrorMessage = "task is failed to be created"
+	TASK_DATABASE_TIMEOUT   ErrorMessage = "task database timeout"
+	TASK_STORAGE_FAILURE    ErrorMessage = "task storage failure"
+	TASK_QUERY_INVALID      ErrorMessage = "task query is invalid"
+	TASK_NOT_DELETED        ErrorMessage = "task has not been deleted"
+	TASK_VERSION_MISMATCH   ErrorMessage = "task has been modified since the given version"
+	TASK_REVISION_NOT_EXIST ErrorMessage = "task revision does not exist"
+	REQUEST_BODY_INVALID    ErrorMessage = "request body is invalid"
+	REQUEST_PARAM_INVALID   ErrorMessage = "request parameter is invalid"
+	REQUEST_BODY_TOO_LARGE  ErrorMessage = "request body is too large"
+	REQUEST_CANCELED        ErrorMessage = "request has been canceled"
+	TASK_INVALID_TRANSITION ErrorMessage = "task cannot move to the given status"
+	TASK_PARENT_INVALID     ErrorMessage = "task parent is invalid"
+	TASK_HAS_CHILDREN       ErrorMessage = "task has subtasks"
+	TASK_DEPENDENCY_INVALID ErrorMessage = "task dependency is invalid"
+	TASK_DEPENDENCY_CYCLE   ErrorMessage = "task dependencies would form a cycle"
+	TASK_BLOCKED            ErrorMessage = "task is waiting on dependencies which are not done"
+	TASK_TAG_INVALID        ErrorMessage = "task tag is invalid"
+	TASK_TAG_NOT_EXIST      ErrorMessage = "task tag does not exist"
+	TASK_FILTER_INVALID     ErrorMessage = "task filter is invalid"
+	SERIES_NOT_EXIST        ErrorMessage = "series does not exist"
+	SERIES_RULE_INVALID     ErrorMessage = "series rule is invalid"
+	SERIES_VERSION_MISMATCH ErrorMessage = "series has been modified since the given version"
+	TASK_ACTION_FORBIDDEN   ErrorMessage = "task can only be changed by its owner or assignees"
+	TASK_USER_INVALID       ErrorMessage = "task user is invalid"
+	REQUEST_UNAUTHENTICATED ErrorMessage = "request is not authenticated"
+	REQUEST_FORBIDDEN       ErrorMessage = "request is not allowed"
+	ACTION_DENIED           ErrorMessage = "action is not allowed by the access policy"
+	PERMISSION_INVALID      ErrorMessage = "permission is not known to the access policy"
+	TENANT_NOT_EXIST        ErrorMessage = "tenant does not exist"
+	TENANT_EXISTS           ErrorMessage = "tenant already exists"
+	TENANT_INVALID          ErrorMessage = "tenant is invalid"
+	TENANT_QUOTA_EXCEEDED   ErrorMessage = "tenant has reached its quota"
+	PROJECT_NOT_EXIST       ErrorMessage = "project does not exist"
+	PROJECT_EXISTS          ErrorMessage = "project already exists"
+	PROJECT_INVALID         ErrorMessage = "project is invalid"
+	PROJECT_NOT_EMPTY       ErrorMessage = "project still holds tasks"
+	PROJECT_MODIFIED        ErrorMessage = "project has been modified since the given version"
+	COMMENT_NOT_EXIST       ErrorMessage = "comment does not exist"
+	COMMENT_INVALID         ErrorMessage = "comment is invalid"
+	COMMENT_NOT_AUTHOR      ErrorMessage = "comment can only be changed by its author"
+	COMMENT_MODIFIED        ErrorMessage = "comment has been modified since the given version"
+	ATTACHMENT_NOT_EXIST    ErrorMessage = "attachment does not exist"
+	ATTACHMENT_INVALID      ErrorMessage = "attachment is invalid"
+	ATTACHMENT_TOO_LARGE    ErrorMessage = "attachment is too large"
+	ATTACHMENTS_DISABLED    ErrorMessage = "attachments are not enabled"
 )
@@ -8,10 +8,18 @@ func NewHTTPSuccess(data interface{}) *HTTPSuccess {
 }
 
 type HTTPSuccess struct {
-	Code int         `json:"code,omitempty"`
-	Data interface{} `json:"data,omitempty"`
+	Code       int         `json:"code,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+	NextCursor string      `json:"nextCursor,omitempty"`
 }
 
 func FromResponse(data interface{}) *HTTPSuccess {
 	return NewHTTPSuccess(data)
 }
+
+// FromPage wraps one page of a listing, with the cursor of the next page if there is one.
+func FromPage(data interface{}, nextCursor string) *HTTPSuccess {
+	s := NewHTTPSuccess(data)
+	s.NextCursor = nextCursor
+	return s
+}
@@ -0,0 +1,428 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/conf"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// apiKeyHeader carries an API key, and apiKeyMetadata is its gRPC counterpart.
+const (
+	apiKeyHeader   = "X-API-Key"
+	apiKeyMetadata = "x-api-key"
+)
+
+// authorizationMetadata carries a bearer token like the Authorization header.
+const authorizationMetadata = "authorization"
+
+// The methods a principal authenticates with, see biz.Principal.
+const (
+	authMethodAPIKey = "api_key"
+	authMethodJWT    = "jwt"
+)
+
+// apiKey is an API key of the config, of which only the hash is known.
+type apiKey struct {
+	hash      [sha256.Size]byte
+	principal string
+	readOnly  bool
+	tenant    string
+}
+
+// Authenticator authenticates requests with the API keys and the JWT keys of the
+// config. It lets every request through when neither is configured.
+type Authenticator struct {
+	apiKeys []apiKey
+	jwt     *conf.Server_Auth_JWT
+	secret  []byte
+	// rsaKeys holds the RS256 keys by kid. The key of the config has no kid.
+	rsaKeys map[string]*rsa.PublicKey
+	log     *log.Helper
+	now     func() time.Time
+}
+
+func NewAuthenticator(c *conf.Server, logger log.Logger) (*Authenticator, error) {
+	a := &Authenticator{rsaKeys: map[string]*rsa.PublicKey{}, log: log.NewHelper(logger), now: time.Now}
+
+	for i, k := range c.GetAuth().GetApiKeys() {
+		hash, err := hex.DecodeString(k.GetSha256())
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("api key %d: sha256 must be %d hex encoded bytes", i, sha256.Size)
+		}
+		if k.GetPrincipal() == "" {
+			return nil, fmt.Errorf("api key %d: principal is empty", i)
+		}
+		key := apiKey{principal: k.GetPrincipal(), readOnly: k.GetReadOnly()}
+		if t := k.GetTenant(); t != "" {
+			tenant, err := biz.NormalizeTenant(t)
+			if err != nil {
+				return nil, fmt.Errorf("api key %d: %w", i, err)
+			}
+			key.tenant = tenant
+		}
+		copy(key.hash[:], hash)
+		a.apiKeys = append(a.apiKeys, key)
+	}
+
+	j := c.GetAuth().GetJwt()
+	if j == nil {
+		return a, nil
+	}
+	a.jwt = j
+	a.secret = []byte(j.GetHs256Secret())
+	if p := j.GetRs256PublicKey(); p != "" {
+		key, err := parseRSAPublicKey([]byte(p))
+		if err != nil {
+			return nil, fmt.Errorf("rs256 public key: %w", err)
+		}
+		a.rsaKeys[""] = key
+	}
+	if f := j.GetJwksFile(); f != "" {
+		keys, err := loadJWKS(f)
+		if err != nil {
+			return nil, fmt.Errorf("jwks file %s: %w", f, err)
+		}
+		for kid, key := range keys {
+			a.rsaKeys[kid] = key
+		}
+	}
+	return a, nil
+}
+
+// Enabled tells whether requests are authenticated at all.
+func (a *Authenticator) Enabled() bool {
+	return len(a.apiKeys) > 0 || len(a.secret) > 0 || len(a.rsaKeys) > 0
+}
+
+// Authenticate returns the principal of an API key or of a bearer token, the value
+// of an Authorization header. Only one of them is checked, the API key first.
+func (a *Authenticator) Authenticate(key, authorization string) (*biz.Principal, error) {
+	if key != "" {
+		return a.authenticateKey(key)
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+	if !strings.HasPrefix(authorization, "Bearer ") || token == "" {
+		return nil, model.ErrorUnauthenticated("%s: %s", encoder.REQUEST_UNAUTHENTICATED, "no API key or bearer token")
+	}
+	return a.authenticateToken(token)
+}
+
+// authenticateKey compares the hash of key with every API key, so that the time it
+// takes tells nothing about which keys exist.
+func (a *Authenticator) authenticateKey(key string) (*biz.Principal, error) {
+	hash := sha256.Sum256([]byte(key))
+	var found *apiKey
+	for i := range a.apiKeys {
+		if subtle.ConstantTimeCompare(hash[:], a.apiKeys[i].hash[:]) == 1 {
+			found = &a.apiKeys[i]
+		}
+	}
+	if found == nil {
+		return nil, model.ErrorUnauthenticated("%s: %s", encoder.REQUEST_UNAUTHENTICATED, "unknown API key")
+	}
+	return &biz.Principal{Subject: found.principal, Method: authMethodAPIKey, ReadOnly: found.readOnly, Tenant: found.tenant}, nil
+}
+
+// jwtHeader is the header of a token.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// authenticateToken verifies the signature and the claims of a JWT.
+func (a *Authenticator) authenticateToken(token string) (*biz.Principal, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return model.ErrorUnauthenticated("%s: %s", encoder.REQUEST_UNAUTHENTICATED, "invalid token, "+fmt.Sprintf(format, args...))
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalid("it is not a JWS compact serialization")
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalid("header: %s", err.Error())
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalid("signature: %s", err.Error())
+	}
+
+	// The alg of the token only picks among the keys of the config, so "none" or
+	// an HS256 token signed with an RSA public key is never accepted.
+	signed := []byte(parts[0] + "." + parts[1])
+	switch header.Alg {
+	case "HS256":
+		if len(a.secret) == 0 {
+			return nil, invalid("HS256 is not accepted")
+		}
+		mac := hmac.New(sha256.New, a.secret)
+		mac.Write(signed)
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return nil, invalid("signature does not match")
+		}
+	case "RS256":
+		key, ok := a.rsaKeys[header.Kid]
+		if !ok {
+			// The key of the config verifies tokens whatever their kid
+			key, ok = a.rsaKeys[""]
+		}
+		if !ok {
+			return nil, invalid("no RS256 key with kid %q", header.Kid)
+		}
+		digest := sha256.Sum256(signed)
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) != nil {
+			return nil, invalid("signature does not match")
+		}
+	default:
+		return nil, invalid("alg %q is not accepted", header.Alg)
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalid("claims: %s", err.Error())
+	}
+	return a.principal(claims, invalid)
+}
+
+// principal checks the registered claims of a verified token and returns who it
+// authenticates.
+func (a *Authenticator) principal(claims map[string]interface{}, invalid func(string, ...interface{}) error) (*biz.Principal, error) {
+	now := a.now()
+	leeway := a.jwt.GetLeeway().AsDuration()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, invalid("exp is missing")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(leeway)) {
+		return nil, invalid("it has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(leeway).Before(time.Unix(int64(nbf), 0)) {
+		return nil, invalid("it is not valid yet")
+	}
+	if iss := a.jwt.GetIssuer(); iss != "" && claims["iss"] != iss {
+		return nil, invalid("iss is not %q", iss)
+	}
+	if aud := a.jwt.GetAudience(); aud != "" && !claimHas(claims["aud"], aud) {
+		return nil, invalid("aud does not hold %q", aud)
+	}
+
+	claim := a.jwt.GetPrincipalClaim()
+	if claim == "" {
+		claim = "sub"
+	}
+	subject, _ := claims[claim].(string)
+	if subject == "" {
+		return nil, invalid("%s is missing", claim)
+	}
+
+	p := &biz.Principal{Subject: subject, Method: authMethodJWT}
+	if scope := a.jwt.GetWriteScope(); scope != "" {
+		s, _ := claims["scope"].(string)
+		p.ReadOnly = !claimHas(strings.Fields(s), scope)
+	}
+	if claim := a.jwt.GetTenantClaim(); claim != "" {
+		if t, _ := claims[claim].(string); t != "" {
+			tenant, err := biz.NormalizeTenant(t)
+			if err != nil {
+				return nil, invalid("%s is not a tenant", claim)
+			}
+			p.Tenant = tenant
+		}
+	}
+	return p, nil
+}
+
+// Middleware authenticates every request before it is routed. A request without
+// valid credentials is rejected with 401, a read only principal may only use the
+// safe methods, and a principal bound to a tenant may not name another one.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	if !a.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := a.Authenticate(r.Header.Get(apiKeyHeader), r.Header.Get("Authorization"))
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, err)
+			return
+		}
+		if p.ReadOnly && !safeMethod(r.Method) {
+			w.Header().Set("Content-Type", "application/json")
+			writeError(w, model.ErrorForbidden("%s: %s is read only", encoder.REQUEST_FORBIDDEN, p.Subject))
+			return
+		}
+		if err := tenantAllowed(p, r.Header.Get(tenantHeader)); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			writeError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(biz.WithPrincipal(r.Context(), p)))
+	})
+}
+
+// Interceptor authenticates every gRPC call like Middleware. Calls of the Get, List,
+// Search and Explain methods are the reads a read only principal may make.
+func (a *Authenticator) Interceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !a.Enabled() {
+		return handler(ctx, req)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	p, err := a.Authenticate(firstMetadata(md, apiKeyMetadata), firstMetadata(md, authorizationMetadata))
+	if err != nil {
+		return nil, err
+	}
+	if p.ReadOnly && !readMethod(info.FullMethod) {
+		return nil, model.ErrorForbidden("%s: %s is read only", encoder.REQUEST_FORBIDDEN, p.Subject)
+	}
+	if err := tenantAllowed(p, firstMetadata(md, tenantMetadata)); err != nil {
+		return nil, err
+	}
+	return handler(biz.WithPrincipal(ctx, p), req)
+}
+
+// tenantAllowed checks that a principal bound to a tenant does not name another.
+func tenantAllowed(p *biz.Principal, tenant string) error {
+	if p.Tenant == "" || tenant == "" || strings.EqualFold(strings.TrimSpace(tenant), p.Tenant) {
+		return nil
+	}
+	return model.ErrorForbidden("%s: %s may only use tenant %s", encoder.REQUEST_FORBIDDEN, p.Subject, p.Tenant)
+}
+
+// safeMethod tells the HTTP methods which do not change tasks.
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// readMethod tells the gRPC methods which do not change tasks.
+func readMethod(fullMethod string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range []string{"Get", "List", "Search", "Explain"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// claimHas tells whether a claim, a string or a list of them, holds want.
+func claimHas(claim interface{}, want string) bool {
+	switch c := claim.(type) {
+	case string:
+		return c == want
+	case []string:
+		for _, v := range c {
+			if v == want {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, v := range c {
+			if v == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// parseRSAPublicKey reads a PEM encoded PKIX or PKCS #1 RSA public key.
+func parseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// jwks is a JSON Web Key Set, of which only the RSA keys are read.
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// loadJWKS reads the RSA keys of a JWKS file by kid.
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: n: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: e: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no RSA keys")
+	}
+	return keys, nil
+}
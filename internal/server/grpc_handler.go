@@ -0,0 +1,713 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/service"
+	"qantas.com/task/model"
+)
+
+// actorMetadata names the user who makes a call, the gRPC counterpart of actorHeader.
+const actorMetadata = "x-actor"
+
+// tenantMetadata names the tenant of a call, the gRPC counterpart of tenantHeader.
+const tenantMetadata = "x-tenant"
+
+// TaskGRPCHandler serves the gRPC task API on top of the same service as the HTTP handlers.
+type TaskGRPCHandler struct {
+	model.UnimplementedTaskServiceServer
+	taskSvc *service.TaskService
+	log     *log.Helper
+}
+
+func NewTaskGRPCHandler(taskSvc *service.TaskService, logger log.Logger) model.TaskServiceServer {
+	return &TaskGRPCHandler{taskSvc: taskSvc, log: log.NewHelper(logger)}
+}
+
+// callContext returns the context the call is served in.
+func (h *TaskGRPCHandler) callContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if actors := md.Get(actorMetadata); len(actors) > 0 {
+		ctx = biz.WithActor(ctx, actors[0])
+	}
+	if tenants := md.Get(tenantMetadata); len(tenants) > 0 {
+		ctx = biz.WithTenant(ctx, tenants[0])
+	}
+	return ctx
+}
+
+func (h *TaskGRPCHandler) ListTasks(ctx context.Context, req *model.ListTasksRequest) (*model.ListTasksReply, error) {
+	page, err := h.taskSvc.ListTasks(h.callContext(ctx), toTaskQuery(req))
+	if err != nil {
+		return nil, err
+	}
+	return toListTasksReply(page), nil
+}
+
+func (h *TaskGRPCHandler) ListDeletedTasks(ctx context.Context, req *model.ListTasksRequest) (*model.ListTasksReply, error) {
+	page, err := h.taskSvc.ListDeletedTasks(h.callContext(ctx), toTaskQuery(req))
+	if err != nil {
+		return nil, err
+	}
+	return toListTasksReply(page), nil
+}
+
+func (h *TaskGRPCHandler) GetTask(ctx context.Context, req *model.GetTaskRequest) (*model.TaskRecord, error) {
+	task, err := h.taskSvc.GetTaskByID(h.callContext(ctx), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) CreateTask(ctx context.Context, req *model.CreateTaskRequest) (*model.TaskRecord, error) {
+	task, err := h.taskSvc.CreateTask(h.callContext(ctx), &model.Task{Name: req.GetName(), Content: req.GetContent(), ParentID: req.GetParentId(), Tags: req.GetTags(),
+		DueAt: fromTimestamp(req.GetDueAt()), RemindAt: fromTimestamp(req.GetRemindAt())})
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) UpdateTask(ctx context.Context, req *model.UpdateTaskRequest) (*model.TaskRecord, error) {
+	task, err := h.taskSvc.UpdateTaskByID(h.callContext(ctx),
+		&model.Task{TaskID: req.GetTaskId(), Name: req.GetName(), Content: req.GetContent(), ParentID: req.GetParentId(), Tags: req.GetTags(),
+			DueAt: fromTimestamp(req.GetDueAt()), RemindAt: fromTimestamp(req.GetRemindAt())},
+		req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) DeleteTask(ctx context.Context, req *model.DeleteTaskRequest) (*emptypb.Empty, error) {
+	if req.GetCascade() {
+		if _, err := h.taskSvc.DeleteTaskTree(h.callContext(ctx), req.GetTaskId()); err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	}
+	if err := h.taskSvc.DeleteTaskByID(h.callContext(ctx), req.GetTaskId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (h *TaskGRPCHandler) RestoreTask(ctx context.Context, req *model.RestoreTaskRequest) (*model.TaskRecord, error) {
+	task, err := h.taskSvc.RestoreTaskByID(h.callContext(ctx), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) PurgeTask(ctx context.Context, req *model.PurgeTaskRequest) (*emptypb.Empty, error) {
+	if err := h.taskSvc.PurgeTaskByID(h.callContext(ctx), req.GetTaskId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (h *TaskGRPCHandler) GetTaskHistory(ctx context.Context, req *model.GetTaskHistoryRequest) (*model.GetTaskHistoryReply, error) {
+	revisions, err := h.taskSvc.GetTaskHistory(h.callContext(ctx), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &model.GetTaskHistoryReply{Revisions: make([]*model.RevisionRecord, 0, len(revisions))}
+	for i := range revisions {
+		reply.Revisions = append(reply.Revisions, toRevisionRecord(&revisions[i]))
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) GetTaskRevision(ctx context.Context, req *model.GetTaskRevisionRequest) (*model.RevisionRecord, error) {
+	revision, err := h.taskSvc.GetTaskRevision(h.callContext(ctx), req.GetTaskId(), req.GetRevision())
+	if err != nil {
+		return nil, err
+	}
+	return toRevisionRecord(revision), nil
+}
+
+func (h *TaskGRPCHandler) RevertTask(ctx context.Context, req *model.RevertTaskRequest) (*model.TaskRecord, error) {
+	task, err := h.taskSvc.RevertTaskByID(h.callContext(ctx), req.GetTaskId(), req.GetRevision())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) TransitionTask(ctx context.Context, req *model.TransitionTaskRequest) (*model.TaskRecord, error) {
+	task, err := h.taskSvc.TransitionTask(h.callContext(ctx), req.GetTaskId(), model.TaskStatus(req.GetStatus()), req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) ReassignTask(ctx context.Context, req *model.ReassignTaskRequest) (*model.TaskRecord, error) {
+	a := &model.TaskAssignment{Owner: req.GetOwner(), Assignees: req.GetAssignees()}
+	task, err := h.taskSvc.ReassignTask(h.callContext(ctx), req.GetTaskId(), a, req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) MoveTask(ctx context.Context, req *model.MoveTaskRequest) (*model.TaskRecord, error) {
+	m := &model.TaskMove{ProjectID: req.GetProjectId(), Column: req.GetColumn(), Before: req.GetBefore(), After: req.GetAfter()}
+	task, err := h.taskSvc.MoveTask(h.callContext(ctx), req.GetTaskId(), m, req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) ListUserTasks(ctx context.Context, req *model.ListUserTasksRequest) (*model.ListTasksReply, error) {
+	page, err := h.taskSvc.ListUserTasks(h.callContext(ctx), req.GetUser(), biz.UserRole(req.GetRole()), toTaskQuery(req.GetQuery()))
+	if err != nil {
+		return nil, err
+	}
+	return toListTasksReply(page), nil
+}
+
+func (h *TaskGRPCHandler) GetTaskChildren(ctx context.Context, req *model.GetTaskChildrenRequest) (*model.ListTasksReply, error) {
+	tasks, err := h.taskSvc.GetTaskChildren(h.callContext(ctx), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+	reply := &model.ListTasksReply{}
+	for i := range tasks {
+		reply.Tasks = append(reply.Tasks, toTaskRecord(&tasks[i]))
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) GetTaskTree(ctx context.Context, req *model.GetTaskTreeRequest) (*model.TaskNode, error) {
+	tree, err := h.taskSvc.GetTaskTree(h.callContext(ctx), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskNode(tree), nil
+}
+
+func (h *TaskGRPCHandler) AddTaskDependency(ctx context.Context, req *model.TaskDependencyRequest) (*model.TaskRecord, error) {
+	task, err := h.taskSvc.AddTaskDependency(h.callContext(ctx), req.GetTaskId(), req.GetDependsOn())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) RemoveTaskDependency(ctx context.Context, req *model.TaskDependencyRequest) (*model.TaskRecord, error) {
+	task, err := h.taskSvc.RemoveTaskDependency(h.callContext(ctx), req.GetTaskId(), req.GetDependsOn())
+	if err != nil {
+		return nil, err
+	}
+	return toTaskRecord(task), nil
+}
+
+func (h *TaskGRPCHandler) ListReadyTasks(ctx context.Context, req *model.ListTasksRequest) (*model.ListTasksReply, error) {
+	page, err := h.taskSvc.ListReadyTasks(h.callContext(ctx), toTaskQuery(req))
+	if err != nil {
+		return nil, err
+	}
+	return toListTasksReply(page), nil
+}
+
+func (h *TaskGRPCHandler) ListOverdueTasks(ctx context.Context, req *model.ListTasksRequest) (*model.ListTasksReply, error) {
+	page, err := h.taskSvc.ListOverdueTasks(h.callContext(ctx), toTaskQuery(req))
+	if err != nil {
+		return nil, err
+	}
+	return toListTasksReply(page), nil
+}
+
+func (h *TaskGRPCHandler) ListDueTasks(ctx context.Context, req *model.ListDueTasksRequest) (*model.ListTasksReply, error) {
+	page, err := h.taskSvc.ListDueTasks(h.callContext(ctx), toTaskQuery(req.GetQuery()), req.GetWithin().AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	return toListTasksReply(page), nil
+}
+
+func (h *TaskGRPCHandler) GetTaskGraph(ctx context.Context, _ *emptypb.Empty) (*model.ListTasksReply, error) {
+	tasks, err := h.taskSvc.GetTaskGraph(h.callContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return toListTasksReply(&biz.TaskPage{Tasks: tasks}), nil
+}
+
+func (h *TaskGRPCHandler) ListTags(ctx context.Context, _ *emptypb.Empty) (*model.ListTagsReply, error) {
+	tags, err := h.taskSvc.ListTags(h.callContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	reply := &model.ListTagsReply{}
+	for _, t := range tags {
+		reply.Tags = append(reply.Tags, &model.ListTagsReply_TagCount{Tag: t.Tag, Count: int32(t.Count)})
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) RenameTag(ctx context.Context, req *model.RenameTagRequest) (*model.RenameTagReply, error) {
+	n, err := h.taskSvc.RenameTag(h.callContext(ctx), req.GetFrom(), req.GetTo())
+	if err != nil {
+		return nil, err
+	}
+	return &model.RenameTagReply{Renamed: int32(n)}, nil
+}
+
+func (h *TaskGRPCHandler) SearchTasks(ctx context.Context, req *model.SearchTasksRequest) (*model.SearchTasksReply, error) {
+	results, err := h.taskSvc.SearchTasks(h.callContext(ctx), &biz.SearchQuery{Query: req.GetQ(), Limit: int(req.GetLimit())})
+	if err != nil {
+		return nil, err
+	}
+	reply := &model.SearchTasksReply{}
+	for i := range results {
+		reply.Results = append(reply.Results, &model.SearchTasksReply_Result{Task: toTaskRecord(&results[i].Task), Score: results[i].Score, Snippet: results[i].Snippet})
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) CreateSeries(ctx context.Context, req *model.SeriesRequest) (*model.SeriesRecord, error) {
+	series, err := h.taskSvc.CreateSeries(h.callContext(ctx), toSeries(req))
+	if err != nil {
+		return nil, err
+	}
+	return toSeriesRecord(series), nil
+}
+
+func (h *TaskGRPCHandler) GetSeries(ctx context.Context, req *model.GetSeriesRequest) (*model.SeriesRecord, error) {
+	series, err := h.taskSvc.GetSeriesByID(h.callContext(ctx), req.GetSeriesId())
+	if err != nil {
+		return nil, err
+	}
+	return toSeriesRecord(series), nil
+}
+
+func (h *TaskGRPCHandler) ListSeries(ctx context.Context, _ *emptypb.Empty) (*model.ListSeriesReply, error) {
+	series, err := h.taskSvc.ListSeries(h.callContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	reply := &model.ListSeriesReply{Series: make([]*model.SeriesRecord, 0, len(series))}
+	for i := range series {
+		reply.Series = append(reply.Series, toSeriesRecord(&series[i]))
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) UpdateSeries(ctx context.Context, req *model.SeriesRequest) (*model.SeriesRecord, error) {
+	series, err := h.taskSvc.UpdateSeries(h.callContext(ctx), toSeries(req), req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return toSeriesRecord(series), nil
+}
+
+func (h *TaskGRPCHandler) StopSeries(ctx context.Context, req *model.GetSeriesRequest) (*model.SeriesRecord, error) {
+	series, err := h.taskSvc.StopSeries(h.callContext(ctx), req.GetSeriesId())
+	if err != nil {
+		return nil, err
+	}
+	return toSeriesRecord(series), nil
+}
+
+func (h *TaskGRPCHandler) ExplainPolicy(ctx context.Context, req *model.ExplainPolicyRequest) (*model.PolicyDecision, error) {
+	d, err := h.taskSvc.ExplainPolicy(h.callContext(ctx), req.GetSubject(), biz.Permission(req.GetPermission()), req.GetProject())
+	if err != nil {
+		return nil, err
+	}
+	reply := &model.PolicyDecision{Subject: d.Subject, Permission: d.Permission, Project: d.Project, Allowed: d.Allowed, Reason: d.Reason}
+	if d.Grant != nil {
+		reply.Grant = &model.PolicyDecision_Grant{Subject: d.Grant.Subject, Role: d.Grant.Role, Project: d.Grant.Project}
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) CreateTenant(ctx context.Context, req *model.TenantRequest) (*model.TenantRecord, error) {
+	tenant := &model.Tenant{TenantID: req.GetTenantId(), MaxTasks: int(req.GetMaxTasks()), MaxSeries: int(req.GetMaxSeries())}
+	result, err := h.taskSvc.CreateTenant(h.callContext(ctx), tenant)
+	if err != nil {
+		return nil, err
+	}
+	return toTenantRecord(result), nil
+}
+
+func (h *TaskGRPCHandler) ListTenants(ctx context.Context, _ *emptypb.Empty) (*model.ListTenantsReply, error) {
+	tenants, err := h.taskSvc.ListTenants(h.callContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	reply := &model.ListTenantsReply{Tenants: make([]*model.TenantRecord, 0, len(tenants))}
+	for i := range tenants {
+		reply.Tenants = append(reply.Tenants, toTenantRecord(&tenants[i]))
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) DeleteTenant(ctx context.Context, req *model.DeleteTenantRequest) (*emptypb.Empty, error) {
+	if err := h.taskSvc.DeleteTenant(h.callContext(ctx), req.GetTenantId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (h *TaskGRPCHandler) CreateProject(ctx context.Context, req *model.ProjectRequest) (*model.ProjectRecord, error) {
+	project, err := h.taskSvc.CreateProject(h.callContext(ctx), toProject(req))
+	if err != nil {
+		return nil, err
+	}
+	return toProjectRecord(project), nil
+}
+
+func (h *TaskGRPCHandler) GetProject(ctx context.Context, req *model.GetProjectRequest) (*model.ProjectRecord, error) {
+	project, err := h.taskSvc.GetProject(h.callContext(ctx), req.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+	return toProjectRecord(project), nil
+}
+
+func (h *TaskGRPCHandler) ListProjects(ctx context.Context, _ *emptypb.Empty) (*model.ListProjectsReply, error) {
+	projects, err := h.taskSvc.ListProjects(h.callContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	reply := &model.ListProjectsReply{Projects: make([]*model.ProjectRecord, 0, len(projects))}
+	for i := range projects {
+		reply.Projects = append(reply.Projects, toProjectRecord(&projects[i]))
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) UpdateProject(ctx context.Context, req *model.ProjectRequest) (*model.ProjectRecord, error) {
+	project, err := h.taskSvc.UpdateProject(h.callContext(ctx), toProject(req), req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return toProjectRecord(project), nil
+}
+
+func (h *TaskGRPCHandler) DeleteProject(ctx context.Context, req *model.GetProjectRequest) (*emptypb.Empty, error) {
+	if err := h.taskSvc.DeleteProject(h.callContext(ctx), req.GetProjectId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (h *TaskGRPCHandler) GetBoard(ctx context.Context, req *model.GetProjectRequest) (*model.Board, error) {
+	board, err := h.taskSvc.GetBoard(h.callContext(ctx), req.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+	reply := &model.Board{Project: toProjectRecord(&board.Project), Columns: make([]*model.Board_Column, 0, len(board.Columns))}
+	for _, c := range board.Columns {
+		column := &model.Board_Column{Name: c.Name, Tasks: make([]*model.TaskRecord, 0, len(c.Tasks))}
+		for i := range c.Tasks {
+			column.Tasks = append(column.Tasks, toTaskRecord(&c.Tasks[i]))
+		}
+		reply.Columns = append(reply.Columns, column)
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) CreateComment(ctx context.Context, req *model.CommentRequest) (*model.CommentRecord, error) {
+	comment, err := h.taskSvc.CreateComment(h.callContext(ctx), req.GetTaskId(), &model.Comment{Body: req.GetBody()})
+	if err != nil {
+		return nil, err
+	}
+	return toCommentRecord(comment), nil
+}
+
+func (h *TaskGRPCHandler) GetComment(ctx context.Context, req *model.GetCommentRequest) (*model.CommentRecord, error) {
+	comment, err := h.taskSvc.GetComment(h.callContext(ctx), req.GetTaskId(), req.GetCommentId())
+	if err != nil {
+		return nil, err
+	}
+	return toCommentRecord(comment), nil
+}
+
+func (h *TaskGRPCHandler) ListComments(ctx context.Context, req *model.ListCommentsRequest) (*model.ListCommentsReply, error) {
+	comments, err := h.taskSvc.ListComments(h.callContext(ctx), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &model.ListCommentsReply{Comments: make([]*model.CommentRecord, 0, len(comments))}
+	for i := range comments {
+		reply.Comments = append(reply.Comments, toCommentRecord(&comments[i]))
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) UpdateComment(ctx context.Context, req *model.CommentRequest) (*model.CommentRecord, error) {
+	comment, err := h.taskSvc.UpdateComment(h.callContext(ctx), req.GetTaskId(), req.GetCommentId(), &model.Comment{Body: req.GetBody()}, req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return toCommentRecord(comment), nil
+}
+
+func (h *TaskGRPCHandler) DeleteComment(ctx context.Context, req *model.DeleteCommentRequest) (*emptypb.Empty, error) {
+	if err := h.taskSvc.DeleteComment(h.callContext(ctx), req.GetTaskId(), req.GetCommentId(), req.GetVersion()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (h *TaskGRPCHandler) ListActivity(ctx context.Context, req *model.ListCommentsRequest) (*model.ListActivityReply, error) {
+	activity, err := h.taskSvc.ListActivity(h.callContext(ctx), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &model.ListActivityReply{Activity: make([]*model.Activity, 0, len(activity))}
+	for _, a := range activity {
+		entry := &model.Activity{Kind: string(a.Kind), At: toTimestamp(a.At), Actor: a.Actor}
+		if a.Revision != nil {
+			entry.Revision = toRevisionRecord(a.Revision)
+		}
+		if a.Comment != nil {
+			entry.Comment = toCommentRecord(a.Comment)
+		}
+		reply.Activity = append(reply.Activity, entry)
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) GetAttachment(ctx context.Context, req *model.GetAttachmentRequest) (*model.AttachmentRecord, error) {
+	attachment, err := h.taskSvc.GetAttachment(h.callContext(ctx), req.GetTaskId(), req.GetAttachmentId())
+	if err != nil {
+		return nil, err
+	}
+	return toAttachmentRecord(attachment), nil
+}
+
+func (h *TaskGRPCHandler) ListAttachments(ctx context.Context, req *model.ListAttachmentsRequest) (*model.ListAttachmentsReply, error) {
+	attachments, err := h.taskSvc.ListAttachments(h.callContext(ctx), req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &model.ListAttachmentsReply{Attachments: make([]*model.AttachmentRecord, 0, len(attachments))}
+	for i := range attachments {
+		reply.Attachments = append(reply.Attachments, toAttachmentRecord(&attachments[i]))
+	}
+	return reply, nil
+}
+
+func (h *TaskGRPCHandler) DeleteAttachment(ctx context.Context, req *model.GetAttachmentRequest) (*emptypb.Empty, error) {
+	if err := h.taskSvc.DeleteAttachment(h.callContext(ctx), req.GetTaskId(), req.GetAttachmentId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toTaskQuery(req *model.ListTasksRequest) *biz.TaskQuery {
+	q := &biz.TaskQuery{
+		PageSize:   int(req.GetPageSize()),
+		Cursor:     req.GetCursor(),
+		SortBy:     biz.TaskSortField(req.GetSortBy()),
+		Descending: req.GetDescending(),
+		NamePrefix: req.GetNamePrefix(),
+		Tags:       req.GetTags(),
+		TagMatch:   biz.TagMatch(req.GetTagMatch()),
+		Filter:     req.GetFilter(),
+	}
+	q.CreatedAfter = fromTimestamp(req.GetCreatedAfter())
+	q.CreatedBefore = fromTimestamp(req.GetCreatedBefore())
+	return q
+}
+
+func toListTasksReply(page *biz.TaskPage) *model.ListTasksReply {
+	reply := &model.ListTasksReply{Tasks: make([]*model.TaskRecord, 0, len(page.Tasks)), NextCursor: page.NextCursor}
+	for i := range page.Tasks {
+		reply.Tasks = append(reply.Tasks, toTaskRecord(&page.Tasks[i]))
+	}
+	return reply
+}
+
+func toTaskRecord(t *model.T_Task) *model.TaskRecord {
+	record := &model.TaskRecord{
+		TaskId:     t.TaskID,
+		Name:       t.Name,
+		Content:    t.Content,
+		CreatedAt:  toTimestamp(t.CreatedAt),
+		UpdatedAt:  toTimestamp(t.UpdatedAt),
+		DeletedAt:  toTimestamp(t.DeletedAt),
+		Version:    t.Version,
+		Status:     string(t.Status),
+		ParentId:   t.ParentID,
+		DependsOn:  t.DependsOn,
+		Tags:       t.Tags,
+		DueAt:      toTimestamp(t.DueAt),
+		RemindAt:   toTimestamp(t.RemindAt),
+		RemindedAt: toTimestamp(t.RemindedAt),
+		SeriesId:   t.SeriesID,
+		CreatedBy:  t.CreatedBy,
+		Owner:      t.Owner,
+		Assignees:  t.Assignees,
+		ProjectId:  t.ProjectID,
+		Column:     t.Column,
+		Rank:       t.Rank,
+	}
+	for status, at := range t.StatusEnteredAt {
+		if record.StatusEnteredAt == nil {
+			record.StatusEnteredAt = make(map[string]*timestamppb.Timestamp, len(t.StatusEnteredAt))
+		}
+		record.StatusEnteredAt[string(status)] = timestamppb.New(at)
+	}
+	return record
+}
+
+func toSeries(req *model.SeriesRequest) *model.Series {
+	return &model.Series{
+		SeriesID: req.GetSeriesId(),
+		Rule:     req.GetRule(),
+		Start:    fromTimestamp(req.GetStart()),
+		Name:     req.GetName(),
+		Content:  req.GetContent(),
+		ParentID: req.GetParentId(),
+		Tags:     req.GetTags(),
+	}
+}
+
+func toSeriesRecord(s *model.T_Series) *model.SeriesRecord {
+	return &model.SeriesRecord{
+		SeriesId:      s.SeriesID,
+		Rule:          s.Rule,
+		Start:         toTimestamp(s.Start),
+		Name:          s.Name,
+		Content:       s.Content,
+		ParentId:      s.ParentID,
+		Tags:          s.Tags,
+		CreatedAt:     toTimestamp(s.CreatedAt),
+		UpdatedAt:     toTimestamp(s.UpdatedAt),
+		StoppedAt:     toTimestamp(s.StoppedAt),
+		Version:       s.Version,
+		Occurrences:   s.Occurrences,
+		CurrentTaskId: s.CurrentTaskID,
+		CurrentAt:     toTimestamp(s.CurrentAt),
+		CreatedBy:     s.CreatedBy,
+	}
+}
+
+func toTenantRecord(t *model.T_Tenant) *model.TenantRecord {
+	record := &model.TenantRecord{
+		TenantId:  t.TenantID,
+		MaxTasks:  int64(t.MaxTasks),
+		MaxSeries: int64(t.MaxSeries),
+		CreatedAt: toTimestamp(t.CreatedAt),
+	}
+	if t.Usage != nil {
+		record.Usage = &model.TenantRecord_Usage{Tasks: int64(t.Usage.Tasks), Series: int64(t.Usage.Series)}
+	}
+	return record
+}
+
+func toProject(req *model.ProjectRequest) *model.Project {
+	return &model.Project{ProjectID: req.GetProjectId(), Name: req.GetName(), Columns: req.GetColumns()}
+}
+
+func toProjectRecord(p *model.T_Project) *model.ProjectRecord {
+	return &model.ProjectRecord{
+		ProjectId: p.ProjectID,
+		Name:      p.Name,
+		Columns:   p.Columns,
+		CreatedAt: toTimestamp(p.CreatedAt),
+		UpdatedAt: toTimestamp(p.UpdatedAt),
+		CreatedBy: p.CreatedBy,
+		Version:   p.Version,
+	}
+}
+
+func toCommentRecord(c *model.T_Comment) *model.CommentRecord {
+	return &model.CommentRecord{
+		CommentId: c.CommentID,
+		TaskId:    c.TaskID,
+		Body:      c.Body,
+		Mentions:  c.Mentions,
+		Author:    c.Author,
+		CreatedAt: toTimestamp(c.CreatedAt),
+		UpdatedAt: toTimestamp(c.UpdatedAt),
+		Version:   c.Version,
+	}
+}
+
+func toAttachmentRecord(a *model.T_Attachment) *model.AttachmentRecord {
+	return &model.AttachmentRecord{
+		AttachmentId: a.AttachmentID,
+		TaskId:       a.TaskID,
+		Name:         a.Name,
+		Sha256:       a.SHA256,
+		Size:         a.Size,
+		ContentType:  a.ContentType,
+		CreatedBy:    a.CreatedBy,
+		CreatedAt:    toTimestamp(a.CreatedAt),
+	}
+}
+
+func toTaskNode(n *model.T_TaskNode) *model.TaskNode {
+	node := &model.TaskNode{Task: toTaskRecord(&n.T_Task)}
+	for i := range n.Children {
+		node.Children = append(node.Children, toTaskNode(&n.Children[i]))
+	}
+	return node
+}
+
+func toRevisionRecord(r *model.T_Revision) *model.RevisionRecord {
+	record := &model.RevisionRecord{
+		TaskId:     r.TaskID,
+		Revision:   r.Revision,
+		Action:     string(r.Action),
+		Actor:      r.Actor,
+		ChangedAt:  toTimestamp(r.ChangedAt),
+		RevertedTo: r.RevertedTo,
+		Task:       toTaskRecord(&model.T_Task{Task: r.Task}),
+	}
+	for _, c := range r.Changes {
+		record.Changes = append(record.Changes, &model.RevisionRecord_Change{
+			Field: c.Field,
+			From:  toValue(c.From),
+			To:    toValue(c.To),
+		})
+	}
+	return record
+}
+
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// toValue converts a field value decoded from JSON. Absent values stay nil.
+func toValue(v interface{}) *structpb.Value {
+	if v == nil {
+		return nil
+	}
+	value, err := structpb.NewValue(v)
+	if err != nil {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"qantas.com/task/internal/conf"
+	"qantas.com/task/model"
+)
+
+// requestIDMetadata carries the request ID of a call, the gRPC counterpart of X-Request-Id.
+const requestIDMetadata = "x-request-id"
+
+// grpcRequestIDPrefix tells the generated IDs of gRPC calls apart from those of HTTP requests.
+const grpcRequestIDPrefix = "grpc"
+
+type GRPCServer struct {
+	server *grpc.Server
+	conf   *conf.Server
+	log    *log.Helper
+}
+
+func NewGRPCServer(c *conf.Server, logger log.Logger, auth *Authenticator, handler model.TaskServiceServer) *GRPCServer {
+	s := &GRPCServer{conf: c, log: log.NewHelper(logger)}
+	s.server = grpc.NewServer(grpc.ChainUnaryInterceptor(s.requestID, s.recoverer, s.status, auth.Interceptor, s.timeout))
+	model.RegisterTaskServiceServer(s.server, handler)
+	return s
+}
+
+// Run listens on the configured address. It returns at once when no address is set.
+func (s *GRPCServer) Run() error {
+	addr := s.conf.GetGrpc().GetAddr()
+	if addr == "" {
+		s.log.Info("grpc server disabled, no address configured")
+		return nil
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(lis)
+}
+
+// Serve accepts connections on lis, which lets tests serve over an in-memory listener.
+func (s *GRPCServer) Serve(lis net.Listener) error {
+	if err := s.server.Serve(lis); err != nil && !stderrors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// Stop stops accepting calls and waits for in-flight ones to finish. The calls still
+// running when ctx is done are cancelled.
+func (s *GRPCServer) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// requestID gives every call a request ID like middleware.RequestID, taken from the
+// x-request-id metadata when the client sends one.
+func (s *GRPCServer) requestID(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := ""
+	if ids := md.Get(requestIDMetadata); len(ids) > 0 {
+		id = ids[0]
+	}
+	if id == "" {
+		id = fmt.Sprintf("%s-%06d", grpcRequestIDPrefix, middleware.NextRequestID())
+	}
+	return handler(context.WithValue(ctx, middleware.RequestIDKey, id), req)
+}
+
+// timeout bounds every call like the timeout middleware of the HTTP server.
+func (s *GRPCServer) timeout(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if d := s.conf.GetGrpc().GetTimeout().AsDuration(); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	return handler(ctx, req)
+}
+
+// recoverer turns a panic in a handler into an internal error, like middleware.Recoverer.
+func (s *GRPCServer) recoverer(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			s.log.Errorf("panic in %s: %v", info.FullMethod, rec)
+			err = errors.InternalServer("PANIC", fmt.Sprint(rec))
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// grpcCodes maps the HTTP codes of errors which kratos has no gRPC code for.
+var grpcCodes = map[int32]codes.Code{
+	http.StatusPreconditionFailed: codes.FailedPrecondition,
+}
+
+// status keeps the reason of a failed call and gives it the gRPC code matching its HTTP code.
+func (s *GRPCServer) status(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if se := new(errors.Error); err != nil && stderrors.As(err, &se) {
+		if code, ok := grpcCodes[se.Code]; ok {
+			st, _ := status.New(code, se.Message).WithDetails(&errdetails.ErrorInfo{Reason: se.Reason, Metadata: se.Metadata})
+			return nil, st.Err()
+		}
+	}
+	return resp, err
+}
@@ -0,0 +1,102 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/conf"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/internal/server"
+	"qantas.com/task/internal/service"
+	"qantas.com/task/mocks"
+	"qantas.com/task/model"
+)
+
+// dialGRPC serves the task API over an in-memory listener and returns a client for it.
+func dialGRPC(t *testing.T, repo biz.ITaskRepo) model.TaskServiceClient {
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskService := service.NewTaskService(biz.NewTaskUsecase(repo, nil, logger), logger)
+	grpcServer := server.NewGRPCServer(&conf.Server{}, logger, &server.Authenticator{}, server.NewTaskGRPCHandler(taskService, logger))
+
+	lis := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(lis)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close(); lis.Close() })
+
+	return model.NewTaskServiceClient(conn)
+}
+
+func TestGRPCHandler(t *testing.T) {
+	requires := require.New(t)
+	taskRepoMock := mocks.TaskRepo{}
+
+	task := &model.T_Task{Task: model.Task{TaskID: 2, Name: "david", Content: "content text"},
+		T_Internal: model.T_Internal{CreatedAt: &time1, Version: 1}}
+	taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(task, nil)
+	taskRepoMock.On("Get", mock.MatchedBy(func(ctx context.Context) bool {
+		return middleware.GetReqID(ctx) == "req-1"
+	}), uint64(3)).Return(
+		nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST)))
+	taskRepoMock.On("Create", mock.MatchedBy(func(ctx context.Context) bool {
+		return biz.ActorFromContext(ctx) == "alice"
+	}), &model.Task{Name: "david", Content: "content text"}).Return(task, nil)
+	taskRepoMock.On("Update", mock.Anything, mock.Anything, uint64(5)).Return(
+		nil, model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH)))
+	taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return q.PageSize == 1 && q.NamePrefix == "da"
+	})).Return(&biz.TaskPage{Tasks: []model.T_Task{*task}, NextCursor: "next"}, nil)
+
+	client := dialGRPC(t, &taskRepoMock)
+	ctx := context.Background()
+
+	got, err := client.GetTask(ctx, &model.GetTaskRequest{TaskId: 2})
+	requires.NoError(err)
+	requires.Equal(uint64(2), got.TaskId)
+	requires.Equal("david", got.Name)
+	requires.Equal(timestamppb.New(time1).AsTime(), got.CreatedAt.AsTime())
+	requires.Nil(got.UpdatedAt)
+	requires.Equal(uint64(1), got.Version)
+
+	// The actor is taken from the call metadata
+	created, err := client.CreateTask(metadata.AppendToOutgoingContext(ctx, "x-actor", "alice"),
+		&model.CreateTaskRequest{Name: "david", Content: "content text"})
+	requires.NoError(err)
+	requires.Equal(uint64(2), created.TaskId)
+
+	list, err := client.ListTasks(ctx, &model.ListTasksRequest{PageSize: 1, NamePrefix: "da"})
+	requires.NoError(err)
+	requires.Equal(1, len(list.Tasks))
+	requires.Equal("next", list.NextCursor)
+
+	// Errors keep their reason and are mapped to the matching gRPC code
+	// The request ID sent by the client reaches the repo
+	_, err = client.GetTask(metadata.AppendToOutgoingContext(ctx, "x-request-id", "req-1"), &model.GetTaskRequest{TaskId: 3})
+	requires.Equal(codes.NotFound, status.Code(err))
+	requires.True(model.IsTaskNotFound(err))
+
+	_, err = client.GetTask(ctx, &model.GetTaskRequest{})
+	requires.Equal(codes.InvalidArgument, status.Code(err))
+	requires.True(model.IsTaskIdUnspecified(err))
+
+	_, err = client.UpdateTask(ctx, &model.UpdateTaskRequest{TaskId: 2, Name: "john", Version: 5})
+	requires.Equal(codes.FailedPrecondition, status.Code(err))
+	requires.Equal(model.ErrorReason_TASK_VERSION_MISMATCH.String(), errors.Reason(err))
+	requires.Equal(string(encoder.TASK_VERSION_MISMATCH), errors.FromError(err).Message)
+}
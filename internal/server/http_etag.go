@@ -0,0 +1,34 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// etag is the strong entity tag of a task version.
+func etag(version uint64) string {
+	return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+// parseIfMatch reads the version a PUT is conditional on. A missing header or
+// "*" makes the update unconditional, and is returned as version 0. Weak tags
+// and tags this server never issued can not match any version.
+func parseIfMatch(header string) (uint64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(header)
+	if err != nil || !strings.HasPrefix(header, `"`) {
+		return 0, model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH))
+	}
+	version, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil || version == 0 {
+		return 0, model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH))
+	}
+	return version, nil
+}
@@ -3,46 +3,78 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"mime"
 	"net/http"
 	"strconv"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/go-kratos/kratos/v2/log"
+	"qantas.com/task/internal/biz"
 	"qantas.com/task/internal/encoder"
 	"qantas.com/task/internal/service"
 	"qantas.com/task/model"
 )
 
+// actorHeader names the user who makes a request which is not authenticated.
+const actorHeader = "X-Actor"
+
+// tenantHeader names the tenant whose tasks a request works on. Without it the
+// request works on the default tenant.
+const tenantHeader = "X-Tenant"
+
 type TasksHTTPHandler struct {
 	taskSvc *service.TaskService
-	ctx     context.Context
 	log     *log.Helper
 }
 
+// deletedTasks is the response to a cascading delete.
+type deletedTasks struct {
+	Deleted int `json:"deleted"`
+}
+
+// renamedTasks is the response to renaming a tag.
+type renamedTasks struct {
+	Renamed int `json:"renamed"`
+}
+
+// requestContext returns the context the request is served in. It carries the
+// request's deadline, cancellation, request ID, actor and tenant down to the repo.
+func (h TasksHTTPHandler) requestContext(r *http.Request) context.Context {
+	return biz.WithTenant(biz.WithActor(r.Context(), r.Header.Get(actorHeader)), r.Header.Get(tenantHeader))
+}
+
 func (h TasksHTTPHandler) ListTasksHTTPHandler() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		result, err := h.taskSvc.ListTasks(h.ctx)
-
 		w.Header().Set("Content-Type", "application/json")
+
+		query, err := parseTaskQuery(r.URL.Query())
 		if err != nil {
-			json.NewEncoder(w).Encode(encoder.FromError(err))
+			writeError(w, err)
 			return
 		}
 
-		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+		result, err := h.taskSvc.ListTasks(h.requestContext(r), query)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromPage(result.Tasks, result.NextCursor))
 	}
 	return fn
 }
 
 func (h TasksHTTPHandler) CreateTaskHTTPHandler() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		var task model.Task
-		json.NewDecoder(r.Body).Decode(&task)
-		result, err := h.taskSvc.CreateTask(h.ctx, &task)
-
 		w.Header().Set("Content-Type", "application/json")
+
+		var task model.Task
+		if err := decodeBody(w, r, &task); err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.CreateTask(h.requestContext(r), &task)
 		if err != nil {
-			json.NewEncoder(w).Encode(encoder.FromError(err))
+			writeError(w, err)
 			return
 		}
 
@@ -53,16 +85,21 @@ func (h TasksHTTPHandler) CreateTaskHTTPHandler() http.HandlerFunc {
 
 func (h TasksHTTPHandler) GetTaskByIdHTTPHandler() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		id, _ := strconv.ParseUint(chi.URLParam(r, "id"), 0, 64)
+		w.Header().Set("Content-Type", "application/json")
 
-		result, err := h.taskSvc.GetTaskByID(h.ctx, id)
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
 
-		w.Header().Set("Content-Type", "application/json")
+		result, err := h.taskSvc.GetTaskByID(h.requestContext(r), id)
 		if err != nil {
-			json.NewEncoder(w).Encode(encoder.FromError(err))
+			writeError(w, err)
 			return
 		}
 
+		w.Header().Set("ETag", etag(result.Version))
 		json.NewEncoder(w).Encode(encoder.FromResponse(*result))
 	}
 	return fn
@@ -70,16 +107,27 @@ func (h TasksHTTPHandler) GetTaskByIdHTTPHandler() http.HandlerFunc {
 
 func (h TasksHTTPHandler) UpdateTaskByIdHTTPHandler() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		var task model.Task
-		json.NewDecoder(r.Body).Decode(&task)
-		result, err := h.taskSvc.UpdateTaskByID(h.ctx, &task)
-
 		w.Header().Set("Content-Type", "application/json")
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var task model.Task
+		if err = decodeBody(w, r, &task); err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.UpdateTaskByID(h.requestContext(r), &task, version)
 		if err != nil {
-			json.NewEncoder(w).Encode(encoder.FromError(err))
+			writeError(w, err)
 			return
 		}
 
+		w.Header().Set("ETag", etag(result.Version))
+
 		json.NewEncoder(w).Encode(encoder.FromResponse(result))
 	}
 
@@ -88,20 +136,1137 @@ func (h TasksHTTPHandler) UpdateTaskByIdHTTPHandler() http.HandlerFunc {
 
 func (h TasksHTTPHandler) DeleteTaskByIdHTTPHandler() http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		cascade, err := queryBool(r, "cascade")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		// Without cascade a task which has subtasks is not deleted
+		if cascade {
+			n, err := h.taskSvc.DeleteTaskTree(h.requestContext(r), id)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			json.NewEncoder(w).Encode(encoder.FromResponse(deletedTasks{Deleted: n}))
+			return
+		}
+
+		err = h.taskSvc.DeleteTaskByID(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(nil))
+	}
+
+	return fn
+}
+
+func (h TasksHTTPHandler) ListDeletedTasksHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		query, err := parseTaskQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.ListDeletedTasks(h.requestContext(r), query)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromPage(result.Tasks, result.NextCursor))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) RestoreTaskByIdHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
 
-		id, _ := strconv.ParseUint(chi.URLParam(r, "id"), 0, 64)
+		result, err := h.taskSvc.RestoreTaskByID(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
 
-		err := h.taskSvc.DeleteTaskByID(h.ctx, id)
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
 
+func (h TasksHTTPHandler) PurgeTaskByIdHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		err = h.taskSvc.PurgeTaskByID(h.requestContext(r), id)
 		if err != nil {
-			json.NewEncoder(w).Encode(encoder.FromError(err))
+			writeError(w, err)
 			return
 		}
 
 		json.NewEncoder(w).Encode(encoder.FromResponse(nil))
 	}
+	return fn
+}
+
+func (h TasksHTTPHandler) GetTaskHistoryHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.GetTaskHistory(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) GetTaskRevisionHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		rev, err := pathUint(r, "rev")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.GetTaskRevision(h.requestContext(r), id, rev)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) RevertTaskByIdHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		rev, err := pathUint(r, "rev")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.RevertTaskByID(h.requestContext(r), id, rev)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) TransitionTaskHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var transition model.TaskTransition
+		if err = decodeBody(w, r, &transition); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.TransitionTask(h.requestContext(r), id, transition.Status, version)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ReassignTaskHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var assignment model.TaskAssignment
+		if err = decodeBody(w, r, &assignment); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.ReassignTask(h.requestContext(r), id, &assignment, version)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+// MoveTaskHTTPHandler places the task on the board of a project, or takes it off
+// its board when the body names no project.
+func (h TasksHTTPHandler) MoveTaskHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var move model.TaskMove
+		if err = decodeBody(w, r, &move); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.MoveTask(h.requestContext(r), id, &move, version)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListUserTasksHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		user, err := pathString(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		query, err := parseTaskQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.ListUserTasks(h.requestContext(r), user, biz.UserRole(r.URL.Query().Get("role")), query)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromPage(result.Tasks, result.NextCursor))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) GetTaskChildrenHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.GetTaskChildren(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
 
+func (h TasksHTTPHandler) GetTaskTreeHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.GetTaskTree(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListReadyTasksHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		query, err := parseTaskQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.ListReadyTasks(h.requestContext(r), query)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromPage(result.Tasks, result.NextCursor))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListOverdueTasksHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		query, err := parseTaskQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.ListOverdueTasks(h.requestContext(r), query)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromPage(result.Tasks, result.NextCursor))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListDueTasksHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		query, err := parseTaskQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		within, err := parseDurationParam(r.URL.Query(), "within")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.ListDueTasks(h.requestContext(r), query, within)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromPage(result.Tasks, result.NextCursor))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) GetTaskGraphHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := h.taskSvc.GetTaskGraph(h.requestContext(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) AddTaskDependencyHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		dep, err := pathUint(r, "dep")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.AddTaskDependency(h.requestContext(r), id, dep)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) RemoveTaskDependencyHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		dep, err := pathUint(r, "dep")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.RemoveTaskDependency(h.requestContext(r), id, dep)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) SearchTasksHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		query, err := parseSearchQuery(r.URL.Query())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.SearchTasks(h.requestContext(r), query)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListTagsHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := h.taskSvc.ListTags(h.requestContext(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) RenameTagHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		tag, err := pathString(r, "tag")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var rename model.TagRename
+		if err := decodeBody(w, r, &rename); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		n, err := h.taskSvc.RenameTag(h.requestContext(r), tag, rename.To)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(renamedTasks{Renamed: n}))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListSeriesHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := h.taskSvc.ListSeries(h.requestContext(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) CreateSeriesHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var series model.Series
+		if err := decodeBody(w, r, &series); err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.CreateSeries(h.requestContext(r), &series)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) GetSeriesByIdHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.GetSeriesByID(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) UpdateSeriesHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var series model.Series
+		if err = decodeBody(w, r, &series); err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.UpdateSeries(h.requestContext(r), &series, version)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) StopSeriesHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.StopSeries(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+// ExplainPolicyHTTPHandler tells whether the access policy would allow the
+// ?subject= the ?permission= in the ?project=, and why.
+func (h TasksHTTPHandler) ExplainPolicyHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		values := r.URL.Query()
+		result, err := h.taskSvc.ExplainPolicy(h.requestContext(r), values.Get("subject"),
+			biz.Permission(values.Get("permission")), values.Get("project"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListTenantsHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := h.taskSvc.ListTenants(h.requestContext(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) CreateTenantHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var tenant model.Tenant
+		if err := decodeBody(w, r, &tenant); err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.CreateTenant(h.requestContext(r), &tenant)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) DeleteTenantHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathString(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := h.taskSvc.DeleteTenant(h.requestContext(r), id); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(nil))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListProjectsHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		result, err := h.taskSvc.ListProjects(h.requestContext(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) CreateProjectHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var project model.Project
+		if err := decodeBody(w, r, &project); err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.CreateProject(h.requestContext(r), &project)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) GetProjectByIdHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathString(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.GetProject(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) UpdateProjectHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var project model.Project
+		if err = decodeBody(w, r, &project); err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.UpdateProject(h.requestContext(r), &project, version)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) DeleteProjectHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathString(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := h.taskSvc.DeleteProject(h.requestContext(r), id); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(nil))
+	}
+	return fn
+}
+
+// GetBoardHTTPHandler returns the live tasks of the project grouped by column.
+func (h TasksHTTPHandler) GetBoardHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathString(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.GetBoard(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListCommentsHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.ListComments(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) CreateCommentHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var comment model.Comment
+		if err = decodeBody(w, r, &comment); err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.CreateComment(h.requestContext(r), id, &comment)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) GetCommentHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		commentID, err := pathUint(r, "comment")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.GetComment(h.requestContext(r), id, commentID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) UpdateCommentHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		commentID, err := pathUint(r, "comment")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var comment model.Comment
+		if err = decodeBody(w, r, &comment); err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.UpdateComment(h.requestContext(r), id, commentID, &comment, version)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(result.Version))
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) DeleteCommentHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		commentID, err := pathUint(r, "comment")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		version, err := parseIfMatch(r.Header.Get("If-Match"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := h.taskSvc.DeleteComment(h.requestContext(r), id, commentID, version); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(nil))
+	}
+	return fn
+}
+
+// ListActivityHTTPHandler returns the revisions of the task and the comments on it, oldest first.
+func (h TasksHTTPHandler) ListActivityHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.ListActivity(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) ListAttachmentsHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		result, err := h.taskSvc.ListAttachments(h.requestContext(r), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+// CreateAttachmentHTTPHandler attaches the file part of a multipart/form-data body
+// to the task.
+func (h TasksHTTPHandler) CreateAttachmentHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		name, content, err := multipartFile(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		result, err := h.taskSvc.CreateAttachment(h.requestContext(r), id, name, content)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(result))
+	}
+	return fn
+}
+
+// DownloadAttachmentHTTPHandler serves the content of the attachment, honouring
+// Range and conditional requests. The ETag is the hash of the content.
+func (h TasksHTTPHandler) DownloadAttachmentHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		attachmentID, err := pathUint(r, "attachment")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		attachment, content, err := h.taskSvc.OpenAttachment(h.requestContext(r), id, attachmentID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		defer content.Close()
+
+		disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})
+		if disposition == "" {
+			disposition = "attachment"
+		}
+		w.Header().Set("Content-Type", attachment.ContentType)
+		w.Header().Set("Content-Disposition", disposition)
+		// Browsers must not take an uploaded file for a page of this server
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("ETag", strconv.Quote(attachment.SHA256))
+		http.ServeContent(w, r, attachment.Name, *attachment.CreatedAt, content)
+	}
+	return fn
+}
+
+func (h TasksHTTPHandler) DeleteAttachmentHTTPHandler() http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := pathUint(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		attachmentID, err := pathUint(r, "attachment")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := h.taskSvc.DeleteAttachment(h.requestContext(r), id, attachmentID); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(encoder.FromResponse(nil))
+	}
 	return fn
 }
 
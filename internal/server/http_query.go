@@ -0,0 +1,108 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// parseTaskQuery reads the listing parameters of GET /tasks:
+//
+//	pageSize      number of tasks per page, 1 to 1000, default 100
+//	cursor        nextCursor returned with the previous page
+//	sortBy        taskID (default), createdAt, updatedAt, name or dueAt
+//	order         asc (default) or desc
+//	namePrefix    only tasks whose name starts with the prefix
+//	createdAfter  only tasks created after the RFC 3339 timestamp
+//	createdBefore only tasks created before the RFC 3339 timestamp
+//	tag           only tasks carrying the tag, may be repeated
+//	tagMatch      all (default) of the tags or any of them
+//	filter        only tasks passing the filter expression, see biz.Filter
+func parseTaskQuery(values url.Values) (*biz.TaskQuery, error) {
+	q := &biz.TaskQuery{
+		Cursor:     values.Get("cursor"),
+		SortBy:     biz.TaskSortField(values.Get("sortBy")),
+		NamePrefix: values.Get("namePrefix"),
+		Tags:       values["tag"],
+		TagMatch:   biz.TagMatch(values.Get("tagMatch")),
+		Filter:     values.Get("filter"),
+	}
+
+	if v := values.Get("pageSize"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, invalidParam("pageSize", v)
+		}
+		q.PageSize = size
+	}
+
+	switch v := values.Get("order"); v {
+	case "", "asc":
+	case "desc":
+		q.Descending = true
+	default:
+		return nil, invalidParam("order", v)
+	}
+
+	var err error
+	if q.CreatedAfter, err = parseTimeParam(values, "createdAfter"); err != nil {
+		return nil, err
+	}
+	if q.CreatedBefore, err = parseTimeParam(values, "createdBefore"); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// parseSearchQuery reads the parameters of GET /tasks/search:
+//
+//	q     the search, see biz.SearchQuery
+//	limit number of results, 1 to 100, default 20
+func parseSearchQuery(values url.Values) (*biz.SearchQuery, error) {
+	q := &biz.SearchQuery{Query: values.Get("q")}
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, invalidParam("limit", v)
+		}
+		q.Limit = limit
+	}
+
+	return q, nil
+}
+
+func parseTimeParam(values url.Values, name string) (*time.Time, error) {
+	v := values.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return nil, invalidParam(name, v)
+	}
+	return &t, nil
+}
+
+// parseDurationParam reads a duration such as 90m or 24h. It is 0 when absent.
+func parseDurationParam(values url.Values, name string) (time.Duration, error) {
+	v := values.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, invalidParam(name, v)
+	}
+	return d, nil
+}
+
+func invalidParam(name, value string) error {
+	return model.ErrorTaskQueryInvalid("%s: %s", encoder.TASK_QUERY_INVALID, fmt.Sprintf("invalid %s %q", name, value))
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"qantas.com/task/internal/encoder"
+	"qantas.com/task/model"
+)
+
+// maxBodyBytes limits the size of a request body.
+const maxBodyBytes = 1 << 20
+
+// decodeBody reads the JSON body of the request into v. Bodies which are too large,
+// malformed, have fields v does not know or hold more than one value are rejected.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	if err == nil && dec.Decode(&struct{}{}) != io.EOF {
+		err = errors.New("unexpected data after the JSON value")
+	}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &tooLarge):
+		return model.ErrorRequestTooLarge("%s: limit is %d bytes", encoder.REQUEST_BODY_TOO_LARGE, tooLarge.Limit)
+	case err == io.EOF:
+		return model.ErrorBadRequest("%s: %s", encoder.REQUEST_BODY_INVALID, "body is empty")
+	default:
+		return model.ErrorBadRequest("%s: %s", encoder.REQUEST_BODY_INVALID, err.Error())
+	}
+}
+
+// multipartFile returns the name and content of the part named file of a
+// multipart/form-data body, so that the file is streamed rather than buffered.
+// The parts before it are skipped, and the ones after it are not read.
+func multipartFile(r *http.Request) (string, io.Reader, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return "", nil, model.ErrorBadRequest("%s: %s", encoder.REQUEST_BODY_INVALID, err.Error())
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return "", nil, model.ErrorBadRequest("%s: %s", encoder.REQUEST_BODY_INVALID, "no file part")
+		}
+		if err != nil {
+			return "", nil, model.ErrorBadRequest("%s: %s", encoder.REQUEST_BODY_INVALID, err.Error())
+		}
+		if part.FormName() == "file" {
+			return part.FileName(), part, nil
+		}
+	}
+}
+
+// pathUint reads a numeric path parameter such as {id}.
+func pathUint(r *http.Request, name string) (uint64, error) {
+	v := chi.URLParam(r, name)
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, model.ErrorBadRequest("%s: %s", encoder.REQUEST_PARAM_INVALID, fmt.Sprintf("invalid %s %q", name, v))
+	}
+	return n, nil
+}
+
+// pathString reads a path parameter such as {tag}, which may be escaped.
+func pathString(r *http.Request, name string) (string, error) {
+	v := chi.URLParam(r, name)
+	s, err := url.PathUnescape(v)
+	if err != nil {
+		return "", model.ErrorBadRequest("%s: %s", encoder.REQUEST_PARAM_INVALID, fmt.Sprintf("invalid %s %q", name, v))
+	}
+	return s, nil
+}
+
+// queryBool reads an optional boolean query parameter such as ?cascade=true.
+func queryBool(r *http.Request, name string) (bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, model.ErrorBadRequest("%s: %s", encoder.REQUEST_PARAM_INVALID, fmt.Sprintf("invalid %s %q", name, v))
+	}
+	return b, nil
+}
+
+// writeError writes the error envelope with the HTTP status matching its code.
+func writeError(w http.ResponseWriter, err error) {
+	e := encoder.FromError(err)
+	w.WriteHeader(e.Code)
+	json.NewEncoder(w).Encode(e)
+}
@@ -2,7 +2,10 @@ package server
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
@@ -11,21 +14,71 @@ import (
 	"qantas.com/task/internal/service"
 )
 
+// defaultTransferTimeout bounds attachment uploads and downloads when the configuration does not.
+const defaultTransferTimeout = 10 * time.Minute
+
 type ITaskHTTPHandler interface {
 	ListTasksHTTPHandler() http.HandlerFunc
 	CreateTaskHTTPHandler() http.HandlerFunc
 	GetTaskByIdHTTPHandler() http.HandlerFunc
 	UpdateTaskByIdHTTPHandler() http.HandlerFunc
 	DeleteTaskByIdHTTPHandler() http.HandlerFunc
+	ListDeletedTasksHTTPHandler() http.HandlerFunc
+	RestoreTaskByIdHTTPHandler() http.HandlerFunc
+	PurgeTaskByIdHTTPHandler() http.HandlerFunc
+	GetTaskHistoryHTTPHandler() http.HandlerFunc
+	GetTaskRevisionHTTPHandler() http.HandlerFunc
+	RevertTaskByIdHTTPHandler() http.HandlerFunc
+	TransitionTaskHTTPHandler() http.HandlerFunc
+	ReassignTaskHTTPHandler() http.HandlerFunc
+	GetTaskChildrenHTTPHandler() http.HandlerFunc
+	GetTaskTreeHTTPHandler() http.HandlerFunc
+	ListReadyTasksHTTPHandler() http.HandlerFunc
+	ListOverdueTasksHTTPHandler() http.HandlerFunc
+	ListDueTasksHTTPHandler() http.HandlerFunc
+	GetTaskGraphHTTPHandler() http.HandlerFunc
+	AddTaskDependencyHTTPHandler() http.HandlerFunc
+	RemoveTaskDependencyHTTPHandler() http.HandlerFunc
+	SearchTasksHTTPHandler() http.HandlerFunc
+	ListTagsHTTPHandler() http.HandlerFunc
+	RenameTagHTTPHandler() http.HandlerFunc
+	ListUserTasksHTTPHandler() http.HandlerFunc
+	ListSeriesHTTPHandler() http.HandlerFunc
+	CreateSeriesHTTPHandler() http.HandlerFunc
+	GetSeriesByIdHTTPHandler() http.HandlerFunc
+	UpdateSeriesHTTPHandler() http.HandlerFunc
+	StopSeriesHTTPHandler() http.HandlerFunc
+	ExplainPolicyHTTPHandler() http.HandlerFunc
+	ListTenantsHTTPHandler() http.HandlerFunc
+	CreateTenantHTTPHandler() http.HandlerFunc
+	DeleteTenantHTTPHandler() http.HandlerFunc
+	ListProjectsHTTPHandler() http.HandlerFunc
+	CreateProjectHTTPHandler() http.HandlerFunc
+	GetProjectByIdHTTPHandler() http.HandlerFunc
+	UpdateProjectHTTPHandler() http.HandlerFunc
+	DeleteProjectHTTPHandler() http.HandlerFunc
+	GetBoardHTTPHandler() http.HandlerFunc
+	MoveTaskHTTPHandler() http.HandlerFunc
+	ListCommentsHTTPHandler() http.HandlerFunc
+	CreateCommentHTTPHandler() http.HandlerFunc
+	GetCommentHTTPHandler() http.HandlerFunc
+	UpdateCommentHTTPHandler() http.HandlerFunc
+	DeleteCommentHTTPHandler() http.HandlerFunc
+	ListActivityHTTPHandler() http.HandlerFunc
+	ListAttachmentsHTTPHandler() http.HandlerFunc
+	CreateAttachmentHTTPHandler() http.HandlerFunc
+	DownloadAttachmentHTTPHandler() http.HandlerFunc
+	DeleteAttachmentHTTPHandler() http.HandlerFunc
 }
 
 type HTTPServer struct {
+	server          *http.Server
 	router          *chi.Mux
 	conf            *conf.Server
 	taskHttpHandler ITaskHTTPHandler
 }
 
-func NewHTTPServer(c *conf.Server, logger log.Logger, httpHandler ITaskHTTPHandler) IServer {
+func NewHTTPServer(c *conf.Server, logger log.Logger, auth *Authenticator, httpHandler ITaskHTTPHandler) *HTTPServer {
 
 	r := chi.NewRouter()
 
@@ -34,32 +87,115 @@ func NewHTTPServer(c *conf.Server, logger log.Logger, httpHandler ITaskHTTPHandl
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(c.Http.Timeout.AsDuration()))
+	r.Use(auth.Middleware)
+
+	// Attachments are sent and fetched at the pace of the client, so the transfer
+	// timeout bounds them in place of the timeout of every other request
+	timeout := middleware.Timeout(c.Http.Timeout.AsDuration())
+	transfer := middleware.Timeout(transferTimeout(c.Http))
 
-	r.Get("/tasks", httpHandler.ListTasksHTTPHandler()) // GET /tasks - Get a list of tasks.
+	r.Group(func(r chi.Router) {
+		r.Use(timeout)
+		r.Get("/tasks", httpHandler.ListTasksHTTPHandler())                // GET    /tasks               - Get a list of tasks.
+		r.Get("/tasks/trash", httpHandler.ListDeletedTasksHTTPHandler())   // GET    /tasks/trash         - Get a list of deleted tasks.
+		r.Get("/tasks/ready", httpHandler.ListReadyTasksHTTPHandler())     // GET    /tasks/ready         - Get a list of tasks whose dependencies are all done.
+		r.Get("/tasks/overdue", httpHandler.ListOverdueTasksHTTPHandler()) // GET    /tasks/overdue       - Get a list of unfinished tasks past their due date.
+		r.Get("/tasks/due", httpHandler.ListDueTasksHTTPHandler())         // GET    /tasks/due           - Get a list of unfinished tasks due within ?within=24h.
+		r.Get("/tasks/graph", httpHandler.GetTaskGraphHTTPHandler())       // GET    /tasks/graph         - Get every task in dependency order.
+		r.Get("/tasks/search", httpHandler.SearchTasksHTTPHandler())       // GET    /tasks/search        - Search the names and contents of the tasks.
+		r.Get("/tags", httpHandler.ListTagsHTTPHandler())                  // GET    /tags                - Get every tag with the number of tasks carrying it.
+		r.Post("/tags/{tag}/rename", httpHandler.RenameTagHTTPHandler())   // POST   /tags/{tag}/rename   - Rename a tag on every task, merging it into an existing one.
+		r.Get("/users/{id}/tasks", httpHandler.ListUserTasksHTTPHandler()) // GET    /users/{id}/tasks    - Get a list of the tasks a user owns or is assigned, or only those of ?role=.
+		r.Get("/series", httpHandler.ListSeriesHTTPHandler())              // GET    /series              - Get every recurring task series.
+		r.Post("/series", httpHandler.CreateSeriesHTTPHandler())           // POST   /series              - Create a series along with its first occurrence.
+		r.Put("/series", httpHandler.UpdateSeriesHTTPHandler())            // PUT    /series              - Update a series and its unfinished occurrences.
+		r.Get("/series/{id}", httpHandler.GetSeriesByIdHTTPHandler())      // GET    /series/{id}         - Get a series by id.
+		r.Post("/series/{id}/stop", httpHandler.StopSeriesHTTPHandler())   // POST   /series/{id}/stop    - Stop creating occurrences of a series.
+		r.Get("/policy/explain", httpHandler.ExplainPolicyHTTPHandler())   // GET    /policy/explain      - Tell whether the access policy allows a ?subject= a ?permission=, and why.
+		r.Get("/tenants", httpHandler.ListTenantsHTTPHandler())            // GET    /tenants             - Get every tenant with what it stores.
+		r.Post("/tenants", httpHandler.CreateTenantHTTPHandler())          // POST   /tenants             - Create a tenant with its quotas.
+		r.Delete("/tenants/{id}", httpHandler.DeleteTenantHTTPHandler())   // DELETE /tenants/{id}        - Delete a tenant with all its tasks and series.
+		r.Get("/projects", httpHandler.ListProjectsHTTPHandler())          // GET    /projects            - Get every project.
+		r.Post("/projects", httpHandler.CreateProjectHTTPHandler())        // POST   /projects            - Create a project with its columns.
+		r.Put("/projects", httpHandler.UpdateProjectHTTPHandler())         // PUT    /projects            - Rename a project or change its columns.
+		r.Get("/projects/{id}", httpHandler.GetProjectByIdHTTPHandler())   // GET    /projects/{id}       - Get a project by id.
+		r.Delete("/projects/{id}", httpHandler.DeleteProjectHTTPHandler()) // DELETE /projects/{id}       - Delete a project which holds no live tasks.
+		r.Get("/projects/{id}/board", httpHandler.GetBoardHTTPHandler())   // GET    /projects/{id}/board - Get the live tasks of a project grouped by column.
+	})
 	r.Route("/task", func(r chi.Router) {
-		r.Get("/{id:[0-9]+}", httpHandler.GetTaskByIdHTTPHandler())       // GET      /task/{id} - Get a task by id.
-		r.Post("/", httpHandler.CreateTaskHTTPHandler())                  // POST     /task      - Create a new task.
-		r.Put("/", httpHandler.UpdateTaskByIdHTTPHandler())               // PUT      /task      - Update a new task by id.
-		r.Delete("/{id:[0-9]+}", httpHandler.DeleteTaskByIdHTTPHandler()) // DELETE   /task/{id} - Delete a task by id.
+		r.Group(func(r chi.Router) {
+			r.Use(transfer)
+			r.Post("/{id}/attachments", httpHandler.CreateAttachmentHTTPHandler())               // POST     /task/{id}/attachments              - Attach a file to a task, as multipart/form-data.
+			r.Get("/{id}/attachments/{attachment}", httpHandler.DownloadAttachmentHTTPHandler()) // GET      /task/{id}/attachments/{attachment} - Download an attachment. Range requests are supported.
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(timeout)
+			r.Get("/{id}", httpHandler.GetTaskByIdHTTPHandler())                                  // GET      /task/{id}                          - Get a task by id.
+			r.Post("/", httpHandler.CreateTaskHTTPHandler())                                      // POST     /task                               - Create a new task.
+			r.Put("/", httpHandler.UpdateTaskByIdHTTPHandler())                                   // PUT      /task                               - Update a new task by id.
+			r.Delete("/{id}", httpHandler.DeleteTaskByIdHTTPHandler())                            // DELETE   /task/{id}                          - Delete a task by id, with ?cascade=true its subtasks too.
+			r.Post("/{id}/restore", httpHandler.RestoreTaskByIdHTTPHandler())                     // POST     /task/{id}/restore                  - Restore a deleted task by id.
+			r.Delete("/{id}/purge", httpHandler.PurgeTaskByIdHTTPHandler())                       // DELETE   /task/{id}/purge                    - Permanently remove a deleted task by id.
+			r.Get("/{id}/history", httpHandler.GetTaskHistoryHTTPHandler())                       // GET      /task/{id}/history                  - Get every revision of a task.
+			r.Get("/{id}/history/{rev}", httpHandler.GetTaskRevisionHTTPHandler())                // GET      /task/{id}/history/{rev}            - Get a revision of a task.
+			r.Post("/{id}/history/{rev}/revert", httpHandler.RevertTaskByIdHTTPHandler())         // POST     /task/{id}/history/{rev}/revert     - Revert a task to a revision.
+			r.Post("/{id}/transition", httpHandler.TransitionTaskHTTPHandler())                   // POST     /task/{id}/transition               - Move a task to another status.
+			r.Post("/{id}/assign", httpHandler.ReassignTaskHTTPHandler())                         // POST     /task/{id}/assign                   - Replace the owner and the assignees of a task.
+			r.Post("/{id}/move", httpHandler.MoveTaskHTTPHandler())                               // POST     /task/{id}/move                     - Place a task on the board of a project, or take it off.
+			r.Get("/{id}/comments", httpHandler.ListCommentsHTTPHandler())                        // GET      /task/{id}/comments                 - Get the comments on a task, oldest first.
+			r.Post("/{id}/comments", httpHandler.CreateCommentHTTPHandler())                      // POST     /task/{id}/comments                 - Comment on a task.
+			r.Get("/{id}/comments/{comment}", httpHandler.GetCommentHTTPHandler())                // GET      /task/{id}/comments/{comment}       - Get a comment on a task.
+			r.Put("/{id}/comments/{comment}", httpHandler.UpdateCommentHTTPHandler())             // PUT      /task/{id}/comments/{comment}       - Edit a comment.
+			r.Delete("/{id}/comments/{comment}", httpHandler.DeleteCommentHTTPHandler())          // DELETE   /task/{id}/comments/{comment}       - Delete a comment.
+			r.Get("/{id}/activity", httpHandler.ListActivityHTTPHandler())                        // GET      /task/{id}/activity                 - Get the revisions of a task and the comments on it, oldest first.
+			r.Get("/{id}/attachments", httpHandler.ListAttachmentsHTTPHandler())                  // GET      /task/{id}/attachments              - Get the attachments of a task, oldest first.
+			r.Delete("/{id}/attachments/{attachment}", httpHandler.DeleteAttachmentHTTPHandler()) // DELETE   /task/{id}/attachments/{attachment} - Delete an attachment.
+			r.Get("/{id}/children", httpHandler.GetTaskChildrenHTTPHandler())                     // GET      /task/{id}/children                 - Get the subtasks of a task.
+			r.Get("/{id}/tree", httpHandler.GetTaskTreeHTTPHandler())                             // GET      /task/{id}/tree                     - Get a task with all its subtasks, nested.
+			r.Put("/{id}/dependencies/{dep}", httpHandler.AddTaskDependencyHTTPHandler())         // PUT      /task/{id}/dependencies/{dep}       - Make a task depend on another one.
+			r.Delete("/{id}/dependencies/{dep}", httpHandler.RemoveTaskDependencyHTTPHandler())   // DELETE   /task/{id}/dependencies/{dep}       - Remove a dependency of a task.
+		})
 	})
 
-	return &HTTPServer{router: r, conf: c, taskHttpHandler: httpHandler}
+	return &HTTPServer{server: &http.Server{Addr: c.Http.Addr, Handler: r}, router: r, conf: c, taskHttpHandler: httpHandler}
 }
 
-func NewTaskHTTPHandler(taskSvc *service.TaskService, logger log.Logger, ctx context.Context) ITaskHTTPHandler {
-	return &TasksHTTPHandler{taskSvc: taskSvc, ctx: ctx, log: log.NewHelper(logger)}
+// transferTimeout returns how long an attachment upload or download may take.
+func transferTimeout(c *conf.Server_HTTP) time.Duration {
+	if d := c.GetTransferTimeout(); d != nil {
+		return d.AsDuration()
+	}
+	return defaultTransferTimeout
+}
+
+func NewTaskHTTPHandler(taskSvc *service.TaskService, logger log.Logger) ITaskHTTPHandler {
+	return &TasksHTTPHandler{taskSvc: taskSvc, log: log.NewHelper(logger)}
 }
 
 func (s *HTTPServer) Run() error {
-	err := http.ListenAndServe(s.conf.Http.Addr, s.router)
+	err := s.server.ListenAndServe()
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
 
-	if err != nil {
+// Serve accepts connections on lis instead of the configured address.
+func (s *HTTPServer) Serve(lis net.Listener) error {
+	err := s.server.Serve(lis)
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 	return nil
 }
 
+// Stop closes the listener and waits for in-flight requests to finish, until ctx is done.
+func (s *HTTPServer) Stop(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
 func (s *HTTPServer) GetRouter() *chi.Mux {
 	return s.router
 }
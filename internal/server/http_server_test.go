@@ -4,17 +4,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/conf"
 	"qantas.com/task/internal/encoder"
 	"qantas.com/task/internal/server"
 	"qantas.com/task/internal/service"
@@ -35,7 +46,7 @@ func TestHTTPHandler(t *testing.T) {
 		callMethod      string
 		mockInputTask   *model.Task
 		mockReturnTask  *model.T_Task
-		mockReturnTasks []model.T_Task
+		mockReturnPage  *biz.TaskPage
 		mockReturnError *errors.Error
 		url             string
 		httpMethod      string
@@ -135,15 +146,34 @@ func TestHTTPHandler(t *testing.T) {
 			description: "list tasks success",
 			mockMethod:  "List",
 			url:         "/tasks",
-			mockReturnTasks: []model.T_Task{
+			mockReturnPage: &biz.TaskPage{Tasks: []model.T_Task{
 				{Task: model.Task{TaskID: 1, Name: "user1", Content: "content1"},
 					T_Internal: model.T_Internal{CreatedAt: &time1}},
 				{Task: model.Task{TaskID: 2, Name: "user2", Content: "content2"},
-					T_Internal: model.T_Internal{CreatedAt: &time2}}},
+					T_Internal: model.T_Internal{CreatedAt: &time2}}}},
 			callMethod:     "ListTasksHTTPHandler",
 			httpMethod:     "GET",
 			expectedOutput: "{\"code\":200,\"data\":[{\"taskID\":1,\"name\":\"user1\",\"content\":\"content1\",\"createdAt\":\"2014-11-12T11:45:26.371Z\"},{\"taskID\":2,\"name\":\"user2\",\"content\":\"content2\",\"createdAt\":\"2015-11-12T11:45:26.371Z\"}]}\n",
 		},
+		{
+			description: "list tasks success - with next cursor",
+			mockMethod:  "List",
+			url:         "/tasks?pageSize=1&sortBy=createdAt&order=desc",
+			mockReturnPage: &biz.TaskPage{Tasks: []model.T_Task{
+				{Task: model.Task{TaskID: 2, Name: "user2", Content: "content2"},
+					T_Internal: model.T_Internal{CreatedAt: &time2}}}, NextCursor: "next"},
+			callMethod:     "ListTasksHTTPHandler",
+			httpMethod:     "GET",
+			expectedOutput: "{\"code\":200,\"data\":[{\"taskID\":2,\"name\":\"user2\",\"content\":\"content2\",\"createdAt\":\"2015-11-12T11:45:26.371Z\"}],\"nextCursor\":\"next\"}\n",
+		},
+		{
+			description:    "list tasks failed - invalid query parameter",
+			mockMethod:     "List",
+			url:            "/tasks?createdAfter=yesterday",
+			callMethod:     "ListTasksHTTPHandler",
+			httpMethod:     "GET",
+			expectedOutput: "{\"code\":400,\"errors\":{\"TASK_QUERY_INVALID\":\"task query is invalid: invalid createdAfter \\\"yesterday\\\"\"}}\n",
+		},
 		{
 			description:     "list tasks failed - database timeout",
 			mockMethod:      "List",
@@ -153,67 +183,1106 @@ func TestHTTPHandler(t *testing.T) {
 			httpMethod:      "GET",
 			expectedOutput:  "{\"code\":500,\"errors\":{\"TASK_DB_TIMEOUT\":\"task database timeout\"}}\n",
 		},
+		{
+			description: "list deleted tasks success",
+			mockMethod:  "List",
+			url:         "/tasks/trash",
+			mockReturnPage: &biz.TaskPage{Tasks: []model.T_Task{
+				{Task: model.Task{TaskID: 1, Name: "user1", Content: "content1"},
+					T_Internal: model.T_Internal{CreatedAt: &time1, DeletedAt: &time2}}}},
+			callMethod:     "ListDeletedTasksHTTPHandler",
+			httpMethod:     "GET",
+			expectedOutput: "{\"code\":200,\"data\":[{\"taskID\":1,\"name\":\"user1\",\"content\":\"content1\",\"createdAt\":\"2014-11-12T11:45:26.371Z\",\"deletedAt\":\"2015-11-12T11:45:26.371Z\"}]}\n",
+		},
+		{
+			description: "restore task by id success",
+			mockMethod:  "Restore",
+			url:         "/task/2/restore",
+			mockReturnTask: &model.T_Task{Task: model.Task{TaskID: 2, Name: "david", Content: "content text"},
+				T_Internal: model.T_Internal{CreatedAt: &time1, UpdatedAt: &time2}},
+			callMethod:     "RestoreTaskByIdHTTPHandler",
+			httpMethod:     "POST",
+			expectedOutput: "{\"code\":200,\"data\":{\"taskID\":2,\"name\":\"david\",\"content\":\"content text\",\"createdAt\":\"2014-11-12T11:45:26.371Z\",\"updatedAt\":\"2015-11-12T11:45:26.371Z\"}}\n",
+		},
+		{
+			description:     "restore task by id failed - task has not been deleted",
+			mockMethod:      "Restore",
+			url:             "/task/2/restore",
+			mockReturnError: model.ErrorTaskNotDeleted(string(encoder.TASK_NOT_DELETED)),
+			callMethod:      "RestoreTaskByIdHTTPHandler",
+			httpMethod:      "POST",
+			expectedOutput:  "{\"code\":409,\"errors\":{\"TASK_NOT_DELETED\":\"task has not been deleted\"}}\n",
+		},
+		{
+			description:    "purge task by id success",
+			mockMethod:     "Purge",
+			url:            "/task/2/purge",
+			callMethod:     "PurgeTaskByIdHTTPHandler",
+			httpMethod:     "DELETE",
+			expectedOutput: "{\"code\":200}\n",
+		},
+		{
+			description:     "purge task by id failed - task not found",
+			mockMethod:      "Purge",
+			url:             "/task/2/purge",
+			mockReturnError: model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST)),
+			callMethod:      "PurgeTaskByIdHTTPHandler",
+			httpMethod:      "DELETE",
+			expectedOutput:  "{\"code\":404,\"errors\":{\"TASK_NOT_FOUND\":\"task does not exist\"}}\n",
+		},
 	} {
 		requires := require.New(t)
-		context := context.Background()
 		logger := log.With(log.NewStdLogger(os.Stdout))
 		taskRepoMock := mocks.TaskRepo{}
 
+		// Update also takes the expected version
+		mockArgs := []interface{}{mock.Anything, mock.Anything}
+		if scenario.mockMethod == "Update" {
+			mockArgs = append(mockArgs, mock.Anything)
+		}
+
 		// Set up database method mock
 		switch scenario.callMethod {
-		case "CreateTaskHTTPHandler", "UpdateTaskByIdHTTPHandler", "GetTaskByIdHTTPHandler":
+		case "CreateTaskHTTPHandler", "UpdateTaskByIdHTTPHandler", "GetTaskByIdHTTPHandler", "RestoreTaskByIdHTTPHandler":
 			if scenario.mockReturnError != nil {
-				taskRepoMock.On(scenario.mockMethod, mock.Anything, mock.Anything).Return(nil, scenario.mockReturnError)
+				taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(nil, scenario.mockReturnError)
 			} else {
-				taskRepoMock.On(scenario.mockMethod, mock.Anything, mock.Anything).Return(scenario.mockReturnTask, nil)
+				taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(scenario.mockReturnTask, nil)
 			}
-		case "DeleteTaskByIdHTTPHandler":
+		case "DeleteTaskByIdHTTPHandler", "PurgeTaskByIdHTTPHandler":
 			if scenario.mockReturnError != nil {
-				taskRepoMock.On(scenario.mockMethod, mock.Anything, mock.Anything).Return(scenario.mockReturnError)
+				taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(scenario.mockReturnError)
 			} else {
-				taskRepoMock.On(scenario.mockMethod, mock.Anything, mock.Anything).Return(nil)
+				taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(nil)
 			}
-		case "ListTasksHTTPHandler":
+		case "ListTasksHTTPHandler", "ListDeletedTasksHTTPHandler":
 			if scenario.mockReturnError != nil {
-				taskRepoMock.On(scenario.mockMethod, mock.Anything).Return(nil, scenario.mockReturnError)
+				taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(nil, scenario.mockReturnError)
 			} else {
-				taskRepoMock.On(scenario.mockMethod, mock.Anything).Return(scenario.mockReturnTasks, nil)
+				taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(scenario.mockReturnPage, nil)
 			}
 		}
 
-		taskUseCase := biz.NewTaskUsecase(&taskRepoMock, logger)
+		taskUseCase := biz.NewTaskUsecase(&taskRepoMock, nil, logger)
 		taskService := service.NewTaskService(taskUseCase, logger)
 
 		// Set up router
 		r := chi.NewRouter()
 
-		httpHandler := server.NewTaskHTTPHandler(taskService, logger, context)
+		httpHandler := server.NewTaskHTTPHandler(taskService, logger)
 
-		r.Get("/tasks", httpHandler.ListTasksHTTPHandler()) // GET /tasks - Get a list of tasks.
+		r.Get("/tasks", httpHandler.ListTasksHTTPHandler())              // GET /tasks       - Get a list of tasks.
+		r.Get("/tasks/trash", httpHandler.ListDeletedTasksHTTPHandler()) // GET /tasks/trash - Get a list of deleted tasks.
 		r.Route("/task", func(r chi.Router) {
-			r.Get("/{id:[0-9]+}", httpHandler.GetTaskByIdHTTPHandler())       // GET      /task/{id} - Get a task by id.
-			r.Post("/", httpHandler.CreateTaskHTTPHandler())                  // POST     /task      - Create a new task.
-			r.Put("/", httpHandler.UpdateTaskByIdHTTPHandler())               // PUT      /task      - Update a new task by id.
-			r.Delete("/{id:[0-9]+}", httpHandler.DeleteTaskByIdHTTPHandler()) // DELETE   /task/{id} - Delete a task by id.
+			r.Get("/{id}", httpHandler.GetTaskByIdHTTPHandler())                          // GET      /task/{id}                      - Get a task by id.
+			r.Post("/", httpHandler.CreateTaskHTTPHandler())                              // POST     /task                           - Create a new task.
+			r.Put("/", httpHandler.UpdateTaskByIdHTTPHandler())                           // PUT      /task                           - Update a new task by id.
+			r.Delete("/{id}", httpHandler.DeleteTaskByIdHTTPHandler())                    // DELETE   /task/{id}                      - Delete a task by id.
+			r.Post("/{id}/restore", httpHandler.RestoreTaskByIdHTTPHandler())             // POST     /task/{id}/restore              - Restore a deleted task by id.
+			r.Delete("/{id}/purge", httpHandler.PurgeTaskByIdHTTPHandler())               // DELETE   /task/{id}/purge                - Permanently remove a deleted task by id.
+			r.Get("/{id}/history", httpHandler.GetTaskHistoryHTTPHandler())               // GET      /task/{id}/history              - Get every revision of a task.
+			r.Get("/{id}/history/{rev}", httpHandler.GetTaskRevisionHTTPHandler())        // GET      /task/{id}/history/{rev}        - Get a revision of a task.
+			r.Post("/{id}/history/{rev}/revert", httpHandler.RevertTaskByIdHTTPHandler()) // POST     /task/{id}/history/{rev}/revert - Revert a task to a revision.
 		})
 		ts := httptest.NewServer(r)
 		defer ts.Close()
 
 		// Send request
+		var res *http.Response
 		var resp string
 
 		switch scenario.callMethod {
-		case "GetTaskByIdHTTPHandler", "DeleteTaskByIdHTTPHandler", "ListTasksHTTPHandler":
-			_, resp = utils.TestRequest(t, ts, scenario.httpMethod, scenario.url, nil)
+		case "GetTaskByIdHTTPHandler", "DeleteTaskByIdHTTPHandler", "ListTasksHTTPHandler",
+			"ListDeletedTasksHTTPHandler", "RestoreTaskByIdHTTPHandler", "PurgeTaskByIdHTTPHandler":
+			res, resp = utils.TestRequest(t, ts, scenario.httpMethod, scenario.url, nil)
 		case "CreateTaskHTTPHandler", "UpdateTaskByIdHTTPHandler":
 			byteArray, err := json.Marshal(*scenario.mockInputTask)
 			if err != nil {
 				t.Fatal(err)
 			}
-			_, resp = utils.TestRequest(t, ts, scenario.httpMethod, scenario.url, bytes.NewBuffer(byteArray))
+			res, resp = utils.TestRequest(t, ts, scenario.httpMethod, scenario.url, bytes.NewBuffer(byteArray))
 		}
 
-		// Verify response
+		// Verify response, the status mirrors the code in the body
 		requires.Equal(scenario.expectedOutput, resp)
+		var body encoder.HTTPSuccess
+		requires.Nil(json.Unmarshal([]byte(resp), &body))
+		requires.Equal(body.Code, res.StatusCode)
+	}
+}
+
+func TestHTTPHandler_ETag(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	current := &model.T_Task{Task: model.Task{TaskID: 2, Name: "david", Content: "content text"},
+		T_Internal: model.T_Internal{CreatedAt: &time1, Version: 3}}
+	updated := &model.T_Task{Task: model.Task{TaskID: 2, Name: "john", Content: "content text"},
+		T_Internal: model.T_Internal{CreatedAt: &time1, UpdatedAt: &time2, Version: 4}}
+	taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(current, nil)
+	taskRepoMock.On("Update", mock.Anything, mock.Anything, uint64(3)).Return(updated, nil)
+	taskRepoMock.On("Update", mock.Anything, mock.Anything, uint64(2)).Return(
+		nil, model.ErrorTaskVersionMismatch(string(encoder.TASK_VERSION_MISMATCH)))
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Get("/task/{id}", httpHandler.GetTaskByIdHTTPHandler())
+	r.Put("/task", httpHandler.UpdateTaskByIdHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	// GET returns the version as the ETag
+	resp, _ := utils.TestRequest(t, ts, "GET", "/task/2", nil)
+	requires.Equal(`"3"`, resp.Header.Get("ETag"))
+
+	put := func(ifMatch string) (*http.Response, string) {
+		return utils.TestRequestWithHeader(t, ts, "PUT", "/task", http.Header{"If-Match": {ifMatch}},
+			strings.NewReader(`{"taskID":2,"name":"john","content":"content text"}`))
+	}
+
+	// A PUT at the current version returns the new ETag
+	resp, body := put(`"3"`)
+	requires.Equal(`"4"`, resp.Header.Get("ETag"))
+	requires.Contains(body, "\"version\":4")
+
+	// A PUT at a stale version, or with an unknown tag, is rejected
+	mismatch := "{\"code\":412,\"errors\":{\"TASK_VERSION_MISMATCH\":\"task has been modified since the given version\"}}\n"
+	for _, ifMatch := range []string{`"2"`, `W/"3"`, "3"} {
+		resp, body = put(ifMatch)
+		requires.Equal(http.StatusPreconditionFailed, resp.StatusCode)
+		requires.Empty(resp.Header.Get("ETag"))
+		requires.Equal(mismatch, body)
 	}
 }
+
+func TestHTTPHandler_BadRequest(t *testing.T) {
+	logger := log.With(log.NewStdLogger(os.Stdout))
+
+	// None of the requests reach the repo
+	taskRepoMock := mocks.TaskRepo{}
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Get("/task/{id}", httpHandler.GetTaskByIdHTTPHandler())
+	r.Post("/task", httpHandler.CreateTaskHTTPHandler())
+	r.Put("/task", httpHandler.UpdateTaskByIdHTTPHandler())
+	r.Get("/task/{id}/history/{rev}", httpHandler.GetTaskRevisionHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, scenario := range []struct {
+		description    string
+		httpMethod     string
+		url            string
+		body           string
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			description:    "malformed json",
+			httpMethod:     "POST",
+			url:            "/task",
+			body:           `{"name":"david",`,
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "{\"code\":400,\"errors\":{\"BAD_REQUEST\":\"request body is invalid: unexpected EOF\"}}\n",
+		},
+		{
+			description:    "empty body",
+			httpMethod:     "POST",
+			url:            "/task",
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "{\"code\":400,\"errors\":{\"BAD_REQUEST\":\"request body is invalid: body is empty\"}}\n",
+		},
+		{
+			description:    "unknown field",
+			httpMethod:     "PUT",
+			url:            "/task",
+			body:           `{"taskID":2,"title":"david"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "{\"code\":400,\"errors\":{\"BAD_REQUEST\":\"request body is invalid: json: unknown field \\\"title\\\"\"}}\n",
+		},
+		{
+			description:    "wrong field type",
+			httpMethod:     "PUT",
+			url:            "/task",
+			body:           `{"taskID":"2"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "{\"code\":400,\"errors\":{\"BAD_REQUEST\":\"request body is invalid: json: cannot unmarshal string into Go struct field Task.taskID of type uint64\"}}\n",
+		},
+		{
+			description:    "more than one value",
+			httpMethod:     "POST",
+			url:            "/task",
+			body:           `{"name":"david"}{"name":"john"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "{\"code\":400,\"errors\":{\"BAD_REQUEST\":\"request body is invalid: unexpected data after the JSON value\"}}\n",
+		},
+		{
+			description:    "body too large",
+			httpMethod:     "POST",
+			url:            "/task",
+			body:           `{"name":"` + strings.Repeat("a", 1<<20) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedOutput: "{\"code\":413,\"errors\":{\"REQUEST_TOO_LARGE\":\"request body is too large: limit is 1048576 bytes\"}}\n",
+		},
+		{
+			description:    "id is not a number",
+			httpMethod:     "GET",
+			url:            "/task/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "{\"code\":400,\"errors\":{\"BAD_REQUEST\":\"request parameter is invalid: invalid id \\\"abc\\\"\"}}\n",
+		},
+		{
+			description:    "id overflows",
+			httpMethod:     "GET",
+			url:            "/task/18446744073709551616",
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "{\"code\":400,\"errors\":{\"BAD_REQUEST\":\"request parameter is invalid: invalid id \\\"18446744073709551616\\\"\"}}\n",
+		},
+		{
+			description:    "revision is negative",
+			httpMethod:     "GET",
+			url:            "/task/2/history/-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedOutput: "{\"code\":400,\"errors\":{\"BAD_REQUEST\":\"request parameter is invalid: invalid rev \\\"-1\\\"\"}}\n",
+		},
+	} {
+		res, resp := utils.TestRequest(t, ts, scenario.httpMethod, scenario.url, strings.NewReader(scenario.body))
+		require.Equal(t, scenario.expectedStatus, res.StatusCode, scenario.description)
+		require.Equal(t, scenario.expectedOutput, resp, scenario.description)
+	}
+	taskRepoMock.AssertExpectations(t)
+}
+
+func TestHTTPHandler_History(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	revisions := []model.T_Revision{
+		{TaskID: 2, Revision: 1, Action: model.RevisionCreated, Actor: "alice", ChangedAt: &time1,
+			Changes: []model.T_Change{{Field: "name", To: "david"}}, Task: model.Task{TaskID: 2, Name: "david"}},
+		{TaskID: 2, Revision: 2, Action: model.RevisionUpdated, Actor: "bob", ChangedAt: &time2,
+			Changes: []model.T_Change{{Field: "name", From: "david", To: "john"}}, Task: model.Task{TaskID: 2, Name: "john"}},
+	}
+	reverted := &model.T_Task{Task: model.Task{TaskID: 2, Name: "david"},
+		T_Internal: model.T_Internal{CreatedAt: &time1, UpdatedAt: &time2, Version: 3}}
+	taskRepoMock.On("History", mock.Anything, uint64(2)).Return(revisions, nil)
+	taskRepoMock.On("Revision", mock.Anything, uint64(2), uint64(1)).Return(&revisions[0], nil)
+	taskRepoMock.On("Revision", mock.Anything, uint64(2), uint64(2)).Return(&revisions[1], nil)
+	taskRepoMock.On("Revision", mock.Anything, uint64(2), uint64(5)).Return(
+		nil, model.ErrorTaskRevisionNotFound(string(encoder.TASK_REVISION_NOT_EXIST)))
+	taskRepoMock.On("Revert", mock.MatchedBy(func(ctx context.Context) bool {
+		return biz.ActorFromContext(ctx) == "carol"
+	}), uint64(2), uint64(1)).Return(reverted, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Get("/task/{id}/history", httpHandler.GetTaskHistoryHTTPHandler())
+	r.Get("/task/{id}/history/{rev}", httpHandler.GetTaskRevisionHTTPHandler())
+	r.Post("/task/{id}/history/{rev}/revert", httpHandler.RevertTaskByIdHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	_, resp := utils.TestRequest(t, ts, "GET", "/task/2/history", nil)
+	requires.Equal("{\"code\":200,\"data\":[{\"taskID\":2,\"revision\":1,\"action\":\"created\",\"actor\":\"alice\",\"changedAt\":\"2014-11-12T11:45:26.371Z\",\"changes\":[{\"field\":\"name\",\"to\":\"david\"}],\"task\":{\"taskID\":2,\"name\":\"david\"}},"+
+		"{\"taskID\":2,\"revision\":2,\"action\":\"updated\",\"actor\":\"bob\",\"changedAt\":\"2015-11-12T11:45:26.371Z\",\"changes\":[{\"field\":\"name\",\"from\":\"david\",\"to\":\"john\"}],\"task\":{\"taskID\":2,\"name\":\"john\"}}]}\n", resp)
+
+	_, resp = utils.TestRequest(t, ts, "GET", "/task/2/history/2", nil)
+	requires.Equal("{\"code\":200,\"data\":{\"taskID\":2,\"revision\":2,\"action\":\"updated\",\"actor\":\"bob\",\"changedAt\":\"2015-11-12T11:45:26.371Z\",\"changes\":[{\"field\":\"name\",\"from\":\"david\",\"to\":\"john\"}],\"task\":{\"taskID\":2,\"name\":\"john\"}}}\n", resp)
+
+	_, resp = utils.TestRequest(t, ts, "GET", "/task/2/history/5", nil)
+	requires.Equal("{\"code\":404,\"errors\":{\"TASK_REVISION_NOT_FOUND\":\"task revision does not exist\"}}\n", resp)
+
+	// The actor header is passed down to the repo
+	res, resp := utils.TestRequestWithHeader(t, ts, "POST", "/task/2/history/1/revert", http.Header{"X-Actor": {"carol"}}, nil)
+	requires.Equal(`"3"`, res.Header.Get("ETag"))
+	requires.Equal("{\"code\":200,\"data\":{\"taskID\":2,\"name\":\"david\",\"createdAt\":\"2014-11-12T11:45:26.371Z\",\"updatedAt\":\"2015-11-12T11:45:26.371Z\",\"version\":3}}\n", resp)
+}
+
+func TestHTTPHandler_RequestContext(t *testing.T) {
+	requires := require.New(t)
+
+	// Log lines written for a request carry its ID
+	var logs bytes.Buffer
+	logger := log.With(log.NewStdLogger(&logs), "request_id", server.RequestID())
+
+	taskRepoMock := mocks.TaskRepo{}
+	taskRepoMock.On("Get", mock.MatchedBy(func(ctx context.Context) bool {
+		_, ok := ctx.Deadline()
+		return ok && middleware.GetReqID(ctx) != ""
+	}), uint64(2)).Return(nil, model.ErrorTaskDbTimeout(string(encoder.TASK_DATABASE_TIMEOUT)))
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Timeout(time.Second))
+	r.Get("/task/{id}", httpHandler.GetTaskByIdHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	// The deadline and request ID of the request reach the repo
+	res, resp := utils.TestRequest(t, ts, "GET", "/task/2", nil)
+	requires.Equal(http.StatusInternalServerError, res.StatusCode)
+	requires.Equal("{\"code\":500,\"errors\":{\"TASK_DB_TIMEOUT\":\"task database timeout\"}}\n", resp)
+	taskRepoMock.AssertExpectations(t)
+
+	requires.Regexp(`request_id=\S+/\S+-\d+ msg=TaskUsecase: GetTaskByID: 2`, logs.String())
+}
+
+func TestHTTPServer_StopDrainsRequests(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+
+	// The repo holds the request until it is released
+	started, release := make(chan struct{}), make(chan struct{})
+	taskRepoMock := mocks.TaskRepo{}
+	taskRepoMock.On("Get", mock.Anything, uint64(2)).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(&model.T_Task{Task: model.Task{TaskID: 2, Name: "david"}, T_Internal: model.T_Internal{Version: 1}}, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpServer := server.NewHTTPServer(&conf.Server{Http: &conf.Server_HTTP{Timeout: durationpb.New(time.Minute)}},
+		logger, &server.Authenticator{}, server.NewTaskHTTPHandler(taskService, logger))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	requires.Nil(err)
+	served := make(chan error, 1)
+	go func() { served <- httpServer.Serve(lis) }()
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		res, err := http.Get("http://" + lis.Addr().String() + "/task/2")
+		if err != nil {
+			t.Error(err)
+		}
+		responses <- res
+	}()
+	<-started
+
+	// Stop waits for the request in flight
+	stopped := make(chan error, 1)
+	go func() { stopped <- httpServer.Stop(context.Background()) }()
+	select {
+	case <-stopped:
+		t.Fatal("stopped before the request finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	res := <-responses
+	requires.Equal(http.StatusOK, res.StatusCode)
+	res.Body.Close()
+	requires.Nil(<-stopped)
+	requires.Nil(<-served)
+
+	// No new connections are accepted
+	_, err = http.Get("http://" + lis.Addr().String() + "/task/2")
+	requires.Error(err)
+}
+
+func TestHTTPServer_StopGracePeriodExpires(t *testing.T) {
+	logger := log.With(log.NewStdLogger(os.Stdout))
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	taskRepoMock := mocks.TaskRepo{}
+	taskRepoMock.On("Get", mock.Anything, uint64(2)).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(nil, model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST)))
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpServer := server.NewHTTPServer(&conf.Server{Http: &conf.Server_HTTP{Timeout: durationpb.New(time.Minute)}},
+		logger, &server.Authenticator{}, server.NewTaskHTTPHandler(taskService, logger))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	go httpServer.Serve(lis)
+	go http.Get("http://" + lis.Addr().String() + "/task/2")
+	<-started
+
+	// A request outliving the grace period makes Stop give up
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, httpServer.Stop(ctx), context.DeadlineExceeded)
+}
+
+func TestHTTPHandler_Transition(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	current := &model.T_Task{Task: model.Task{TaskID: 2, Name: "david"},
+		T_Internal: model.T_Internal{CreatedAt: &time1, Version: 1, Status: model.StatusTodo,
+			StatusEnteredAt: map[model.TaskStatus]time.Time{model.StatusTodo: time1}}}
+	moved := &model.T_Task{Task: model.Task{TaskID: 2, Name: "david"},
+		T_Internal: model.T_Internal{CreatedAt: &time1, UpdatedAt: &time2, Version: 2, Status: model.StatusInProgress,
+			StatusEnteredAt: map[model.TaskStatus]time.Time{model.StatusTodo: time1, model.StatusInProgress: time2}}}
+	taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(current, nil)
+	taskRepoMock.On("Transition", mock.Anything, uint64(2), model.StatusInProgress, uint64(1)).Return(moved, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Post("/task/{id}/transition", httpHandler.TransitionTaskHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequestWithHeader(t, ts, "POST", "/task/2/transition", http.Header{"If-Match": {`"1"`}},
+		strings.NewReader(`{"status":"in_progress"}`))
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal(`"2"`, res.Header.Get("ETag"))
+	requires.Equal("{\"code\":200,\"data\":{\"taskID\":2,\"name\":\"david\",\"createdAt\":\"2014-11-12T11:45:26.371Z\",\"updatedAt\":\"2015-11-12T11:45:26.371Z\",\"version\":2,"+
+		"\"status\":\"in_progress\",\"statusEnteredAt\":{\"in_progress\":\"2015-11-12T11:45:26.371Z\",\"todo\":\"2014-11-12T11:45:26.371Z\"}}}\n", resp)
+
+	// The workflow does not allow skipping straight to done
+	res, resp = utils.TestRequest(t, ts, "POST", "/task/2/transition", strings.NewReader(`{"status":"done"}`))
+	requires.Equal(http.StatusConflict, res.StatusCode)
+	requires.Equal("{\"code\":409,\"errors\":{\"INVALID_TRANSITION\":\"task cannot move to the given status: from todo to done\"}}\n", resp)
+
+	// A stale version is rejected before the workflow is checked
+	res, _ = utils.TestRequestWithHeader(t, ts, "POST", "/task/2/transition", http.Header{"If-Match": {`"5"`}},
+		strings.NewReader(`{"status":"in_progress"}`))
+	requires.Equal(http.StatusPreconditionFailed, res.StatusCode)
+
+	res, _ = utils.TestRequest(t, ts, "POST", "/task/2/transition", strings.NewReader(`{"state":"done"}`))
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func TestHTTPHandler_Subtasks(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	taskRepoMock.On("Get", mock.Anything, uint64(1)).Return(&model.T_Task{Task: model.Task{TaskID: 1, Name: "parent"}}, nil)
+	taskRepoMock.On("Children", mock.Anything, uint64(1)).Return(
+		[]model.T_Task{{Task: model.Task{TaskID: 2, Name: "child", ParentID: 1}}}, nil)
+	taskRepoMock.On("Children", mock.Anything, uint64(2)).Return([]model.T_Task{}, nil)
+	taskRepoMock.On("Delete", mock.Anything, uint64(1)).Return(model.ErrorTaskHasChildren(string(encoder.TASK_HAS_CHILDREN)))
+	taskRepoMock.On("DeleteCascade", mock.Anything, uint64(1)).Return(2, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Delete("/task/{id}", httpHandler.DeleteTaskByIdHTTPHandler())
+	r.Get("/task/{id}/children", httpHandler.GetTaskChildrenHTTPHandler())
+	r.Get("/task/{id}/tree", httpHandler.GetTaskTreeHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequest(t, ts, "GET", "/task/1/children", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":[{\"taskID\":2,\"name\":\"child\",\"parentID\":1}]}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/task/1/tree", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":{\"taskID\":1,\"name\":\"parent\",\"children\":[{\"taskID\":2,\"name\":\"child\",\"parentID\":1}]}}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "DELETE", "/task/1", nil)
+	requires.Equal(http.StatusConflict, res.StatusCode)
+	requires.Equal("{\"code\":409,\"errors\":{\"TASK_HAS_CHILDREN\":\"task has subtasks\"}}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "DELETE", "/task/1?cascade=true", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":{\"deleted\":2}}\n", resp)
+
+	res, _ = utils.TestRequest(t, ts, "DELETE", "/task/1?cascade=maybe", nil)
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func TestHTTPHandler_Dependencies(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	taskRepoMock.On("AddDependency", mock.Anything, uint64(2), uint64(1)).Return(
+		&model.T_Task{Task: model.Task{TaskID: 2, Name: "deploy"}, T_Internal: model.T_Internal{Version: 2, DependsOn: []uint64{1}}}, nil)
+	taskRepoMock.On("AddDependency", mock.Anything, uint64(1), uint64(2)).Return(
+		nil, model.ErrorTaskDependencyCycle("%s: task 2 already depends on task 1", encoder.TASK_DEPENDENCY_CYCLE))
+	taskRepoMock.On("RemoveDependency", mock.Anything, uint64(2), uint64(1)).Return(
+		&model.T_Task{Task: model.Task{TaskID: 2, Name: "deploy"}, T_Internal: model.T_Internal{Version: 3}}, nil)
+	taskRepoMock.On("TopologicalOrder", mock.Anything).Return(
+		[]model.T_Task{{Task: model.Task{TaskID: 1, Name: "build"}}, {Task: model.Task{TaskID: 2, Name: "deploy"}, T_Internal: model.T_Internal{DependsOn: []uint64{1}}}}, nil)
+	taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool { return q.Ready })).Return(
+		&biz.TaskPage{Tasks: []model.T_Task{{Task: model.Task{TaskID: 1, Name: "build"}}}}, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Get("/tasks/ready", httpHandler.ListReadyTasksHTTPHandler())
+	r.Get("/tasks/graph", httpHandler.GetTaskGraphHTTPHandler())
+	r.Put("/task/{id}/dependencies/{dep}", httpHandler.AddTaskDependencyHTTPHandler())
+	r.Delete("/task/{id}/dependencies/{dep}", httpHandler.RemoveTaskDependencyHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequest(t, ts, "PUT", "/task/2/dependencies/1", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal(`"2"`, res.Header.Get("ETag"))
+	requires.Equal("{\"code\":200,\"data\":{\"taskID\":2,\"name\":\"deploy\",\"version\":2,\"dependsOn\":[1]}}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "PUT", "/task/1/dependencies/2", nil)
+	requires.Equal(http.StatusConflict, res.StatusCode)
+	requires.Equal("{\"code\":409,\"errors\":{\"TASK_DEPENDENCY_CYCLE\":\"task dependencies would form a cycle: task 2 already depends on task 1\"}}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "DELETE", "/task/2/dependencies/1", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":{\"taskID\":2,\"name\":\"deploy\",\"version\":3}}\n", resp)
+
+	res, _ = utils.TestRequest(t, ts, "PUT", "/task/2/dependencies/x", nil)
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/tasks/ready", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":[{\"taskID\":1,\"name\":\"build\"}]}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/tasks/graph", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":[{\"taskID\":1,\"name\":\"build\"},{\"taskID\":2,\"name\":\"deploy\",\"dependsOn\":[1]}]}\n", resp)
+}
+
+func TestHTTPHandler_Tags(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	taskRepoMock.On("Tags", mock.Anything).Return([]model.T_TagCount{{Tag: "bug", Count: 2}, {Tag: "needs review", Count: 1}}, nil)
+	taskRepoMock.On("RenameTag", mock.Anything, "needs review", "review").Return(1, nil)
+	taskRepoMock.On("RenameTag", mock.Anything, "missing", "bug").Return(0, model.ErrorTaskTagNotFound("%s: %q", encoder.TASK_TAG_NOT_EXIST, "missing"))
+	taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return reflect.DeepEqual([]string{"bug", "urgent"}, q.Tags) && q.TagMatch == biz.TagMatchAny
+	})).Return(&biz.TaskPage{Tasks: []model.T_Task{{Task: model.Task{TaskID: 1, Name: "fix", Tags: []string{"bug"}}}}}, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Get("/tasks", httpHandler.ListTasksHTTPHandler())
+	r.Get("/tags", httpHandler.ListTagsHTTPHandler())
+	r.Post("/tags/{tag}/rename", httpHandler.RenameTagHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequest(t, ts, "GET", "/tags", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":[{\"tag\":\"bug\",\"count\":2},{\"tag\":\"needs review\",\"count\":1}]}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/tasks?tag=urgent&tag=Bug&tagMatch=any", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":[{\"taskID\":1,\"name\":\"fix\",\"tags\":[\"bug\"]}]}\n", resp)
+
+	res, _ = utils.TestRequest(t, ts, "GET", "/tasks?tag=bug&tagMatch=none", nil)
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+
+	res, resp = utils.TestRequest(t, ts, "POST", "/tags/needs%20review/rename", strings.NewReader(`{"to":"review"}`))
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":{\"renamed\":1}}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "POST", "/tags/missing/rename", strings.NewReader(`{"to":"bug"}`))
+	requires.Equal(http.StatusNotFound, res.StatusCode)
+	requires.Equal("{\"code\":404,\"errors\":{\"TASK_TAG_NOT_FOUND\":\"task tag does not exist: \\\"missing\\\"\"}}\n", resp)
+}
+
+func TestHTTPHandler_SearchTasks(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	taskRepoMock.On("Search", mock.Anything, mock.MatchedBy(func(q *biz.SearchQuery) bool {
+		return q.Query == `"login bug" -docs` && q.Limit == 5
+	})).Return([]model.T_SearchResult{{
+		Task:    model.T_Task{Task: model.Task{TaskID: 1, Name: "fix", Content: "the login bug"}},
+		Score:   1.5,
+		Snippet: "the **login** **bug**",
+	}}, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Get("/tasks/search", httpHandler.SearchTasksHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequest(t, ts, "GET", "/tasks/search?q=%22login+bug%22+-docs&limit=5", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":[{\"task\":{\"taskID\":1,\"name\":\"fix\",\"content\":\"the login bug\"},\"score\":1.5,\"snippet\":\"the **login** **bug**\"}]}\n", resp)
+
+	for _, query := range []string{"", "?q=login&limit=many", "?q=login&limit=1000", "?q=%22login"} {
+		res, _ = utils.TestRequest(t, ts, "GET", "/tasks/search"+query, nil)
+		requires.Equal(http.StatusBadRequest, res.StatusCode, query)
+	}
+}
+
+func TestHTTPHandler_ListTasks_Filter(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return q.Filter == `status = todo AND name ~ "deploy*"`
+	})).Return(&biz.TaskPage{Tasks: []model.T_Task{{Task: model.Task{TaskID: 1, Name: "deploy"}}}}, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Get("/tasks", httpHandler.ListTasksHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequest(t, ts, "GET", "/tasks?filter="+url.QueryEscape(`status = todo AND name ~ "deploy*"`), nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":[{\"taskID\":1,\"name\":\"deploy\"}]}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/tasks?filter="+url.QueryEscape(`status = todo AND`), nil)
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+	requires.Equal("{\"code\":400,\"errors\":{\"TASK_INVALID_FILTER\":\"task filter is invalid: filter ends unexpectedly at offset 17, expected a field or \\\"(\\\"\"}}\n", resp)
+}
+
+func TestHTTPHandler_DueTasks(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	due := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	page := &biz.TaskPage{Tasks: []model.T_Task{{Task: model.Task{TaskID: 1, Name: "report", DueAt: &due}}}}
+	taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return q.Unfinished && q.DueAfter == nil && q.DueBefore != nil
+	})).Return(page, nil)
+	taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return q.Unfinished && q.DueAfter != nil && q.DueBefore.Sub(*q.DueAfter) == 90*time.Minute
+	})).Return(page, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Get("/tasks/overdue", httpHandler.ListOverdueTasksHTTPHandler())
+	r.Get("/tasks/due", httpHandler.ListDueTasksHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, url := range []string{"/tasks/overdue", "/tasks/due?within=90m"} {
+		res, resp := utils.TestRequest(t, ts, "GET", url, nil)
+		requires.Equal(http.StatusOK, res.StatusCode, url)
+		requires.Equal("{\"code\":200,\"data\":[{\"taskID\":1,\"name\":\"report\",\"dueAt\":\"2026-01-02T09:00:00Z\"}]}\n", resp, url)
+	}
+
+	res, resp := utils.TestRequest(t, ts, "GET", "/tasks/due?within=tomorrow", nil)
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+	requires.Equal("{\"code\":400,\"errors\":{\"TASK_QUERY_INVALID\":\"task query is invalid: invalid within \\\"tomorrow\\\"\"}}\n", resp)
+
+	res, _ = utils.TestRequest(t, ts, "GET", "/tasks/due?within=-1h", nil)
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func TestHTTPHandler_Series(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	first := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	series := &model.T_Series{Series: model.Series{SeriesID: 1, Rule: "FREQ=WEEKLY;BYDAY=MO", Start: &start, Name: "bins"},
+		Version: 1, Occurrences: 1, CurrentTaskID: 4, CurrentAt: &first}
+	taskRepoMock.On("CreateSeries", mock.Anything, mock.Anything, first).Return(series, nil)
+	taskRepoMock.On("GetSeries", mock.Anything, uint64(1)).Return(series, nil)
+	taskRepoMock.On("GetSeries", mock.Anything, uint64(2)).Return(nil, model.ErrorSeriesNotFound(string(encoder.SERIES_NOT_EXIST)))
+	taskRepoMock.On("UpdateSeries", mock.Anything, mock.MatchedBy(func(s *model.Series) bool {
+		return s.Start.Equal(start) && s.Name == "recycling"
+	}), uint64(1)).Return(series, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Post("/series", httpHandler.CreateSeriesHTTPHandler())
+	r.Put("/series", httpHandler.UpdateSeriesHTTPHandler())
+	r.Get("/series/{id}", httpHandler.GetSeriesByIdHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	want := "{\"code\":200,\"data\":{\"seriesID\":1,\"rule\":\"FREQ=WEEKLY;BYDAY=MO\",\"start\":\"2026-01-01T09:00:00Z\",\"name\":\"bins\"," +
+		"\"version\":1,\"occurrences\":1,\"currentTaskID\":4,\"currentAt\":\"2026-01-05T09:00:00Z\"}}\n"
+	res, resp := utils.TestRequest(t, ts, "POST", "/series", strings.NewReader(`{"rule":"FREQ=WEEKLY;BYDAY=MO","start":"2026-01-01T09:00:00Z","name":"bins"}`))
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal(`"1"`, res.Header.Get("ETag"))
+	requires.Equal(want, resp)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/series/1", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal(want, resp)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/series/2", nil)
+	requires.Equal(http.StatusNotFound, res.StatusCode)
+	requires.Equal("{\"code\":404,\"errors\":{\"SERIES_NOT_FOUND\":\"series does not exist\"}}\n", resp)
+
+	// The start is kept when an update leaves it out
+	res, _ = utils.TestRequestWithHeader(t, ts, "PUT", "/series", http.Header{"If-Match": {`"1"`}},
+		strings.NewReader(`{"seriesID":1,"rule":"FREQ=WEEKLY;BYDAY=MO","name":"recycling"}`))
+	requires.Equal(http.StatusOK, res.StatusCode)
+
+	res, resp = utils.TestRequest(t, ts, "POST", "/series", strings.NewReader(`{"rule":"0 25 * * *","name":"bins"}`))
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+	requires.Equal("{\"code\":400,\"errors\":{\"SERIES_INVALID_RULE\":\"series rule is invalid: invalid hour \\\"25\\\"\"}}\n", resp)
+}
+
+func TestHTTPHandler_Users(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	current := &model.T_Task{Task: model.Task{TaskID: 2, Name: "deploy"},
+		T_Internal: model.T_Internal{Version: 1, Status: model.StatusTodo, CreatedBy: "alice", Owner: "alice"}}
+	reassigned := &model.T_Task{Task: model.Task{TaskID: 2, Name: "deploy"},
+		T_Internal: model.T_Internal{Version: 2, Status: model.StatusTodo, CreatedBy: "alice", Owner: "alice", Assignees: []string{"bob"}}}
+	taskRepoMock.On("Get", mock.Anything, uint64(2)).Return(current, nil)
+	taskRepoMock.On("Reassign", mock.Anything, uint64(2), "alice", []string{"bob"}, uint64(1)).Return(reassigned, nil)
+	taskRepoMock.On("List", mock.Anything, mock.MatchedBy(func(q *biz.TaskQuery) bool {
+		return q.User == "bob smith" && q.Role == biz.UserRoleAssignee && q.PageSize == 10
+	})).Return(&biz.TaskPage{Tasks: []model.T_Task{*reassigned}}, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Get("/users/{id}/tasks", httpHandler.ListUserTasksHTTPHandler())
+	r.Post("/task/{id}/assign", httpHandler.ReassignTaskHTTPHandler())
+	r.Post("/task/{id}/transition", httpHandler.TransitionTaskHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequestWithHeader(t, ts, "POST", "/task/2/assign", http.Header{"If-Match": {`"1"`}, "X-Actor": {"alice"}},
+		strings.NewReader(`{"owner":"alice","assignees":["bob"]}`))
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal(`"2"`, res.Header.Get("ETag"))
+	requires.Equal("{\"code\":200,\"data\":{\"taskID\":2,\"name\":\"deploy\",\"version\":2,\"status\":\"todo\","+
+		"\"createdBy\":\"alice\",\"owner\":\"alice\",\"assignees\":[\"bob\"]}}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/users/bob%20smith/tasks?role=assignee&pageSize=10", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Contains(resp, "\"taskID\":2")
+
+	res, _ = utils.TestRequest(t, ts, "GET", "/users/bob/tasks?role=watcher", nil)
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+
+	// Only the owner and the assignees move the task
+	res, resp = utils.TestRequestWithHeader(t, ts, "POST", "/task/2/transition", http.Header{"X-Actor": {"mallory"}},
+		strings.NewReader(`{"status":"in_progress"}`))
+	requires.Equal(http.StatusForbidden, res.StatusCode)
+	requires.Equal("{\"code\":403,\"errors\":{\"TASK_FORBIDDEN\":\"task can only be changed by its owner or assignees: mallory is neither the owner nor an assignee of task 2\"}}\n", resp)
+
+	res, _ = utils.TestRequest(t, ts, "POST", "/task/2/assign", strings.NewReader(`{"owner":"alice","watchers":["bob"]}`))
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func TestHTTPHandler_Projects(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	project := &model.T_Project{Project: model.Project{ProjectID: "apollo", Columns: []string{"todo", "done"}}, Version: 2}
+	moved := &model.T_Task{Task: model.Task{TaskID: 2, Name: "deploy"},
+		T_Internal: model.T_Internal{Version: 4, Status: model.StatusTodo, ProjectID: "apollo", Column: "done", Rank: 65536}}
+	taskRepoMock.On("UpdateProject", mock.Anything, &project.Project, uint64(1)).Return(project, nil)
+	taskRepoMock.On("Move", mock.Anything, uint64(2), &model.TaskMove{ProjectID: "apollo", Column: "done"}, uint64(3)).Return(moved, nil)
+	taskRepoMock.On("Board", mock.Anything, "apollo").Return(&model.T_Board{Project: *project,
+		Columns: []model.T_BoardColumn{{Name: "todo", Tasks: []model.T_Task{}}, {Name: "done", Tasks: []model.T_Task{*moved}}}}, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Put("/projects", httpHandler.UpdateProjectHTTPHandler())
+	r.Get("/projects/{id}/board", httpHandler.GetBoardHTTPHandler())
+	r.Post("/task/{id}/move", httpHandler.MoveTaskHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequestWithHeader(t, ts, "PUT", "/projects", http.Header{"If-Match": {`"1"`}},
+		strings.NewReader(`{"projectID":"Apollo","columns":["todo","done"]}`))
+	requires.Equal(http.StatusOK, res.StatusCode, resp)
+	requires.Equal(`"2"`, res.Header.Get("ETag"))
+
+	res, resp = utils.TestRequestWithHeader(t, ts, "POST", "/task/2/move", http.Header{"If-Match": {`"3"`}},
+		strings.NewReader(`{"projectID":"apollo","column":"done"}`))
+	requires.Equal(http.StatusOK, res.StatusCode, resp)
+	requires.Equal(`"4"`, res.Header.Get("ETag"))
+	requires.Equal("{\"code\":200,\"data\":{\"taskID\":2,\"name\":\"deploy\",\"version\":4,\"status\":\"todo\","+
+		"\"projectID\":\"apollo\",\"column\":\"done\",\"rank\":65536}}\n", resp)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/projects/apollo/board", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Contains(resp, `"columns":[{"name":"todo","tasks":[]},{"name":"done","tasks":[{"taskID":2,`)
+
+	res, resp = utils.TestRequest(t, ts, "POST", "/task/2/move", strings.NewReader(`{"projectID":"apollo","before":3,"after":4}`))
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+	requires.Contains(resp, "PROJECT_INVALID")
+	res, _ = utils.TestRequest(t, ts, "GET", "/projects/a%20b/board", nil)
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func TestHTTPHandler_Comments(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	createdAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	comment := &model.T_Comment{CommentID: 5, TaskID: 2, Comment: model.Comment{Body: "ping @bob", Mentions: []string{"bob"}},
+		Author: "alice", CreatedAt: &createdAt, Version: 1}
+	taskRepoMock.On("CreateComment", mock.Anything, uint64(2), &comment.Comment).Return(comment, nil)
+	taskRepoMock.On("GetComment", mock.Anything, uint64(2), uint64(5)).Return(comment, nil)
+	taskRepoMock.On("GetComment", mock.Anything, uint64(2), uint64(6)).Return(nil, model.ErrorCommentNotFound("%s: 6", encoder.COMMENT_NOT_EXIST))
+	taskRepoMock.On("History", mock.Anything, uint64(2)).Return([]model.T_Revision{
+		{TaskID: 2, Revision: 1, Action: model.RevisionCreated, Actor: "alice", ChangedAt: &createdAt}}, nil)
+	taskRepoMock.On("ListComments", mock.Anything, uint64(2)).Return([]model.T_Comment{*comment}, nil)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Post("/task/{id}/comments", httpHandler.CreateCommentHTTPHandler())
+	r.Put("/task/{id}/comments/{comment}", httpHandler.UpdateCommentHTTPHandler())
+	r.Delete("/task/{id}/comments/{comment}", httpHandler.DeleteCommentHTTPHandler())
+	r.Get("/task/{id}/activity", httpHandler.ListActivityHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequestWithHeader(t, ts, "POST", "/task/2/comments", http.Header{"X-Actor": {"alice"}},
+		strings.NewReader(`{"body":"ping @bob"}`))
+	requires.Equal(http.StatusOK, res.StatusCode, resp)
+	requires.Equal(`"1"`, res.Header.Get("ETag"))
+	requires.Equal("{\"code\":200,\"data\":{\"commentID\":5,\"taskID\":2,\"body\":\"ping @bob\",\"mentions\":[\"bob\"],"+
+		"\"author\":\"alice\",\"createdAt\":\"2026-03-02T09:00:00Z\",\"version\":1}}\n", resp)
+
+	// Only the author may change a comment
+	res, resp = utils.TestRequestWithHeader(t, ts, "PUT", "/task/2/comments/5", http.Header{"X-Actor": {"mallory"}},
+		strings.NewReader(`{"body":"pwned"}`))
+	requires.Equal(http.StatusForbidden, res.StatusCode)
+	requires.Equal("{\"code\":403,\"errors\":{\"COMMENT_FORBIDDEN\":\"comment can only be changed by its author: mallory is not the author of comment 5\"}}\n", resp)
+	res, _ = utils.TestRequestWithHeader(t, ts, "PUT", "/task/2/comments/5", http.Header{"X-Actor": {"alice"}, "If-Match": {`"2"`}},
+		strings.NewReader(`{"body":"ping @carol"}`))
+	requires.Equal(http.StatusPreconditionFailed, res.StatusCode)
+	res, _ = utils.TestRequestWithHeader(t, ts, "DELETE", "/task/2/comments/6", http.Header{"X-Actor": {"alice"}}, nil)
+	requires.Equal(http.StatusNotFound, res.StatusCode)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/task/2/activity", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Contains(resp, `"data":[{"kind":"revision","at":"2026-03-02T09:00:00Z","actor":"alice","revision":{"taskID":2,"revision":1,"action":"created"`)
+	requires.Contains(resp, `{"kind":"comment","at":"2026-03-02T09:00:00Z","actor":"alice","comment":{"commentID":5,`)
+	taskRepoMock.AssertNotCalled(t, "UpdateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// nopSeekCloser is content which needs no closing, as the attachment store returns it.
+type nopSeekCloser struct {
+	*strings.Reader
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+func TestHTTPHandler_Attachments(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	createdAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	attachment := &model.T_Attachment{AttachmentID: 3, TaskID: 2, Name: "plan v2.txt", CreatedBy: "alice", CreatedAt: &createdAt,
+		Blob: model.Blob{SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", Size: 10, ContentType: "text/plain; charset=utf-8"}}
+	var uploaded string
+	taskRepoMock.On("CreateAttachment", mock.Anything, uint64(2), "plan v2.txt", mock.Anything).Run(func(args mock.Arguments) {
+		content, _ := io.ReadAll(args.Get(3).(io.Reader))
+		uploaded = string(content)
+	}).Return(attachment, nil)
+	taskRepoMock.On("OpenAttachment", mock.Anything, uint64(2), uint64(3)).Return(
+		func(context.Context, uint64, uint64) (*model.T_Attachment, io.ReadSeekCloser, error) {
+			return attachment, nopSeekCloser{strings.NewReader("0123456789")}, nil
+		})
+	taskRepoMock.On("OpenAttachment", mock.Anything, uint64(2), uint64(4)).Return(nil, nil,
+		model.ErrorAttachmentNotFound("%s: 4", encoder.ATTACHMENT_NOT_EXIST))
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Post("/task/{id}/attachments", httpHandler.CreateAttachmentHTTPHandler())
+	r.Get("/task/{id}/attachments/{attachment}", httpHandler.DownloadAttachmentHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	requires.Nil(form.WriteField("note", "skipped"))
+	part, err := form.CreateFormFile("file", "plan v2.txt")
+	requires.Nil(err)
+	_, err = part.Write([]byte("0123456789"))
+	requires.Nil(err)
+	requires.Nil(form.Close())
+
+	res, resp := utils.TestRequestWithHeader(t, ts, "POST", "/task/2/attachments", http.Header{"Content-Type": {form.FormDataContentType()}}, &body)
+	requires.Equal(http.StatusOK, res.StatusCode, resp)
+	requires.Equal("0123456789", uploaded)
+	requires.Contains(resp, `"data":{"attachmentID":3,"taskID":2,"name":"plan v2.txt","sha256":"9f86d0`)
+	res, _ = utils.TestRequestWithHeader(t, ts, "POST", "/task/2/attachments", http.Header{"Content-Type": {"application/json"}}, strings.NewReader(`{}`))
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/task/2/attachments/3", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("0123456789", resp)
+	requires.Equal("text/plain; charset=utf-8", res.Header.Get("Content-Type"))
+	requires.Equal(`attachment; filename="plan v2.txt"`, res.Header.Get("Content-Disposition"))
+	requires.Equal(`"`+attachment.SHA256+`"`, res.Header.Get("ETag"))
+
+	// Downloads can be resumed, and revalidated by the hash of the content
+	res, resp = utils.TestRequestWithHeader(t, ts, "GET", "/task/2/attachments/3", http.Header{"Range": {"bytes=4-"}}, nil)
+	requires.Equal(http.StatusPartialContent, res.StatusCode)
+	requires.Equal("456789", resp)
+	requires.Equal("bytes 4-9/10", res.Header.Get("Content-Range"))
+	res, _ = utils.TestRequestWithHeader(t, ts, "GET", "/task/2/attachments/3", http.Header{"If-None-Match": {`"` + attachment.SHA256 + `"`}}, nil)
+	requires.Equal(http.StatusNotModified, res.StatusCode)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/task/2/attachments/4", nil)
+	requires.Equal(http.StatusNotFound, res.StatusCode)
+	requires.Equal("{\"code\":404,\"errors\":{\"ATTACHMENT_NOT_FOUND\":\"attachment does not exist: 4\"}}\n", resp)
+}
+
+func TestHTTPServer_SlowAttachments(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+
+	attachment := &model.T_Attachment{AttachmentID: 3, TaskID: 2, Name: "plan.txt", CreatedAt: &time1,
+		Blob: model.Blob{SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", Size: 10, ContentType: "text/plain; charset=utf-8"}}
+	var uploaded string
+	var uploadErr error
+	taskRepoMock.On("CreateAttachment", mock.Anything, uint64(2), "plan.txt", mock.Anything).Run(func(args mock.Arguments) {
+		content, _ := io.ReadAll(args.Get(3).(io.Reader))
+		uploaded = string(content)
+		uploadErr = args.Get(0).(context.Context).Err()
+	}).Return(attachment, nil)
+	taskRepoMock.On("OpenAttachment", mock.Anything, uint64(2), uint64(3)).Return(
+		func(ctx context.Context, _ uint64, _ uint64) (*model.T_Attachment, io.ReadSeekCloser, error) {
+			// The download is still going after the request timeout
+			time.Sleep(100 * time.Millisecond)
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+			return attachment, nopSeekCloser{strings.NewReader("0123456789")}, nil
+		})
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, nil, logger), logger)
+	httpServer := server.NewHTTPServer(&conf.Server{Http: &conf.Server_HTTP{
+		Timeout: durationpb.New(20 * time.Millisecond), TransferTimeout: durationpb.New(time.Minute)}},
+		logger, &server.Authenticator{}, server.NewTaskHTTPHandler(taskService, logger))
+	ts := httptest.NewServer(httpServer.GetRouter())
+	defer ts.Close()
+
+	// The client sends the file slower than the request timeout allows
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		part, err := form.CreateFormFile("file", "plan.txt")
+		if err == nil {
+			_, err = part.Write([]byte("01234"))
+		}
+		time.Sleep(100 * time.Millisecond)
+		if err == nil {
+			_, err = part.Write([]byte("56789"))
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+
+	res, resp := utils.TestRequestWithHeader(t, ts, "POST", "/task/2/attachments", http.Header{"Content-Type": {form.FormDataContentType()}}, body)
+	requires.Equal(http.StatusOK, res.StatusCode, resp)
+	requires.Equal("0123456789", uploaded)
+	requires.Nil(uploadErr)
+
+	res, resp = utils.TestRequest(t, ts, "GET", "/task/2/attachments/3", nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("0123456789", resp)
+}
+
+func TestHTTPHandler_ExplainPolicy(t *testing.T) {
+	requires := require.New(t)
+	logger := log.With(log.NewStdLogger(os.Stdout))
+	taskRepoMock := mocks.TaskRepo{}
+	// The repo makes the policy check on the task, which is on no board
+	taskRepoMock.On("Purge", mock.Anything, uint64(2)).Return(func(ctx context.Context, _ uint64) error {
+		if c := biz.TaskCheckFromContext(ctx); c != nil {
+			return c.Allow("")
+		}
+		return nil
+	})
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	requires.Nil(os.WriteFile(path, []byte(`
+roles:
+  admin: ["*"]
+  viewer: [task.read]
+grants:
+  - {subject: "*", role: viewer}
+  - {subject: alice, role: admin}
+`), 0o600))
+	c := &conf.Server{Auth: &conf.Server_Auth{PolicyFile: path, ApiKeys: []*conf.Server_Auth_APIKey{
+		{Principal: "alice", Sha256: keyHash("alice-key")},
+		{Principal: "bob", Sha256: keyHash("bob-key")},
+	}}}
+	policy, err := server.NewPolicy(c, logger)
+	requires.Nil(err)
+	auth, err := server.NewAuthenticator(c, logger)
+	requires.Nil(err)
+
+	taskService := service.NewTaskService(biz.NewTaskUsecase(&taskRepoMock, policy, logger), logger)
+	httpHandler := server.NewTaskHTTPHandler(taskService, logger)
+
+	r := chi.NewRouter()
+	r.Use(auth.Middleware)
+	r.Get("/policy/explain", httpHandler.ExplainPolicyHTTPHandler())
+	r.Delete("/task/{id}/purge", httpHandler.PurgeTaskByIdHTTPHandler())
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, resp := utils.TestRequestWithHeader(t, ts, "GET", "/policy/explain?subject=bob&permission=task.purge",
+		http.Header{"X-Api-Key": {"alice-key"}}, nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+	requires.Equal("{\"code\":200,\"data\":{\"subject\":\"bob\",\"permission\":\"task.purge\",\"allowed\":false,"+
+		"\"reason\":\"bob has roles viewer, none of which allows task.purge\"}}\n", resp)
+
+	res, resp = utils.TestRequestWithHeader(t, ts, "DELETE", "/task/2/purge", http.Header{"X-Api-Key": {"bob-key"}}, nil)
+	requires.Equal(http.StatusForbidden, res.StatusCode)
+	requires.Equal("{\"code\":403,\"errors\":{\"FORBIDDEN\":\"action is not allowed by the access policy: bob has roles viewer, none of which allows task.purge\"}}\n", resp)
+
+	res, _ = utils.TestRequestWithHeader(t, ts, "DELETE", "/task/2/purge", http.Header{"X-Api-Key": {"alice-key"}}, nil)
+	requires.Equal(http.StatusOK, res.StatusCode)
+
+	// Only admins may ask
+	res, _ = utils.TestRequestWithHeader(t, ts, "GET", "/policy/explain?subject=bob&permission=task.read",
+		http.Header{"X-Api-Key": {"bob-key"}}, nil)
+	requires.Equal(http.StatusForbidden, res.StatusCode)
+
+	res, _ = utils.TestRequestWithHeader(t, ts, "GET", "/policy/explain?subject=bob&permission=task.view",
+		http.Header{"X-Api-Key": {"alice-key"}}, nil)
+	requires.Equal(http.StatusBadRequest, res.StatusCode)
+
+	// A broken policy stops the server from starting
+	requires.Nil(os.WriteFile(path, []byte("roles: {viewer: [task.view]}"), 0o600))
+	_, err = server.NewPolicy(c, logger)
+	requires.ErrorContains(err, `unknown permission "task.view"`)
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"qantas.com/task/internal/biz"
+	"qantas.com/task/internal/conf"
+)
+
+// NewPolicy loads the access policy of the config. There is none, and everything
+// is allowed, when no policy file is configured.
+func NewPolicy(c *conf.Server, logger log.Logger) (*biz.Policy, error) {
+	path := c.GetAuth().GetPolicyFile()
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", path, err)
+	}
+	p, err := biz.ParsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", path, err)
+	}
+	log.NewHelper(logger).Infof("loaded access policy %s: %d roles, %d grants", path, len(p.Roles), len(p.Grants))
+	return p, nil
+}
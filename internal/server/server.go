@@ -1,12 +1,27 @@
 package server
 
 import (
+	"context"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-kratos/kratos/v2/log"
 	"github.com/google/wire"
 )
 
 type IServer interface {
+	// Run serves until the server fails or is stopped. It returns nil once stopped.
 	Run() error
+	// Stop stops accepting connections and waits for in-flight requests until ctx is done.
+	Stop(ctx context.Context) error
 }
 
 // ProviderSet is server providers.
-var ProviderSet = wire.NewSet(NewHTTPServer, NewTaskHTTPHandler)
+var ProviderSet = wire.NewSet(NewAuthenticator, NewPolicy, NewHTTPServer, NewTaskHTTPHandler, NewGRPCServer, NewTaskGRPCHandler)
+
+// RequestID returns a log valuer for the ID of the request being served, so that
+// log.Helper.WithContext(ctx) ties every log line to its request.
+func RequestID() log.Valuer {
+	return func(ctx context.Context) interface{} {
+		return middleware.GetReqID(ctx)
+	}
+}
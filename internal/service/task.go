@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/go-kratos/kratos/v2/log"
 
@@ -17,12 +19,12 @@ func NewTaskService(uc *biz.TaskUsecase, logger log.Logger) *TaskService {
 	return &TaskService{uc: uc}
 }
 
-func (t *TaskService) ListTasks(ctx context.Context) ([]model.T_Task, error) {
-	tasks, err := t.uc.ListTasks(ctx)
+func (t *TaskService) ListTasks(ctx context.Context, q *biz.TaskQuery) (*biz.TaskPage, error) {
+	page, err := t.uc.ListTasks(ctx, q)
 	if err != nil {
 		return nil, err
 	}
-	return tasks, nil
+	return page, nil
 }
 
 func (t *TaskService) CreateTask(ctx context.Context, task *model.Task) (*model.T_Task, error) {
@@ -41,8 +43,8 @@ func (t *TaskService) GetTaskByID(ctx context.Context, id uint64) (*model.T_Task
 	return task, nil
 }
 
-func (t *TaskService) UpdateTaskByID(ctx context.Context, task *model.Task) (*model.T_Task, error) {
-	t_task, err := t.uc.UpdateTaskByID(ctx, task)
+func (t *TaskService) UpdateTaskByID(ctx context.Context, task *model.Task, version uint64) (*model.T_Task, error) {
+	t_task, err := t.uc.UpdateTaskByID(ctx, task, version)
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +59,362 @@ func (t *TaskService) DeleteTaskByID(ctx context.Context, id uint64) error {
 	return nil
 }
 
+func (t *TaskService) ListDeletedTasks(ctx context.Context, q *biz.TaskQuery) (*biz.TaskPage, error) {
+	page, err := t.uc.ListDeletedTasks(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (t *TaskService) RestoreTaskByID(ctx context.Context, id uint64) (*model.T_Task, error) {
+	task, err := t.uc.RestoreTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (t *TaskService) PurgeTaskByID(ctx context.Context, id uint64) error {
+	err := t.uc.PurgeTaskByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *TaskService) GetTaskHistory(ctx context.Context, id uint64) ([]model.T_Revision, error) {
+	revisions, err := t.uc.GetTaskHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (t *TaskService) GetTaskRevision(ctx context.Context, id uint64, rev uint64) (*model.T_Revision, error) {
+	revision, err := t.uc.GetTaskRevision(ctx, id, rev)
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (t *TaskService) RevertTaskByID(ctx context.Context, id uint64, rev uint64) (*model.T_Task, error) {
+	task, err := t.uc.RevertTaskByID(ctx, id, rev)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (t *TaskService) TransitionTask(ctx context.Context, id uint64, status model.TaskStatus, version uint64) (*model.T_Task, error) {
+	task, err := t.uc.TransitionTask(ctx, id, status, version)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (t *TaskService) ReassignTask(ctx context.Context, id uint64, a *model.TaskAssignment, version uint64) (*model.T_Task, error) {
+	task, err := t.uc.ReassignTask(ctx, id, a, version)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (t *TaskService) ListUserTasks(ctx context.Context, user string, role biz.UserRole, q *biz.TaskQuery) (*biz.TaskPage, error) {
+	page, err := t.uc.ListUserTasks(ctx, user, role, q)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (t *TaskService) GetTaskChildren(ctx context.Context, id uint64) ([]model.T_Task, error) {
+	tasks, err := t.uc.GetTaskChildren(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (t *TaskService) GetTaskTree(ctx context.Context, id uint64) (*model.T_TaskNode, error) {
+	tree, err := t.uc.GetTaskTree(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func (t *TaskService) DeleteTaskTree(ctx context.Context, id uint64) (int, error) {
+	return t.uc.DeleteTaskTree(ctx, id)
+}
+
+func (t *TaskService) AddTaskDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error) {
+	task, err := t.uc.AddTaskDependency(ctx, id, dependsOn)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (t *TaskService) RemoveTaskDependency(ctx context.Context, id uint64, dependsOn uint64) (*model.T_Task, error) {
+	task, err := t.uc.RemoveTaskDependency(ctx, id, dependsOn)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (t *TaskService) ListReadyTasks(ctx context.Context, q *biz.TaskQuery) (*biz.TaskPage, error) {
+	page, err := t.uc.ListReadyTasks(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (t *TaskService) ListOverdueTasks(ctx context.Context, q *biz.TaskQuery) (*biz.TaskPage, error) {
+	page, err := t.uc.ListOverdueTasks(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (t *TaskService) ListDueTasks(ctx context.Context, q *biz.TaskQuery, within time.Duration) (*biz.TaskPage, error) {
+	page, err := t.uc.ListDueTasks(ctx, q, within)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (t *TaskService) GetTaskGraph(ctx context.Context) ([]model.T_Task, error) {
+	tasks, err := t.uc.GetTaskGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (t *TaskService) ListTags(ctx context.Context) ([]model.T_TagCount, error) {
+	tags, err := t.uc.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (t *TaskService) RenameTag(ctx context.Context, from string, to string) (int, error) {
+	return t.uc.RenameTag(ctx, from, to)
+}
+
+func (t *TaskService) SearchTasks(ctx context.Context, q *biz.SearchQuery) ([]model.T_SearchResult, error) {
+	results, err := t.uc.SearchTasks(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (t *TaskService) CreateSeries(ctx context.Context, s *model.Series) (*model.T_Series, error) {
+	series, err := t.uc.CreateSeries(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (t *TaskService) GetSeriesByID(ctx context.Context, id uint64) (*model.T_Series, error) {
+	series, err := t.uc.GetSeriesByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (t *TaskService) ListSeries(ctx context.Context) ([]model.T_Series, error) {
+	series, err := t.uc.ListSeries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (t *TaskService) UpdateSeries(ctx context.Context, s *model.Series, version uint64) (*model.T_Series, error) {
+	series, err := t.uc.UpdateSeries(ctx, s, version)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (t *TaskService) StopSeries(ctx context.Context, id uint64) (*model.T_Series, error) {
+	series, err := t.uc.StopSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (t *TaskService) ExplainPolicy(ctx context.Context, subject string, perm biz.Permission, project string) (*model.T_PolicyDecision, error) {
+	decision, err := t.uc.ExplainPolicy(ctx, subject, perm, project)
+	if err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
+
+func (t *TaskService) CreateTenant(ctx context.Context, tenant *model.Tenant) (*model.T_Tenant, error) {
+	result, err := t.uc.CreateTenant(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (t *TaskService) ListTenants(ctx context.Context) ([]model.T_Tenant, error) {
+	tenants, err := t.uc.ListTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+func (t *TaskService) DeleteTenant(ctx context.Context, tenant string) error {
+	return t.uc.DeleteTenant(ctx, tenant)
+}
+
+func (t *TaskService) CreateProject(ctx context.Context, p *model.Project) (*model.T_Project, error) {
+	project, err := t.uc.CreateProject(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (t *TaskService) GetProject(ctx context.Context, id string) (*model.T_Project, error) {
+	project, err := t.uc.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (t *TaskService) ListProjects(ctx context.Context) ([]model.T_Project, error) {
+	projects, err := t.uc.ListProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (t *TaskService) UpdateProject(ctx context.Context, p *model.Project, version uint64) (*model.T_Project, error) {
+	project, err := t.uc.UpdateProject(ctx, p, version)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (t *TaskService) DeleteProject(ctx context.Context, id string) error {
+	return t.uc.DeleteProject(ctx, id)
+}
+
+func (t *TaskService) GetBoard(ctx context.Context, id string) (*model.T_Board, error) {
+	board, err := t.uc.GetBoard(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+func (t *TaskService) MoveTask(ctx context.Context, id uint64, m *model.TaskMove, version uint64) (*model.T_Task, error) {
+	task, err := t.uc.MoveTask(ctx, id, m, version)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (t *TaskService) CreateComment(ctx context.Context, id uint64, c *model.Comment) (*model.T_Comment, error) {
+	comment, err := t.uc.CreateComment(ctx, id, c)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (t *TaskService) GetComment(ctx context.Context, id uint64, commentID uint64) (*model.T_Comment, error) {
+	comment, err := t.uc.GetComment(ctx, id, commentID)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (t *TaskService) ListComments(ctx context.Context, id uint64) ([]model.T_Comment, error) {
+	comments, err := t.uc.ListComments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (t *TaskService) UpdateComment(ctx context.Context, id uint64, commentID uint64, c *model.Comment, version uint64) (*model.T_Comment, error) {
+	comment, err := t.uc.UpdateComment(ctx, id, commentID, c, version)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (t *TaskService) DeleteComment(ctx context.Context, id uint64, commentID uint64, version uint64) error {
+	return t.uc.DeleteComment(ctx, id, commentID, version)
+}
+
+func (t *TaskService) ListActivity(ctx context.Context, id uint64) ([]model.T_Activity, error) {
+	activity, err := t.uc.ListActivity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+func (t *TaskService) CreateAttachment(ctx context.Context, id uint64, name string, r io.Reader) (*model.T_Attachment, error) {
+	attachment, err := t.uc.CreateAttachment(ctx, id, name, r)
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (t *TaskService) GetAttachment(ctx context.Context, id uint64, attachmentID uint64) (*model.T_Attachment, error) {
+	attachment, err := t.uc.GetAttachment(ctx, id, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (t *TaskService) OpenAttachment(ctx context.Context, id uint64, attachmentID uint64) (*model.T_Attachment, io.ReadSeekCloser, error) {
+	return t.uc.OpenAttachment(ctx, id, attachmentID)
+}
+
+func (t *TaskService) ListAttachments(ctx context.Context, id uint64) ([]model.T_Attachment, error) {
+	attachments, err := t.uc.ListAttachments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (t *TaskService) DeleteAttachment(ctx context.Context, id uint64, attachmentID uint64) error {
+	return t.uc.DeleteAttachment(ctx, id, attachmentID)
+}
+
 func (t *TaskService) GetTaskUsecase() *biz.TaskUsecase {
 	return t.uc
 }
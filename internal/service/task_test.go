@@ -29,7 +29,7 @@ func TestTaskService(t *testing.T) {
 		mockInputTask    *model.Task
 		mockInputInteger uint64
 		mockReturnTask   *model.T_Task
-		mockReturnTasks  []model.T_Task
+		mockReturnPage   *biz.TaskPage
 		mockReturnError  *errors.Error
 		expectedError    *errors.Error
 	}
@@ -133,11 +133,11 @@ func TestTaskService(t *testing.T) {
 			description: "List tasks success",
 			mockMethod:  "List",
 			callMethod:  "ListTasks",
-			mockReturnTasks: []model.T_Task{
+			mockReturnPage: &biz.TaskPage{Tasks: []model.T_Task{
 				{Task: model.Task{TaskID: 1, Name: "user1", Content: "content1"},
 					T_Internal: model.T_Internal{CreatedAt: &time1}},
 				{Task: model.Task{TaskID: 2, Name: "user2", Content: "content2"},
-					T_Internal: model.T_Internal{CreatedAt: &time2}}},
+					T_Internal: model.T_Internal{CreatedAt: &time2}}}},
 		},
 		{
 			description:     "List tasks failed - database timeout",
@@ -146,6 +146,68 @@ func TestTaskService(t *testing.T) {
 			mockReturnError: model.ErrorTaskDbTimeout(string(encoder.TASK_DATABASE_TIMEOUT)),
 			expectedError:   model.ErrorTaskDbTimeout(string(encoder.TASK_DATABASE_TIMEOUT)),
 		},
+		{
+			description: "List deleted tasks success",
+			mockMethod:  "List",
+			callMethod:  "ListDeletedTasks",
+			mockReturnPage: &biz.TaskPage{Tasks: []model.T_Task{
+				{Task: model.Task{TaskID: 1, Name: "user1", Content: "content1"},
+					T_Internal: model.T_Internal{CreatedAt: &time1, DeletedAt: &time2}}}},
+		},
+		{
+			description:      "restore task by id success",
+			mockMethod:       "Restore",
+			callMethod:       "RestoreTaskByID",
+			mockInputInteger: 2,
+			mockReturnTask: &model.T_Task{Task: model.Task{TaskID: 2, Name: "user", Content: "content"},
+				T_Internal: model.T_Internal{CreatedAt: &time1, UpdatedAt: &time2}},
+		},
+		{
+			description:      "restore task by id failed - task has not been deleted",
+			mockMethod:       "Restore",
+			callMethod:       "RestoreTaskByID",
+			mockInputInteger: 2,
+			mockReturnError:  model.ErrorTaskNotDeleted(string(encoder.TASK_NOT_DELETED)),
+			expectedError:    model.ErrorTaskNotDeleted(string(encoder.TASK_NOT_DELETED)),
+		},
+		{
+			description:      "restore task by id failed - id not specified",
+			mockMethod:       "Restore",
+			callMethod:       "RestoreTaskByID",
+			mockInputInteger: 0,
+			mockReturnError:  model.ErrorTaskNotFound(string(encoder.TASK_NOT_EXIST)),
+			expectedError:    model.ErrorTaskIdUnspecified(string(encoder.TASK_ID_NOT_SPECIFIED)),
+		},
+		{
+			description:      "purge task by id success",
+			mockMethod:       "Purge",
+			callMethod:       "PurgeTaskByID",
+			mockInputInteger: 2,
+		},
+		{
+			description:      "purge task by id failed - task has not been deleted",
+			mockMethod:       "Purge",
+			callMethod:       "PurgeTaskByID",
+			mockInputInteger: 2,
+			mockReturnError:  model.ErrorTaskNotDeleted(string(encoder.TASK_NOT_DELETED)),
+			expectedError:    model.ErrorTaskNotDeleted(string(encoder.TASK_NOT_DELETED)),
+		},
+		{
+			description:      "revert task by id success",
+			mockMethod:       "Revert",
+			callMethod:       "RevertTaskByID",
+			mockInputInteger: 2,
+			mockReturnTask: &model.T_Task{Task: model.Task{TaskID: 2, Name: "user", Content: "content"},
+				T_Internal: model.T_Internal{CreatedAt: &time1, UpdatedAt: &time2, Version: 3}},
+		},
+		{
+			description:      "revert task by id failed - revision not found",
+			mockMethod:       "Revert",
+			callMethod:       "RevertTaskByID",
+			mockInputInteger: 2,
+			mockReturnError:  model.ErrorTaskRevisionNotFound(string(encoder.TASK_REVISION_NOT_EXIST)),
+			expectedError:    model.ErrorTaskRevisionNotFound(string(encoder.TASK_REVISION_NOT_EXIST)),
+		},
 	} {
 		t.Run(scenario.description, func(t *testing.T) {
 			requires := require.New(t)
@@ -155,49 +217,68 @@ func TestTaskService(t *testing.T) {
 
 			taskRepoMock := mocks.TaskRepo{}
 
+			// Update also takes the expected version, and Revert the revision
+			mockArgs := []interface{}{mock.Anything, mock.Anything}
+			if scenario.mockMethod == "Update" || scenario.mockMethod == "Revert" {
+				mockArgs = append(mockArgs, mock.Anything)
+			}
+
+			// Revert first reads the revision it goes back to, to check its parent
+			if scenario.mockMethod == "Revert" {
+				taskRepoMock.On("Revision", mock.Anything, mock.Anything, mock.Anything).Return(&model.T_Revision{Revision: 1}, nil)
+			}
+
 			// Set up dabase mock
 			switch scenario.callMethod {
-			case "CreateTask", "UpdateTaskByID", "GetTaskByID":
+			case "CreateTask", "UpdateTaskByID", "GetTaskByID", "RestoreTaskByID", "RevertTaskByID":
 				if scenario.mockReturnError != nil {
-					taskRepoMock.On(scenario.mockMethod, mock.Anything, mock.Anything).Return(nil, scenario.mockReturnError)
+					taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(nil, scenario.mockReturnError)
 				} else {
-					taskRepoMock.On(scenario.mockMethod, mock.Anything, mock.Anything).Return(scenario.mockReturnTask, nil)
+					taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(scenario.mockReturnTask, nil)
 				}
-			case "DeleteTaskByID":
+			case "DeleteTaskByID", "PurgeTaskByID":
 				if scenario.mockReturnError != nil {
-					taskRepoMock.On(scenario.mockMethod, mock.Anything, mock.Anything).Return(scenario.mockReturnError)
+					taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(scenario.mockReturnError)
 				} else {
-					taskRepoMock.On(scenario.mockMethod, mock.Anything, mock.Anything).Return(nil)
+					taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(nil)
 				}
-			case "ListTasks":
+			case "ListTasks", "ListDeletedTasks":
 				if scenario.mockReturnError != nil {
-					taskRepoMock.On(scenario.mockMethod, mock.Anything).Return(nil, scenario.mockReturnError)
+					taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(nil, scenario.mockReturnError)
 				} else {
-					taskRepoMock.On(scenario.mockMethod, mock.Anything).Return(scenario.mockReturnTasks, nil)
+					taskRepoMock.On(scenario.mockMethod, mockArgs...).Return(scenario.mockReturnPage, nil)
 				}
 			}
 
-			taskUseCase := biz.NewTaskUsecase(&taskRepoMock, logger)
+			taskUseCase := biz.NewTaskUsecase(&taskRepoMock, nil, logger)
 			taskService := service.NewTaskService(taskUseCase, logger)
 
 			var err error
 			var responseTask *model.T_Task
-			var responseTasks []model.T_Task
+			var responsePage *biz.TaskPage
 			switch scenario.callMethod {
 			case "CreateTask":
 				responseTask, err = taskService.CreateTask(context, scenario.mockInputTask)
 			case "UpdateTaskByID":
-				responseTask, err = taskService.UpdateTaskByID(context, scenario.mockInputTask)
+				responseTask, err = taskService.UpdateTaskByID(context, scenario.mockInputTask, 0)
 			case "GetTaskByID":
 				responseTask, err = taskService.GetTaskByID(context, scenario.mockInputInteger)
 			case "DeleteTaskByID":
 				err = taskService.DeleteTaskByID(context, scenario.mockInputInteger)
 			case "ListTasks":
-				responseTasks, err = taskService.ListTasks(context)
+				responsePage, err = taskService.ListTasks(context, &biz.TaskQuery{})
+			case "ListDeletedTasks":
+				responsePage, err = taskService.ListDeletedTasks(context, &biz.TaskQuery{})
+			case "RestoreTaskByID":
+				responseTask, err = taskService.RestoreTaskByID(context, scenario.mockInputInteger)
+			case "PurgeTaskByID":
+				err = taskService.PurgeTaskByID(context, scenario.mockInputInteger)
+			case "RevertTaskByID":
+				responseTask, err = taskService.RevertTaskByID(context, scenario.mockInputInteger, 1)
 			}
 
 			switch scenario.callMethod {
-			case "CreateTask", "UpdateTaskByID", "GetTaskByID":
+			case "CreateTask", "UpdateTaskByID", "GetTaskByID", "RestoreTaskByID", "RevertTaskByID":
 				if scenario.mockReturnError != nil {
 					se := new(errors.Error)
 					requires.True(errors.As(err, &se))
@@ -206,7 +287,7 @@ func TestTaskService(t *testing.T) {
 					requires.Nil(err)
 					requires.Equal(*scenario.mockReturnTask, *responseTask)
 				}
-			case "DeleteTaskByID":
+			case "DeleteTaskByID", "PurgeTaskByID":
 				if scenario.mockReturnError != nil {
 					se := new(errors.Error)
 					requires.True(errors.As(err, &se))
@@ -214,14 +295,14 @@ func TestTaskService(t *testing.T) {
 				} else {
 					requires.Nil(err)
 				}
-			case "ListTasks":
+			case "ListTasks", "ListDeletedTasks":
 				if scenario.mockReturnError != nil {
 					se := new(errors.Error)
 					requires.True(errors.As(err, &se))
 					requires.Equal(*scenario.expectedError, *se)
 				} else {
 					requires.Nil(err)
-					requires.Equal(scenario.mockReturnTasks, responseTasks)
+					requires.Equal(scenario.mockReturnPage, responsePage)
 				}
 			}
 		})
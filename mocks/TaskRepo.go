@@ -4,8 +4,11 @@ package mocks
 
 import (
 	context "context"
+	io "io"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
+	biz "qantas.com/task/internal/biz"
 	model "qantas.com/task/model"
 )
 
@@ -14,25 +17,789 @@ type TaskRepo struct {
 	mock.Mock
 }
 
+// AddDependency provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) AddDependency(_a0 context.Context, _a1 uint64, _a2 uint64) (*model.T_Task, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*model.T_Task, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *model.T_Task); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AdvanceSeries provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *TaskRepo) AdvanceSeries(_a0 context.Context, _a1 uint64, _a2 uint64, _a3 *time.Time) (*model.T_Series, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 *model.T_Series
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, *time.Time) (*model.T_Series, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, *time.Time) *model.T_Series); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, *time.Time) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Board provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) Board(_a0 context.Context, _a1 string) (*model.T_Board, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *model.T_Board
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.T_Board, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.T_Board); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Board)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Children provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) Children(_a0 context.Context, _a1 uint64) ([]model.T_Task, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []model.T_Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.T_Task, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.T_Task); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: _a0, _a1
 func (_m *TaskRepo) Create(_a0 context.Context, _a1 *model.Task) (*model.T_Task, error) {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 *model.T_Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.Task) (*model.T_Task, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Task) (*model.T_Task, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Task) *model.T_Task); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Task) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAttachment provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *TaskRepo) CreateAttachment(_a0 context.Context, _a1 uint64, _a2 string, _a3 io.Reader) (*model.T_Attachment, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 *model.T_Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, io.Reader) (*model.T_Attachment, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, io.Reader) *model.T_Attachment); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string, io.Reader) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateComment provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) CreateComment(_a0 context.Context, _a1 uint64, _a2 *model.Comment) (*model.T_Comment, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *model.Comment) (*model.T_Comment, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *model.Comment) *model.T_Comment); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, *model.Comment) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateProject provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) CreateProject(_a0 context.Context, _a1 *model.Project) (*model.T_Project, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *model.T_Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Project) (*model.T_Project, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Project) *model.T_Project); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Project) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSeries provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) CreateSeries(_a0 context.Context, _a1 *model.Series, _a2 time.Time) (*model.T_Series, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Series
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Series, time.Time) (*model.T_Series, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Series, time.Time) *model.T_Series); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Series, time.Time) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTenant provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) CreateTenant(_a0 context.Context, _a1 *model.Tenant) (*model.T_Tenant, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *model.T_Tenant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Tenant) (*model.T_Tenant, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Tenant) *model.T_Tenant); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Tenant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Tenant) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) Delete(_a0 context.Context, _a1 uint64) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAttachment provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) DeleteAttachment(_a0 context.Context, _a1 uint64, _a2 uint64) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCascade provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) DeleteCascade(_a0 context.Context, _a1 uint64) (int, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (int, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) int); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteComment provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *TaskRepo) DeleteComment(_a0 context.Context, _a1 uint64, _a2 uint64, _a3 uint64) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, uint64) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProject provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) DeleteProject(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTenant provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) DeleteTenant(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DueReminders provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) DueReminders(_a0 context.Context, _a1 time.Time) ([]model.T_Task, *time.Time, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []model.T_Task
+	var r1 *time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]model.T_Task, *time.Time, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []model.T_Task); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) *time.Time); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DueSeries provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) DueSeries(_a0 context.Context, _a1 time.Time) ([]model.T_Series, *time.Time, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []model.T_Series
+	var r1 *time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]model.T_Series, *time.Time, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []model.T_Series); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) *time.Time); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Time) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Empty provides a mock function with given fields: _a0
+func (_m *TaskRepo) Empty(_a0 context.Context) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) Get(_a0 context.Context, _a1 uint64) (*model.T_Task, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *model.T_Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*model.T_Task, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.T_Task); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAttachment provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) GetAttachment(_a0 context.Context, _a1 uint64, _a2 uint64) (*model.T_Attachment, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*model.T_Attachment, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *model.T_Attachment); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComment provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) GetComment(_a0 context.Context, _a1 uint64, _a2 uint64) (*model.T_Comment, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*model.T_Comment, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *model.T_Comment); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProject provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) GetProject(_a0 context.Context, _a1 string) (*model.T_Project, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *model.T_Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.T_Project, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.T_Project); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeries provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) GetSeries(_a0 context.Context, _a1 uint64) (*model.T_Series, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *model.T_Series
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*model.T_Series, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.T_Series); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// History provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) History(_a0 context.Context, _a1 uint64) ([]model.T_Revision, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []model.T_Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.T_Revision, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.T_Revision); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) List(_a0 context.Context, _a1 *biz.TaskQuery) (*biz.TaskPage, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *biz.TaskPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *biz.TaskQuery) (*biz.TaskPage, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *biz.TaskQuery) *biz.TaskPage); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*biz.TaskPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *biz.TaskQuery) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAttachments provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) ListAttachments(_a0 context.Context, _a1 uint64) ([]model.T_Attachment, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []model.T_Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.T_Attachment, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.T_Attachment); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListComments provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) ListComments(_a0 context.Context, _a1 uint64) ([]model.T_Comment, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []model.T_Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.T_Comment, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.T_Comment); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListProjects provides a mock function with given fields: _a0
+func (_m *TaskRepo) ListProjects(_a0 context.Context) ([]model.T_Project, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []model.T_Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.T_Project, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.T_Project); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSeries provides a mock function with given fields: _a0
+func (_m *TaskRepo) ListSeries(_a0 context.Context) ([]model.T_Series, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []model.T_Series
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.T_Series, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.T_Series); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTenants provides a mock function with given fields: _a0
+func (_m *TaskRepo) ListTenants(_a0 context.Context) ([]model.T_Tenant, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []model.T_Tenant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.T_Tenant, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.T_Tenant); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_Tenant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkReminded provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) MarkReminded(_a0 context.Context, _a1 uint64, _a2 time.Time) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Move provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *TaskRepo) Move(_a0 context.Context, _a1 uint64, _a2 *model.TaskMove, _a3 uint64) (*model.T_Task, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 *model.T_Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *model.TaskMove, uint64) (*model.T_Task, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *model.Task) *model.T_Task); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *model.TaskMove, uint64) *model.T_Task); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.T_Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *model.Task) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, *model.TaskMove, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -40,8 +807,43 @@ func (_m *TaskRepo) Create(_a0 context.Context, _a1 *model.Task) (*model.T_Task,
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: _a0, _a1
-func (_m *TaskRepo) Delete(_a0 context.Context, _a1 uint64) error {
+// OpenAttachment provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) OpenAttachment(_a0 context.Context, _a1 uint64, _a2 uint64) (*model.T_Attachment, io.ReadSeekCloser, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Attachment
+	var r1 io.ReadSeekCloser
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*model.T_Attachment, io.ReadSeekCloser, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *model.T_Attachment); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) io.ReadSeekCloser); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.ReadSeekCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, uint64) error); ok {
+		r2 = rf(_a0, _a1, _a2)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Purge provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) Purge(_a0 context.Context, _a1 uint64) error {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 error
@@ -54,22 +856,108 @@ func (_m *TaskRepo) Delete(_a0 context.Context, _a1 uint64) error {
 	return r0
 }
 
-// Empty provides a mock function with given fields: _a0
-func (_m *TaskRepo) Empty(_a0 context.Context) error {
-	ret := _m.Called(_a0)
+// PurgeDeletedBefore provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) PurgeDeletedBefore(_a0 context.Context, _a1 time.Time) (int, error) {
+	ret := _m.Called(_a0, _a1)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = rf(_a0)
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// Get provides a mock function with given fields: _a0, _a1
-func (_m *TaskRepo) Get(_a0 context.Context, _a1 uint64) (*model.T_Task, error) {
+// Reassign provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *TaskRepo) Reassign(_a0 context.Context, _a1 uint64, _a2 string, _a3 []string, _a4 uint64) (*model.T_Task, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 *model.T_Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, []string, uint64) (*model.T_Task, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, []string, uint64) *model.T_Task); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string, []string, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveDependency provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) RemoveDependency(_a0 context.Context, _a1 uint64, _a2 uint64) (*model.T_Task, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*model.T_Task, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *model.T_Task); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenameTag provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) RenameTag(_a0 context.Context, _a1 string, _a2 string) (int, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) Restore(_a0 context.Context, _a1 uint64) (*model.T_Task, error) {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 *model.T_Task
@@ -94,8 +982,138 @@ func (_m *TaskRepo) Get(_a0 context.Context, _a1 uint64) (*model.T_Task, error)
 	return r0, r1
 }
 
-// List provides a mock function with given fields: _a0
-func (_m *TaskRepo) List(_a0 context.Context) ([]model.T_Task, error) {
+// Revert provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) Revert(_a0 context.Context, _a1 uint64, _a2 uint64) (*model.T_Task, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*model.T_Task, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *model.T_Task); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revision provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) Revision(_a0 context.Context, _a1 uint64, _a2 uint64) (*model.T_Revision, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) (*model.T_Revision, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) *model.T_Revision); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) Search(_a0 context.Context, _a1 *biz.SearchQuery) ([]model.T_SearchResult, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []model.T_SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *biz.SearchQuery) ([]model.T_SearchResult, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *biz.SearchQuery) []model.T_SearchResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *biz.SearchQuery) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StopSeries provides a mock function with given fields: _a0, _a1
+func (_m *TaskRepo) StopSeries(_a0 context.Context, _a1 uint64) (*model.T_Series, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *model.T_Series
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*model.T_Series, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.T_Series); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Tags provides a mock function with given fields: _a0
+func (_m *TaskRepo) Tags(_a0 context.Context) ([]model.T_TagCount, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []model.T_TagCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.T_TagCount, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.T_TagCount); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.T_TagCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TopologicalOrder provides a mock function with given fields: _a0
+func (_m *TaskRepo) TopologicalOrder(_a0 context.Context) ([]model.T_Task, error) {
 	ret := _m.Called(_a0)
 
 	var r0 []model.T_Task
@@ -120,25 +1138,129 @@ func (_m *TaskRepo) List(_a0 context.Context) ([]model.T_Task, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: _a0, _a1
-func (_m *TaskRepo) Update(_a0 context.Context, _a1 *model.Task) (*model.T_Task, error) {
-	ret := _m.Called(_a0, _a1)
+// Transition provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *TaskRepo) Transition(_a0 context.Context, _a1 uint64, _a2 model.TaskStatus, _a3 uint64) (*model.T_Task, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	var r0 *model.T_Task
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.Task) (*model.T_Task, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.TaskStatus, uint64) (*model.T_Task, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *model.Task) *model.T_Task); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.TaskStatus, uint64) *model.T_Task); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.T_Task)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *model.Task) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.TaskStatus, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) Update(_a0 context.Context, _a1 *model.Task, _a2 uint64) (*model.T_Task, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Task, uint64) (*model.T_Task, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Task, uint64) *model.T_Task); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Task, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateComment provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *TaskRepo) UpdateComment(_a0 context.Context, _a1 uint64, _a2 uint64, _a3 *model.Comment, _a4 uint64) (*model.T_Comment, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 *model.T_Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, *model.Comment, uint64) (*model.T_Comment, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, *model.Comment, uint64) *model.T_Comment); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, *model.Comment, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProject provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) UpdateProject(_a0 context.Context, _a1 *model.Project, _a2 uint64) (*model.T_Project, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Project, uint64) (*model.T_Project, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Project, uint64) *model.T_Project); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Project, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSeries provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TaskRepo) UpdateSeries(_a0 context.Context, _a1 *model.Series, _a2 uint64) (*model.T_Series, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *model.T_Series
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Series, uint64) (*model.T_Series, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Series, uint64) *model.T_Series); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.T_Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Series, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Blob is the content of an attachment as the attachment store keeps it, named by
+// the hex SHA-256 of the content. Attachments with the same content share a blob.
+type Blob struct {
+	SHA256      string `json:"sha256"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"contentType"`
+}
+
+// T_Attachment is a file attached to a task. Attachment IDs are unique within a tenant.
+type T_Attachment struct {
+	AttachmentID uint64 `json:"attachmentID"`
+	TaskID       uint64 `json:"taskID"`
+	Name         string `json:"name"`
+	Blob
+	CreatedBy string     `json:"createdBy,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Comment is the part of a comment its clients write. Mentions are taken from the
+// body, and are not written by clients.
+type Comment struct {
+	Body     string   `json:"body"`
+	Mentions []string `json:"mentions,omitempty"`
+}
+
+// T_Comment is a stored comment on a task. Comment IDs are unique within a tenant.
+type T_Comment struct {
+	CommentID uint64 `json:"commentID"`
+	TaskID    uint64 `json:"taskID"`
+	Comment
+	Author    string     `json:"author,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	// Version starts at 1 and is incremented by every edit of the comment.
+	Version uint64 `json:"version,omitempty"`
+}
+
+type ActivityKind string
+
+const (
+	// ActivityRevision is a change to the task, as its history records it.
+	ActivityRevision ActivityKind = "revision"
+	ActivityComment  ActivityKind = "comment"
+)
+
+// T_Activity is an entry of the activity feed of a task: either a revision of the
+// task or a comment on it, whichever Kind says.
+type T_Activity struct {
+	Kind     ActivityKind `json:"kind"`
+	At       *time.Time   `json:"at,omitempty"`
+	Actor    string       `json:"actor,omitempty"`
+	Revision *T_Revision  `json:"revision,omitempty"`
+	Comment  *T_Comment   `json:"comment,omitempty"`
+}
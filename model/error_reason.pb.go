@@ -24,25 +24,148 @@ const (
 type ErrorReason int32
 
 const (
-	ErrorReason_TASK_ID_UNSPECIFIED ErrorReason = 0
-	ErrorReason_TASK_NOT_FOUND      ErrorReason = 1
-	ErrorReason_TASK_CREATION_ERROR ErrorReason = 2
-	ErrorReason_TASK_DB_TIMEOUT     ErrorReason = 3
+	ErrorReason_TASK_ID_UNSPECIFIED      ErrorReason = 0
+	ErrorReason_TASK_NOT_FOUND           ErrorReason = 1
+	ErrorReason_TASK_CREATION_ERROR      ErrorReason = 2
+	ErrorReason_TASK_DB_TIMEOUT          ErrorReason = 3
+	ErrorReason_TASK_STORAGE_ERROR       ErrorReason = 4
+	ErrorReason_TASK_QUERY_INVALID       ErrorReason = 5
+	ErrorReason_TASK_NOT_DELETED         ErrorReason = 6
+	ErrorReason_TASK_VERSION_MISMATCH    ErrorReason = 7
+	ErrorReason_TASK_REVISION_NOT_FOUND  ErrorReason = 8
+	ErrorReason_BAD_REQUEST              ErrorReason = 9
+	ErrorReason_REQUEST_TOO_LARGE        ErrorReason = 10
+	ErrorReason_REQUEST_CANCELED         ErrorReason = 11
+	ErrorReason_INVALID_TRANSITION       ErrorReason = 12
+	ErrorReason_TASK_INVALID_PARENT      ErrorReason = 13
+	ErrorReason_TASK_HAS_CHILDREN        ErrorReason = 14
+	ErrorReason_TASK_INVALID_DEPENDENCY  ErrorReason = 15
+	ErrorReason_TASK_DEPENDENCY_CYCLE    ErrorReason = 16
+	ErrorReason_TASK_BLOCKED             ErrorReason = 17
+	ErrorReason_TASK_INVALID_TAG         ErrorReason = 18
+	ErrorReason_TASK_TAG_NOT_FOUND       ErrorReason = 19
+	ErrorReason_TASK_INVALID_FILTER      ErrorReason = 20
+	ErrorReason_SERIES_NOT_FOUND         ErrorReason = 21
+	ErrorReason_SERIES_INVALID_RULE      ErrorReason = 22
+	ErrorReason_SERIES_VERSION_MISMATCH  ErrorReason = 23
+	ErrorReason_TASK_FORBIDDEN           ErrorReason = 24
+	ErrorReason_TASK_INVALID_USER        ErrorReason = 25
+	ErrorReason_UNAUTHENTICATED          ErrorReason = 26
+	ErrorReason_FORBIDDEN                ErrorReason = 27
+	ErrorReason_TENANT_NOT_FOUND         ErrorReason = 28
+	ErrorReason_TENANT_ALREADY_EXISTS    ErrorReason = 29
+	ErrorReason_TENANT_INVALID           ErrorReason = 30
+	ErrorReason_TENANT_QUOTA_EXCEEDED    ErrorReason = 31
+	ErrorReason_PROJECT_NOT_FOUND        ErrorReason = 32
+	ErrorReason_PROJECT_ALREADY_EXISTS   ErrorReason = 33
+	ErrorReason_PROJECT_INVALID          ErrorReason = 34
+	ErrorReason_PROJECT_NOT_EMPTY        ErrorReason = 35
+	ErrorReason_PROJECT_VERSION_MISMATCH ErrorReason = 36
+	ErrorReason_COMMENT_NOT_FOUND        ErrorReason = 37
+	ErrorReason_COMMENT_INVALID          ErrorReason = 38
+	ErrorReason_COMMENT_FORBIDDEN        ErrorReason = 39
+	ErrorReason_COMMENT_VERSION_MISMATCH ErrorReason = 40
+	ErrorReason_ATTACHMENT_NOT_FOUND     ErrorReason = 41
+	ErrorReason_ATTACHMENT_INVALID       ErrorReason = 42
+	ErrorReason_ATTACHMENT_TOO_LARGE     ErrorReason = 43
+	ErrorReason_ATTACHMENTS_DISABLED     ErrorReason = 44
 )
 
 // Enum value maps for ErrorReason.
 var (
 	ErrorReason_name = map[int32]string{
-		0: "TASK_ID_UNSPECIFIED",
-		1: "TASK_NOT_FOUND",
-		2: "TASK_CREATION_ERROR",
-		3: "TASK_DB_TIMEOUT",
+		0:  "TASK_ID_UNSPECIFIED",
+		1:  "TASK_NOT_FOUND",
+		2:  "TASK_CREATION_ERROR",
+		3:  "TASK_DB_TIMEOUT",
+		4:  "TASK_STORAGE_ERROR",
+		5:  "TASK_QUERY_INVALID",
+		6:  "TASK_NOT_DELETED",
+		7:  "TASK_VERSION_MISMATCH",
+		8:  "TASK_REVISION_NOT_FOUND",
+		9:  "BAD_REQUEST",
+		10: "REQUEST_TOO_LARGE",
+		11: "REQUEST_CANCELED",
+		12: "INVALID_TRANSITION",
+		13: "TASK_INVALID_PARENT",
+		14: "TASK_HAS_CHILDREN",
+		15: "TASK_INVALID_DEPENDENCY",
+		16: "TASK_DEPENDENCY_CYCLE",
+		17: "TASK_BLOCKED",
+		18: "TASK_INVALID_TAG",
+		19: "TASK_TAG_NOT_FOUND",
+		20: "TASK_INVALID_FILTER",
+		21: "SERIES_NOT_FOUND",
+		22: "SERIES_INVALID_RULE",
+		23: "SERIES_VERSION_MISMATCH",
+		24: "TASK_FORBIDDEN",
+		25: "TASK_INVALID_USER",
+		26: "UNAUTHENTICATED",
+		27: "FORBIDDEN",
+		28: "TENANT_NOT_FOUND",
+		29: "TENANT_ALREADY_EXISTS",
+		30: "TENANT_INVALID",
+		31: "TENANT_QUOTA_EXCEEDED",
+		32: "PROJECT_NOT_FOUND",
+		33: "PROJECT_ALREADY_EXISTS",
+		34: "PROJECT_INVALID",
+		35: "PROJECT_NOT_EMPTY",
+		36: "PROJECT_VERSION_MISMATCH",
+		37: "COMMENT_NOT_FOUND",
+		38: "COMMENT_INVALID",
+		39: "COMMENT_FORBIDDEN",
+		40: "COMMENT_VERSION_MISMATCH",
+		41: "ATTACHMENT_NOT_FOUND",
+		42: "ATTACHMENT_INVALID",
+		43: "ATTACHMENT_TOO_LARGE",
+		44: "ATTACHMENTS_DISABLED",
 	}
 	ErrorReason_value = map[string]int32{
-		"TASK_ID_UNSPECIFIED": 0,
-		"TASK_NOT_FOUND":      1,
-		"TASK_CREATION_ERROR": 2,
-		"TASK_DB_TIMEOUT":     3,
+		"TASK_ID_UNSPECIFIED":      0,
+		"TASK_NOT_FOUND":           1,
+		"TASK_CREATION_ERROR":      2,
+		"TASK_DB_TIMEOUT":          3,
+		"TASK_STORAGE_ERROR":       4,
+		"TASK_QUERY_INVALID":       5,
+		"TASK_NOT_DELETED":         6,
+		"TASK_VERSION_MISMATCH":    7,
+		"TASK_REVISION_NOT_FOUND":  8,
+		"BAD_REQUEST":              9,
+		"REQUEST_TOO_LARGE":        10,
+		"REQUEST_CANCELED":         11,
+		"INVALID_TRANSITION":       12,
+		"TASK_INVALID_PARENT":      13,
+		"TASK_HAS_CHILDREN":        14,
+		"TASK_INVALID_DEPENDENCY":  15,
+		"TASK_DEPENDENCY_CYCLE":    16,
+		"TASK_BLOCKED":             17,
+		"TASK_INVALID_TAG":         18,
+		"TASK_TAG_NOT_FOUND":       19,
+		"TASK_INVALID_FILTER":      20,
+		"SERIES_NOT_FOUND":         21,
+		"SERIES_INVALID_RULE":      22,
+		"SERIES_VERSION_MISMATCH":  23,
+		"TASK_FORBIDDEN":           24,
+		"TASK_INVALID_USER":        25,
+		"UNAUTHENTICATED":          26,
+		"FORBIDDEN":                27,
+		"TENANT_NOT_FOUND":         28,
+		"TENANT_ALREADY_EXISTS":    29,
+		"TENANT_INVALID":           30,
+		"TENANT_QUOTA_EXCEEDED":    31,
+		"PROJECT_NOT_FOUND":        32,
+		"PROJECT_ALREADY_EXISTS":   33,
+		"PROJECT_INVALID":          34,
+		"PROJECT_NOT_EMPTY":        35,
+		"PROJECT_VERSION_MISMATCH": 36,
+		"COMMENT_NOT_FOUND":        37,
+		"COMMENT_INVALID":          38,
+		"COMMENT_FORBIDDEN":        39,
+		"COMMENT_VERSION_MISMATCH": 40,
+		"ATTACHMENT_NOT_FOUND":     41,
+		"ATTACHMENT_INVALID":       42,
+		"ATTACHMENT_TOO_LARGE":     43,
+		"ATTACHMENTS_DISABLED":     44,
 	}
 )
 
@@ -79,7 +202,7 @@ var file_error_reason_proto_rawDesc = []byte{
 	0x0a, 0x12, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x61, 0x70, 0x69, 0x2e, 0x6b, 0x72, 0x61, 0x74, 0x6f, 0x73,
 	0x2e, 0x76, 0x31, 0x1a, 0x13, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2f, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2a, 0x86, 0x01, 0x0a, 0x0b, 0x45, 0x72, 0x72,
+	0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2a, 0xd0, 0x0a, 0x0a, 0x0b, 0x45, 0x72, 0x72,
 	0x6f, 0x72, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x13, 0x54, 0x41, 0x53, 0x4b,
 	0x5f, 0x49, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
 	0x00, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x18, 0x0a, 0x0e, 0x54, 0x41, 0x53, 0x4b, 0x5f,
@@ -87,10 +210,87 @@ var file_error_reason_proto_rawDesc = []byte{
 	0x03, 0x12, 0x1d, 0x0a, 0x13, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x49,
 	0x4f, 0x4e, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x1a, 0x04, 0xa8, 0x45, 0xf4, 0x03,
 	0x12, 0x19, 0x0a, 0x0f, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x44, 0x42, 0x5f, 0x54, 0x49, 0x4d, 0x45,
-	0x4f, 0x55, 0x54, 0x10, 0x03, 0x1a, 0x04, 0xa8, 0x45, 0xf4, 0x03, 0x1a, 0x04, 0xa0, 0x45, 0xf4,
-	0x03, 0x42, 0x1d, 0x5a, 0x1b, 0x71, 0x61, 0x6e, 0x74, 0x61, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x74, 0x61, 0x73, 0x6b, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x3b, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x4f, 0x55, 0x54, 0x10, 0x03, 0x1a, 0x04, 0xa8, 0x45, 0xf4, 0x03, 0x12, 0x1c, 0x0a, 0x12, 0x54,
+	0x41, 0x53, 0x4b, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x41, 0x47, 0x45, 0x5f, 0x45, 0x52, 0x52, 0x4f,
+	0x52, 0x10, 0x04, 0x1a, 0x04, 0xa8, 0x45, 0xf4, 0x03, 0x12, 0x1c, 0x0a, 0x12, 0x54, 0x41, 0x53,
+	0x4b, 0x5f, 0x51, 0x55, 0x45, 0x52, 0x59, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10,
+	0x05, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1a, 0x0a, 0x10, 0x54, 0x41, 0x53, 0x4b, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x06, 0x1a, 0x04, 0xa8,
+	0x45, 0x99, 0x03, 0x12, 0x1f, 0x0a, 0x15, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x56, 0x45, 0x52, 0x53,
+	0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x07, 0x1a, 0x04,
+	0xa8, 0x45, 0x9c, 0x03, 0x12, 0x21, 0x0a, 0x17, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x52, 0x45, 0x56,
+	0x49, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10,
+	0x08, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x15, 0x0a, 0x0b, 0x42, 0x41, 0x44, 0x5f, 0x52,
+	0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10, 0x09, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1b,
+	0x0a, 0x11, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x4c, 0x41,
+	0x52, 0x47, 0x45, 0x10, 0x0a, 0x1a, 0x04, 0xa8, 0x45, 0x9d, 0x03, 0x12, 0x1a, 0x0a, 0x10, 0x52,
+	0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x45, 0x44, 0x10,
+	0x0b, 0x1a, 0x04, 0xa8, 0x45, 0xf3, 0x03, 0x12, 0x1c, 0x0a, 0x12, 0x49, 0x4e, 0x56, 0x41, 0x4c,
+	0x49, 0x44, 0x5f, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x0c, 0x1a,
+	0x04, 0xa8, 0x45, 0x99, 0x03, 0x12, 0x1d, 0x0a, 0x13, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x49, 0x4e,
+	0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x50, 0x41, 0x52, 0x45, 0x4e, 0x54, 0x10, 0x0d, 0x1a, 0x04,
+	0xa8, 0x45, 0x90, 0x03, 0x12, 0x1b, 0x0a, 0x11, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x48, 0x41, 0x53,
+	0x5f, 0x43, 0x48, 0x49, 0x4c, 0x44, 0x52, 0x45, 0x4e, 0x10, 0x0e, 0x1a, 0x04, 0xa8, 0x45, 0x99,
+	0x03, 0x12, 0x21, 0x0a, 0x17, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49,
+	0x44, 0x5f, 0x44, 0x45, 0x50, 0x45, 0x4e, 0x44, 0x45, 0x4e, 0x43, 0x59, 0x10, 0x0f, 0x1a, 0x04,
+	0xa8, 0x45, 0x90, 0x03, 0x12, 0x1f, 0x0a, 0x15, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x44, 0x45, 0x50,
+	0x45, 0x4e, 0x44, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x43, 0x59, 0x43, 0x4c, 0x45, 0x10, 0x10, 0x1a,
+	0x04, 0xa8, 0x45, 0x99, 0x03, 0x12, 0x16, 0x0a, 0x0c, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x42, 0x4c,
+	0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10, 0x11, 0x1a, 0x04, 0xa8, 0x45, 0x99, 0x03, 0x12, 0x1a, 0x0a,
+	0x10, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x54, 0x41,
+	0x47, 0x10, 0x12, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1c, 0x0a, 0x12, 0x54, 0x41, 0x53,
+	0x4b, 0x5f, 0x54, 0x41, 0x47, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10,
+	0x13, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x1d, 0x0a, 0x13, 0x54, 0x41, 0x53, 0x4b, 0x5f,
+	0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x46, 0x49, 0x4c, 0x54, 0x45, 0x52, 0x10, 0x14,
+	0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1a, 0x0a, 0x10, 0x53, 0x45, 0x52, 0x49, 0x45, 0x53,
+	0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x15, 0x1a, 0x04, 0xa8, 0x45,
+	0x94, 0x03, 0x12, 0x1d, 0x0a, 0x13, 0x53, 0x45, 0x52, 0x49, 0x45, 0x53, 0x5f, 0x49, 0x4e, 0x56,
+	0x41, 0x4c, 0x49, 0x44, 0x5f, 0x52, 0x55, 0x4c, 0x45, 0x10, 0x16, 0x1a, 0x04, 0xa8, 0x45, 0x90,
+	0x03, 0x12, 0x21, 0x0a, 0x17, 0x53, 0x45, 0x52, 0x49, 0x45, 0x53, 0x5f, 0x56, 0x45, 0x52, 0x53,
+	0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x17, 0x1a, 0x04,
+	0xa8, 0x45, 0x9c, 0x03, 0x12, 0x18, 0x0a, 0x0e, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x46, 0x4f, 0x52,
+	0x42, 0x49, 0x44, 0x44, 0x45, 0x4e, 0x10, 0x18, 0x1a, 0x04, 0xa8, 0x45, 0x93, 0x03, 0x12, 0x1b,
+	0x0a, 0x11, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x55,
+	0x53, 0x45, 0x52, 0x10, 0x19, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x19, 0x0a, 0x0f, 0x55,
+	0x4e, 0x41, 0x55, 0x54, 0x48, 0x45, 0x4e, 0x54, 0x49, 0x43, 0x41, 0x54, 0x45, 0x44, 0x10, 0x1a,
+	0x1a, 0x04, 0xa8, 0x45, 0x91, 0x03, 0x12, 0x13, 0x0a, 0x09, 0x46, 0x4f, 0x52, 0x42, 0x49, 0x44,
+	0x44, 0x45, 0x4e, 0x10, 0x1b, 0x1a, 0x04, 0xa8, 0x45, 0x93, 0x03, 0x12, 0x1a, 0x0a, 0x10, 0x54,
+	0x45, 0x4e, 0x41, 0x4e, 0x54, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10,
+	0x1c, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x1f, 0x0a, 0x15, 0x54, 0x45, 0x4e, 0x41, 0x4e,
+	0x54, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x53,
+	0x10, 0x1d, 0x1a, 0x04, 0xa8, 0x45, 0x99, 0x03, 0x12, 0x18, 0x0a, 0x0e, 0x54, 0x45, 0x4e, 0x41,
+	0x4e, 0x54, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x1e, 0x1a, 0x04, 0xa8, 0x45,
+	0x90, 0x03, 0x12, 0x1f, 0x0a, 0x15, 0x54, 0x45, 0x4e, 0x41, 0x4e, 0x54, 0x5f, 0x51, 0x55, 0x4f,
+	0x54, 0x41, 0x5f, 0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x1f, 0x1a, 0x04, 0xa8,
+	0x45, 0x99, 0x03, 0x12, 0x1b, 0x0a, 0x11, 0x50, 0x52, 0x4f, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x4e,
+	0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x20, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03,
+	0x12, 0x20, 0x0a, 0x16, 0x50, 0x52, 0x4f, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x41, 0x4c, 0x52, 0x45,
+	0x41, 0x44, 0x59, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x53, 0x10, 0x21, 0x1a, 0x04, 0xa8, 0x45,
+	0x99, 0x03, 0x12, 0x19, 0x0a, 0x0f, 0x50, 0x52, 0x4f, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x49, 0x4e,
+	0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x22, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1b, 0x0a,
+	0x11, 0x50, 0x52, 0x4f, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x4d, 0x50,
+	0x54, 0x59, 0x10, 0x23, 0x1a, 0x04, 0xa8, 0x45, 0x99, 0x03, 0x12, 0x22, 0x0a, 0x18, 0x50, 0x52,
+	0x4f, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x49,
+	0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x24, 0x1a, 0x04, 0xa8, 0x45, 0x9c, 0x03, 0x12, 0x1b,
+	0x0a, 0x11, 0x43, 0x4f, 0x4d, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f,
+	0x55, 0x4e, 0x44, 0x10, 0x25, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x19, 0x0a, 0x0f, 0x43,
+	0x4f, 0x4d, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x26,
+	0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1b, 0x0a, 0x11, 0x43, 0x4f, 0x4d, 0x4d, 0x45, 0x4e,
+	0x54, 0x5f, 0x46, 0x4f, 0x52, 0x42, 0x49, 0x44, 0x44, 0x45, 0x4e, 0x10, 0x27, 0x1a, 0x04, 0xa8,
+	0x45, 0x93, 0x03, 0x12, 0x22, 0x0a, 0x18, 0x43, 0x4f, 0x4d, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x56,
+	0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10,
+	0x28, 0x1a, 0x04, 0xa8, 0x45, 0x9c, 0x03, 0x12, 0x1e, 0x0a, 0x14, 0x41, 0x54, 0x54, 0x41, 0x43,
+	0x48, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10,
+	0x29, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x1c, 0x0a, 0x12, 0x41, 0x54, 0x54, 0x41, 0x43,
+	0x48, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x2a, 0x1a,
+	0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1e, 0x0a, 0x14, 0x41, 0x54, 0x54, 0x41, 0x43, 0x48, 0x4d,
+	0x45, 0x4e, 0x54, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x4c, 0x41, 0x52, 0x47, 0x45, 0x10, 0x2b, 0x1a,
+	0x04, 0xa8, 0x45, 0x9d, 0x03, 0x12, 0x1e, 0x0a, 0x14, 0x41, 0x54, 0x54, 0x41, 0x43, 0x48, 0x4d,
+	0x45, 0x4e, 0x54, 0x53, 0x5f, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x2c, 0x1a,
+	0x04, 0xa8, 0x45, 0xf5, 0x03, 0x1a, 0x04, 0xa0, 0x45, 0xf4, 0x03, 0x42, 0x1d, 0x5a, 0x1b, 0x71,
+	0x61, 0x6e, 0x74, 0x61, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x61, 0x73, 0x6b, 0x2f, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x3b, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
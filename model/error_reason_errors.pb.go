@@ -58,3 +58,495 @@ func IsTaskDbTimeout(err error) bool {
 func ErrorTaskDbTimeout(format string, args ...interface{}) *errors.Error {
 	return errors.New(500, ErrorReason_TASK_DB_TIMEOUT.String(), fmt.Sprintf(format, args...))
 }
+
+func IsTaskStorageError(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_STORAGE_ERROR.String() && e.Code == 500
+}
+
+func ErrorTaskStorageError(format string, args ...interface{}) *errors.Error {
+	return errors.New(500, ErrorReason_TASK_STORAGE_ERROR.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskQueryInvalid(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_QUERY_INVALID.String() && e.Code == 400
+}
+
+func ErrorTaskQueryInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_TASK_QUERY_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskNotDeleted(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_NOT_DELETED.String() && e.Code == 409
+}
+
+func ErrorTaskNotDeleted(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_TASK_NOT_DELETED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskVersionMismatch(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_VERSION_MISMATCH.String() && e.Code == 412
+}
+
+func ErrorTaskVersionMismatch(format string, args ...interface{}) *errors.Error {
+	return errors.New(412, ErrorReason_TASK_VERSION_MISMATCH.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskRevisionNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_REVISION_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorTaskRevisionNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_TASK_REVISION_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsBadRequest(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_BAD_REQUEST.String() && e.Code == 400
+}
+
+func ErrorBadRequest(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_BAD_REQUEST.String(), fmt.Sprintf(format, args...))
+}
+
+func IsRequestTooLarge(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_REQUEST_TOO_LARGE.String() && e.Code == 413
+}
+
+func ErrorRequestTooLarge(format string, args ...interface{}) *errors.Error {
+	return errors.New(413, ErrorReason_REQUEST_TOO_LARGE.String(), fmt.Sprintf(format, args...))
+}
+
+func IsRequestCanceled(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_REQUEST_CANCELED.String() && e.Code == 499
+}
+
+func ErrorRequestCanceled(format string, args ...interface{}) *errors.Error {
+	return errors.New(499, ErrorReason_REQUEST_CANCELED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsInvalidTransition(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_INVALID_TRANSITION.String() && e.Code == 409
+}
+
+func ErrorInvalidTransition(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_INVALID_TRANSITION.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskInvalidParent(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_INVALID_PARENT.String() && e.Code == 400
+}
+
+func ErrorTaskInvalidParent(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_TASK_INVALID_PARENT.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskHasChildren(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_HAS_CHILDREN.String() && e.Code == 409
+}
+
+func ErrorTaskHasChildren(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_TASK_HAS_CHILDREN.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskInvalidDependency(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_INVALID_DEPENDENCY.String() && e.Code == 400
+}
+
+func ErrorTaskInvalidDependency(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_TASK_INVALID_DEPENDENCY.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskDependencyCycle(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_DEPENDENCY_CYCLE.String() && e.Code == 409
+}
+
+func ErrorTaskDependencyCycle(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_TASK_DEPENDENCY_CYCLE.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskBlocked(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_BLOCKED.String() && e.Code == 409
+}
+
+func ErrorTaskBlocked(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_TASK_BLOCKED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskInvalidTag(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_INVALID_TAG.String() && e.Code == 400
+}
+
+func ErrorTaskInvalidTag(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_TASK_INVALID_TAG.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskTagNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_TAG_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorTaskTagNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_TASK_TAG_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskInvalidFilter(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_INVALID_FILTER.String() && e.Code == 400
+}
+
+func ErrorTaskInvalidFilter(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_TASK_INVALID_FILTER.String(), fmt.Sprintf(format, args...))
+}
+
+func IsSeriesNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_SERIES_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorSeriesNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_SERIES_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsSeriesInvalidRule(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_SERIES_INVALID_RULE.String() && e.Code == 400
+}
+
+func ErrorSeriesInvalidRule(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_SERIES_INVALID_RULE.String(), fmt.Sprintf(format, args...))
+}
+
+func IsSeriesVersionMismatch(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_SERIES_VERSION_MISMATCH.String() && e.Code == 412
+}
+
+func ErrorSeriesVersionMismatch(format string, args ...interface{}) *errors.Error {
+	return errors.New(412, ErrorReason_SERIES_VERSION_MISMATCH.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskForbidden(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_FORBIDDEN.String() && e.Code == 403
+}
+
+func ErrorTaskForbidden(format string, args ...interface{}) *errors.Error {
+	return errors.New(403, ErrorReason_TASK_FORBIDDEN.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTaskInvalidUser(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TASK_INVALID_USER.String() && e.Code == 400
+}
+
+func ErrorTaskInvalidUser(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_TASK_INVALID_USER.String(), fmt.Sprintf(format, args...))
+}
+
+func IsUnauthenticated(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_UNAUTHENTICATED.String() && e.Code == 401
+}
+
+func ErrorUnauthenticated(format string, args ...interface{}) *errors.Error {
+	return errors.New(401, ErrorReason_UNAUTHENTICATED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsForbidden(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_FORBIDDEN.String() && e.Code == 403
+}
+
+func ErrorForbidden(format string, args ...interface{}) *errors.Error {
+	return errors.New(403, ErrorReason_FORBIDDEN.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTenantNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TENANT_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorTenantNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_TENANT_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTenantAlreadyExists(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TENANT_ALREADY_EXISTS.String() && e.Code == 409
+}
+
+func ErrorTenantAlreadyExists(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_TENANT_ALREADY_EXISTS.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTenantInvalid(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TENANT_INVALID.String() && e.Code == 400
+}
+
+func ErrorTenantInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_TENANT_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsTenantQuotaExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_TENANT_QUOTA_EXCEEDED.String() && e.Code == 409
+}
+
+func ErrorTenantQuotaExceeded(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_TENANT_QUOTA_EXCEEDED.String(), fmt.Sprintf(format, args...))
+}
+
+func IsProjectNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_PROJECT_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorProjectNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_PROJECT_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsProjectAlreadyExists(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_PROJECT_ALREADY_EXISTS.String() && e.Code == 409
+}
+
+func ErrorProjectAlreadyExists(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_PROJECT_ALREADY_EXISTS.String(), fmt.Sprintf(format, args...))
+}
+
+func IsProjectInvalid(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_PROJECT_INVALID.String() && e.Code == 400
+}
+
+func ErrorProjectInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_PROJECT_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsProjectNotEmpty(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_PROJECT_NOT_EMPTY.String() && e.Code == 409
+}
+
+func ErrorProjectNotEmpty(format string, args ...interface{}) *errors.Error {
+	return errors.New(409, ErrorReason_PROJECT_NOT_EMPTY.String(), fmt.Sprintf(format, args...))
+}
+
+func IsProjectVersionMismatch(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_PROJECT_VERSION_MISMATCH.String() && e.Code == 412
+}
+
+func ErrorProjectVersionMismatch(format string, args ...interface{}) *errors.Error {
+	return errors.New(412, ErrorReason_PROJECT_VERSION_MISMATCH.String(), fmt.Sprintf(format, args...))
+}
+
+func IsCommentNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_COMMENT_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorCommentNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_COMMENT_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsCommentInvalid(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_COMMENT_INVALID.String() && e.Code == 400
+}
+
+func ErrorCommentInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_COMMENT_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsCommentForbidden(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_COMMENT_FORBIDDEN.String() && e.Code == 403
+}
+
+func ErrorCommentForbidden(format string, args ...interface{}) *errors.Error {
+	return errors.New(403, ErrorReason_COMMENT_FORBIDDEN.String(), fmt.Sprintf(format, args...))
+}
+
+func IsCommentVersionMismatch(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_COMMENT_VERSION_MISMATCH.String() && e.Code == 412
+}
+
+func ErrorCommentVersionMismatch(format string, args ...interface{}) *errors.Error {
+	return errors.New(412, ErrorReason_COMMENT_VERSION_MISMATCH.String(), fmt.Sprintf(format, args...))
+}
+
+func IsAttachmentNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_ATTACHMENT_NOT_FOUND.String() && e.Code == 404
+}
+
+func ErrorAttachmentNotFound(format string, args ...interface{}) *errors.Error {
+	return errors.New(404, ErrorReason_ATTACHMENT_NOT_FOUND.String(), fmt.Sprintf(format, args...))
+}
+
+func IsAttachmentInvalid(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_ATTACHMENT_INVALID.String() && e.Code == 400
+}
+
+func ErrorAttachmentInvalid(format string, args ...interface{}) *errors.Error {
+	return errors.New(400, ErrorReason_ATTACHMENT_INVALID.String(), fmt.Sprintf(format, args...))
+}
+
+func IsAttachmentTooLarge(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_ATTACHMENT_TOO_LARGE.String() && e.Code == 413
+}
+
+func ErrorAttachmentTooLarge(format string, args ...interface{}) *errors.Error {
+	return errors.New(413, ErrorReason_ATTACHMENT_TOO_LARGE.String(), fmt.Sprintf(format, args...))
+}
+
+func IsAttachmentsDisabled(err error) bool {
+	if err == nil {
+		return false
+	}
+	e := errors.FromError(err)
+	return e.Reason == ErrorReason_ATTACHMENTS_DISABLED.String() && e.Code == 501
+}
+
+func ErrorAttachmentsDisabled(format string, args ...interface{}) *errors.Error {
+	return errors.New(501, ErrorReason_ATTACHMENTS_DISABLED.String(), fmt.Sprintf(format, args...))
+}
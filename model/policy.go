@@ -0,0 +1,21 @@
+package model
+
+// PolicyGrant gives a subject a role of the access policy, in every project or
+// only in the given one.
+type PolicyGrant struct {
+	Subject string `json:"subject" yaml:"subject"`
+	Role    string `json:"role" yaml:"role"`
+	Project string `json:"project,omitempty" yaml:"project"`
+}
+
+// T_PolicyDecision tells whether the access policy allows a subject a permission,
+// and why.
+type T_PolicyDecision struct {
+	Subject    string `json:"subject"`
+	Permission string `json:"permission"`
+	Project    string `json:"project,omitempty"`
+	Allowed    bool   `json:"allowed"`
+	// Grant is the first grant giving the permission, when it is allowed.
+	Grant  *PolicyGrant `json:"grant,omitempty"`
+	Reason string       `json:"reason"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// Project is the part of a project its clients write. Its tasks are laid out on a
+// board, in the columns listed here from left to right.
+type Project struct {
+	ProjectID string   `json:"projectID"`
+	Name      string   `json:"name,omitempty"`
+	Columns   []string `json:"columns"`
+}
+
+// T_Project is a stored project.
+type T_Project struct {
+	Project
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+	// Version starts at 1 and is incremented by every change to the project.
+	Version uint64 `json:"version,omitempty"`
+}
+
+// TaskMove is the body of a request placing a task on the board of a project. An
+// empty ProjectID takes the task off its board. The column defaults to the first
+// one of the project, and the task goes right before the task Before, right after
+// the task After, or at the bottom of the column when neither is given.
+type TaskMove struct {
+	ProjectID string `json:"projectID"`
+	Column    string `json:"column,omitempty"`
+	Before    uint64 `json:"before,omitempty"`
+	After     uint64 `json:"after,omitempty"`
+}
+
+// T_Board is a project with its live tasks grouped by column.
+type T_Board struct {
+	Project T_Project       `json:"project"`
+	Columns []T_BoardColumn `json:"columns"`
+}
+
+// T_BoardColumn is a column of a board, with its tasks from top to bottom.
+type T_BoardColumn struct {
+	Name  string   `json:"name"`
+	Tasks []T_Task `json:"tasks"`
+}
+
+// HasColumn reports whether the project has the column.
+func (x *Project) HasColumn(column string) bool {
+	if x == nil {
+		return false
+	}
+	for _, c := range x.Columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
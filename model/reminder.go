@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// T_Reminder is sent when the reminder of a task falls due.
+type T_Reminder struct {
+	TaskID   uint64     `json:"taskID"`
+	Name     string     `json:"name,omitempty"`
+	DueAt    *time.Time `json:"dueAt,omitempty"`
+	RemindAt time.Time  `json:"remindAt"`
+	SentAt   time.Time  `json:"sentAt"`
+}
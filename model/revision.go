@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+type RevisionAction string
+
+const (
+	RevisionCreated  RevisionAction = "created"
+	RevisionUpdated  RevisionAction = "updated"
+	RevisionDeleted  RevisionAction = "deleted"
+	RevisionRestored RevisionAction = "restored"
+	RevisionReverted RevisionAction = "reverted"
+	// RevisionTransitioned moved the task to another status.
+	RevisionTransitioned RevisionAction = "transitioned"
+	// RevisionReassigned changed the owner or the assignees of the task.
+	RevisionReassigned RevisionAction = "reassigned"
+	// RevisionMoved placed the task on the board of a project, or took it off.
+	RevisionMoved RevisionAction = "moved"
+)
+
+// T_Revision records one change to a task: who made it, when, what it changed,
+// and the task as it was right after. Revision numbers are the task versions.
+type T_Revision struct {
+	TaskID    uint64         `json:"taskID"`
+	Revision  uint64         `json:"revision"`
+	Action    RevisionAction `json:"action"`
+	Actor     string         `json:"actor,omitempty"`
+	ChangedAt *time.Time     `json:"changedAt,omitempty"`
+	Changes   []T_Change     `json:"changes,omitempty"`
+	// RevertedTo is the revision a reverted task was brought back to.
+	RevertedTo uint64 `json:"revertedTo,omitempty"`
+	Task       Task   `json:"task"`
+}
+
+// T_Change is the old and the new value of a single task field.
+type T_Change struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// T_SearchResult is a task matching a search, with its relevance and an excerpt
+// of its content in which the matched words are marked as **word**.
+type T_SearchResult struct {
+	Task    T_Task  `json:"task"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet,omitempty"`
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+// T_Series is a recurring task. Its occurrences are tasks created from the series
+// one at a time, each due at the next time its rule gives.
+type T_Series struct {
+	Series
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	// CreatedBy is the user who created the series, who creates and owns its occurrences.
+	CreatedBy string `json:"createdBy,omitempty"`
+	// StoppedAt is when the series was stopped, or ran out of occurrences. No
+	// occurrences are created after it.
+	StoppedAt *time.Time `json:"stoppedAt,omitempty"`
+	// Version starts at 1 and is incremented by every change to the series.
+	Version uint64 `json:"version,omitempty"`
+	// Occurrences counts the tasks created for the series so far.
+	Occurrences uint64 `json:"occurrences,omitempty"`
+	// CurrentTaskID is the latest occurrence, and CurrentAt the time it is due by
+	// the rule. The next one is created once it is finished or CurrentAt has passed.
+	CurrentTaskID uint64     `json:"currentTaskID,omitempty"`
+	CurrentAt     *time.Time `json:"currentAt,omitempty"`
+}
+
+// Series is the part of a recurring task its clients write: the rule, and the
+// fields every occurrence is created with.
+type Series struct {
+	SeriesID uint64 `json:"seriesID,omitempty"`
+	// Rule is an iCalendar RRULE such as FREQ=WEEKLY;BYDAY=MO, or a cron expression
+	// such as 0 9 * * 1.
+	Rule string `json:"rule,omitempty"`
+	// Start is the earliest time an occurrence can be due. The times of the rule are
+	// taken in its time zone, and an RRULE occurs at its time of day.
+	Start    *time.Time `json:"start,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	Content  string     `json:"content,omitempty"`
+	ParentID uint64     `json:"parentID,omitempty"`
+	Tags     []string   `json:"tags,omitempty"`
+}
+
+// Occurrence returns the task of the occurrence due at the given time.
+func (x *Series) Occurrence(at time.Time) Task {
+	return Task{Name: x.Name, Content: x.Content, ParentID: x.ParentID, Tags: x.Tags, DueAt: &at}
+}
+
+// Stopped reports whether no more occurrences are created for the series.
+func (x *T_Series) Stopped() bool {
+	return x != nil && x.StoppedAt != nil
+}
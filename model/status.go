@@ -0,0 +1,26 @@
+package model
+
+// TaskStatus is the stage of its workflow a task is in.
+type TaskStatus string
+
+const (
+	StatusTodo       TaskStatus = "todo"
+	StatusInProgress TaskStatus = "in_progress"
+	StatusBlocked    TaskStatus = "blocked"
+	StatusDone       TaskStatus = "done"
+	StatusCancelled  TaskStatus = "cancelled"
+)
+
+// Valid reports whether s is one of the known statuses.
+func (s TaskStatus) Valid() bool {
+	switch s {
+	case StatusTodo, StatusInProgress, StatusBlocked, StatusDone, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// TaskTransition is the body of a request moving a task to another status.
+type TaskTransition struct {
+	Status TaskStatus `json:"status"`
+}
@@ -0,0 +1,13 @@
+package model
+
+// T_TagCount is a tag with the number of live tasks carrying it.
+type T_TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagRename is the request to rename a tag on every task. Renaming a tag to
+// one which is already in use merges the two.
+type TagRename struct {
+	To string `json:"to"`
+}
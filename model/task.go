@@ -13,12 +13,52 @@ type Task struct {
 	TaskID  uint64 `json:"taskID,omitempty"`
 	Name    string `json:"name,omitempty"`
 	Content string `json:"content,omitempty"`
+	// ParentID makes the task a subtask of another one. 0 is a top level task.
+	ParentID uint64 `json:"parentID,omitempty"`
+	// Tags label the task. They are stored lower case, sorted and without duplicates.
+	Tags []string `json:"tags,omitempty"`
+	// DueAt is when the task should be finished by.
+	DueAt *time.Time `json:"dueAt,omitempty"`
+	// RemindAt is when a reminder of the task is sent.
+	RemindAt *time.Time `json:"remindAt,omitempty"`
+}
+
+// T_TaskNode is a task together with its subtasks, recursively.
+type T_TaskNode struct {
+	T_Task
+	Children []T_TaskNode `json:"children,omitempty"`
 }
 
 type T_Internal struct {
 	CreatedAt *time.Time `json:"createdAt,omitempty"`
 	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+	// Version starts at 1 and is incremented by every change to the task.
+	Version uint64 `json:"version,omitempty"`
+	// Status only changes through transitions allowed by the workflow.
+	Status TaskStatus `json:"status,omitempty"`
+	// StatusEnteredAt is when the task last entered each status it has been in.
+	StatusEnteredAt map[TaskStatus]time.Time `json:"statusEnteredAt,omitempty"`
+	// DependsOn lists the IDs of the tasks which must be done before this one can
+	// start, in ascending order. The dependencies of all tasks form a DAG.
+	DependsOn []uint64 `json:"dependsOn,omitempty"`
+	// RemindedAt is when the last reminder of the task was sent. The reminder at
+	// RemindAt is pending until RemindedAt has caught up with it.
+	RemindedAt *time.Time `json:"remindedAt,omitempty"`
+	// SeriesID is the recurring task the task is an occurrence of. 0 is a one-off task.
+	SeriesID uint64 `json:"seriesID,omitempty"`
+	// CreatedBy is the user who created the task, and its first owner.
+	CreatedBy string `json:"createdBy,omitempty"`
+	// Owner and Assignees are the users who may move the task through its workflow.
+	// A task which has neither may be moved by anyone. Assignees are sorted and
+	// without duplicates.
+	Owner     string   `json:"owner,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	// ProjectID and Column place the task on the board of a project, where Rank
+	// orders it within its column, lowest first. Tasks are only placed by moves.
+	ProjectID string `json:"projectID,omitempty"`
+	Column    string `json:"column,omitempty"`
+	Rank      uint64 `json:"rank,omitempty"`
 }
 
 func (x *Task) GetTaskID() uint64 {
@@ -42,6 +82,41 @@ func (x *Task) GetContent() string {
 	return ""
 }
 
+func (x *Task) GetParentID() uint64 {
+	if x != nil {
+		return x.ParentID
+	}
+	return 0
+}
+
+func (x *Task) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Task) GetDueAt() *time.Time {
+	if x != nil {
+		return x.DueAt
+	}
+	return &time.Time{}
+}
+
+func (x *Task) GetRemindAt() *time.Time {
+	if x != nil {
+		return x.RemindAt
+	}
+	return &time.Time{}
+}
+
+func (x *T_Internal) GetDependsOn() []uint64 {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
 func (x *T_Internal) GetCreatedAt() *time.Time {
 	if x != nil {
 		return x.CreatedAt
@@ -62,3 +137,99 @@ func (x *T_Internal) GetDeletedAt() *time.Time {
 	}
 	return &time.Time{}
 }
+
+// GetStatus returns the status of the task. Tasks stored before statuses were
+// introduced are still to do.
+func (x *T_Internal) GetStatus() TaskStatus {
+	if x != nil && x.Status != "" {
+		return x.Status
+	}
+	return StatusTodo
+}
+
+func (x *T_Internal) GetRemindedAt() *time.Time {
+	if x != nil {
+		return x.RemindedAt
+	}
+	return &time.Time{}
+}
+
+func (x *T_Internal) GetSeriesID() uint64 {
+	if x != nil {
+		return x.SeriesID
+	}
+	return 0
+}
+
+func (x *T_Internal) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *T_Internal) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *T_Internal) GetAssignees() []string {
+	if x != nil {
+		return x.Assignees
+	}
+	return nil
+}
+
+func (x *T_Internal) GetProjectID() string {
+	if x != nil {
+		return x.ProjectID
+	}
+	return ""
+}
+
+// Assigned reports whether the user owns the task or is one of its assignees.
+func (x *T_Internal) Assigned(user string) bool {
+	if x == nil || user == "" {
+		return false
+	}
+	if x.Owner == user {
+		return true
+	}
+	for _, a := range x.Assignees {
+		if a == user {
+			return true
+		}
+	}
+	return false
+}
+
+// Unassigned reports whether the task has neither an owner nor assignees.
+func (x *T_Internal) Unassigned() bool {
+	return x.GetOwner() == "" && len(x.GetAssignees()) == 0
+}
+
+// Finished reports whether the task is done or cancelled.
+func (x *T_Internal) Finished() bool {
+	status := x.GetStatus()
+	return status == StatusDone || status == StatusCancelled
+}
+
+// ReminderDue reports whether the reminder of the task is due at now and has not
+// been sent yet. Reminders of finished tasks are never due.
+func (x *T_Task) ReminderDue(now time.Time) bool {
+	return x.RemindAt != nil && !x.RemindAt.After(now) && !x.Reminded() && !x.Finished()
+}
+
+// Reminded reports whether the reminder at RemindAt has been sent.
+func (x *T_Task) Reminded() bool {
+	return x.RemindAt != nil && x.RemindedAt != nil && !x.RemindedAt.Before(*x.RemindAt)
+}
+
+func (x *T_Internal) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Tenant is the body of a request creating a tenant. Quotas of 0 are unlimited.
+type Tenant struct {
+	TenantID string `json:"tenantID"`
+	// MaxTasks bounds how many tasks the tenant stores, trashed ones included.
+	MaxTasks int `json:"maxTasks,omitempty"`
+	// MaxSeries bounds how many recurring task series the tenant stores.
+	MaxSeries int `json:"maxSeries,omitempty"`
+}
+
+// T_Tenant is a stored tenant.
+type T_Tenant struct {
+	Tenant
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	// Usage is what the tenant stores, filled in when tenants are listed.
+	Usage *TenantUsage `json:"usage,omitempty"`
+}
+
+// TenantUsage counts what a tenant stores, to compare with its quotas.
+type TenantUsage struct {
+	Tasks  int `json:"tasks"`
+	Series int `json:"series"`
+}
@@ -0,0 +1,8 @@
+package model
+
+// TaskAssignment is the body of a request reassigning a task. It replaces both
+// the owner and the assignees; an empty owner leaves the task without one.
+type TaskAssignment struct {
+	Owner     string   `json:"owner"`
+	Assignees []string `json:"assignees"`
+}
@@ -9,11 +9,19 @@ import (
 )
 
 func TestRequest(t *testing.T, ts *httptest.Server, method, path string, body io.Reader) (*http.Response, string) {
+	return TestRequestWithHeader(t, ts, method, path, nil, body)
+}
+
+// TestRequestWithHeader sends a request with the given headers, such as If-Match.
+func TestRequestWithHeader(t *testing.T, ts *httptest.Server, method, path string, header http.Header, body io.Reader) (*http.Response, string) {
 	req, err := http.NewRequest(method, ts.URL+path, body)
 	if err != nil {
 		t.Fatal(err)
 		return nil, ""
 	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {